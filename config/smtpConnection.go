@@ -1,49 +1,84 @@
 package config
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/smtp"
 	"os"
 )
 
 var SMTPClient *smtp.Client
 
-func SMTPConnect() error {
-	host := os.Getenv("SMTP_HOST")
-	port := os.Getenv("SMTP_PORT")
-	email := os.Getenv("SMTP_EMAIL")
-	password := os.Getenv("SMTP_PASSWORD")
+// SMTPSettings is how to reach the SMTP server mail goes out through. Email is both the
+// login and the sender address.
+type SMTPSettings struct {
+	Host     string
+	Port     string
+	Email    string
+	Password string
+}
 
-	fmt.Printf("Connecting to SMTP server at %s:%s with email %s\n", host, port, email)
+// SMTPSettingsFromEnv reads SMTP_HOST, SMTP_PORT, SMTP_EMAIL and SMTP_PASSWORD
+func SMTPSettingsFromEnv() SMTPSettings {
+	return SMTPSettings{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Email:    os.Getenv("SMTP_EMAIL"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+	}
+}
 
-	smtpAuth := smtp.PlainAuth("", email, password, host)
+func SMTPConnect() error {
+	settings := SMTPSettingsFromEnv()
+	fmt.Printf("Connecting to SMTP server at %s:%s with email %s\n", settings.Host, settings.Port, settings.Email)
 
-	// connect to smtp server
-	client, err := smtp.Dial(host + ":" + port)
+	client, err := DialSMTP(context.Background(), settings)
 	if err != nil {
 		fmt.Println("Failed to connect to SMTP server:", err)
 		return err
 	}
 
 	SMTPClient = client
-	client = nil
+	return nil
+}
+
+// DialSMTP opens a new session with the SMTP server, upgrading it to TLS when the server
+// offers STARTTLS. It only authenticates when a password is set, so a local stand-in
+// without authentication works too.
+func DialSMTP(ctx context.Context, settings SMTPSettings) (*smtp.Client, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(settings.Host, settings.Port))
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, settings.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
 
 	// initiate TLS handshake
-	if ok, _ := SMTPClient.Extension("STARTTLS"); ok {
-		config := &tls.Config{ServerName: host}
-		if err = SMTPClient.StartTLS(config); err != nil {
-			fmt.Println("Failed to start TLS:", err)
-			return err
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		config := &tls.Config{ServerName: settings.Host}
+		if err = client.StartTLS(config); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("starting TLS: %w", err)
 		}
 	}
 
 	// authenticate
-	err = SMTPClient.Auth(smtpAuth)
-	if err != nil {
-		fmt.Println("Failed to authenticate:", err)
-		return err
+	if settings.Password != "" {
+		if err = client.Auth(smtp.PlainAuth("", settings.Email, settings.Password, settings.Host)); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("authenticating: %w", err)
+		}
 	}
 
-	return nil
+	return client, nil
 }
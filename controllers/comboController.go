@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func GetCombos() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		combos, err := Repos.Combos.List(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch combos from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Combos fetched successfully", "combos": combos})
+	}
+}
+
+func GetCombo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("combo_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Combo ID is required"})
+			return
+		}
+
+		combo, err := Repos.Combos.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No combo found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch combo from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Combo fetched successfully", "combo": combo})
+	}
+}
+
+func CreateCombo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		var combo models.Combo
+		if err := c.BindJSON(&combo); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for creating combo", "details": err.Error()})
+			return
+		}
+
+		if !validateCombo(ctx, c, &combo) {
+			return
+		}
+
+		// The combo and its slots are saved together
+		err := UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			var err error
+			combo, err = repos.Combos.Create(ctx, combo)
+			return err
+		})
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create combo in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Combo created successfully", "combo": combo})
+	}
+}
+
+func UpdateCombo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("combo_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Combo ID is required"})
+			return
+		}
+
+		var combo models.Combo
+		if err := c.BindJSON(&combo); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for updating combo", "details": err.Error()})
+			return
+		}
+
+		// Combos never move to another restaurant
+		current, err := Repos.Combos.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No combo found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch combo from database", "details": err.Error()})
+			return
+		}
+		combo.ID, combo.RestaurantID = id, current.RestaurantID
+
+		if !validateCombo(ctx, c, &combo) {
+			return
+		}
+
+		// Combos already ordered keep their price and components
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			combo, err = repos.Combos.Update(ctx, combo)
+			return err
+		})
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No combo found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update combo in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Combo updated successfully", "combo": combo})
+	}
+}
+
+func DeleteCombo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("combo_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Combo ID is required"})
+			return
+		}
+
+		if err := Repos.Combos.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No combo found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete combo from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Combo deleted successfully", "combo_id": id})
+	}
+}
+
+// validateCombo responds with the validation errors of the combo, if any. Every food filling
+// its slots must be the restaurant's and offered once per slot.
+func validateCombo(ctx context.Context, c *gin.Context, combo *models.Combo) bool {
+	if err := validate.Struct(combo); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+		}
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		return false
+	}
+
+	foods, err := comboFoods(ctx, Repos, combo.RestaurantID)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch foods from database", "details": err.Error()})
+		return false
+	}
+	for _, slot := range combo.Slots {
+		offered := map[uint]bool{slot.FoodID: true}
+		if _, ok := foods[slot.FoodID]; !ok {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Food does not belong to this restaurant", "food_id": slot.FoodID})
+			return false
+		}
+		for _, choice := range slot.Choices {
+			if _, ok := foods[choice.FoodID]; !ok {
+				c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Food does not belong to this restaurant", "food_id": choice.FoodID})
+				return false
+			}
+			if offered[choice.FoodID] {
+				c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "The " + slot.Name + " slot offers the same food more than once", "food_id": choice.FoodID})
+				return false
+			}
+			offered[choice.FoodID] = true
+		}
+	}
+
+	combo.Price = toFixed(combo.Price, 2)
+	if combo.TaxCategory == "" {
+		combo.TaxCategory = models.TaxCategoryFood
+	}
+	return true
+}
+
+// comboFoods loads the foods of a restaurant by ID, the ones its combos may be filled with
+func comboFoods(ctx context.Context, repos repository.Repositories, restaurantID uint) (map[uint]models.Food, error) {
+	foods, err := repos.Foods.List(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Food, len(foods))
+	for _, food := range foods {
+		byID[food.ID] = food
+	}
+	return byID, nil
+}
+
+// expandCombo prices a combo ordered with the guest's picks and fills in its components,
+// answering with 404 for a combo that doesn't exist and 400 for picks that don't fit it
+func expandCombo(ctx context.Context, item *models.OrderItem) (models.Combo, map[uint]models.Food, error) {
+	combo, err := Repos.Combos.Get(ctx, item.ComboID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return combo, nil, abortWith(http.StatusNotFound, gin.H{"error": "No combo found with given ID", "combo_id": item.ComboID})
+		}
+		return combo, nil, fmt.Errorf("fetching the combo: %w", err)
+	}
+	foods, err := comboFoods(ctx, Repos, combo.RestaurantID)
+	if err != nil {
+		return combo, nil, fmt.Errorf("fetching the foods: %w", err)
+	}
+
+	components, unitPrice, err := combo.Expand(foods, item.ComboPicks)
+	if err != nil {
+		return combo, nil, abortWith(http.StatusBadRequest, gin.H{"error": "The combo can't be ordered like this", "combo_id": combo.ID, "details": err.Error()})
+	}
+	item.FoodID, item.VariantID, item.VariantName, item.ModifierIDs, item.Modifiers = 0, 0, "", nil, nil
+	item.UnitPrice, item.Components = unitPrice, models.ScaleComponents(components, item.Quantity)
+	return combo, foods, nil
+}
+
+// checkComboOrderable makes sure a guest can order a combo from a restaurant right now, with
+// the foods they picked for its slots
+func checkComboOrderable(ctx context.Context, repos repository.Repositories, combo models.Combo, foods map[uint]models.Food, components []models.OrderItem, restaurantID uint) error {
+	if combo.RestaurantID != restaurantID {
+		return abortWith(http.StatusBadRequest, gin.H{"error": "The combo is not on this restaurant's menu", "combo_id": combo.ID})
+	}
+	menus, at, err := orderingSchedule(ctx, repos, restaurantID)
+	if err != nil {
+		return err
+	}
+	reason := models.ComboUnavailableReason(combo, foods, menus, at)
+	for _, component := range components {
+		if reason == "" {
+			reason = models.FoodUnavailableReason(foods[component.FoodID], menus, at)
+		}
+	}
+	if reason != "" {
+		return abortWith(http.StatusConflict, gin.H{"error": "The combo can't be ordered right now", "reason": reason, "combo_id": combo.ID})
+	}
+	return nil
+}
@@ -0,0 +1,407 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"restaurant-management/gateway"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func GetCreditNotes() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("invoice_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+			return
+		}
+
+		notes, err := Repos.CreditNotes.ListByInvoice(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch credit notes from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Credit notes fetched successfully", "invoice_id": id, "credit_notes": notes})
+	}
+}
+
+func GetCreditNote() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("credit_note_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Credit note ID is required"})
+			return
+		}
+
+		note, err := Repos.CreditNotes.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No credit note found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch credit note from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Credit note fetched successfully", "credit_note": note})
+	}
+}
+
+// IssueCreditNote corrects an order's paid invoice with a credit note, voiding items or
+// crediting an amount, and gives the money back through refunds against its payments.
+// The invoice itself is kept as it was. Refunds of online payments are sent to the
+// payment provider once the credit note is stored.
+func IssueCreditNote() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("invoice_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+			return
+		}
+
+		var request models.IssueCreditNote
+		if err := c.BindJSON(&request); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for issuing a credit note", "details": err.Error()})
+			return
+		}
+
+		if err := validate.Struct(request); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+		if len(request.Items) > 0 && request.Amount > 0 {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Void items or credit an amount, not both"})
+			return
+		}
+
+		var note models.CreditNote
+		var invoice models.Invoice
+		transactions := map[uint]string{}
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			var order models.Order
+			invoice, order, err = lockInvoiceOrder(ctx, repos, id)
+			if err != nil {
+				return err
+			}
+			if err := checkCreditable(invoice); err != nil {
+				return err
+			}
+
+			left := helpers.ToCents(invoice.Total) - helpers.ToCents(invoice.Credited)
+			if len(request.Items) > 0 {
+				discounts, err := repos.Discounts.ListByOrder(ctx, order.ID)
+				if err != nil {
+					return fmt.Errorf("fetching discounts: %w", err)
+				}
+				voided, err := repos.CreditNotes.VoidedQuantities(ctx, order.ID)
+				if err != nil {
+					return fmt.Errorf("fetching voided items: %w", err)
+				}
+				perItem := helpers.ComputeDiscounts(order.OrderItems, discounts).PerItem
+				if note, err = helpers.CreditItems(invoice, order.OrderItems, perItem, voided, request.Items, request.ReasonCode); err != nil {
+					return abortWith(http.StatusBadRequest, gin.H{"error": "Only items of the order that are left to void can be voided", "details": err.Error()})
+				}
+			} else {
+				amount := request.Amount
+				if amount == 0 {
+					amount = helpers.FromCents(left)
+				}
+				if helpers.ToCents(amount) > left {
+					return abortWith(http.StatusBadRequest, gin.H{"error": "The credit is more than is left of the invoice", "left": helpers.FromCents(left)})
+				}
+				note = helpers.CreditAmount(invoice, amount, request.ReasonCode)
+			}
+			if helpers.ToCents(note.Total) <= 0 {
+				return abortWith(http.StatusBadRequest, gin.H{"error": "There is nothing to credit for the voided items"})
+			}
+			note.Notes, note.CreatedBy = request.Notes, statusActor(c)
+
+			refunds, err := planRefunds(invoice.Payments, request.Refunds, note.Total)
+			if err != nil {
+				return err
+			}
+
+			if note, err = repos.CreditNotes.Create(ctx, note); err != nil {
+				return fmt.Errorf("storing the credit note: %w", err)
+			}
+			credited := helpers.FromCents(helpers.ToCents(invoice.Credited) + helpers.ToCents(note.Total))
+			if _, err := repos.Invoices.UpdateCredited(ctx, invoice.ID, credited); err != nil {
+				return fmt.Errorf("updating what has been credited: %w", err)
+			}
+			for _, refund := range refunds {
+				refund.CreditNoteID, refund.CreatedBy = note.ID, note.CreatedBy
+				if refund, err = repos.Refunds.Create(ctx, refund); err != nil {
+					return fmt.Errorf("recording the refund: %w", err)
+				}
+				note.Refunds = append(note.Refunds, refund)
+			}
+			for _, payment := range invoice.Payments {
+				transactions[payment.ID] = payment.TransactionID
+			}
+
+			invoice, err = repos.Invoices.Get(ctx, invoice.ID)
+			return err
+		})
+		if err != nil {
+			respondError(c, err, "Failed to issue the credit note")
+			return
+		}
+
+		// The money moves once the credit note is committed, a refund that fails can be retried
+		message := "Credit note issued successfully"
+		for i, refund := range note.Refunds {
+			if refund.Status != models.RefundStatusPending {
+				continue
+			}
+			if note.Refunds[i] = sendRefund(ctx, refund, transactions[refund.PaymentID]); note.Refunds[i].Status == models.RefundStatusFailed {
+				message = "Credit note issued, but refunds failed and can be retried"
+			}
+		}
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": message, "credit_note": note, "invoice": invoice})
+	}
+}
+
+// RetryRefund sends a refund the payment provider refused or couldn't be reached for again
+func RetryRefund() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("refund_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Refund ID is required"})
+			return
+		}
+
+		var refund models.Refund
+		var transactionID string
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			refund, err = repos.Refunds.Get(ctx, id)
+			if errors.Is(err, repository.ErrNotFound) {
+				return abortWith(http.StatusNotFound, gin.H{"error": "No refund found with given ID", "refund_id": id})
+			}
+			if err != nil {
+				return fmt.Errorf("fetching the refund: %w", err)
+			}
+			note, err := repos.CreditNotes.Get(ctx, refund.CreditNoteID)
+			if err != nil {
+				return fmt.Errorf("fetching the credit note: %w", err)
+			}
+			invoice, _, err := lockInvoiceOrder(ctx, repos, note.InvoiceID)
+			if err != nil {
+				return err
+			}
+
+			// Another retry of the same refund may have held the lock first
+			if refund, err = repos.Refunds.Get(ctx, id); err != nil {
+				return fmt.Errorf("fetching the refund: %w", err)
+			}
+			if refund.Status != models.RefundStatusFailed {
+				return abortWith(http.StatusConflict, gin.H{"error": "Only failed refunds can be retried", "status": refund.Status})
+			}
+			for _, payment := range invoice.Payments {
+				if payment.ID != refund.PaymentID {
+					continue
+				}
+				if left := helpers.ToCents(payment.Amount) - helpers.ToCents(payment.Refunded); helpers.ToCents(refund.Amount) > left {
+					return abortWith(http.StatusConflict, gin.H{"error": "The refund is more than is left of the payment", "left": helpers.FromCents(left)})
+				}
+				transactionID = payment.TransactionID
+			}
+
+			refund, err = repos.Refunds.UpdateStatus(ctx, id, models.RefundStatusPending, "", "")
+			return err
+		})
+		if err != nil {
+			respondError(c, err, "Failed to retry the refund")
+			return
+		}
+
+		refund = sendRefund(ctx, refund, transactionID)
+		if refund.Status == models.RefundStatusFailed {
+			c.IndentedJSON(http.StatusBadGateway, gin.H{"error": "The payment provider refused the refund", "details": refund.FailureReason, "refund": refund})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Refund sent successfully", "refund": refund})
+	}
+}
+
+func DownloadCreditNote() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		creditNoteID := c.Param("credit_note_id")
+		id, err := parseID(creditNoteID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Credit note ID is required"})
+			return
+		}
+
+		note, err := Repos.CreditNotes.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Credit note not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch credit note", "details": err.Error()})
+			return
+		}
+
+		invoice, err := Repos.Invoices.Get(ctx, note.InvoiceID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice", "details": err.Error()})
+			return
+		}
+
+		restaurant, err := Repos.Restaurants.Get(ctx, note.RestaurantID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant", "details": err.Error()})
+			return
+		}
+
+		layout, err := documentLayout(ctx, restaurant)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice template", "details": err.Error()})
+			return
+		}
+
+		document, err := helpers.GenerateCreditNotePdf(note, invoice, restaurant, layout)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate PDF", "details": err.Error()})
+			return
+		}
+
+		c.Header("Content-Disposition", "attachment; filename=credit-note-"+note.Number+".pdf")
+		c.Data(http.StatusOK, "application/pdf", document.GetBytes())
+	}
+}
+
+// checkCreditable refuses credit notes on anything but an order's paid invoice with something left to credit
+func checkCreditable(invoice models.Invoice) error {
+	switch {
+	case invoice.ParentID != 0:
+		return abortWith(http.StatusConflict, gin.H{"error": "Credit notes are issued against the order's invoice, not one of its parts", "parent_id": invoice.ParentID})
+	case invoice.Status != models.InvoiceStatusPaid:
+		return abortWith(http.StatusConflict, gin.H{"error": "Only paid invoices can be credited, change the order while it is open", "status": invoice.Status})
+	case helpers.ToCents(invoice.Credited) >= helpers.ToCents(invoice.Total):
+		return abortWith(http.StatusConflict, gin.H{"error": "The invoice has been credited in full", "credited": invoice.Credited})
+	}
+	return nil
+}
+
+// planRefunds shares what a credit note gives back out over the payments of its invoice,
+// as requested or else from the latest payment back. No payment gives back more than is
+// left of its amount after earlier refunds, tips are kept. Refunds of payments taken
+// through a payment gateway start out pending, the others are done at the till.
+func planRefunds(payments []models.Payment, requested []models.RefundPayment, total float64) ([]models.Refund, error) {
+	byID := map[uint]models.Payment{}
+	left := map[uint]int64{}
+	for _, payment := range payments {
+		byID[payment.ID] = payment
+		left[payment.ID] = helpers.ToCents(payment.Amount) - helpers.ToCents(payment.Refunded)
+	}
+
+	if len(requested) == 0 {
+		remaining := helpers.ToCents(total)
+		for i := len(payments) - 1; i >= 0 && remaining > 0; i-- {
+			amount := min(left[payments[i].ID], remaining)
+			if amount <= 0 {
+				continue
+			}
+			requested = append(requested, models.RefundPayment{PaymentID: payments[i].ID, Amount: helpers.FromCents(amount)})
+			remaining -= amount
+		}
+	}
+
+	var refunds []models.Refund
+	var sum int64
+	for _, request := range requested {
+		payment, ok := byID[request.PaymentID]
+		if !ok {
+			return nil, abortWith(http.StatusBadRequest, gin.H{"error": "Refunds can only go against payments of the invoice", "payment_id": request.PaymentID})
+		}
+		amount := helpers.ToCents(request.Amount)
+		if amount > left[payment.ID] {
+			return nil, abortWith(http.StatusBadRequest, gin.H{"error": "The refund is more than is left of the payment", "payment_id": payment.ID, "left": helpers.FromCents(left[payment.ID])})
+		}
+		left[payment.ID] -= amount
+		sum += amount
+
+		status := models.RefundStatusSucceeded
+		if payment.TransactionID != "" {
+			status = models.RefundStatusPending
+		}
+		refunds = append(refunds, models.Refund{PaymentID: payment.ID, Amount: helpers.FromCents(amount), Method: payment.Method, Provider: payment.Provider, Status: status})
+	}
+	if sum != helpers.ToCents(total) {
+		return nil, abortWith(http.StatusBadRequest, gin.H{"error": "The refunds must add up to the credit note's total", "total": total, "refunds": helpers.FromCents(sum)})
+	}
+	return refunds, nil
+}
+
+// sendRefund asks the payment provider to give back a pending refund and records how it
+// went. Refunds the provider declines or can't be reached for are marked failed.
+func sendRefund(ctx context.Context, refund models.Refund, transactionID string) models.Refund {
+	status, providerRefundID, reason := models.RefundStatusSucceeded, "", ""
+	if refund.Provider != Gateway.Name() {
+		status, reason = models.RefundStatusFailed, "the payment was taken through another payment provider"
+	} else if result, err := Gateway.Refund(ctx, transactionID, refund.Amount); err != nil {
+		status, reason = models.RefundStatusFailed, err.Error()
+	} else {
+		providerRefundID = result.ID
+		// Providers that refund asynchronously confirm it with a webhook
+		if result.Status != gateway.IntentSucceeded {
+			status = models.RefundStatusPending
+		}
+	}
+
+	updated, err := Repos.Refunds.UpdateStatus(ctx, refund.ID, status, providerRefundID, reason)
+	if err != nil {
+		log.Printf("Failed to record refund %d as %s: %v", refund.ID, status, err)
+		refund.Status, refund.ProviderRefundID, refund.FailureReason = status, providerRefundID, reason
+		return refund
+	}
+	return updated
+}
+
+// confirmRefund marks a refund the payment provider reports as done succeeded
+func confirmRefund(ctx context.Context, repos repository.Repositories, event gateway.Event) error {
+	refund, err := repos.Refunds.GetByProviderID(ctx, Gateway.Name(), event.RefundID)
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Printf("Ignoring %s webhook %s for unknown refund %s", event.Type, event.ID, event.RefundID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fetching the refund: %w", err)
+	}
+	if refund.Status == models.RefundStatusSucceeded {
+		return nil
+	}
+	_, err = repos.Refunds.UpdateStatus(ctx, refund.ID, models.RefundStatusSucceeded, "", "")
+	return err
+}
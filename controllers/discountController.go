@@ -0,0 +1,357 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func GetOrderDiscounts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("order_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
+			return
+		}
+
+		discounts, err := Repos.Discounts.ListByOrder(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order discounts from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Order discounts fetched successfully", "discounts": discounts})
+	}
+}
+
+// ApplyOrderDiscount applies a coupon, a promotion or a manual discount to an open order.
+// Coupons and promotions count towards their usage limit. Manual discounts wait for a
+// manager's approval unless a manager applies them.
+func ApplyOrderDiscount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("order_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
+			return
+		}
+
+		var request models.ApplyDiscount
+		if err := c.BindJSON(&request); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for applying a discount", "details": err.Error()})
+			return
+		}
+
+		if err := validate.Struct(request); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+
+		request.Code = strings.ToUpper(strings.TrimSpace(request.Code))
+		given := 0
+		for _, set := range []bool{request.Code != "", request.PromotionID != 0, request.Kind != ""} {
+			if set {
+				given++
+			}
+		}
+		if given != 1 {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide exactly one of code, promotion_id or a manual kind"})
+			return
+		}
+		if request.Kind != "" {
+			switch {
+			case request.Value <= 0 || (request.Kind == models.DiscountPercentage && request.Value > 100):
+				c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Manual discounts need a value above 0, percentages up to 100"})
+				return
+			case strings.TrimSpace(request.Reason) == "":
+				c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Manual discounts need a reason"})
+				return
+			}
+		}
+
+		var discount models.OrderDiscount
+		var order models.Order
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			current, err := lockOpenOrder(ctx, repos, id)
+			if err != nil {
+				return err
+			}
+
+			actor := statusActor(c)
+			if request.Kind != "" {
+				discount = models.OrderDiscount{
+					Description: request.Reason,
+					Kind:        request.Kind,
+					Value:       request.Value,
+					Scope:       models.DiscountScopeOrder,
+					Status:      models.DiscountStatusPending,
+					RequestedBy: actor,
+				}
+				if managesRestaurant(c, current.RestaurantID) {
+					discount.Status, discount.ApprovedBy = models.DiscountStatusApproved, actor
+				}
+			} else {
+				promotion, err := claimPromotion(ctx, repos, current.RestaurantID, request)
+				if err != nil {
+					return err
+				}
+				discount = models.OrderDiscount{
+					PromotionID: promotion.ID,
+					Description: promotion.Name,
+					Kind:        promotion.Kind,
+					Value:       promotion.Value,
+					BuyQuantity: promotion.BuyQuantity,
+					GetQuantity: promotion.GetQuantity,
+					Scope:       promotion.Scope,
+					MenuID:      promotion.MenuID,
+					FoodID:      promotion.FoodID,
+					Status:      models.DiscountStatusApproved,
+					RequestedBy: actor,
+				}
+			}
+
+			discount.OrderID = id
+			if discount, err = repos.Discounts.Create(ctx, discount); err != nil {
+				if errors.Is(err, repository.ErrDuplicate) {
+					return abortWith(http.StatusConflict, gin.H{"error": "This promotion is already applied to the order"})
+				}
+				return fmt.Errorf("applying the discount: %w", err)
+			}
+
+			if order, err = applyDiscounts(ctx, repos, id); err != nil {
+				return err
+			}
+			discount = findDiscount(order, discount)
+			return nil
+		})
+		if err != nil {
+			respondError(c, err, "Failed to apply the discount")
+			return
+		}
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Discount applied successfully", "discount": discount, "discount_total": order.DiscountTotal})
+	}
+}
+
+// ApproveOrderDiscount lets a manager approve a pending manual discount
+func ApproveOrderDiscount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("discount_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Discount ID is required"})
+			return
+		}
+
+		var discount models.OrderDiscount
+		var order models.Order
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			if discount, err = lockDiscountOrder(ctx, repos, id); err != nil {
+				return err
+			}
+			if discount.Status == models.DiscountStatusApproved {
+				return abortWith(http.StatusConflict, gin.H{"error": "Discount is already approved", "discount_id": id})
+			}
+
+			if discount, err = repos.Discounts.Approve(ctx, id, statusActor(c)); err != nil {
+				return fmt.Errorf("approving the discount: %w", err)
+			}
+			if order, err = applyDiscounts(ctx, repos, discount.OrderID); err != nil {
+				return err
+			}
+			discount = findDiscount(order, discount)
+			return nil
+		})
+		if err != nil {
+			respondError(c, err, "Failed to approve the discount")
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Discount approved successfully", "discount": discount, "discount_total": order.DiscountTotal})
+	}
+}
+
+// RemoveOrderDiscount takes a discount off an open order, giving a coupon's use back
+func RemoveOrderDiscount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("discount_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Discount ID is required"})
+			return
+		}
+
+		var order models.Order
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			discount, err := lockDiscountOrder(ctx, repos, id)
+			if err != nil {
+				return err
+			}
+
+			if err := repos.Discounts.Delete(ctx, id); err != nil {
+				return fmt.Errorf("removing the discount: %w", err)
+			}
+			if err := releasePromotion(ctx, repos, discount.PromotionID); err != nil {
+				return err
+			}
+			order, err = applyDiscounts(ctx, repos, discount.OrderID)
+			return err
+		})
+		if err != nil {
+			respondError(c, err, "Failed to remove the discount")
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Discount removed successfully", "discount_id": id, "discount_total": order.DiscountTotal})
+	}
+}
+
+// claimPromotion locks the requested coupon or promotion, checks it can be used now and
+// counts the use
+func claimPromotion(ctx context.Context, repos repository.Repositories, restaurantID uint, request models.ApplyDiscount) (models.Promotion, error) {
+	var promotion models.Promotion
+	var err error
+	if request.Code != "" {
+		promotion, err = repos.Promotions.GetByCodeForUpdate(ctx, restaurantID, request.Code)
+	} else {
+		promotion, err = repos.Promotions.GetForUpdate(ctx, request.PromotionID)
+	}
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && promotion.RestaurantID != restaurantID) {
+		return promotion, abortWith(http.StatusNotFound, gin.H{"error": "No promotion found for this restaurant"})
+	}
+	if err != nil {
+		return promotion, fmt.Errorf("fetching the promotion: %w", err)
+	}
+
+	now := time.Now().UTC()
+	var problem string
+	switch {
+	case promotion.Disabled:
+		problem = "Promotion is disabled"
+	case promotion.StartsAt != nil && now.Before(*promotion.StartsAt):
+		problem = "Promotion has not started yet"
+	case promotion.EndsAt != nil && !now.Before(*promotion.EndsAt):
+		problem = "Promotion has ended"
+	case promotion.UsageLimit > 0 && promotion.TimesUsed >= promotion.UsageLimit:
+		problem = "Promotion has reached its usage limit of " + strconv.Itoa(promotion.UsageLimit)
+	}
+	if problem != "" {
+		return promotion, abortWith(http.StatusConflict, gin.H{"error": problem, "promotion_id": promotion.ID})
+	}
+
+	if err := repos.Promotions.AddUsage(ctx, promotion.ID, 1); err != nil {
+		return promotion, fmt.Errorf("counting the promotion use: %w", err)
+	}
+	return promotion, nil
+}
+
+// releasePromotion gives a use back to a promotion that is no longer applied to an order
+func releasePromotion(ctx context.Context, repos repository.Repositories, promotionID uint) error {
+	if promotionID == 0 {
+		return nil
+	}
+	if err := repos.Promotions.AddUsage(ctx, promotionID, -1); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("releasing the promotion use: %w", err)
+	}
+	return nil
+}
+
+// releaseOrderPromotions gives back the uses of every promotion applied to a cancelled order
+func releaseOrderPromotions(ctx context.Context, repos repository.Repositories, orderID uint) error {
+	discounts, err := repos.Discounts.ListByOrder(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("fetching the order discounts: %w", err)
+	}
+	for _, discount := range discounts {
+		if err := releasePromotion(ctx, repos, discount.PromotionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockDiscountOrder loads a discount and locks its order, which must still be open
+func lockDiscountOrder(ctx context.Context, repos repository.Repositories, id uint) (models.OrderDiscount, error) {
+	discount, err := repos.Discounts.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return discount, abortWith(http.StatusNotFound, gin.H{"error": "No discount found with given ID", "discount_id": id})
+		}
+		return discount, fmt.Errorf("fetching the discount: %w", err)
+	}
+	_, err = lockOpenOrder(ctx, repos, discount.OrderID)
+	return discount, err
+}
+
+// applyDiscounts recalculates what each discount of the order takes off its current items,
+// stores the amounts and the order's discount total, and brings the invoice in line
+func applyDiscounts(ctx context.Context, repos repository.Repositories, orderID uint) (models.Order, error) {
+	order, err := repos.Orders.Get(ctx, orderID)
+	if err != nil {
+		return order, fmt.Errorf("fetching the order: %w", err)
+	}
+
+	result := helpers.ComputeDiscounts(order.OrderItems, order.Discounts)
+	for i, discount := range order.Discounts {
+		if amount := result.Amounts[discount.ID]; amount != discount.Amount {
+			if err := repos.Discounts.UpdateAmount(ctx, discount.ID, amount); err != nil {
+				return order, fmt.Errorf("updating the discount amount: %w", err)
+			}
+			order.Discounts[i].Amount = amount
+		}
+	}
+	if result.Total != order.DiscountTotal {
+		if err := repos.Orders.UpdateDiscountTotal(ctx, orderID, result.Total); err != nil {
+			return order, fmt.Errorf("updating the order discount total: %w", err)
+		}
+		order.DiscountTotal = result.Total
+	}
+
+	if err := CreateInvoiceFromOrder(ctx, repos, order); err != nil {
+		return order, fmt.Errorf("updating the invoice for the order: %w", err)
+	}
+	return order, nil
+}
+
+// findDiscount returns the discount as it stands on the repriced order
+func findDiscount(order models.Order, discount models.OrderDiscount) models.OrderDiscount {
+	for _, applied := range order.Discounts {
+		if applied.ID == discount.ID {
+			return applied
+		}
+	}
+	return discount
+}
+
+// managesRestaurant reports whether the caller is a manager or owner of the restaurant
+func managesRestaurant(c *gin.Context, restaurantID uint) bool {
+	claims, ok := helpers.GetClaims(c)
+	if !ok {
+		return false
+	}
+	role := claims.MembershipRole(restaurantID)
+	return role == models.MembershipManager || role == models.MembershipOwner
+}
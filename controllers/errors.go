@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestError aborts a unit of work with a specific response instead of a generic 500
+type requestError struct {
+	status int
+	body   gin.H
+}
+
+func (e *requestError) Error() string {
+	return fmt.Sprint(e.body["error"])
+}
+
+func abortWith(status int, body gin.H) error {
+	return &requestError{status: status, body: body}
+}
+
+// respondError writes the response for an error returned by a unit of work,
+// falling back to a 500 with message for anything that is not a requestError
+func respondError(c *gin.Context, err error, message string) {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		c.IndentedJSON(reqErr.status, reqErr.body)
+		return
+	}
+	c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": message, "details": err.Error()})
+}
@@ -2,9 +2,11 @@ package controllers
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"net/http"
+	"restaurant-management/helpers"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,45 +20,39 @@ func GetFoods() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id, ok := c.GetQuery("restaurant_id")
-		var rows *sql.Rows
-		var err error
-
-		if ok && id != "" {
-			rows, err = Db.QueryContext(ctx, "SELECT * FROM foods WHERE restaurant_id = $1", id)
-		} else {
-			rows, err = Db.QueryContext(ctx, "SELECT * FROM foods")
+		// Without a restaurant_id every food is listed
+		var restaurantID uint
+		if id, ok := c.GetQuery("restaurant_id"); ok && id != "" {
+			var err error
+			if restaurantID, err = parseID(id); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is invalid", "details": err.Error()})
+				return
+			}
 		}
 
+		foods, err := Repos.Foods.List(ctx, restaurantID)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch foods from database", "details": err.Error()})
 			return
 		}
 
-		defer rows.Close()
-		var foods []models.Food
-
-		for rows.Next() {
-			var food models.Food
-			if err := rows.Scan(&food.ID, &food.Name, &food.Price, &food.Description, &food.ImageURL, &food.MenuID, &food.RestaurantID, &food.Ingredients, &food.PrepTime, &food.Calories, &food.SpicyLevel, &food.Vegetarian, &food.Available, &food.CreatedAt, &food.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan food data", "details": err.Error()})
-				return
-			}
-			foods = append(foods, food)
-		}
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Foods fetched successfully", "foods": foods})
 	}
 }
 
 func GetFood() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.Param("food_id")
-		if id == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("food_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Food ID is required"})
 			return
 		}
-		var food models.Food
-		if err := Db.QueryRow("SELECT * FROM foods WHERE id = $1", id).Scan(&food.ID, &food.Name, &food.Price, &food.Description, &food.ImageURL, &food.MenuID, &food.RestaurantID, &food.Ingredients, &food.PrepTime, &food.Calories, &food.SpicyLevel, &food.Vegetarian, &food.Available, &food.CreatedAt, &food.UpdatedAt); err != nil {
+
+		food, err := Repos.Foods.Get(ctx, id)
+		if err != nil {
 			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Food not found", "details": err.Error()})
 			return
 		}
@@ -87,19 +83,21 @@ func CreateFood() gin.HandlerFunc {
 			return
 		}
 
-		err := Db.QueryRowContext(ctx, `
-			INSERT INTO foods 
-			(name, price, description, image_url, menu_id, restaurant_id, ingredients, prep_time, calories, spicy_level, vegetarian, available)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-			RETURNING id, name, price, description, image_url, menu_id, restaurant_id, ingredients, prep_time, calories, spicy_level, vegetarian, available, created_at, updated_at`,
-			food.Name, toFixed(food.Price, 2), food.Description, food.ImageURL, food.MenuID,
-			food.RestaurantID, food.Ingredients, food.PrepTime, food.Calories, food.SpicyLevel,
-			food.Vegetarian, food.Available,
-		).Scan(
-			&food.ID, &food.Name, &food.Price, &food.Description, &food.ImageURL, &food.MenuID,
-			&food.RestaurantID, &food.Ingredients, &food.PrepTime, &food.Calories, &food.SpicyLevel,
-			&food.Vegetarian, &food.Available, &food.CreatedAt, &food.UpdatedAt,
-		)
+		if !checkStation(ctx, c, food.StationID, food.RestaurantID) || !checkSchedule(c, food.Availability) || !checkModifierGroups(c, food) ||
+			!checkDiet(c, &food) {
+			return
+		}
+
+		food.Price = toFixed(food.Price, 2)
+		if food.TaxCategory == "" {
+			food.TaxCategory = models.TaxCategoryFood
+		}
+		// The food and its schedule are saved together
+		err := UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			var err error
+			food, err = repos.Foods.Create(ctx, food)
+			return err
+		})
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create food item in database", "details": err.Error()})
 			return
@@ -114,8 +112,8 @@ func UpdateFood() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("food_id")
-		if id == "" {
+		id, err := parseID(c.Param("food_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Food ID is required"})
 			return
 		}
@@ -136,23 +134,34 @@ func UpdateFood() gin.HandlerFunc {
 			return
 		}
 
+		if !checkStation(ctx, c, food.StationID, food.RestaurantID) || !checkSchedule(c, food.Availability) || !checkModifierGroups(c, food) ||
+			!checkDiet(c, &food) {
+			return
+		}
+
 		// Update the food item in the database
-		if err := Db.QueryRowContext(ctx, `
-			UPDATE foods SET 
-			name = $1, price = $2, description = $3, image_url = $4,
-			menu_id = $5, restaurant_id = $6, ingredients = $7, prep_time = $8,
-			calories = $9, spicy_level = $10, vegetarian = $11, available = $12,
-			updated_at = CURRENT_TIMESTAMP 
-			WHERE id = $13 RETURNING id, name, price, description, image_url, menu_id, restaurant_id, ingredients, prep_time, calories, spicy_level, vegetarian, available, created_at, updated_at`,
-			food.Name, toFixed(food.Price, 2), food.Description, food.ImageURL,
-			food.MenuID, food.RestaurantID, food.Ingredients, food.PrepTime,
-			food.Calories, food.SpicyLevel, food.Vegetarian, food.Available, id,
-		).Scan(
-			&food.ID, &food.Name, &food.Price, &food.Description, &food.ImageURL, &food.MenuID,
-			&food.RestaurantID, &food.Ingredients, &food.PrepTime, &food.Calories, &food.SpicyLevel,
-			&food.Vegetarian, &food.Available, &food.CreatedAt, &food.UpdatedAt,
-		); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update food item in database", "details": err.Error()})
+		food.ID = id
+		food.Price = toFixed(food.Price, 2)
+		if food.TaxCategory == "" {
+			food.TaxCategory = models.TaxCategoryFood
+		}
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			existing, err := repos.Foods.Get(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err := checkOptionIDs(existing, food); err != nil {
+				return err
+			}
+			food, err = repos.Foods.Update(ctx, food)
+			return err
+		})
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Food not found in database"})
+				return
+			}
+			respondError(c, err, "Failed to update food item in database")
 			return
 		}
 
@@ -165,23 +174,136 @@ func DeleteFood() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("food_id")
-		if id == "" {
+		id, err := parseID(c.Param("food_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Food ID is required"})
 			return
 		}
-		result, err := Db.ExecContext(ctx, "DELETE FROM foods WHERE id = $1", id)
-		if err != nil {
+		if err := Repos.Foods.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Food not found in database"})
+				return
+			}
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete food item from database", "details": err.Error()})
 			return
 		}
-		rowsAffected, err := result.RowsAffected()
-		if err != nil || rowsAffected == 0 {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Food not found in database", "details": err.Error()})
+		c.IndentedJSON(http.StatusNoContent, gin.H{"message": "Food item deleted successfully"})
+	}
+}
+
+// GetFoodSales reports what each food of a restaurant sold in the paid orders placed between
+// from and to. A combo's revenue is attributed to the foods it was made of, by the share of
+// its price each component carries, so foods sold in combos are counted with the rest.
+func GetFoodSales() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		from, to, ok := reportPeriod(c)
+		if !ok {
+			return
+		}
+
+		sales, err := Repos.OrderItems.SalesByFood(ctx, id, from, to)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch food sales from database", "details": err.Error()})
 			return
 		}
-		c.IndentedJSON(http.StatusNoContent, gin.H{"message": "Food item deleted successfully"})
+
+		var total int64
+		for _, food := range sales {
+			total += helpers.ToCents(food.Revenue)
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Food sales fetched successfully", "restaurant_id": id, "revenue": helpers.FromCents(total), "foods": sales})
+	}
+}
+
+// checkSchedule makes sure the seasons of a menu or food's schedule don't end before they start
+// and its hours are HH:MM and don't end when they start, answering the request when they do
+func checkSchedule(c *gin.Context, schedule models.Schedule) bool {
+	for i, window := range schedule {
+		if window.StartDate != "" && window.EndDate != "" && window.EndDate < window.StartDate {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "An availability window can't end before it starts", "window": i})
+			return false
+		}
+		if window.StartTime == "" && window.EndTime == "" {
+			continue
+		}
+		start, startOK := models.ClockMinutes(window.StartTime)
+		end, endOK := models.ClockMinutes(window.EndTime)
+		if !startOK || !endOK {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "An availability window's hours must be given as HH:MM", "window": i})
+			return false
+		}
+		if start == end {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "An availability window's hours can't end when they start", "window": i})
+			return false
+		}
+	}
+	return true
+}
+
+// checkModifierGroups makes sure each modifier group of a food can be picked from as its
+// selection limits say, answering the request when one can't
+func checkModifierGroups(c *gin.Context, food models.Food) bool {
+	for _, group := range food.ModifierGroups {
+		if group.Minimum() > len(group.Options) || group.MaxSelections > 0 && group.Minimum() > group.MaxSelections {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "The modifier group " + group.Name + " asks for more options than can be picked"})
+			return false
+		}
+	}
+	return true
+}
+
+// checkDiet tidies up the allergens and dietary tags of a food and makes sure no tag
+// contradicts an allergen, answering the request when one does
+func checkDiet(c *gin.Context, food *models.Food) bool {
+	food.NormalizeDiet()
+	if conflict := food.DietConflict(); conflict != "" {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "The dietary tags don't match the allergens", "details": conflict})
+		return false
+	}
+	return true
+}
+
+// checkOptionIDs makes sure the variants, modifier groups and options a food is updated with
+// that have an ID are ones it already had, options staying in their group. Those without an
+// ID are added.
+func checkOptionIDs(existing, food models.Food) error {
+	variants, groups := map[uint]bool{}, map[uint]map[uint]bool{}
+	for _, variant := range existing.Variants {
+		variants[variant.ID] = true
+	}
+	for _, group := range existing.ModifierGroups {
+		groups[group.ID] = map[uint]bool{}
+		for _, option := range group.Options {
+			groups[group.ID][option.ID] = true
+		}
+	}
+
+	for _, variant := range food.Variants {
+		if variant.ID != 0 && !variants[variant.ID] {
+			return abortWith(http.StatusBadRequest, gin.H{"error": "Variant is not one of the food's", "variant_id": variant.ID})
+		}
+	}
+	for _, group := range food.ModifierGroups {
+		if group.ID != 0 && groups[group.ID] == nil {
+			return abortWith(http.StatusBadRequest, gin.H{"error": "Modifier group is not one of the food's", "modifier_group_id": group.ID})
+		}
+		for _, option := range group.Options {
+			if option.ID != 0 && !groups[group.ID][option.ID] {
+				return abortWith(http.StatusBadRequest, gin.H{"error": "Modifier is not an option of its group", "modifier_id": option.ID})
+			}
+		}
 	}
+	return nil
 }
 
 func round(num float64) int {
@@ -0,0 +1,345 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"restaurant-management/gateway"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxWebhookBytes caps the size of a webhook body read before its signature is checked
+const maxWebhookBytes = 64 << 10
+
+func GetPaymentIntents() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("invoice_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+			return
+		}
+
+		intents, err := Repos.PaymentIntents.ListByInvoice(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payment intents from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Payment intents fetched successfully", "invoice_id": id, "payment_intents": intents})
+	}
+}
+
+// CreatePaymentIntent starts an online payment of an invoice or one part of a split check,
+// for its balance unless an amount is given, plus an optional tip. The client secret lets
+// the guest's device confirm the payment with the provider.
+func CreatePaymentIntent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("invoice_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+			return
+		}
+
+		var intent models.PaymentIntent
+		if err := c.BindJSON(&intent); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for starting an online payment", "details": err.Error()})
+			return
+		}
+
+		if err := validate.Struct(intent); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+
+		invoice, err := Repos.Invoices.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No invoice found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice from database", "details": err.Error()})
+			return
+		}
+		if intent.Amount == 0 {
+			intent.Amount = invoice.Balance
+		}
+		if err := checkPayable(invoice, intent.Amount); err != nil {
+			respondError(c, err, "Failed to start the online payment")
+			return
+		}
+		if intent.TipStaffID, err = tipStaff(ctx, Repos, invoice.RestaurantID, intent.TipStaffID, statusActor(c)); err != nil {
+			respondError(c, err, "Failed to start the online payment")
+			return
+		}
+
+		// Nothing moves until the intent is captured, so the provider is called outside of a transaction
+		providerIntent, err := Gateway.CreateIntent(ctx, gateway.IntentRequest{
+			Amount:    helpers.FromCents(helpers.ToCents(intent.Amount) + helpers.ToCents(intent.Tip)),
+			Currency:  gateway.Currency(),
+			Reference: fmt.Sprintf("Invoice #%d, order #%d", invoice.ID, invoice.OrderID),
+		})
+		if err != nil {
+			c.IndentedJSON(http.StatusBadGateway, gin.H{"error": "The payment provider refused the payment", "details": err.Error()})
+			return
+		}
+
+		intent, err = Repos.PaymentIntents.Create(ctx, models.PaymentIntent{
+			InvoiceID:        invoice.ID,
+			Provider:         Gateway.Name(),
+			ProviderIntentID: providerIntent.ID,
+			Amount:           intent.Amount,
+			Tip:              intent.Tip,
+			TipStaffID:       intent.TipStaffID,
+			Currency:         providerIntent.Currency,
+			Status:           providerIntent.Status,
+			CreatedBy:        statusActor(c),
+		})
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to store payment intent in database", "details": err.Error()})
+			return
+		}
+		intent.ClientSecret = providerIntent.ClientSecret
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Payment intent created successfully", "payment_intent": intent})
+	}
+}
+
+// CapturePaymentIntent takes the money an intent holds and records it as a payment.
+// Capturing an intent that already succeeded returns it unchanged.
+func CapturePaymentIntent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("intent_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Payment intent ID is required"})
+			return
+		}
+
+		intent, err := Repos.PaymentIntents.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No payment intent found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payment intent from database", "details": err.Error()})
+			return
+		}
+		switch {
+		case intent.Status == gateway.IntentSucceeded:
+			c.IndentedJSON(http.StatusOK, gin.H{"message": "Payment intent already captured", "payment_intent": intent})
+			return
+		case intent.Status != gateway.IntentRequiresCapture:
+			c.IndentedJSON(http.StatusConflict, gin.H{"error": "Only intents waiting for capture can be captured", "status": intent.Status})
+			return
+		case intent.Provider != Gateway.Name():
+			c.IndentedJSON(http.StatusConflict, gin.H{"error": "The intent belongs to another payment provider", "provider": intent.Provider})
+			return
+		}
+
+		// The check may have been settled some other way since the intent was created
+		invoice, err := Repos.Invoices.Get(ctx, intent.InvoiceID)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice from database", "details": err.Error()})
+			return
+		}
+		if err := checkPayable(invoice, intent.Amount); err != nil {
+			respondError(c, err, "Failed to capture the payment")
+			return
+		}
+
+		providerIntent, err := Gateway.Capture(ctx, intent.ProviderIntentID)
+		if errors.Is(err, gateway.ErrDeclined) {
+			if _, err := Repos.PaymentIntents.UpdateStatus(ctx, intent.ID, gateway.IntentFailed, err.Error(), 0); err != nil {
+				log.Printf("Failed to mark payment intent %d as failed: %v", intent.ID, err)
+			}
+			c.IndentedJSON(http.StatusPaymentRequired, gin.H{"error": "The payment was declined", "details": err.Error()})
+			return
+		}
+		if err != nil {
+			c.IndentedJSON(http.StatusBadGateway, gin.H{"error": "Failed to capture the payment with the provider", "details": err.Error()})
+			return
+		}
+
+		var current, order models.Order
+		var payment models.Payment
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			intent, payment, invoice, current, order, err = settleIntent(ctx, c, repos, intent, providerIntent.TransactionID)
+			return err
+		})
+		if err != nil {
+			respondError(c, err, "Failed to record the captured payment")
+			return
+		}
+
+		publishOrderPaid(current, order)
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Payment captured successfully", "payment_intent": intent, "payment": payment, "invoice": invoice})
+	}
+}
+
+// PaymentWebhook handles the payment provider's notifications. Every event is handled
+// once, redeliveries are acknowledged without doing anything. Errors make the provider
+// deliver the event again later.
+func PaymentWebhook() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBytes))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Failed to read the webhook", "details": err.Error()})
+			return
+		}
+
+		event, err := Gateway.VerifyWebhook(payload, c.GetHeader(gateway.SignatureHeader))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook", "details": err.Error()})
+			return
+		}
+
+		// A succeeded intent is only settled once the provider confirms it, outside of a transaction
+		var providerIntent gateway.Intent
+		if event.Type == gateway.EventIntentSucceeded {
+			if providerIntent, err = Gateway.GetIntent(ctx, event.IntentID); err != nil {
+				c.IndentedJSON(http.StatusBadGateway, gin.H{"error": "Failed to confirm the payment with the provider", "details": err.Error()})
+				return
+			}
+		}
+
+		var current, order models.Order
+		var handled bool
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			handled, err = repos.WebhookEvents.Record(ctx, models.WebhookEvent{Provider: Gateway.Name(), EventID: event.ID, EventType: event.Type})
+			if err != nil || !handled {
+				return err
+			}
+
+			switch event.Type {
+			case gateway.EventIntentSucceeded, gateway.EventIntentFailed:
+			case gateway.EventRefundSucceeded:
+				return confirmRefund(ctx, repos, event)
+			default:
+				return nil
+			}
+
+			intent, err := repos.PaymentIntents.GetByProviderID(ctx, Gateway.Name(), event.IntentID)
+			if errors.Is(err, repository.ErrNotFound) {
+				log.Printf("Ignoring %s webhook %s for unknown payment intent %s", event.Type, event.ID, event.IntentID)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("fetching the payment intent: %w", err)
+			}
+
+			if event.Type == gateway.EventIntentFailed {
+				if intent.Status != gateway.IntentRequiresCapture {
+					return nil
+				}
+				_, err := repos.PaymentIntents.UpdateStatus(ctx, intent.ID, gateway.IntentFailed, event.Reason, 0)
+				return err
+			}
+			if reason := unconfirmedReason(intent, event, providerIntent); reason != "" {
+				log.Printf("Ignoring %s webhook %s for payment intent %s: %s", event.Type, event.ID, event.IntentID, reason)
+				return nil
+			}
+			_, _, _, current, order, err = settleIntent(ctx, c, repos, intent, providerIntent.TransactionID)
+			// The invoice may have gone with its order, the event is still handled so it isn't sent again
+			var reqErr *requestError
+			if errors.As(err, &reqErr) && reqErr.status == http.StatusNotFound {
+				log.Printf("Ignoring %s webhook %s for payment intent %s, its invoice no longer exists", event.Type, event.ID, event.IntentID)
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			respondError(c, err, "Failed to handle the webhook")
+			return
+		}
+
+		publishOrderPaid(current, order)
+
+		if !handled {
+			c.IndentedJSON(http.StatusOK, gin.H{"message": "Webhook already handled", "event_id": event.ID})
+			return
+		}
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Webhook handled successfully", "event_id": event.ID})
+	}
+}
+
+// unconfirmedReason says why a payment_intent.succeeded event doesn't match the intent it is for
+// or what the provider says of it, "" when it does
+func unconfirmedReason(intent models.PaymentIntent, event gateway.Event, providerIntent gateway.Intent) string {
+	charged := helpers.ToCents(intent.Amount) + helpers.ToCents(intent.Tip)
+	switch {
+	case intent.Status != gateway.IntentRequiresCapture && intent.Status != gateway.IntentSucceeded:
+		return "the intent is " + intent.Status
+	case providerIntent.Status != gateway.IntentSucceeded:
+		return "the provider says it is " + providerIntent.Status
+	case helpers.ToCents(event.Amount) != charged || helpers.ToCents(providerIntent.Amount) != charged:
+		return fmt.Sprintf("%.2f was paid but the intent is for %.2f", event.Amount, helpers.FromCents(charged))
+	case event.TransactionID != providerIntent.TransactionID:
+		return "the transaction is not the provider's"
+	}
+	return ""
+}
+
+// settleIntent records the money of a succeeded intent as an online payment, once. The
+// money has already moved, so unlike a payment at the till it is recorded even if the
+// check was settled some other way in the meantime.
+func settleIntent(ctx context.Context, c *gin.Context, repos repository.Repositories, intent models.PaymentIntent, transactionID string) (models.PaymentIntent, models.Payment, models.Invoice, models.Order, models.Order, error) {
+	var payment models.Payment
+	invoice, current, err := lockInvoiceOrder(ctx, repos, intent.InvoiceID)
+	if err != nil {
+		return intent, payment, invoice, current, current, err
+	}
+
+	// Capture and webhook race to record the same intent, whoever comes second finds it done
+	if intent, err = repos.PaymentIntents.Get(ctx, intent.ID); err != nil {
+		return intent, payment, invoice, current, current, fmt.Errorf("fetching the payment intent: %w", err)
+	}
+	if intent.PaymentID != 0 {
+		return intent, payment, invoice, current, current, nil
+	}
+
+	payment, invoice, order, err := takePayment(ctx, c, repos, invoice, current, models.Payment{
+		InvoiceID:     intent.InvoiceID,
+		Amount:        intent.Amount,
+		Tip:           intent.Tip,
+		TipStaffID:    intent.TipStaffID,
+		Method:        "online",
+		Reference:     intent.ProviderIntentID,
+		Provider:      intent.Provider,
+		TransactionID: transactionID,
+		RecordedBy:    statusActor(c),
+	})
+	if errors.Is(err, repository.ErrDuplicate) {
+		return intent, payment, invoice, current, current, abortWith(http.StatusConflict, gin.H{"error": "The transaction is already recorded", "transaction_id": transactionID})
+	}
+	if err != nil {
+		return intent, payment, invoice, current, order, err
+	}
+
+	intent, err = repos.PaymentIntents.UpdateStatus(ctx, intent.ID, gateway.IntentSucceeded, "", payment.ID)
+	return intent, payment, invoice, current, order, err
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"restaurant-management/gateway"
+	"restaurant-management/models"
+)
+
+// confirmedIntent starts an online payment of an order's invoice and has the guest confirm it
+// with the mock provider, as their device would, leaving the webhook to be delivered
+func confirmedIntent(t *testing.T, order models.Order) (models.PaymentIntent, gateway.Intent) {
+	t.Helper()
+	if code := moveOrder(t, order.ID, models.OrderStatusPreparing); code != http.StatusOK {
+		t.Fatalf("moving the order to preparing answered %d", code)
+	}
+	invoice, err := Repos.Invoices.GetByOrder(context.Background(), order.ID)
+	if err != nil {
+		t.Fatalf("GetByOrder: %v", err)
+	}
+
+	var created struct {
+		Intent models.PaymentIntent `json:"payment_intent"`
+	}
+	path := fmt.Sprintf("/invoice-intents/%d", invoice.ID)
+	if recorder := serve(t, CreatePaymentIntent(), http.MethodPost, "/invoice-intents/:invoice_id", path, models.PaymentIntent{}, &created); recorder.Code != http.StatusCreated {
+		t.Fatalf("creating the intent answered %d: %s", recorder.Code, recorder.Body)
+	}
+
+	providerIntent, err := Gateway.(*gateway.Mock).Capture(context.Background(), created.Intent.ProviderIntentID)
+	if err != nil {
+		t.Fatalf("confirming the intent: %v", err)
+	}
+	return created.Intent, providerIntent
+}
+
+// deliverWebhook sends an event to PaymentWebhook signed by the mock provider, or with signature when given
+func deliverWebhook(t *testing.T, event gateway.Event, signature string) (int, string) {
+	t.Helper()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("encoding the event: %v", err)
+	}
+	if signature == "" {
+		signature = Gateway.(*gateway.Mock).Sign(payload, time.Now())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payments", bytes.NewReader(payload))
+	req.Header.Set(gateway.SignatureHeader, signature)
+	var body struct {
+		Message string `json:"message"`
+	}
+	recorder := serveRequest(t, PaymentWebhook(), "/webhooks/payments", req, &body)
+	return recorder.Code, body.Message
+}
+
+func succeeded(id string, intent gateway.Intent) gateway.Event {
+	return gateway.Event{ID: id, Type: gateway.EventIntentSucceeded, IntentID: intent.ID, TransactionID: intent.TransactionID, Amount: intent.Amount}
+}
+
+// paymentsOf lists the payments taken against the intent's invoice
+func paymentsOf(t *testing.T, intent models.PaymentIntent) []models.Payment {
+	t.Helper()
+	payments, err := Repos.Payments.ListByInvoice(context.Background(), intent.InvoiceID)
+	if err != nil {
+		t.Fatalf("ListByInvoice: %v", err)
+	}
+	return payments
+}
+
+func TestPaymentWebhookRejectsBadSignatures(t *testing.T) {
+	setup(t)
+	intent, providerIntent := confirmedIntent(t, seedOrder(t, 12.5))
+
+	forged := gateway.NewMock("not the secret")
+	payload, _ := json.Marshal(succeeded("evt_1", providerIntent))
+	for name, signature := range map[string]string{
+		"forged": forged.Sign(payload, time.Now()),
+		"stale":  Gateway.(*gateway.Mock).Sign(payload, time.Now().Add(-time.Hour)),
+		"bogus":  "t=1,v1=00",
+	} {
+		if code, _ := deliverWebhook(t, succeeded("evt_1", providerIntent), signature); code != http.StatusBadRequest {
+			t.Errorf("a %s signature answered %d, want %d", name, code, http.StatusBadRequest)
+		}
+	}
+	if payments := paymentsOf(t, intent); len(payments) != 0 {
+		t.Errorf("unsigned webhooks recorded %d payments", len(payments))
+	}
+}
+
+func TestPaymentWebhookSettlesAnIntentOnce(t *testing.T) {
+	setup(t)
+	ctx := context.Background()
+	intent, providerIntent := confirmedIntent(t, seedOrder(t, 12.5))
+
+	if code, message := deliverWebhook(t, succeeded("evt_1", providerIntent), ""); code != http.StatusOK || message != "Webhook handled successfully" {
+		t.Fatalf("the webhook answered %d %q", code, message)
+	}
+	// Providers deliver at least once, the same event again changes nothing
+	if code, message := deliverWebhook(t, succeeded("evt_1", providerIntent), ""); code != http.StatusOK || message != "Webhook already handled" {
+		t.Fatalf("the second delivery answered %d %q", code, message)
+	}
+
+	payments := paymentsOf(t, intent)
+	if len(payments) != 1 {
+		t.Fatalf("the invoice has %d payments, want 1", len(payments))
+	}
+	if payments[0].TransactionID != providerIntent.TransactionID || payments[0].Method != "online" {
+		t.Errorf("the payment is %s with transaction %q, want online with %q", payments[0].Method, payments[0].TransactionID, providerIntent.TransactionID)
+	}
+	invoice, err := Repos.Invoices.Get(ctx, intent.InvoiceID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if invoice.AmountPaid != 12.5 || invoice.Status != models.InvoiceStatusPaid {
+		t.Errorf("the invoice is %s with %.2f paid, want paid in full", invoice.Status, invoice.AmountPaid)
+	}
+	if intent, err = Repos.PaymentIntents.Get(ctx, intent.ID); err != nil || intent.Status != gateway.IntentSucceeded || intent.PaymentID != payments[0].ID {
+		t.Errorf("the intent is %s for payment %d, %v, want succeeded for payment %d", intent.Status, intent.PaymentID, err, payments[0].ID)
+	}
+}
+
+func TestPaymentWebhookIgnoresWhatTheProviderDoesntConfirm(t *testing.T) {
+	setup(t)
+	intent, providerIntent := confirmedIntent(t, seedOrder(t, 12.5))
+
+	underpaid := succeeded("evt_1", providerIntent)
+	underpaid.Amount = 1
+	otherTransaction := succeeded("evt_2", providerIntent)
+	otherTransaction.TransactionID = "ch_forged"
+	for _, event := range []gateway.Event{underpaid, otherTransaction} {
+		if code, _ := deliverWebhook(t, event, ""); code != http.StatusOK {
+			t.Errorf("event %s answered %d, want it acknowledged", event.ID, code)
+		}
+	}
+
+	if payments := paymentsOf(t, intent); len(payments) != 0 {
+		t.Errorf("events the provider doesn't confirm recorded %d payments", len(payments))
+	}
+}
+
+func TestPaymentWebhookAcknowledgesADeletedInvoice(t *testing.T) {
+	setup(t)
+	order := seedOrder(t, 12.5)
+	intent, providerIntent := confirmedIntent(t, order)
+	if code := moveOrder(t, order.ID, models.OrderStatusCancelled); code != http.StatusOK {
+		t.Fatalf("cancelling the order answered %d", code)
+	}
+
+	if code, message := deliverWebhook(t, succeeded("evt_1", providerIntent), ""); code != http.StatusOK || message != "Webhook handled successfully" {
+		t.Fatalf("the webhook answered %d %q, want it acknowledged", code, message)
+	}
+	// Stored as handled, so the provider's retries stop
+	if code, message := deliverWebhook(t, succeeded("evt_1", providerIntent), ""); code != http.StatusOK || message != "Webhook already handled" {
+		t.Fatalf("the second delivery answered %d %q", code, message)
+	}
+	if payments := paymentsOf(t, intent); len(payments) != 0 {
+		t.Errorf("the webhook recorded %d payments for a deleted invoice", len(payments))
+	}
+}
@@ -2,11 +2,59 @@ package controllers
 
 import (
 	"database/sql"
+	"errors"
+	"log"
+	"strconv"
+
 	"restaurant-management/database"
+	"restaurant-management/gateway"
+	"restaurant-management/mailer"
+	"restaurant-management/notify"
+	"restaurant-management/repository"
 )
 
 var Db *sql.DB
 
+// Repos is the data access layer every controller reads and writes through
+var Repos repository.Repositories
+
+// UnitOfWork groups repository calls that must succeed or fail together
+var UnitOfWork repository.UnitOfWork
+
+// Notifier tells guests their table is ready
+var Notifier notify.Notifier
+
+// Gateway takes online payments
+var Gateway gateway.Provider
+
+// Mailer emails invoices to customers
+var Mailer mailer.Mailer
+
 func InitControllers() {
 	Db = database.Client
+	Repos = repository.NewPostgres(Db)
+	UnitOfWork = repository.NewPostgresUnitOfWork(Db)
+
+	var err error
+	if Notifier, err = notify.FromEnv(); err != nil {
+		log.Fatal("Error configuring the guest notifier:", err)
+	}
+	if Gateway, err = gateway.FromEnv(); err != nil {
+		log.Fatal("Error configuring the payment gateway:", err)
+	}
+	if Mailer, err = mailer.FromEnv(); err != nil {
+		log.Fatal("Error configuring the mailer:", err)
+	}
+}
+
+// parseID converts a route or query ID into the uint the repositories expect
+func parseID(value string) (uint, error) {
+	if value == "" {
+		return 0, errors.New("ID is required")
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.New("ID must be a positive integer")
+	}
+	return uint(id), nil
 }
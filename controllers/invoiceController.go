@@ -2,11 +2,12 @@ package controllers
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"restaurant-management/helpers"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,37 +18,44 @@ func GetInvoices() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("restaurant_id")
-		if id == "" {
+		id, err := parseID(c.Param("restaurant_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Please provide Restaurant ID"})
 			return
 		}
 
-		query := `SELECT * FROM invoices WHERE restaurant_id = $1`
-		rows, err := Db.QueryContext(ctx, query, id)
+		invoices, err := Repos.Invoices.ListByRestaurant(ctx, id)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoices from database", "details": err.Error()})
 			return
 		}
 
-		var invoices []models.Invoice
-		for rows.Next() {
-			var invoice models.Invoice
-			if err := rows.Scan(&invoice.ID, &invoice.OrderID, &invoice.RestaurantID, &invoice.Amount, &invoice.Tax, &invoice.Total, &invoice.Status, &invoice.PaymentMethod, &invoice.CreatedAt, &invoice.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan invoice from database", "details": err.Error()})
-				return
-			}
-			invoices = append(invoices, invoice)
-		}
-
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Invoices fetched successfully", "invoices": invoices})
 	}
 }
 
 func GetInvoice() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Logic to get an invoice by ID
-		c.JSON(200, gin.H{"message": "Get invoice by ID"})
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("invoice_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+			return
+		}
+
+		invoice, err := Repos.Invoices.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No invoice found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Invoice fetched successfully", "invoice": invoice})
 	}
 }
 
@@ -78,60 +86,129 @@ func GetInvoiceByOrderID() gin.HandlerFunc {
 	}
 }
 
-func CreateInvoiceFromOrder(order models.Order) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	const (
-		queryInsert = `
-			INSERT INTO invoices (order_id, amount, tax, total, status, payment_method, restaurant_id)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-			ON CONFLICT (order_id)
-			DO UPDATE SET 
-				amount = EXCLUDED.amount,
-				tax = EXCLUDED.tax,
-				total = EXCLUDED.total,
-				status = EXCLUDED.status,
-				payment_method = EXCLUDED.payment_method,
-				restaurant_id = EXCLUDED.restaurant_id,
-				updated_at = CURRENT_TIMESTAMP
-		`
-		queryDelete = `
-			DELETE FROM invoices WHERE order_id = $1
-		`
-	)
-
-	var tax = 0.00
-	var total = order.TotalPrice + tax
-	var status = "pending"
-	var paymentMethod = "cash"
-
-	var result sql.Result
-	var err error
+// CreateInvoiceFromOrder keeps the order's invoice in line with its status, items, discounts
+// and the restaurant's tax rates. It runs on the repositories of the caller's unit of work so both change together.
+// The invoice is paid once payments cover its total, an order can only be marked paid after that.
+func CreateInvoiceFromOrder(ctx context.Context, repos repository.Repositories, order models.Order) error {
+	existing, err := repos.Invoices.GetByOrder(ctx, order.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("fetching the invoice: %w", err)
+	}
+	// Paid invoices are kept as they were charged, credit notes correct them
+	if order.Status == models.OrderStatusPaid && existing.Status == models.InvoiceStatusPaid {
+		return nil
+	}
 
 	switch order.Status {
 	case "preparing", "ready", "served", "paid":
-		if order.Status == "paid" {
-			status = "paid"
-		} else {
-			status = "pending"
+		rates, err := repos.TaxRates.List(ctx, order.RestaurantID)
+		if err != nil {
+			return fmt.Errorf("fetching tax rates: %w", err)
+		}
+		discounts, err := repos.Discounts.ListByOrder(ctx, order.ID)
+		if err != nil {
+			return fmt.Errorf("fetching discounts: %w", err)
+		}
+
+		// Items are taxed on what is left of them after discounts
+		discount := helpers.ComputeDiscounts(order.OrderItems, discounts)
+		amounts := map[string]float64{}
+		for _, item := range order.OrderItems {
+			amounts[item.TaxCategory] += item.SubTotal - discount.PerItem[item.ID]
+		}
+		tax := helpers.ComputeTax(amounts, rates)
+
+		// Service charges go on what the items cost after discounts, before tax
+		guests, err := orderGuests(ctx, repos, order)
+		if err != nil {
+			return err
+		}
+		charges, err := repos.ServiceCharges.List(ctx, order.RestaurantID)
+		if err != nil {
+			return fmt.Errorf("fetching service charges: %w", err)
+		}
+		service := helpers.ComputeServiceCharges(tax.Net, guests, charges)
+		total := helpers.FromCents(helpers.ToCents(tax.Total) + helpers.ToCents(service.Total))
+
+		if helpers.ToCents(total) < helpers.ToCents(existing.AmountPaid) {
+			return abortWith(http.StatusConflict, gin.H{"error": "The order can't cost less than has already been paid", "amount_paid": existing.AmountPaid, "total": total})
 		}
-		fmt.Println("Status: " + status)
-		result, err = Db.ExecContext(ctx, queryInsert, order.ID, order.TotalPrice, tax, total, status, paymentMethod, order.RestaurantID)
+		status := invoiceStatus(total, existing.AmountPaid)
+		if order.Status == models.OrderStatusPaid && status != models.InvoiceStatusPaid {
+			return abortWith(http.StatusConflict, gin.H{"error": "The order still has a balance to pay", "balance": helpers.FromCents(helpers.ToCents(total) - helpers.ToCents(existing.AmountPaid))})
+		}
+		paymentMethod := existing.PaymentMethod
+		if paymentMethod == "" {
+			paymentMethod = "cash"
+		}
+
+		invoice, err := repos.Invoices.UpsertForOrder(ctx, models.Invoice{
+			OrderID:        order.ID,
+			RestaurantID:   order.RestaurantID,
+			Amount:         tax.Net,
+			Discount:       discount.Total,
+			Tax:            tax.Tax,
+			ServiceCharge:  service.Total,
+			Total:          total,
+			Taxes:          tax.Lines,
+			ServiceCharges: service.Lines,
+			Status:         status,
+			PaymentMethod:  paymentMethod,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Parts of a split check no longer add up once the order changes, so they are merged back
+		if existing.SplitMode == "" || helpers.ToCents(existing.Total) == helpers.ToCents(invoice.Total) && helpers.ToCents(existing.Discount) == helpers.ToCents(invoice.Discount) {
+			return nil
+		}
+		if existing.AmountPaid > 0 {
+			return abortWith(http.StatusConflict, gin.H{"error": "Part of the split check has been paid, the order can't change", "invoice_id": existing.ID})
+		}
+		_, err = repos.Invoices.ReplaceSplits(ctx, invoice.ID, "", nil)
+		return err
 	case "pending", "cancelled":
-		result, err = Db.ExecContext(ctx, queryDelete, order.ID)
+		if existing.AmountPaid > 0 {
+			return abortWith(http.StatusConflict, gin.H{"error": "Payments have been taken on the order", "amount_paid": existing.AmountPaid})
+		}
+		return repos.Invoices.DeleteByOrder(ctx, order.ID)
 	}
 
+	return nil
+}
+
+// issueInvoice numbers the order's invoice next in the restaurant's invoice series
+func issueInvoice(ctx context.Context, repos repository.Repositories, orderID uint) error {
+	invoice, err := repos.Invoices.GetByOrder(ctx, orderID)
 	if err != nil {
-		return err
+		return fmt.Errorf("fetching the invoice: %w", err)
 	}
+	_, err = repos.Invoices.Issue(ctx, invoice.ID)
+	return err
+}
 
-	affectedRows, err := result.RowsAffected()
-	if err != nil || affectedRows == 0 {
-		return err
+// orderGuests is the size of the party an order is for, the capacity of its table unless the guests were counted
+func orderGuests(ctx context.Context, repos repository.Repositories, order models.Order) (int, error) {
+	if order.GuestCount > 0 || order.TableID == 0 {
+		return order.GuestCount, nil
 	}
+	table, err := repos.Tables.Get(ctx, order.TableID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("fetching the order's table: %w", err)
+	}
+	return table.Capacity, nil
+}
 
-	return nil
+// invoiceStatus is paid once what has been paid covers the total
+func invoiceStatus(total, amountPaid float64) string {
+	if helpers.ToCents(amountPaid) >= helpers.ToCents(total) {
+		return models.InvoiceStatusPaid
+	}
+	return models.InvoiceStatusPending
 }
 
 func DownloadInvoice() gin.HandlerFunc {
@@ -140,60 +217,42 @@ func DownloadInvoice() gin.HandlerFunc {
 		defer cancel()
 
 		invoiceID := c.Param("invoice_id")
-		if invoiceID == "" {
+		id, err := parseID(invoiceID)
+		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
 			return
 		}
 
-		var invoice models.Invoice
-		query := `SELECT * FROM invoices WHERE id = $1`
-		err := Db.QueryRowContext(ctx, query, invoiceID).
-			Scan(&invoice.ID, &invoice.OrderID, &invoice.RestaurantID, &invoice.Amount, &invoice.Tax, &invoice.Total,
-				&invoice.Status, &invoice.PaymentMethod, &invoice.CreatedAt, &invoice.UpdatedAt)
+		invoice, err := Repos.Invoices.Get(ctx, id)
 		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice", "details": err.Error()})
 			return
 		}
 
-		var order models.Order
-		err = Db.QueryRowContext(ctx, "SELECT * FROM orders WHERE id = $1", invoice.OrderID).
-			Scan(&order.ID, &order.TableID, &order.RestaurantID, &order.OrderDate, &order.TotalPrice,
-				&order.Status, &order.Notes, &order.CreatedAt, &order.UpdatedAt)
+		// Items come back with their food names for the PDF
+		order, err := Repos.Orders.Get(ctx, invoice.OrderID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order", "details": err.Error()})
 			return
 		}
 
-		rows, err := Db.Query(`
-			SELECT oi.id, oi.order_id, oi.food_id, f.name, oi.quantity, oi.unit_price, oi.subtotal, oi.created_at, oi.updated_at
-			FROM orderitems oi
-			JOIN foods f ON f.id = oi.food_id
-			WHERE oi.order_id = $1
-		`, order.ID)
+		restaurant, err := Repos.Restaurants.Get(ctx, invoice.RestaurantID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order items", "details": err.Error()})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant", "details": err.Error()})
 			return
 		}
-		defer rows.Close()
 
-		for rows.Next() {
-			var item models.OrderItem
-			if err := rows.Scan(&item.ID, &item.OrderID, &item.FoodID, &item.FoodName, &item.Quantity, &item.UnitPrice, &item.SubTotal, &item.CreatedAt, &item.UpdatedAt); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan order item", "details": err.Error()})
-				return
-			}
-			order.OrderItems = append(order.OrderItems, item)
-		}
-
-		var restaurant models.Restaurant
-		err = Db.QueryRowContext(ctx, "SELECT * FROM restaurants WHERE id = $1", invoice.RestaurantID).
-			Scan(&restaurant.ID, &restaurant.Name, &restaurant.OwnerID, &restaurant.Logo, &restaurant.Address, &restaurant.Description, &restaurant.CreatedAt, &restaurant.UpdatedAt)
+		layout, err := documentLayout(ctx, restaurant)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant", "details": err.Error()})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice template", "details": err.Error()})
 			return
 		}
 
-		document, err := helpers.GeneratePdfFromData(invoice, order, restaurant)
+		document, err := helpers.GeneratePdfFromData(invoice, order, restaurant, layout)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate PDF", "details": err.Error()})
 			return
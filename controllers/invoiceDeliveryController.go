@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"restaurant-management/helpers"
+	"restaurant-management/mailer"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxDeliveryAttempts is how often an invoice email is tried before the delivery fails
+const maxDeliveryAttempts = 3
+
+func GetInvoiceDeliveries() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("invoice_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+			return
+		}
+
+		deliveries, err := Repos.Deliveries.ListByInvoice(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice deliveries from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Invoice deliveries fetched successfully", "invoice_id": id, "deliveries": deliveries})
+	}
+}
+
+// SendInvoice emails an invoice's PDF to a customer. The delivery is recorded before the
+// mail server is tried, and every try is counted on it; temporary failures are tried
+// again a few times, a recipient the server refuses is not.
+func SendInvoice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("invoice_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+			return
+		}
+
+		var request models.SendInvoice
+		if err := c.BindJSON(&request); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide the email address to send the invoice to", "details": err.Error()})
+			return
+		}
+		if err := validate.Struct(request); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+
+		invoice, err := Repos.Invoices.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice", "details": err.Error()})
+			return
+		}
+		order, err := Repos.Orders.Get(ctx, invoice.OrderID)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order", "details": err.Error()})
+			return
+		}
+		restaurant, err := Repos.Restaurants.Get(ctx, invoice.RestaurantID)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant", "details": err.Error()})
+			return
+		}
+
+		layout, err := documentLayout(ctx, restaurant)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice template", "details": err.Error()})
+			return
+		}
+
+		document, err := helpers.GeneratePdfFromData(invoice, order, restaurant, layout)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate PDF", "details": err.Error()})
+			return
+		}
+		subject, body, err := helpers.InvoiceEmail(invoice, restaurant, request.Message)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to render the email", "details": err.Error()})
+			return
+		}
+		filename := "invoice-" + strconv.FormatUint(uint64(invoice.ID), 10) + ".pdf"
+		if invoice.Number != "" {
+			filename = "invoice-" + invoice.Number + ".pdf"
+		}
+
+		delivery, err := Repos.Deliveries.Create(ctx, models.InvoiceDelivery{
+			InvoiceID:    invoice.ID,
+			RestaurantID: invoice.RestaurantID,
+			Recipient:    request.Email,
+			SentBy:       statusActor(c),
+		})
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to record the delivery", "details": err.Error()})
+			return
+		}
+
+		delivery, err = deliver(ctx, delivery, mailer.Message{
+			To:          request.Email,
+			Subject:     subject,
+			HTML:        body,
+			Attachments: []mailer.Attachment{{Filename: filename, ContentType: "application/pdf", Data: document.GetBytes()}},
+		})
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to record the delivery", "details": err.Error()})
+			return
+		}
+		if delivery.Status != models.DeliveryStatusSent {
+			c.IndentedJSON(http.StatusBadGateway, gin.H{"error": "The invoice could not be sent", "details": delivery.LastError, "delivery": delivery})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Invoice sent successfully", "delivery": delivery})
+	}
+}
+
+// deliver tries to send a message until it goes through, the server refuses it for good or
+// the attempts run out, recording every try on the delivery. Tries are recorded even once
+// the request has timed out, so no delivery is left pending.
+func deliver(ctx context.Context, delivery models.InvoiceDelivery, message mailer.Message) (models.InvoiceDelivery, error) {
+	for attempt := 1; ; attempt++ {
+		sendErr := Mailer.Send(ctx, message)
+
+		status, lastError := models.DeliveryStatusSent, ""
+		if sendErr != nil {
+			status, lastError = models.DeliveryStatusPending, sendErr.Error()
+			if attempt == maxDeliveryAttempts || mailer.Permanent(sendErr) || ctx.Err() != nil {
+				status = models.DeliveryStatusFailed
+			}
+		}
+
+		recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		var err error
+		delivery, err = Repos.Deliveries.RecordAttempt(recordCtx, delivery.ID, status, lastError)
+		cancel()
+		if err != nil || status != models.DeliveryStatusPending {
+			return delivery, err
+		}
+
+		select {
+		case <-time.After(time.Duration(attempt) * time.Second):
+		case <-ctx.Done():
+		}
+	}
+}
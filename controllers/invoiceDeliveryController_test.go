@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"restaurant-management/mailer"
+	"restaurant-management/models"
+)
+
+// refusingMailer stands in for a mail server that refuses every recipient for good
+type refusingMailer struct{}
+
+func (refusingMailer) Send(ctx context.Context, message mailer.Message) error {
+	return &textproto.Error{Code: 550, Msg: "no such user"}
+}
+
+// invoicedOrder seeds an order and moves it to preparing, which gives it an invoice
+func invoicedOrder(t *testing.T) models.Invoice {
+	t.Helper()
+	order := seedOrder(t, 12.5)
+	if code := moveOrder(t, order.ID, models.OrderStatusPreparing); code != http.StatusOK {
+		t.Fatalf("moving the order to preparing answered %d", code)
+	}
+	invoice, err := Repos.Invoices.GetByOrder(context.Background(), order.ID)
+	if err != nil {
+		t.Fatalf("GetByOrder: %v", err)
+	}
+	return invoice
+}
+
+func TestSendInvoiceMailsThePDF(t *testing.T) {
+	setup(t)
+	outbox := t.TempDir()
+	Mailer = mailer.NewFileMailer(outbox)
+	invoice := invoicedOrder(t)
+
+	path := fmt.Sprintf("/invoices/%d/send", invoice.ID)
+	request := models.SendInvoice{Email: "guest@example.com"}
+	if recorder := serve(t, SendInvoice(), http.MethodPost, "/invoices/:invoice_id/send", path, request, nil); recorder.Code != http.StatusOK {
+		t.Fatalf("sending the invoice answered %d: %s", recorder.Code, recorder.Body)
+	}
+
+	files, err := filepath.Glob(filepath.Join(outbox, "*.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("the outbox holds %v, %v, want one message", files, err)
+	}
+	sent, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("reading the message: %v", err)
+	}
+	if !strings.Contains(string(sent), "To: guest@example.com") || !strings.Contains(string(sent), "application/pdf") {
+		t.Errorf("the message doesn't go to the guest with the PDF attached:\n%s", sent)
+	}
+
+	deliveries, err := Repos.Deliveries.ListByInvoice(context.Background(), invoice.ID)
+	if err != nil {
+		t.Fatalf("ListByInvoice: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Status != models.DeliveryStatusSent || deliveries[0].Attempts != 1 {
+		t.Errorf("deliveries are %+v, want one sent on the first attempt", deliveries)
+	}
+}
+
+func TestSendInvoiceGivesUpOnARefusedRecipient(t *testing.T) {
+	setup(t)
+	Mailer = refusingMailer{}
+	invoice := invoicedOrder(t)
+
+	path := fmt.Sprintf("/invoices/%d/send", invoice.ID)
+	request := models.SendInvoice{Email: "nobody@example.com"}
+	if code := serve(t, SendInvoice(), http.MethodPost, "/invoices/:invoice_id/send", path, request, nil).Code; code != http.StatusBadGateway {
+		t.Fatalf("a refused recipient answered %d, want %d", code, http.StatusBadGateway)
+	}
+
+	deliveries, err := Repos.Deliveries.ListByInvoice(context.Background(), invoice.ID)
+	if err != nil {
+		t.Fatalf("ListByInvoice: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Status != models.DeliveryStatusFailed || deliveries[0].Attempts != 1 || deliveries[0].LastError == "" {
+		t.Errorf("deliveries are %+v, want one failed after a single attempt", deliveries)
+	}
+}
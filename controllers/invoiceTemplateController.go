@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func GetInvoiceTemplate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		template, err := Repos.Templates.Get(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice template from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Invoice template fetched successfully", "invoice_template": template})
+	}
+}
+
+// SetInvoiceTemplate changes how a restaurant's invoices and credit notes are printed,
+// documents printed before keep the layout they were printed with
+func SetInvoiceTemplate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		template, ok := bindInvoiceTemplate(c)
+		if !ok {
+			return
+		}
+
+		template, err := Repos.Templates.Upsert(ctx, template)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to save invoice template in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Invoice template saved successfully", "invoice_template": template})
+	}
+}
+
+// PreviewInvoiceTemplate prints a sample invoice with a template before it is saved
+func PreviewInvoiceTemplate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		template, ok := bindInvoiceTemplate(c)
+		if !ok {
+			return
+		}
+
+		restaurant, err := Repos.Restaurants.Get(ctx, template.RestaurantID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant", "details": err.Error()})
+			return
+		}
+		series, err := Repos.NumberSeries.Get(ctx, restaurant.ID, models.SequenceInvoice)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch number series", "details": err.Error()})
+			return
+		}
+
+		// Unlike a real invoice, a logo that doesn't load is reported so it can be fixed
+		layout, err := helpers.LoadLayout(ctx, template, restaurant)
+		if err != nil {
+			c.IndentedJSON(http.StatusUnprocessableEntity, gin.H{"error": "The restaurant's logo can't be printed", "details": err.Error()})
+			return
+		}
+
+		invoice, order := sampleInvoice(restaurant.ID, series)
+		document, err := helpers.GeneratePdfFromData(invoice, order, restaurant, layout)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate PDF", "details": err.Error()})
+			return
+		}
+
+		c.Header("Content-Disposition", "inline; filename=invoice-preview.pdf")
+		c.Data(http.StatusOK, "application/pdf", document.GetBytes())
+	}
+}
+
+// bindInvoiceTemplate reads and validates a template from the request body, answering the request when it is not valid
+func bindInvoiceTemplate(c *gin.Context) (models.InvoiceTemplate, bool) {
+	var template models.InvoiceTemplate
+	if err := c.BindJSON(&template); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for the invoice template", "details": err.Error()})
+		return template, false
+	}
+	if template.PaperSize == "" {
+		template.PaperSize = models.PaperA4
+	}
+
+	if err := validate.Struct(template); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+		}
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		return template, false
+	}
+	return template, true
+}
+
+// sampleInvoice is a partly paid invoice for previews, numbered as the restaurant's next invoice would be
+func sampleInvoice(restaurantID uint, series models.NumberSeries) (models.Invoice, models.Order) {
+	issuedAt := time.Now()
+	items := []models.OrderItem{
+		{ID: 1, FoodName: "Margherita Pizza", Quantity: 2, UnitPrice: 9.50, SubTotal: 19.00},
+		{ID: 2, FoodName: "Caesar Salad", Quantity: 1, UnitPrice: 7.25, SubTotal: 7.25},
+		{ID: 3, FoodName: "Sparkling Water", Quantity: 3, UnitPrice: 2.00, SubTotal: 6.00},
+	}
+	order := models.Order{ID: 1, RestaurantID: restaurantID, Status: models.OrderStatusServed, OrderItems: items, TotalPrice: 32.25}
+	invoice := models.Invoice{
+		ID:           1,
+		Number:       series.Format(series.Period(issuedAt), 1),
+		IssuedAt:     &issuedAt,
+		OrderID:      order.ID,
+		Amount:       32.25,
+		Tax:          3.23,
+		Total:        35.48,
+		AmountPaid:   10,
+		Balance:      25.48,
+		Taxes:        []models.InvoiceTax{{Name: "Sales tax", Category: models.TaxCategoryFood, Rate: 10, TaxableAmount: 32.25, TaxAmount: 3.23}},
+		Status:       models.InvoiceStatusPending,
+		CreatedAt:    issuedAt,
+		RestaurantID: restaurantID,
+	}
+	return invoice, order
+}
+
+// documentLayout loads how the restaurant prints its documents. A logo that doesn't load is
+// logged and left out, the document is still printed.
+func documentLayout(ctx context.Context, restaurant models.Restaurant) (helpers.Layout, error) {
+	template, err := Repos.Templates.Get(ctx, restaurant.ID)
+	if err != nil {
+		return helpers.Layout{}, err
+	}
+	layout, err := helpers.LoadLayout(ctx, template, restaurant)
+	if err != nil {
+		log.Printf("Printing without the logo of restaurant %d: %v", restaurant.ID, err)
+	}
+	return layout, nil
+}
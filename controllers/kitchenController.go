@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"restaurant-management/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// kitchenHeartbeat keeps idle feed connections from being closed by proxies
+const kitchenHeartbeat = 15 * time.Second
+
+// StreamKitchenFeed streams a restaurant's order events as server-sent events. Clients
+// resume after a reconnect by sending the Last-Event-ID header (or the last_event_id
+// query parameter) with the ID of the last event they received.
+func StreamKitchenFeed() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := parseID(c.Param("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("last_event_id")
+		}
+
+		ctx := c.Request.Context()
+		feed, err := utils.SubscribeKitchenFeed(ctx, id, lastEventID)
+		if err != nil {
+			if errors.Is(err, utils.ErrInvalidEventID) {
+				c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid last event ID", "last_event_id": lastEventID})
+				return
+			}
+			c.IndentedJSON(http.StatusServiceUnavailable, gin.H{"error": "Kitchen feed is unavailable", "details": err.Error()})
+			return
+		}
+		defer feed.Close()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		fmt.Fprintf(c.Writer, "retry: %d\n\n", (3 * time.Second).Milliseconds())
+
+		heartbeat := time.NewTicker(kitchenHeartbeat)
+		defer heartbeat.Stop()
+
+		// Replay whatever the client missed, then push new events as they are announced
+		if !sendKitchenEvents(c, feed) {
+			return
+		}
+		notifications := feed.Notifications()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-notifications:
+				if !ok || !sendKitchenEvents(c, feed) {
+					return
+				}
+			case <-heartbeat.C:
+				fmt.Fprint(c.Writer, ": heartbeat\n\n")
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// GetStationQueue lists the items the kitchen still has to prepare, optionally for one station
+func GetStationQueue() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		var stationID uint
+		if c.Query("station_id") != "" {
+			if stationID, err = parseID(c.Query("station_id")); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid station ID"})
+				return
+			}
+			station, err := Repos.Stations.Get(ctx, stationID)
+			if err != nil || station.RestaurantID != id {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No station found with given ID in this restaurant", "station_id": stationID})
+				return
+			}
+		}
+
+		tickets, err := Repos.OrderItems.StationQueue(ctx, id, stationID)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch the station queue", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Station queue fetched successfully", "station_id": stationID, "queue": tickets})
+	}
+}
+
+// BumpOrderItem marks one item of a preparing order as done. Bumping the last
+// outstanding item moves the order to ready.
+func BumpOrderItem() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("order_item_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order item ID is required"})
+			return
+		}
+
+		var item models.OrderItem
+		var current, order models.Order
+		var promoted bool
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			if item, err = repos.OrderItems.Get(ctx, id); err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return abortWith(http.StatusNotFound, gin.H{"error": "No order item found with the given ID", "order_item_id": id})
+				}
+				return fmt.Errorf("fetching the order item: %w", err)
+			}
+			if current, err = lockOrder(ctx, repos, item.OrderID); err != nil {
+				return err
+			}
+			order = current
+
+			if helpers.OrderStatus(current.Status) != models.OrderStatusPreparing {
+				return abortWith(http.StatusConflict, gin.H{"error": "Only items of preparing orders can be bumped", "order_id": current.ID, "status": current.Status})
+			}
+			if item.ComboID != 0 {
+				return abortWith(http.StatusConflict, gin.H{"error": "A combo is bumped through its components", "order_item_id": id})
+			}
+			if item.PrepStatus == models.PrepStatusDone {
+				return abortWith(http.StatusConflict, gin.H{"error": "Order item was already bumped", "order_item_id": id})
+			}
+
+			if item, err = repos.OrderItems.Bump(ctx, id); err != nil {
+				return fmt.Errorf("bumping the order item: %w", err)
+			}
+
+			items, err := repos.OrderItems.ListByOrder(ctx, current.ID)
+			if err != nil {
+				return fmt.Errorf("fetching the order items: %w", err)
+			}
+			for _, orderItem := range items {
+				if orderItem.PrepStatus != models.PrepStatusDone {
+					return nil
+				}
+			}
+
+			current.OrderItems = items
+			order, err = changeOrderStatus(ctx, c, repos, current, models.OrderStatusReady)
+			promoted = err == nil
+			return err
+		})
+		if err != nil {
+			respondError(c, err, "Failed to bump the order item")
+			return
+		}
+
+		publishKitchenEvent(models.KitchenEvent{Type: models.KitchenEventItemBumped, RestaurantID: order.RestaurantID, OrderID: order.ID, Status: order.Status, OrderItem: &item})
+		if promoted {
+			publishKitchenEvent(models.KitchenEvent{Type: models.KitchenEventStatusChanged, RestaurantID: order.RestaurantID, OrderID: order.ID, Status: order.Status, FromStatus: models.OrderStatusPreparing})
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Order item bumped successfully", "order_item": item, "order_status": order.Status})
+	}
+}
+
+// sendKitchenEvents writes every event the client has not seen yet, reporting false once the stream should end
+func sendKitchenEvents(c *gin.Context, feed *utils.KitchenFeed) bool {
+	events, missed, err := feed.Next(c.Request.Context())
+	if err != nil {
+		writeServerEvent(c, "", "error", gin.H{"error": "Failed to read the kitchen feed", "details": err.Error()})
+		return false
+	}
+
+	if missed {
+		writeServerEvent(c, "", "reset", gin.H{"message": "Older events are no longer available, reload the orders before applying new events"})
+	}
+	for _, event := range events {
+		writeServerEvent(c, event.ID, event.Type, event)
+	}
+
+	c.Writer.Flush()
+	return true
+}
+
+func writeServerEvent(c *gin.Context, id, event string, data any) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	if id != "" {
+		fmt.Fprintf(c.Writer, "id: %s\n", id)
+	}
+	fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, payload)
+}
+
+// publishKitchenEvent pushes a committed change to the kitchen feed. The change is already
+// saved, so a feed outage is only logged instead of failing the request.
+func publishKitchenEvent(event models.KitchenEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := utils.PublishKitchenEvent(ctx, event); err != nil {
+		log.Printf("Failed to publish %s event for order %d: %v", event.Type, event.OrderID, err)
+	}
+}
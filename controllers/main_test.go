@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"restaurant-management/config"
+	"restaurant-management/gateway"
+	"restaurant-management/mailer"
+	"restaurant-management/models"
+	"restaurant-management/notify"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	// Nothing listens there, so kitchen events fail fast and are only logged
+	config.RedisClient = redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	os.Exit(m.Run())
+}
+
+// testWebhookSecret signs the mock provider's webhooks in tests
+const testWebhookSecret = "test-webhook-secret"
+
+// setup points the controllers at fresh in-memory repositories and stand-ins for the outside services
+func setup(t *testing.T) {
+	t.Helper()
+	Repos, UnitOfWork = repository.NewMemory()
+	Notifier = notify.NewFileNotifier(filepath.Join(t.TempDir(), "notifications.jsonl"))
+	Gateway = gateway.NewMock(testWebhookSecret)
+	Mailer = mailer.NewFileMailer(t.TempDir())
+}
+
+// serve runs a request through handler mounted on pattern, and decodes the JSON response into body
+func serve(t *testing.T, handler gin.HandlerFunc, method, pattern, path string, request any, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var payload []byte
+	switch request := request.(type) {
+	case nil:
+	case []byte:
+		payload = request
+	default:
+		var err error
+		if payload, err = json.Marshal(request); err != nil {
+			t.Fatalf("encoding the request: %v", err)
+		}
+	}
+	return serveRequest(t, handler, pattern, httptest.NewRequest(method, path, bytes.NewReader(payload)), body)
+}
+
+func serveRequest(t *testing.T, handler gin.HandlerFunc, pattern string, req *http.Request, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	router := gin.New()
+	router.Handle(req.Method, pattern, handler)
+	req.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	if body != nil && recorder.Body.Len() > 0 {
+		if err := json.Unmarshal(recorder.Body.Bytes(), body); err != nil {
+			t.Fatalf("decoding the response %s: %v", recorder.Body, err)
+		}
+	}
+	return recorder
+}
+
+// seedOrder stores a restaurant, a table and a pending order for it with one item
+func seedOrder(t *testing.T, subtotal float64) models.Order {
+	t.Helper()
+	ctx := context.Background()
+
+	restaurant, err := Repos.Restaurants.Create(ctx, models.Restaurant{Name: "Test Kitchen", OwnerID: 1, Address: "1 Test Street"})
+	if err != nil {
+		t.Fatalf("creating the restaurant: %v", err)
+	}
+	table, err := Repos.Tables.Create(ctx, models.Table{Name: "T1", RestaurantID: int(restaurant.ID), Capacity: 2, Status: models.TableStatusAvailable})
+	if err != nil {
+		t.Fatalf("creating the table: %v", err)
+	}
+	order, err := Repos.Orders.Create(ctx, models.Order{TableID: table.ID, RestaurantID: restaurant.ID, OrderDate: time.Now(), TotalPrice: subtotal, Status: models.OrderStatusPending})
+	if err != nil {
+		t.Fatalf("creating the order: %v", err)
+	}
+	if _, err := Repos.OrderItems.Create(ctx, models.OrderItem{OrderID: order.ID, FoodID: 1, Quantity: 1, UnitPrice: subtotal, SubTotal: subtotal}); err != nil {
+		t.Fatalf("creating the order item: %v", err)
+	}
+	if order, err = Repos.Orders.Get(ctx, order.ID); err != nil {
+		t.Fatalf("fetching the order: %v", err)
+	}
+	return order
+}
@@ -2,11 +2,14 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 )
 
 func GetMenus() gin.HandlerFunc {
@@ -14,22 +17,19 @@ func GetMenus() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM menus")
+		id, err := parseID(c.Query("restaurant_id"))
 		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus data from database", "details": err.Error()})
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
 			return
 		}
-		defer rows.Close()
-
-		var menus []models.Menu
 
-		for rows.Next() {
-			var menu models.Menu
-			if err := rows.Scan(&menu.ID, &menu.Name, &menu.RestaurantID, &menu.CreatedAt, &menu.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menu data", "details": err.Error()})
-				return
-			}
-			menus = append(menus, menu)
+		menus, err := Repos.Menus.List(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menus data from database", "details": err.Error()})
+			return
+		}
+		if c.Query("tree") == "true" {
+			menus = models.MenuTree(menus)
 		}
 
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Menus fetched successfully", "menus": menus})
@@ -41,15 +41,18 @@ func GetMenu() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("menu_id")
-		if id == "" {
+		id, err := parseID(c.Param("menu_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Menu ID is required"})
 			return
 		}
 
-		var menu models.Menu
-
-		if err := Db.QueryRowContext(ctx, "SELECT * FROM menus WHERE id = $1", id).Scan(&menu.ID, &menu.Name, &menu.RestaurantID, &menu.CreatedAt, &menu.UpdatedAt); err != nil {
+		menu, err := Repos.Menus.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Menu not found with the given ID"})
+				return
+			}
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch the menu", "details": err.Error()})
 			return
 		}
@@ -69,7 +72,17 @@ func CreateMenu() gin.HandlerFunc {
 			return
 		}
 
-		err := Db.QueryRowContext(ctx, "INSERT INTO menus (name, restaurant_id) VALUES ($1, $2) RETURNING id, name, restaurant_id, created_at, updated_at", menu.Name, menu.RestaurantID).Scan(&menu.ID, &menu.Name, &menu.RestaurantID, &menu.CreatedAt, &menu.UpdatedAt)
+		if !validateMenu(c, menu) || !checkSchedule(c, menu.Availability) || !checkStation(ctx, c, menu.StationID, menu.RestaurantID) ||
+			!checkParentMenu(ctx, c, menu) {
+			return
+		}
+
+		// The menu and its schedule are saved together
+		err := UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			var err error
+			menu, err = repos.Menus.Create(ctx, menu)
+			return err
+		})
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create menu in database", "details": err.Error()})
 			return
@@ -84,8 +97,8 @@ func UpdateMenu() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("menu_id")
-		if id == "" {
+		id, err := parseID(c.Param("menu_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Menu ID is required"})
 			return
 		}
@@ -97,19 +110,22 @@ func UpdateMenu() gin.HandlerFunc {
 			return
 		}
 
-		result, err := Db.ExecContext(ctx, "UPDATE menus SET name = $1, restaurant_id = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3", menu.Name, menu.RestaurantID, id)
-		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update menu in database", "details": err.Error()})
+		menu.ID = id
+		if !validateMenu(c, menu) || !checkSchedule(c, menu.Availability) || !checkStation(ctx, c, menu.StationID, menu.RestaurantID) ||
+			!checkParentMenu(ctx, c, menu) {
 			return
 		}
 
-		rowsAffected, err := result.RowsAffected()
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			_, err := repos.Menus.Update(ctx, menu)
+			return err
+		})
 		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve rows affected", "details": err.Error()})
-			return
-		}
-		if rowsAffected == 0 {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No item with the given ID"})
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No item with the given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update menu in database", "details": err.Error()})
 			return
 		}
 
@@ -122,28 +138,56 @@ func DeleteMenu() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("menu_id")
-		if id == "" {
+		id, err := parseID(c.Param("menu_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Menu ID is required"})
 			return
 		}
 
-		result, err := Db.ExecContext(ctx, "DELETE FROM menus WHERE id = $1", id)
-		if err != nil {
+		if err := Repos.Menus.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Menu not found with the given ID"})
+				return
+			}
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete the menu from database", "details": err.Error()})
 			return
 		}
 
-		rowsAffected, err := result.RowsAffected()
-		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve rows affected", "details": err.Error()})
-			return
+		c.IndentedJSON(http.StatusNoContent, gin.H{"message": "Menu deleted successfully"})
+	}
+}
+
+func validateMenu(c *gin.Context, menu models.Menu) bool {
+	if err := validate.Struct(menu); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
 		}
-		if rowsAffected == 0 {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Menu not found with the given ID"})
-			return
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		return false
+	}
+	return true
+}
+
+// checkParentMenu makes sure a menu is nested under a category of the same restaurant and
+// never, however deep, under itself
+func checkParentMenu(ctx context.Context, c *gin.Context, menu models.Menu) bool {
+	for parentID := menu.ParentID; parentID != 0; {
+		if parentID == menu.ID {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "A menu can't be nested under itself or one of its sub-categories", "parent_id": menu.ParentID})
+			return false
 		}
 
-		c.IndentedJSON(http.StatusNoContent, gin.H{"message": "Menu deleted successfully"})
+		parent, err := Repos.Menus.Get(ctx, parentID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch parent menu from database", "details": err.Error()})
+			return false
+		}
+		if err != nil || parent.RestaurantID != menu.RestaurantID {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Parent menu does not belong to this restaurant", "parent_id": menu.ParentID})
+			return false
+		}
+		parentID = parent.ParentID
 	}
+	return true
 }
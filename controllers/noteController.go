@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,26 +17,16 @@ func GetNotes() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("restaurant_id")
-		if id == "" {
+		id, err := parseID(c.Param("restaurant_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
 			return
 		}
-		var notes []models.Note
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM notes WHERE restaurant_id = $1", id)
+		notes, err := Repos.Notes.ListByRestaurant(ctx, id)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notes from database", "details": err.Error()})
 			return
 		}
-		defer rows.Close()
-		for rows.Next() {
-			var note models.Note
-			if err := rows.Scan(&note.ID, &note.Title, &note.Content, &note.Priority, &note.RestaurantID, &note.CreatedAt, &note.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan notes", "details": err.Error()})
-				return
-			}
-			notes = append(notes, note)
-		}
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Notes fetched successfully", "notes": notes})
 	}
 }
@@ -67,13 +59,8 @@ func CreateNote() gin.HandlerFunc {
 			return
 		}
 
-		query := `
-			INSERT INTO notes (title, content, priority, restaurant_id)
-			VALUES ($1, $2, $3, $4)
-			RETURNING id, title, content, priority, restaurant_id, created_at, updated_at
-		`
-
-		if err := Db.QueryRowContext(ctx, query, note.Title, note.Content, note.Priority, note.RestaurantID).Scan(&note.ID, &note.Title, &note.Content, &note.Priority, &note.RestaurantID, &note.CreatedAt, &note.UpdatedAt); err != nil {
+		note, err := Repos.Notes.Create(ctx, note)
+		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create Note in Database", "details": err.Error()})
 			return
 		}
@@ -87,8 +74,8 @@ func UpdateNote() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("note_id")
-		if id == "" {
+		id, err := parseID(c.Param("note_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Note ID is required"})
 			return
 		}
@@ -109,14 +96,13 @@ func UpdateNote() gin.HandlerFunc {
 			return
 		}
 
-		query := `
-			UPDATE notes
-			SET title = $1, content = $2, priority = $3, updated_at = CURRENT_TIMESTAMP
-			WHERE id = $4
-			RETURNING id, title, content, priority, restaurant_id, created_at, updated_at
-		`
-
-		if err := Db.QueryRowContext(ctx, query, note.Title, note.Content, note.Priority, id).Scan(&note.ID, &note.Title, &note.Content, &note.Priority, &note.RestaurantID, &note.CreatedAt, &note.UpdatedAt); err != nil {
+		note.ID = id
+		note, err = Repos.Notes.Update(ctx, note)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No note found with given ID"})
+				return
+			}
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update Note in Database", "details": err.Error()})
 			return
 		}
@@ -130,14 +116,13 @@ func DeleteNote() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("note_id")
-		if id == "" {
+		id, err := parseID(c.Param("note_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Note ID is required"})
 			return
 		}
 
-		query := "DELETE FROM notes WHERE id = $1"
-		if _, err := Db.ExecContext(ctx, query, id); err != nil {
+		if err := Repos.Notes.Delete(ctx, id); err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete Note from Database", "details": err.Error()})
 			return
 		}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"restaurant-management/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func GetNumberSeries() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		series, err := Repos.NumberSeries.List(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch number series from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Number series fetched successfully", "number_series": series})
+	}
+}
+
+// SetNumberSeries sets up how a restaurant numbers invoices or credit notes from now on.
+// Documents already numbered keep their numbers, and a series going back to a period it
+// used before carries on where that period's count left off.
+func SetNumberSeries() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		var series models.NumberSeries
+		if err := c.BindJSON(&series); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for setting up the number series", "details": err.Error()})
+			return
+		}
+		defaults := models.DefaultNumberSeries(series.RestaurantID, series.Kind)
+		if series.Padding == 0 {
+			series.Padding = defaults.Padding
+		}
+		if series.FiscalYearStart == 0 {
+			series.FiscalYearStart = defaults.FiscalYearStart
+		}
+
+		if err := validate.Struct(series); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+
+		series, err := Repos.NumberSeries.Upsert(ctx, series)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to save number series in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Number series saved successfully", "number_series": series})
+	}
+}
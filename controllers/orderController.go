@@ -2,8 +2,12 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"restaurant-management/helpers"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,52 +18,20 @@ func GetOrders() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Query("restaurant_id")
-		if id == "" {
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Please provide the Restaurant ID"})
 			return
 		}
 
-		var orders []models.Order
-		var orderItems []models.OrderItem
-
-		// Order by order_date DESC to get latest orders first
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM orders WHERE restaurant_id = $1 ORDER BY order_date DESC", id)
+		// Latest orders come first, each with its items
+		orders, err := Repos.Orders.List(ctx, id)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders from database", "details": err.Error()})
 			return
 		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var order models.Order
-			if err := rows.Scan(&order.ID, &order.TableID, &order.RestaurantID, &order.OrderDate, &order.TotalPrice, &order.Status, &order.Notes, &order.CreatedAt, &order.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan order data", "details": err.Error()})
-				return
-			}
-			orders = append(orders, order)
-		}
-		rowsOrderItems, err := Db.QueryContext(ctx, "SELECT * FROM orderitems WHERE order_id IN (SELECT id FROM orders WHERE restaurant_id = $1)", id)
-		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order items from database", "details": err.Error()})
-			return
-		}
-		defer rowsOrderItems.Close()
-		for rowsOrderItems.Next() {
-			var orderItem models.OrderItem
-			if err := rowsOrderItems.Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.FoodID, &orderItem.Quantity, &orderItem.UnitPrice, &orderItem.SubTotal, &orderItem.CreatedAt, &orderItem.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan order item data", "details": err.Error()})
-				return
-			}
-			orderItems = append(orderItems, orderItem)
-		}
-
-		for i, order := range orders {
-			for _, orderItem := range orderItems {
-				if order.ID == orderItem.OrderID {
-					orders[i].OrderItems = append(orders[i].OrderItems, orderItem)
-				}
-			}
+		for i := range orders {
+			orders[i].AllergenWarnings = models.AllergenWarnings(orders[i].Allergens, orders[i].OrderItems)
 		}
 
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Orders fetched successfully", "orders": orders})
@@ -71,39 +43,22 @@ func GetOrder() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("order_id")
-		if id == "" {
+		id, err := parseID(c.Param("order_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
 			return
 		}
 
-		var order models.Order
-		var orderItems []models.OrderItem
-		// var totalPrice float64
-
-		if err := Db.QueryRowContext(ctx, "SELECT * FROM orders WHERE id = $1", id).
-			Scan(&order.ID, &order.TableID, &order.RestaurantID, &order.OrderDate, &order.TotalPrice, &order.Status, &order.Notes, &order.CreatedAt, &order.UpdatedAt); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order from database", "details": err.Error()})
-			return
-		}
-
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM orderitems WHERE order_id = $1", id)
+		order, err := Repos.Orders.Get(ctx, id)
 		if err != nil {
-			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Failed to fetch order items related to order: " + id, "details": err.Error()})
-			return
-		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var orderItem models.OrderItem
-			if err := rows.Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.FoodID, &orderItem.Quantity, &orderItem.UnitPrice, &orderItem.SubTotal, &orderItem.CreatedAt, &orderItem.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan the order items where order is " + id, "details": err.Error()})
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No order found with given ID"})
 				return
 			}
-			// totalPrice += orderItem.SubTotal
-			orderItems = append(orderItems, orderItem)
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order from database", "details": err.Error()})
+			return
 		}
-		order.OrderItems = orderItems
+		order.AllergenWarnings = models.AllergenWarnings(order.Allergens, order.OrderItems)
 
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Order fetched successfully", "order": order})
 	}
@@ -121,17 +76,28 @@ func CreateOrder() gin.HandlerFunc {
 			return
 		}
 
-		query := `
-			INSERT INTO orders (table_id, restaurant_id, order_date, status, notes)
-			VALUES ($1, $2, $3, $4, $5)
-			RETURNING id, table_id, restaurant_id, order_date, total_price, status, notes, created_at, updated_at
-		`
-		if err := Db.QueryRowContext(ctx, query, order.TableID, order.RestaurantID, order.OrderDate, order.Status, order.Notes).
-			Scan(&order.ID, &order.TableID, &order.RestaurantID, &order.OrderDate, &order.TotalPrice, &order.Status, &order.Notes, &order.CreatedAt, &order.UpdatedAt); err != nil {
+		// Every order enters the lifecycle as pending
+		order.Status = helpers.OrderStatus(order.Status)
+		if order.Status != models.OrderStatusPending {
+			c.IndentedJSON(http.StatusConflict, gin.H{"error": "New orders must start as pending", "status": order.Status})
+			return
+		}
+		if order.GuestCount < 0 {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Guest count can't be negative"})
+			return
+		}
+		if !checkDeclaredAllergens(c, &order) {
+			return
+		}
+
+		order, err := Repos.Orders.Create(ctx, order)
+		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order in database", "details": err.Error()})
 			return
 		}
 
+		publishKitchenEvent(models.KitchenEvent{Type: models.KitchenEventOrderCreated, RestaurantID: order.RestaurantID, OrderID: order.ID, Status: order.Status})
+
 		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Order created successfully", "order": order})
 	}
 }
@@ -141,8 +107,8 @@ func CreateOrderId() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		var id int
-		if err := Db.QueryRowContext(ctx, "INSERT INTO orders DEFAULT VALUES RETURNING id").Scan(&id); err != nil {
+		id, err := Repos.Orders.CreateBlank(ctx)
+		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to created Order ID", "details": err.Error()})
 			return
 		}
@@ -152,62 +118,105 @@ func CreateOrderId() gin.HandlerFunc {
 }
 
 func UpdateOrder() gin.HandlerFunc {
+	return updateOrder(false)
+}
+
+// updateOrder overwrites an order with the request. Guests, coming through the public customer
+// routes, can't change its status or date, nor move it to another restaurant.
+func updateOrder(guest bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("order_id")
-		if id == "" {
+		id, err := parseID(c.Param("order_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
 			return
 		}
 
 		var order models.Order
-		var orderItems []models.OrderItem
-		var totalPrice float64
-
 		if err := c.BindJSON(&order); err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Please provide correct data to update order", "details": err.Error()})
 			return
 		}
-
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM orderitems WHERE order_id = $1", id)
-		if err != nil {
-			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Failed to fetch order items related to order: " + id, "details": err.Error()})
+		if order.GuestCount < 0 {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Guest count can't be negative"})
+			return
+		}
+		if !checkDeclaredAllergens(c, &order) {
 			return
 		}
-		defer rows.Close()
 
-		for rows.Next() {
-			var orderItem models.OrderItem
-			if err := rows.Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.FoodID, &orderItem.Quantity, &orderItem.UnitPrice, &orderItem.SubTotal, &orderItem.CreatedAt, &orderItem.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan the order items where order is " + id, "details": err.Error()})
-				return
+		var current models.Order
+		var statusChanged bool
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			current, err = lockOrder(ctx, repos, id)
+			if err != nil {
+				return err
+			}
+			if guest {
+				if err := checkGuestUpdate(current, &order); err != nil {
+					return err
+				}
+			}
+
+			// The total always comes from the stored items, never from the request
+			var totalPrice float64
+			for _, orderItem := range current.OrderItems {
+				totalPrice += orderItem.SubTotal
+			}
+			if totalPrice <= 0 {
+				return abortWith(http.StatusInternalServerError, gin.H{"error": "Failed to add total price of the order items where order is " + c.Param("order_id")})
+			}
+
+			// A status change through a full update follows the same lifecycle as UpdateOrderStatus
+			// An update without a status leaves it as it is
+			from := helpers.OrderStatus(current.Status)
+			if order.Status == "" {
+				order.Status = from
+			}
+			statusChanged = order.Status != from
+			if statusChanged {
+				if err := helpers.CanTransitionOrder(from, order.Status); err != nil {
+					return abortWith(http.StatusConflict, gin.H{"error": "Illegal order status transition", "details": err})
+				}
 			}
-			totalPrice += orderItem.SubTotal
-			orderItems = append(orderItems, orderItem)
-		}
 
-		if totalPrice <= 0 {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to add total price of the order items where order is " + id})
+			order.ID = id
+			order.OrderItems = current.OrderItems
+			order.TotalPrice = totalPrice
+			// Declared allergens are kept unless the update lists them again, even as []
+			if order.Allergens == nil {
+				order.Allergens = current.Allergens
+			}
+
+			if order, err = repos.Orders.Update(ctx, order); err != nil {
+				return err
+			}
+
+			if statusChanged {
+				return recordOrderTransition(ctx, c, repos, order, from)
+			}
+			// Service charges depend on the party, so the invoice follows a change of guests or table
+			if order.GuestCount != current.GuestCount || order.TableID != current.TableID {
+				return CreateInvoiceFromOrder(ctx, repos, order)
+			}
+			return nil
+		})
+		if err != nil {
+			respondError(c, err, "Failed to update the order in database")
 			return
 		}
 
-		order.OrderItems = orderItems
-		order.TotalPrice = totalPrice
-
-		query := `
-			UPDATE orders
-			SET table_id = $1, restaurant_id = $2, order_date = $3, status = $4, total_price = $5, notes = $6, updated_at = CURRENT_TIMESTAMP
-			WHERE id = $7
-			RETURNING id, table_id, restaurant_id, order_date, total_price, status, notes, created_at, updated_at
-		`
-		row := Db.QueryRowContext(ctx, query, order.TableID, order.RestaurantID, order.OrderDate, order.Status, order.TotalPrice, order.Notes, id)
-		if err := row.Scan(&order.ID, &order.TableID, &order.RestaurantID, &order.OrderDate, &order.TotalPrice, &order.Status, &order.Notes, &order.CreatedAt, &order.UpdatedAt); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update the order in database", "details": err.Error()})
-			return
+		// Blank orders reach the kitchen once they are attached to a restaurant
+		if current.RestaurantID == 0 && order.RestaurantID != 0 {
+			publishKitchenEvent(models.KitchenEvent{Type: models.KitchenEventOrderCreated, RestaurantID: order.RestaurantID, OrderID: id, Status: order.Status})
+		} else if statusChanged {
+			publishKitchenEvent(models.KitchenEvent{Type: models.KitchenEventStatusChanged, RestaurantID: order.RestaurantID, OrderID: id, Status: order.Status, FromStatus: helpers.OrderStatus(current.Status)})
 		}
 
+		order.AllergenWarnings = models.AllergenWarnings(order.Allergens, order.OrderItems)
+
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Order updated successfully", "order": order})
 	}
 }
@@ -217,62 +226,201 @@ func UpdateOrderStatus() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("order_id")
-		if id == "" {
+		id, err := parseID(c.Param("order_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
 			return
 		}
 
 		var orderStatus models.OrderStatus
-		var order models.Order
 		if err := c.BindJSON(&orderStatus); err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Please provide the correct status to update order status", "details": err.Error()})
 			return
 		}
 
-		query := `
-			UPDATE orders
-			SET status = $1, updated_at = CURRENT_TIMESTAMP
-			WHERE id = $2
-			RETURNING id, table_id, restaurant_id, order_date, total_price, status, notes, created_at, updated_at			
-		`
-		if err := Db.QueryRowContext(ctx, query, orderStatus.Status, id).Scan(&order.ID, &order.TableID, &order.RestaurantID, &order.OrderDate, &order.TotalPrice, &order.Status, &order.Notes, &order.CreatedAt, &order.UpdatedAt); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status in database", "details": err.Error()})
+		var current, order models.Order
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			current, err = lockOrder(ctx, repos, id)
+			if err != nil {
+				return err
+			}
+			order, err = changeOrderStatus(ctx, c, repos, current, orderStatus.Status)
+			return err
+		})
+		if err != nil {
+			respondError(c, err, "Failed to update status in database")
 			return
 		}
 
-		if err := CreateInvoiceFromOrder(order); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invoices for the order in database", "details": err.Error()})
-			return
-		}
+		publishKitchenEvent(models.KitchenEvent{Type: models.KitchenEventStatusChanged, RestaurantID: order.RestaurantID, OrderID: id, Status: order.Status, FromStatus: helpers.OrderStatus(current.Status)})
 
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Order updated successfully", "order_id": id})
 	}
 }
 
-func DeleteOrder() gin.HandlerFunc {
+func GetOrderHistory() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("order_id")
-		if id == "" {
+		id, err := parseID(c.Param("order_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
 			return
 		}
 
-		result, err := Db.ExecContext(ctx, "DELETE FROM orders WHERE id = $1", id)
+		history, err := Repos.Orders.StatusHistory(ctx, id)
 		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete order from database", "details": err.Error()})
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order status history", "details": err.Error()})
 			return
 		}
-		rowsAffected, err := result.RowsAffected()
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Order status history fetched successfully", "order_id": id, "history": history})
+	}
+}
+
+// checkNoMoneyTaken refuses to let an order go once its invoice, or one of its splits, has
+// been paid towards, refunded or credited
+func checkNoMoneyTaken(ctx context.Context, repos repository.Repositories, orderID uint) error {
+	invoice, err := repos.Invoices.GetByOrder(ctx, orderID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fetching the invoice: %w", err)
+	}
+	splits, err := repos.Invoices.ListSplits(ctx, invoice.ID)
+	if err != nil {
+		return fmt.Errorf("fetching the split invoices: %w", err)
+	}
+	for _, invoice := range append([]models.Invoice{invoice}, splits...) {
+		payments, err := repos.Payments.ListByInvoice(ctx, invoice.ID)
+		if err != nil {
+			return fmt.Errorf("fetching the payments: %w", err)
+		}
+		notes, err := repos.CreditNotes.ListByInvoice(ctx, invoice.ID)
 		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve affected rows for orders", "details": err.Error()})
+			return fmt.Errorf("fetching the credit notes: %w", err)
+		}
+		if helpers.ToCents(invoice.AmountPaid) > 0 || len(payments) > 0 || len(notes) > 0 {
+			return abortWith(http.StatusConflict, gin.H{"error": "Orders with payments or credit notes can't be deleted, issue a credit note instead", "order_id": orderID, "invoice_id": invoice.ID})
+		}
+	}
+	return nil
+}
+
+// checkGuestUpdate keeps what guests may not change of an order as it is: the status and date,
+// and the restaurant once the order has one. Only blank orders are attached to a restaurant.
+func checkGuestUpdate(current models.Order, order *models.Order) error {
+	if order.Status != "" && helpers.OrderStatus(order.Status) != helpers.OrderStatus(current.Status) {
+		return abortWith(http.StatusForbidden, gin.H{"error": "Guests can't change the status of an order", "status": helpers.OrderStatus(current.Status)})
+	}
+	if current.RestaurantID != 0 && order.RestaurantID != 0 && order.RestaurantID != current.RestaurantID {
+		return abortWith(http.StatusForbidden, gin.H{"error": "The order already belongs to a restaurant", "restaurant_id": current.RestaurantID})
+	}
+	if current.RestaurantID != 0 {
+		order.RestaurantID = current.RestaurantID
+	}
+	order.Status, order.OrderDate = current.Status, current.OrderDate
+	return nil
+}
+
+// checkDeclaredAllergens makes sure guests declare allergens by the names foods list them
+// under, answering the request when they don't. A missing list stays nil.
+func checkDeclaredAllergens(c *gin.Context, order *models.Order) bool {
+	if unknown := models.UnknownName(order.Allergens, models.Allergens); unknown != "" {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Unknown allergen " + unknown, "allergens": models.Allergens})
+		return false
+	}
+	if order.Allergens != nil {
+		order.Allergens = models.NormalizeAllergens(order.Allergens)
+	}
+	return true
+}
+
+// lockOrder loads the order and holds its row lock for the rest of the unit of work,
+// so concurrent changes to the same order queue up behind each other
+func lockOrder(ctx context.Context, repos repository.Repositories, id uint) (models.Order, error) {
+	order, err := repos.Orders.GetForUpdate(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return order, abortWith(http.StatusNotFound, gin.H{"error": "No order found with given ID", "order_id": id})
+	}
+	return order, err
+}
+
+// changeOrderStatus moves a locked order to a new status if its lifecycle allows it
+func changeOrderStatus(ctx context.Context, c *gin.Context, repos repository.Repositories, current models.Order, to string) (models.Order, error) {
+	from := helpers.OrderStatus(current.Status)
+	if err := helpers.CanTransitionOrder(from, to); err != nil {
+		return current, abortWith(http.StatusConflict, gin.H{"error": "Illegal order status transition", "details": err})
+	}
+
+	order, err := repos.Orders.UpdateStatus(ctx, current.ID, to)
+	if err != nil {
+		return order, err
+	}
+	order.OrderItems = current.OrderItems
+
+	return order, recordOrderTransition(ctx, c, repos, order, from)
+}
+
+// recordOrderTransition writes the history entry for a status change and brings the invoice in line with it
+func recordOrderTransition(ctx context.Context, c *gin.Context, repos repository.Repositories, order models.Order, from string) error {
+	if _, err := repos.Orders.RecordStatusChange(ctx, models.OrderStatusChange{OrderID: order.ID, FromStatus: from, ToStatus: order.Status, ChangedBy: statusActor(c)}); err != nil {
+		return fmt.Errorf("recording the status change: %w", err)
+	}
+	if err := CreateInvoiceFromOrder(ctx, repos, order); err != nil {
+		return fmt.Errorf("creating the invoice for the order: %w", err)
+	}
+	// The invoice is final once the order is paid, so it takes its legal number then
+	if order.Status == models.OrderStatusPaid {
+		if err := issueInvoice(ctx, repos, order.ID); err != nil {
+			return fmt.Errorf("issuing the invoice: %w", err)
+		}
+	}
+	// Coupons used by an order that never gets paid can be used again
+	if order.Status == models.OrderStatusCancelled {
+		return releaseOrderPromotions(ctx, repos, order.ID)
+	}
+	return nil
+}
+
+// statusActor returns the user behind a status change, or nil on the public customer routes
+func statusActor(c *gin.Context) *uint {
+	claims, ok := helpers.GetClaims(c)
+	if !ok {
+		return nil
+	}
+	return &claims.UserID
+}
+
+func DeleteOrder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("order_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
 			return
 		}
-		if rowsAffected == 0 {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No order found with given ID"})
+
+		// Orders money was taken or credited for are kept with their invoice for the books
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			order, err := lockOrder(ctx, repos, id)
+			if err != nil {
+				return err
+			}
+			if order.Status == models.OrderStatusPaid {
+				return abortWith(http.StatusConflict, gin.H{"error": "Paid orders can't be deleted, issue a credit note instead", "order_id": id})
+			}
+			if err := checkNoMoneyTaken(ctx, repos, id); err != nil {
+				return err
+			}
+			return repos.Orders.Delete(ctx, id)
+		})
+		if err != nil {
+			respondError(c, err, "Failed to delete order from database")
 			return
 		}
 
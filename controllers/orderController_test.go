@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"restaurant-management/models"
+	"restaurant-management/repository"
+)
+
+// moveOrder changes an order's status through UpdateOrderStatus
+func moveOrder(t *testing.T, orderID uint, status string) int {
+	t.Helper()
+	path := fmt.Sprintf("/orders-status/%d", orderID)
+	return serve(t, UpdateOrderStatus(), http.MethodPatch, "/orders-status/:order_id", path, models.OrderStatus{Status: status}, nil).Code
+}
+
+func TestGetOrderReturnsItsItems(t *testing.T) {
+	setup(t)
+	order := seedOrder(t, 12.5)
+
+	var fetched struct {
+		Order models.Order `json:"order"`
+	}
+	path := fmt.Sprintf("/orders/%d", order.ID)
+	if recorder := serve(t, GetOrder(), http.MethodGet, "/orders/:order_id", path, nil, &fetched); recorder.Code != http.StatusOK {
+		t.Fatalf("fetching the order answered %d: %s", recorder.Code, recorder.Body)
+	}
+	if fetched.Order.ID != order.ID || len(fetched.Order.OrderItems) != 1 {
+		t.Errorf("fetched order %d with %d items, want order %d with 1", fetched.Order.ID, len(fetched.Order.OrderItems), order.ID)
+	}
+
+	if code := serve(t, GetOrder(), http.MethodGet, "/orders/:order_id", "/orders/999", nil, nil).Code; code != http.StatusNotFound {
+		t.Errorf("fetching an unknown order answered %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestUpdateOrderStatusCreatesTheInvoice(t *testing.T) {
+	setup(t)
+	ctx := context.Background()
+	order := seedOrder(t, 12.5)
+
+	if code := moveOrder(t, order.ID, models.OrderStatusPreparing); code != http.StatusOK {
+		t.Fatalf("moving the order to preparing answered %d", code)
+	}
+
+	invoice, err := Repos.Invoices.GetByOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("the order has no invoice: %v", err)
+	}
+	if invoice.Total != 12.5 || invoice.Status != "pending" {
+		t.Errorf("invoice is %.2f %s, want 12.50 pending", invoice.Total, invoice.Status)
+	}
+	history, err := Repos.Orders.StatusHistory(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("StatusHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].FromStatus != models.OrderStatusPending || history[0].ToStatus != models.OrderStatusPreparing {
+		t.Errorf("history is %+v, want one change from pending to preparing", history)
+	}
+}
+
+func TestUpdateOrderStatusRefusesIllegalTransitions(t *testing.T) {
+	setup(t)
+	ctx := context.Background()
+	order := seedOrder(t, 12.5)
+
+	if code := moveOrder(t, order.ID, models.OrderStatusServed); code != http.StatusConflict {
+		t.Fatalf("moving a pending order to served answered %d, want %d", code, http.StatusConflict)
+	}
+
+	order, err := Repos.Orders.Get(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if order.Status != models.OrderStatusPending {
+		t.Errorf("status is %q, want it left pending", order.Status)
+	}
+	if history, err := Repos.Orders.StatusHistory(ctx, order.ID); err != nil || len(history) != 0 {
+		t.Errorf("history is %+v, %v, want nothing recorded", history, err)
+	}
+}
+
+func TestUpdateOrderStatusRollsBackAnUnpaidOrder(t *testing.T) {
+	setup(t)
+	ctx := context.Background()
+	order := seedOrder(t, 12.5)
+	for _, status := range []string{models.OrderStatusPreparing, models.OrderStatusReady, models.OrderStatusServed} {
+		if code := moveOrder(t, order.ID, status); code != http.StatusOK {
+			t.Fatalf("moving the order to %s answered %d", status, code)
+		}
+	}
+
+	// The invoice refuses to be paid with a balance left, which undoes the status change it came with
+	if code := moveOrder(t, order.ID, models.OrderStatusPaid); code != http.StatusConflict {
+		t.Fatalf("marking an unpaid order paid answered %d, want %d", code, http.StatusConflict)
+	}
+
+	order, err := Repos.Orders.Get(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if order.Status != models.OrderStatusServed {
+		t.Errorf("status is %q, want it left served", order.Status)
+	}
+	history, err := Repos.Orders.StatusHistory(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("StatusHistory: %v", err)
+	}
+	if len(history) != 3 {
+		t.Errorf("history has %d changes, want the 3 that went through", len(history))
+	}
+}
+
+func TestCancellingAnOrderDropsItsInvoice(t *testing.T) {
+	setup(t)
+	order := seedOrder(t, 12.5)
+	for _, status := range []string{models.OrderStatusPreparing, models.OrderStatusCancelled} {
+		if code := moveOrder(t, order.ID, status); code != http.StatusOK {
+			t.Fatalf("moving the order to %s answered %d", status, code)
+		}
+	}
+
+	if _, err := Repos.Invoices.GetByOrder(context.Background(), order.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("the cancelled order still has an invoice: %v", err)
+	}
+}
+
+func TestUpdateOrderKeepsTheStatusWhenNoneIsSent(t *testing.T) {
+	setup(t)
+	order := seedOrder(t, 12.5)
+	if code := moveOrder(t, order.ID, models.OrderStatusPreparing); code != http.StatusOK {
+		t.Fatalf("moving the order to preparing answered %d", code)
+	}
+
+	path := fmt.Sprintf("/orders/%d", order.ID)
+	update := map[string]any{"table_id": order.TableID, "restaurant_id": order.RestaurantID, "guest_count": 2}
+	if recorder := serve(t, UpdateOrder(), http.MethodPatch, "/orders/:order_id", path, update, nil); recorder.Code != http.StatusOK {
+		t.Fatalf("updating the order answered %d: %s", recorder.Code, recorder.Body)
+	}
+
+	order, err := Repos.Orders.Get(context.Background(), order.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if order.Status != models.OrderStatusPreparing || order.GuestCount != 2 {
+		t.Errorf("order is %s for %d guests, want preparing for 2", order.Status, order.GuestCount)
+	}
+}
+
+func TestCustomerUpdateOrderCantChangeTheStatus(t *testing.T) {
+	setup(t)
+	order := seedOrder(t, 12.5)
+
+	path := fmt.Sprintf("/customer/orders/%d", order.ID)
+	update := map[string]any{"table_id": order.TableID, "restaurant_id": order.RestaurantID, "status": models.OrderStatusCancelled}
+	if code := serve(t, CustomerUpdateOrder(), http.MethodPatch, "/customer/orders/:order_id", path, update, nil).Code; code != http.StatusForbidden {
+		t.Fatalf("a guest cancelling the order answered %d, want %d", code, http.StatusForbidden)
+	}
+
+	update = map[string]any{"table_id": order.TableID, "restaurant_id": order.RestaurantID + 1}
+	if code := serve(t, CustomerUpdateOrder(), http.MethodPatch, "/customer/orders/:order_id", path, update, nil).Code; code != http.StatusForbidden {
+		t.Fatalf("a guest moving the order to another restaurant answered %d, want %d", code, http.StatusForbidden)
+	}
+}
+
+func TestDeleteOrderRefusesOrdersWithPayments(t *testing.T) {
+	setup(t)
+	ctx := context.Background()
+	order := seedOrder(t, 12.5)
+	if code := moveOrder(t, order.ID, models.OrderStatusPreparing); code != http.StatusOK {
+		t.Fatalf("moving the order to preparing answered %d", code)
+	}
+	invoice, err := Repos.Invoices.GetByOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("GetByOrder: %v", err)
+	}
+	if _, err := Repos.Payments.Create(ctx, models.Payment{InvoiceID: invoice.ID, Amount: 5, Method: "cash"}); err != nil {
+		t.Fatalf("creating the payment: %v", err)
+	}
+
+	path := fmt.Sprintf("/orders/%d", order.ID)
+	if code := serve(t, DeleteOrder(), http.MethodDelete, "/orders/:order_id", path, nil, nil).Code; code != http.StatusConflict {
+		t.Fatalf("deleting an order with a payment answered %d, want %d", code, http.StatusConflict)
+	}
+	if _, err := Repos.Orders.Get(ctx, order.ID); err != nil {
+		t.Errorf("the order is gone: %v", err)
+	}
+}
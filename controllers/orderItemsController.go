@@ -2,8 +2,12 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"restaurant-management/helpers"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,21 +18,16 @@ func GetOrderItems() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		var orderItems []models.OrderItem
-
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM orderitems")
+		id, err := parseID(c.Query("restaurant_id"))
 		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order items from database", "details": err.Error()})
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Please provide the Restaurant ID"})
 			return
 		}
 
-		for rows.Next() {
-			var orderItem models.OrderItem
-			if err := rows.Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.FoodID, &orderItem.Quantity, &orderItem.UnitPrice, &orderItem.SubTotal, &orderItem.CreatedAt, &orderItem.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan order items", "details": err.Error()})
-				return
-			}
-			orderItems = append(orderItems, orderItem)
+		orderItems, err := Repos.OrderItems.ListByRestaurant(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order items from database", "details": err.Error()})
+			return
 		}
 
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Order items fetched successfully", "order_items": orderItems})
@@ -40,14 +39,14 @@ func GetOrderItem() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("order_item_id")
-		if id == "" {
+		id, err := parseID(c.Param("order_item_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order item ID is required"})
 			return
 		}
 
-		var orderItem models.OrderItem
-		if err := Db.QueryRowContext(ctx, "SELECT * FROM orderitems WHERE id = $1", id).Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.FoodID, &orderItem.Quantity, &orderItem.UnitPrice, &orderItem.SubTotal, &orderItem.CreatedAt, &orderItem.UpdatedAt); err != nil {
+		orderItem, err := Repos.OrderItems.Get(ctx, id)
+		if err != nil {
 			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Order item not found", "details": err.Error()})
 			return
 		}
@@ -57,36 +56,86 @@ func GetOrderItem() gin.HandlerFunc {
 }
 
 func CreateOrderItem() gin.HandlerFunc {
+	return createOrderItem(false)
+}
+
+// createOrderItem adds a food or a combo to an open order. With orderableOnly it must be on
+// the order's restaurant's menu and orderable right now, as guests ordering themselves are held to.
+func createOrderItem(orderableOnly bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
 		var orderItem models.OrderItem
-		var foodPrice float64
-
 		if err := c.BindJSON(&orderItem); err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for creating order item", "details": err.Error()})
 			return
 		}
 
-		if err := Db.QueryRowContext(ctx, "SELECT name, price FROM foods WHERE id = $1", orderItem.FoodID).Scan(&orderItem.FoodName, &foodPrice); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch food in database with the given ID", "food_id": orderItem.FoodID, "details": err.Error()})
-			return
-		}
-
 		if orderItem.Quantity == 0 {
 			orderItem.Quantity = 1
 		}
 
-		orderItem.UnitPrice = foodPrice
-		orderItem.SubTotal = foodPrice * float64(orderItem.Quantity)
+		var food models.Food
+		var combo models.Combo
+		var foods map[uint]models.Food
+		var err error
+		if orderItem.ComboID != 0 {
+			// A combo is one item at its bundle price with what the picks add, its components
+			// are what the kitchen prepares
+			if combo, foods, err = expandCombo(ctx, &orderItem); err != nil {
+				respondError(c, err, "Failed to fetch combo from database")
+				return
+			}
+		} else {
+			food, err = Repos.Foods.Get(ctx, orderItem.FoodID)
+			if err != nil {
+				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch food in database with the given ID", "food_id": orderItem.FoodID, "details": err.Error()})
+				return
+			}
+
+			// The price is the food's, or its variant's, with what the modifiers add or take off
+			selection, err := food.Select(orderItem.VariantID, orderItem.ModifierIDs)
+			if err != nil {
+				c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "The food can't be ordered like this", "food_id": food.ID, "details": err.Error()})
+				return
+			}
+			orderItem.VariantName, orderItem.Modifiers = selection.VariantName, selection.Modifiers
+			orderItem.UnitPrice, orderItem.Components = selection.UnitPrice, nil
+		}
+		orderItem.ParentID, orderItem.SlotName, orderItem.ComboPicks = 0, "", nil
+		orderItem.SubTotal = toFixed(orderItem.UnitPrice*float64(orderItem.Quantity), 2)
 
-		if err := Db.QueryRowContext(ctx, "INSERT INTO orderitems (order_id, food_id, quantity, unit_price, subtotal) VALUES ($1, $2, $3, $4, $5) RETURNING id, order_id, food_id, quantity, unit_price, subtotal, created_at, updated_at", orderItem.OrderID, orderItem.FoodID, orderItem.Quantity, orderItem.UnitPrice, orderItem.SubTotal).Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.FoodID, &orderItem.Quantity, &orderItem.UnitPrice, &orderItem.SubTotal, &orderItem.CreatedAt, &orderItem.UpdatedAt); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order item in database", "details": err.Error()})
+		var order models.Order
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			if order, err = lockOpenOrder(ctx, repos, orderItem.OrderID); err != nil {
+				return err
+			}
+			if orderableOnly {
+				if orderItem.ComboID != 0 {
+					err = checkComboOrderable(ctx, repos, combo, foods, orderItem.Components, order.RestaurantID)
+				} else {
+					err = checkOrderable(ctx, repos, food, order.RestaurantID)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			if orderItem, err = repos.OrderItems.Create(ctx, orderItem); err != nil {
+				return fmt.Errorf("creating the order item: %w", err)
+			}
+			return syncOrderTotal(ctx, repos, orderItem.OrderID)
+		})
+		if err != nil {
+			respondError(c, err, "Failed to create order item in database")
 			return
 		}
 
-		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Order item created successfully", "order_item": orderItem})
+		publishKitchenEvent(models.KitchenEvent{Type: models.KitchenEventItemAdded, RestaurantID: order.RestaurantID, OrderID: order.ID, Status: order.Status, OrderItem: &orderItem})
+
+		// Staff and guests hear right away when the item holds an allergen the guests declared
+		warnings := models.AllergenWarnings(order.Allergens, []models.OrderItem{orderItem})
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Order item created successfully", "order_item": orderItem, "allergen_warnings": warnings})
 	}
 }
 
@@ -95,15 +144,13 @@ func UpdateOrderItem() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("order_item_id")
-		if id == "" {
+		id, err := parseID(c.Param("order_item_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order item ID is required"})
 			return
 		}
 
-		var orderItem models.OrderItem
 		var updateOrderItem models.UpdateOrderItem
-
 		if err := c.BindJSON(&updateOrderItem); err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide the correct Quantity to update order items", "details": err.Error()})
 			return
@@ -114,19 +161,26 @@ func UpdateOrderItem() gin.HandlerFunc {
 			return
 		}
 
-		if err := Db.QueryRowContext(ctx, "SELECT * FROM orderitems WHERE id = $1", id).Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.FoodID, &orderItem.Quantity, &orderItem.UnitPrice, &orderItem.SubTotal, &orderItem.CreatedAt, &orderItem.UpdatedAt); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed fetch order item in database with the given ID", "details": err.Error()})
-			return
-		}
+		var orderItem models.OrderItem
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			current, err := lockItemOrder(ctx, repos, id)
+			if err != nil {
+				return err
+			}
+			if err := checkNotComponent(current); err != nil {
+				return err
+			}
 
-		orderItem.SubTotal = orderItem.UnitPrice * float64(updateOrderItem.Quantity)
+			// The components of a combo follow its quantity
+			subTotal := toFixed(current.UnitPrice*float64(updateOrderItem.Quantity), 2)
 
-		err := Db.QueryRowContext(ctx,
-			"UPDATE orderitems SET quantity = $1, subtotal = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3 RETURNING id, order_id, food_id, quantity, unit_price, subtotal, created_at, updated_at",
-			updateOrderItem.Quantity, orderItem.SubTotal, id,
-		).Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.FoodID, &orderItem.Quantity, &orderItem.UnitPrice, &orderItem.SubTotal, &orderItem.CreatedAt, &orderItem.UpdatedAt)
+			if orderItem, err = repos.OrderItems.UpdateQuantity(ctx, id, updateOrderItem.Quantity, subTotal); err != nil {
+				return fmt.Errorf("updating the order item: %w", err)
+			}
+			return syncOrderTotal(ctx, repos, orderItem.OrderID)
+		})
 		if err != nil {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No order item found with the given ID or failed to update", "details": err.Error(), "order_item_id": id})
+			respondError(c, err, "Failed to update order item in database")
 			return
 		}
 
@@ -139,30 +193,75 @@ func DeleteOrderItem() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("order_item_id")
-		if id == "" {
+		id, err := parseID(c.Param("order_item_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order item ID is required"})
 			return
 		}
 
-		result, err := Db.ExecContext(ctx, "DELETE FROM orderitems WHERE id = $1", id)
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			current, err := lockItemOrder(ctx, repos, id)
+			if err != nil {
+				return err
+			}
+			if err := checkNotComponent(current); err != nil {
+				return err
+			}
+			if err := repos.OrderItems.Delete(ctx, id); err != nil {
+				return fmt.Errorf("deleting the order item: %w", err)
+			}
+			return syncOrderTotal(ctx, repos, current.OrderID)
+		})
 		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete order item from database", "details": err.Error()})
+			respondError(c, err, "Failed to delete order item from database")
 			return
 		}
 
-		rowsAffected, err := result.RowsAffected()
-		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve affected rows", "details": err.Error()})
-			return
-		}
-		if rowsAffected == 0 {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No order item found with the given ID", "order_item_id": id})
-			return
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Order item deleted successfully", "order_item_id": id})
+	}
+}
+
+// lockOpenOrder locks the order an item change belongs to and refuses orders that are already settled
+func lockOpenOrder(ctx context.Context, repos repository.Repositories, orderID uint) (models.Order, error) {
+	order, err := lockOrder(ctx, repos, orderID)
+	if err != nil {
+		return order, err
+	}
+	switch helpers.OrderStatus(order.Status) {
+	case models.OrderStatusPaid, models.OrderStatusCancelled:
+		return order, abortWith(http.StatusConflict, gin.H{"error": "Items of a " + order.Status + " order can't be changed", "order_id": orderID})
+	}
+	return order, nil
+}
+
+// lockItemOrder loads an order item and locks its order before the item is changed
+func lockItemOrder(ctx context.Context, repos repository.Repositories, id uint) (models.OrderItem, error) {
+	item, err := repos.OrderItems.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return item, abortWith(http.StatusNotFound, gin.H{"error": "No order item found with the given ID", "order_item_id": id})
 		}
+		return item, fmt.Errorf("fetching the order item: %w", err)
+	}
+	_, err = lockOpenOrder(ctx, repos, item.OrderID)
+	return item, err
+}
 
-		c.IndentedJSON(http.StatusOK, gin.H{"message": "Order item deleted successfully", "order_item_id": id})
+// checkNotComponent refuses changes to a component of a combo, which is changed or removed with its combo
+func checkNotComponent(item models.OrderItem) error {
+	if item.ParentID != 0 {
+		return abortWith(http.StatusConflict, gin.H{"error": "Components of a combo change with the combo", "order_item_id": item.ID, "parent_id": item.ParentID})
+	}
+	return nil
+}
+
+// syncOrderTotal recomputes the order total and discounts from its items and carries them over to the invoice
+func syncOrderTotal(ctx context.Context, repos repository.Repositories, orderID uint) error {
+	if _, err := repos.Orders.RecalculateTotal(ctx, orderID); err != nil {
+		return fmt.Errorf("recalculating the order total: %w", err)
 	}
+	_, err := applyDiscounts(ctx, repos, orderID)
+	return err
 }
 
 func GetOrderItemsByOrder() gin.HandlerFunc {
@@ -170,29 +269,18 @@ func GetOrderItemsByOrder() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("order_id")
-		if id == "" {
+		id, err := parseID(c.Param("order_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
 			return
 		}
 
-		var orderItems []models.OrderItem
-
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM orderitems WHERE order_id = $1", id)
+		orderItems, err := Repos.OrderItems.ListByOrder(ctx, id)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order items", "details": err.Error()})
 			return
 		}
 
-		for rows.Next() {
-			var orderItem models.OrderItem
-			if err := rows.Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.FoodID, &orderItem.Quantity, &orderItem.UnitPrice, &orderItem.SubTotal, &orderItem.CreatedAt, &orderItem.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan order items", "details": err.Error()})
-				return
-			}
-			orderItems = append(orderItems, orderItem)
-		}
-
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Order items by order_id fetched successfully", "order_id": id, "order_items": orderItems})
 	}
 }
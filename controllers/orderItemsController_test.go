@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"restaurant-management/models"
+)
+
+// seedFood stores a food of the order's restaurant
+func seedFood(t *testing.T, order models.Order, price float64) models.Food {
+	t.Helper()
+	food, err := Repos.Foods.Create(context.Background(), models.Food{Name: "Bread", Price: price, MenuID: 1, RestaurantID: order.RestaurantID})
+	if err != nil {
+		t.Fatalf("creating the food: %v", err)
+	}
+	return food
+}
+
+func TestCreateOrderItemUpdatesTheOrderTotal(t *testing.T) {
+	setup(t)
+	order := seedOrder(t, 12.5)
+	food := seedFood(t, order, 4)
+
+	item := models.OrderItem{OrderID: order.ID, FoodID: food.ID, Quantity: 2}
+	if recorder := serve(t, CreateOrderItem(), http.MethodPost, "/order-items", "/order-items", item, nil); recorder.Code != http.StatusCreated {
+		t.Fatalf("adding the item answered %d: %s", recorder.Code, recorder.Body)
+	}
+
+	order, err := Repos.Orders.Get(context.Background(), order.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if order.TotalPrice != 20.5 || len(order.OrderItems) != 2 {
+		t.Errorf("the order totals %.2f over %d items, want 20.50 over 2", order.TotalPrice, len(order.OrderItems))
+	}
+}
+
+func TestCreateOrderItemRefusesClosedOrders(t *testing.T) {
+	setup(t)
+	order := seedOrder(t, 12.5)
+	food := seedFood(t, order, 4)
+	if code := moveOrder(t, order.ID, models.OrderStatusCancelled); code != http.StatusOK {
+		t.Fatalf("cancelling the order answered %d", code)
+	}
+
+	item := models.OrderItem{OrderID: order.ID, FoodID: food.ID, Quantity: 1}
+	if code := serve(t, CreateOrderItem(), http.MethodPost, "/order-items", "/order-items", item, nil).Code; code != http.StatusConflict {
+		t.Fatalf("adding to a cancelled order answered %d, want %d", code, http.StatusConflict)
+	}
+
+	items, err := Repos.OrderItems.ListByOrder(context.Background(), order.ID)
+	if err != nil {
+		t.Fatalf("ListByOrder: %v", err)
+	}
+	if len(items) != 1 {
+		t.Errorf("the cancelled order has %d items, want the 1 it had", len(items))
+	}
+}
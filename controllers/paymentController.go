@@ -0,0 +1,375 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func GetPayments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("invoice_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+			return
+		}
+
+		payments, err := Repos.Payments.ListByInvoice(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payments from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Payments fetched successfully", "invoice_id": id, "payments": payments})
+	}
+}
+
+// GetTipReport totals the tips of a restaurant per staff member, over an optional range
+// of days from and to (YYYY-MM-DD, UTC, both included)
+func GetTipReport() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		from, to, ok := reportPeriod(c)
+		if !ok {
+			return
+		}
+
+		tips, err := Repos.Payments.TipsByStaff(ctx, id, from, to)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tips from database", "details": err.Error()})
+			return
+		}
+
+		var total int64
+		for _, summary := range tips {
+			total += helpers.ToCents(summary.Tips)
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Tips fetched successfully", "restaurant_id": id, "total": helpers.FromCents(total), "staff": tips})
+	}
+}
+
+// reportPeriod reads the days a report covers from the from and to query parameters, both
+// YYYY-MM-DD and included, as the bounds [from, to) with zero for an open one. It answers the
+// request when either is malformed.
+func reportPeriod(c *gin.Context) (time.Time, time.Time, bool) {
+	var from, to time.Time
+	var err error
+	if day := c.Query("from"); day != "" {
+		if from, err = time.Parse(time.DateOnly, day); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "From must be formatted as YYYY-MM-DD", "details": err.Error()})
+			return from, to, false
+		}
+	}
+	if day := c.Query("to"); day != "" {
+		if to, err = time.Parse(time.DateOnly, day); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "To must be formatted as YYYY-MM-DD", "details": err.Error()})
+			return from, to, false
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to, true
+}
+
+// CreatePayment takes a payment against an invoice or one part of a split check, with an
+// optional tip on top. The order is marked paid as soon as its balance reaches zero, if it
+// has been served.
+func CreatePayment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("invoice_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+			return
+		}
+
+		var payment models.Payment
+		if err := c.BindJSON(&payment); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for taking a payment", "details": err.Error()})
+			return
+		}
+
+		if err := validate.Struct(payment); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+		payment.InvoiceID, payment.RecordedBy = id, statusActor(c)
+
+		var current, order models.Order
+		var invoice models.Invoice
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			invoice, current, err = lockInvoiceOrder(ctx, repos, id)
+			if err != nil {
+				return err
+			}
+			if err := checkPayable(invoice, payment.Amount); err != nil {
+				return err
+			}
+			if payment.TipStaffID, err = tipStaff(ctx, repos, invoice.RestaurantID, payment.TipStaffID, payment.RecordedBy); err != nil {
+				return err
+			}
+			payment, invoice, order, err = takePayment(ctx, c, repos, invoice, current, payment)
+			return err
+		})
+		if err != nil {
+			respondError(c, err, "Failed to take the payment")
+			return
+		}
+
+		publishOrderPaid(current, order)
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Payment taken successfully", "payment": payment, "invoice": invoice})
+	}
+}
+
+// SplitInvoice splits an order's invoice into parts paid separately, evenly or by items.
+// Splitting again replaces the parts, as long as nothing has been paid yet.
+func SplitInvoice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("invoice_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+			return
+		}
+
+		var split models.SplitInvoice
+		if err := c.BindJSON(&split); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for splitting the invoice", "details": err.Error()})
+			return
+		}
+
+		if err := validate.Struct(split); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+		if split.Mode == models.SplitEvenly && split.Parts == 0 {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Splitting evenly needs the number of parts"})
+			return
+		}
+		if split.Mode == models.SplitByItems && len(split.Groups) == 0 {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Splitting by items needs the groups of order item IDs"})
+			return
+		}
+
+		var invoice models.Invoice
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			var order models.Order
+			invoice, order, err = lockSplittableInvoice(ctx, repos, id)
+			if err != nil {
+				return err
+			}
+
+			var splits []models.Invoice
+			switch split.Mode {
+			case models.SplitEvenly:
+				splits = helpers.SplitEvenly(invoice, split.Parts)
+			case models.SplitByItems:
+				discounts, err := repos.Discounts.ListByOrder(ctx, order.ID)
+				if err != nil {
+					return fmt.Errorf("fetching discounts: %w", err)
+				}
+				perItem := helpers.ComputeDiscounts(order.OrderItems, discounts).PerItem
+				if splits, err = helpers.SplitByItems(invoice, order.OrderItems, perItem, split.Groups); err != nil {
+					return abortWith(http.StatusBadRequest, gin.H{"error": "Every order item must be in exactly one group", "details": err.Error()})
+				}
+			}
+
+			if invoice.Splits, err = repos.Invoices.ReplaceSplits(ctx, invoice.ID, split.Mode, splits); err != nil {
+				return fmt.Errorf("splitting the invoice: %w", err)
+			}
+			invoice.SplitMode = split.Mode
+			return nil
+		})
+		if err != nil {
+			respondError(c, err, "Failed to split the invoice")
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Invoice split successfully", "invoice": invoice})
+	}
+}
+
+// MergeInvoice undoes a split before any part has been paid
+func MergeInvoice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("invoice_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+			return
+		}
+
+		var invoice models.Invoice
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			invoice, _, err = lockSplittableInvoice(ctx, repos, id)
+			if err != nil {
+				return err
+			}
+			if _, err := repos.Invoices.ReplaceSplits(ctx, invoice.ID, "", nil); err != nil {
+				return fmt.Errorf("merging the invoice: %w", err)
+			}
+			invoice.SplitMode, invoice.Splits = "", nil
+			return nil
+		})
+		if err != nil {
+			respondError(c, err, "Failed to merge the invoice")
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Invoice merged successfully", "invoice": invoice})
+	}
+}
+
+// lockInvoiceOrder locks the order of an invoice and loads the invoice under that lock,
+// so payments and splits of the same check queue up behind each other and behind order changes
+func lockInvoiceOrder(ctx context.Context, repos repository.Repositories, id uint) (models.Invoice, models.Order, error) {
+	invoice, err := repos.Invoices.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return invoice, models.Order{}, abortWith(http.StatusNotFound, gin.H{"error": "No invoice found with given ID", "invoice_id": id})
+	}
+	if err != nil {
+		return invoice, models.Order{}, fmt.Errorf("fetching the invoice: %w", err)
+	}
+
+	order, err := lockOrder(ctx, repos, invoice.OrderID)
+	if err != nil {
+		return invoice, order, err
+	}
+
+	// The invoice goes away when its order is cancelled while waiting for the lock
+	invoice, err = repos.Invoices.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return invoice, order, abortWith(http.StatusNotFound, gin.H{"error": "No invoice found with given ID", "invoice_id": id})
+	}
+	return invoice, order, err
+}
+
+// lockSplittableInvoice locks an order's invoice that may still be split or merged
+func lockSplittableInvoice(ctx context.Context, repos repository.Repositories, id uint) (models.Invoice, models.Order, error) {
+	invoice, order, err := lockInvoiceOrder(ctx, repos, id)
+	if err != nil {
+		return invoice, order, err
+	}
+	if invoice.ParentID != 0 {
+		return invoice, order, abortWith(http.StatusConflict, gin.H{"error": "Only an order's invoice can be split, not one of its parts", "parent_id": invoice.ParentID})
+	}
+	if invoice.AmountPaid > 0 {
+		return invoice, order, abortWith(http.StatusConflict, gin.H{"error": "Payments have already been taken on the invoice", "amount_paid": invoice.AmountPaid})
+	}
+	return invoice, order, nil
+}
+
+// checkPayable refuses payments a check can't take: against a split check, a paid invoice or beyond the balance
+func checkPayable(invoice models.Invoice, amount float64) error {
+	switch {
+	case invoice.SplitMode != "":
+		return abortWith(http.StatusConflict, gin.H{"error": "The check is split, take payments against its parts", "splits": invoice.Splits})
+	case invoice.Status == models.InvoiceStatusPaid:
+		return abortWith(http.StatusConflict, gin.H{"error": "The invoice is already paid", "invoice_id": invoice.ID})
+	case helpers.ToCents(amount) > helpers.ToCents(invoice.Balance):
+		return abortWith(http.StatusBadRequest, gin.H{"error": "The payment is more than the balance", "balance": invoice.Balance})
+	}
+	return nil
+}
+
+// tipStaff returns who a tip is credited to, the staff member named if they work at the
+// restaurant, or else whoever takes the payment
+func tipStaff(ctx context.Context, repos repository.Repositories, restaurantID uint, named, taker *uint) (*uint, error) {
+	if named == nil {
+		return taker, nil
+	}
+	memberships, err := repos.Restaurants.Memberships(ctx, *named)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the staff member's restaurants: %w", err)
+	}
+	for _, membership := range memberships {
+		if membership.RestaurantID == restaurantID {
+			return named, nil
+		}
+	}
+	return nil, abortWith(http.StatusBadRequest, gin.H{"error": "Tips can only go to staff of the restaurant", "tip_staff_id": *named})
+}
+
+// takePayment records a payment against a locked invoice and marks its served order paid
+// once the balance reaches zero. It returns the stored payment, the order's invoice with
+// its parts and balance, and the order as it is now.
+func takePayment(ctx context.Context, c *gin.Context, repos repository.Repositories, invoice models.Invoice, current models.Order, payment models.Payment) (models.Payment, models.Invoice, models.Order, error) {
+	order := current
+	payment, err := repos.Payments.Create(ctx, payment)
+	if err != nil {
+		return payment, invoice, order, fmt.Errorf("recording the payment: %w", err)
+	}
+	if invoice, err = addPayment(ctx, repos, invoice, payment); err != nil {
+		return payment, invoice, order, err
+	}
+
+	if invoice.Status == models.InvoiceStatusPaid && helpers.CanTransitionOrder(current.Status, models.OrderStatusPaid) == nil {
+		if order, err = changeOrderStatus(ctx, c, repos, current, models.OrderStatusPaid); err != nil {
+			return payment, invoice, order, err
+		}
+	}
+
+	invoice, err = repos.Invoices.Get(ctx, invoice.ID)
+	return payment, invoice, order, err
+}
+
+// publishOrderPaid tells the kitchen about an order a payment marked paid, once the payment is committed
+func publishOrderPaid(current, order models.Order) {
+	if order.Status != current.Status {
+		publishKitchenEvent(models.KitchenEvent{Type: models.KitchenEventStatusChanged, RestaurantID: order.RestaurantID, OrderID: order.ID, Status: order.Status, FromStatus: helpers.OrderStatus(current.Status)})
+	}
+}
+
+// addPayment adds a payment and its tip to what has been paid of its invoice and, for a part
+// of a split check, of the order's invoice. It returns the order's invoice.
+func addPayment(ctx context.Context, repos repository.Repositories, invoice models.Invoice, payment models.Payment) (models.Invoice, error) {
+	paid := helpers.FromCents(helpers.ToCents(invoice.AmountPaid) + helpers.ToCents(payment.Amount))
+	tip := helpers.FromCents(helpers.ToCents(invoice.Tip) + helpers.ToCents(payment.Tip))
+	updated, err := repos.Invoices.UpdatePayment(ctx, invoice.ID, paid, tip, invoiceStatus(invoice.Total, paid), payment.Method)
+	if err != nil {
+		return updated, fmt.Errorf("updating the invoice balance: %w", err)
+	}
+	if invoice.ParentID == 0 {
+		return updated, nil
+	}
+
+	parent, err := repos.Invoices.Get(ctx, invoice.ParentID)
+	if err != nil {
+		return parent, fmt.Errorf("fetching the split invoice: %w", err)
+	}
+	return addPayment(ctx, repos, parent, payment)
+}
@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func GetPromotions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		promotions, err := Repos.Promotions.List(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch promotions from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Promotions fetched successfully", "promotions": promotions})
+	}
+}
+
+func GetPromotion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("promotion_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Promotion ID is required"})
+			return
+		}
+
+		promotion, err := Repos.Promotions.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No promotion found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch promotion from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Promotion fetched successfully", "promotion": promotion})
+	}
+}
+
+func CreatePromotion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		var promotion models.Promotion
+		if err := c.BindJSON(&promotion); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for creating promotion", "details": err.Error()})
+			return
+		}
+
+		if !checkPromotion(ctx, c, &promotion) {
+			return
+		}
+
+		promotion, err := Repos.Promotions.Create(ctx, promotion)
+		if err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.IndentedJSON(http.StatusConflict, gin.H{"error": "The restaurant already has a coupon with this code"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create promotion in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Promotion created successfully", "promotion": promotion})
+	}
+}
+
+func UpdatePromotion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("promotion_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Promotion ID is required"})
+			return
+		}
+
+		var promotion models.Promotion
+		if err := c.BindJSON(&promotion); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for updating promotion", "details": err.Error()})
+			return
+		}
+
+		// Promotions stay with their restaurant, orders they were applied to keep their copy of the rule
+		current, err := Repos.Promotions.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No promotion found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch promotion from database", "details": err.Error()})
+			return
+		}
+		promotion.ID, promotion.RestaurantID = id, current.RestaurantID
+
+		if !checkPromotion(ctx, c, &promotion) {
+			return
+		}
+
+		promotion, err = Repos.Promotions.Update(ctx, promotion)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No promotion found with given ID"})
+				return
+			}
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.IndentedJSON(http.StatusConflict, gin.H{"error": "The restaurant already has a coupon with this code"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update promotion in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Promotion updated successfully", "promotion": promotion})
+	}
+}
+
+func DeletePromotion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("promotion_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Promotion ID is required"})
+			return
+		}
+
+		if err := Repos.Promotions.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No promotion found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete promotion from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Promotion deleted successfully", "promotion_id": id})
+	}
+}
+
+// checkPromotion validates a promotion, normalises its code, scope and window, and makes
+// sure a scoped promotion points at a menu or food of its own restaurant
+func checkPromotion(ctx context.Context, c *gin.Context, promotion *models.Promotion) bool {
+	if err := validate.Struct(promotion); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+		}
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		return false
+	}
+
+	promotion.Code = strings.ToUpper(strings.TrimSpace(promotion.Code))
+	if promotion.Scope == "" {
+		promotion.Scope = models.DiscountScopeOrder
+	}
+	if promotion.StartsAt != nil {
+		startsAt := promotion.StartsAt.UTC()
+		promotion.StartsAt = &startsAt
+	}
+	if promotion.EndsAt != nil {
+		endsAt := promotion.EndsAt.UTC()
+		promotion.EndsAt = &endsAt
+	}
+
+	var problem string
+	switch {
+	case promotion.Kind == models.DiscountPercentage && (promotion.Value <= 0 || promotion.Value > 100):
+		problem = "Percentage promotions need a value above 0 and up to 100"
+	case promotion.Kind == models.DiscountFixed && promotion.Value <= 0:
+		problem = "Fixed promotions need a value above 0"
+	case promotion.Kind == models.DiscountBuyXGetY && (promotion.BuyQuantity < 1 || promotion.GetQuantity < 1):
+		problem = "Buy X get Y promotions need a buy_quantity and get_quantity of at least 1"
+	case promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt):
+		problem = "Promotions must end after they start"
+	}
+	if problem != "" {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": problem})
+		return false
+	}
+
+	// Only the ID of the promotion's own scope is kept
+	switch promotion.Scope {
+	case models.DiscountScopeMenu:
+		promotion.FoodID = 0
+		menu, err := Repos.Menus.Get(ctx, promotion.MenuID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch menu from database", "details": err.Error()})
+			return false
+		}
+		if err != nil || menu.RestaurantID != promotion.RestaurantID {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Menu does not belong to this restaurant", "menu_id": promotion.MenuID})
+			return false
+		}
+	case models.DiscountScopeFood:
+		promotion.MenuID = 0
+		food, err := Repos.Foods.Get(ctx, promotion.FoodID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch food from database", "details": err.Error()})
+			return false
+		}
+		if err != nil || food.RestaurantID != promotion.RestaurantID {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Food does not belong to this restaurant", "food_id": promotion.FoodID})
+			return false
+		}
+	default:
+		promotion.MenuID, promotion.FoodID = 0, 0
+	}
+	return true
+}
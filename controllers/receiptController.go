@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/printer"
+	"restaurant-management/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unassignedStation names the kitchen ticket of items not routed to any station
+const unassignedStation = "Kitchen"
+
+// GetReceipt returns an invoice as raw ESC/POS bytes for a thermal printer the client drives itself
+func GetReceipt() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		receipt, _, ok := loadReceipt(ctx, c)
+		if !ok {
+			return
+		}
+
+		c.Header("Content-Disposition", "attachment; filename=receipt-"+c.Param("invoice_id")+".bin")
+		c.Data(http.StatusOK, "application/octet-stream", receipt)
+	}
+}
+
+// PrintReceipt pushes an invoice's receipt to the restaurant's receipt printer
+func PrintReceipt() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		receipt, template, ok := loadReceipt(ctx, c)
+		if !ok {
+			return
+		}
+		if template.ReceiptPrinter == "" {
+			c.IndentedJSON(http.StatusConflict, gin.H{"error": "The restaurant has no receipt printer set up"})
+			return
+		}
+
+		if err := printer.Send(ctx, template.ReceiptPrinter, receipt); err != nil {
+			c.IndentedJSON(http.StatusBadGateway, gin.H{"error": "The receipt could not be printed", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Receipt printed successfully", "printer": template.ReceiptPrinter})
+	}
+}
+
+// loadReceipt renders the receipt of the invoice named in the path along with the template
+// it was rendered with, answering the request when it can't
+func loadReceipt(ctx context.Context, c *gin.Context) ([]byte, models.InvoiceTemplate, bool) {
+	id, err := parseID(c.Param("invoice_id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+		return nil, models.InvoiceTemplate{}, false
+	}
+
+	invoice, err := Repos.Invoices.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+			return nil, models.InvoiceTemplate{}, false
+		}
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice", "details": err.Error()})
+		return nil, models.InvoiceTemplate{}, false
+	}
+	order, err := Repos.Orders.Get(ctx, invoice.OrderID)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order", "details": err.Error()})
+		return nil, models.InvoiceTemplate{}, false
+	}
+	restaurant, err := Repos.Restaurants.Get(ctx, invoice.RestaurantID)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant", "details": err.Error()})
+		return nil, models.InvoiceTemplate{}, false
+	}
+	template, err := Repos.Templates.Get(ctx, restaurant.ID)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice template", "details": err.Error()})
+		return nil, models.InvoiceTemplate{}, false
+	}
+
+	return helpers.GenerateReceipt(invoice, order, restaurant, template), template, true
+}
+
+// GetKitchenTickets returns an order's kitchen tickets as raw ESC/POS bytes, one ticket cut
+// off per station, or only the ticket of ?station_id=
+func GetKitchenTickets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		tickets, ok := loadKitchenTickets(ctx, c)
+		if !ok {
+			return
+		}
+
+		var job []byte
+		for _, ticket := range tickets {
+			job = append(job, ticket.job...)
+		}
+
+		c.Header("Content-Disposition", "attachment; filename=kitchen-tickets-"+c.Param("order_id")+".bin")
+		c.Data(http.StatusOK, "application/octet-stream", job)
+	}
+}
+
+// PrintKitchenTickets pushes each of an order's kitchen tickets to its station's printer.
+// Stations without a printer of their own print on the restaurant's receipt printer, and
+// their tickets are skipped when it has none either. A printer that can't be reached
+// doesn't stop the other tickets from printing.
+func PrintKitchenTickets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		tickets, ok := loadKitchenTickets(ctx, c)
+		if !ok {
+			return
+		}
+
+		prints := make([]models.KitchenTicketPrint, 0, len(tickets))
+		failed := false
+		for _, ticket := range tickets {
+			result := models.KitchenTicketPrint{StationID: ticket.stationID, Station: ticket.station, Printer: ticket.printer, Items: ticket.items}
+			if ticket.printer == "" {
+				result.Error = "no printer set up for this station or the restaurant"
+			} else if err := printer.Send(ctx, ticket.printer, ticket.job); err != nil {
+				result.Error, failed = err.Error(), true
+			} else {
+				result.Printed = true
+			}
+			prints = append(prints, result)
+		}
+
+		if failed {
+			c.IndentedJSON(http.StatusBadGateway, gin.H{"error": "Some kitchen tickets could not be printed", "prints": prints})
+			return
+		}
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Kitchen tickets printed", "prints": prints})
+	}
+}
+
+// kitchenTicket is one station's ticket for an order and the printer it goes to
+type kitchenTicket struct {
+	stationID uint
+	station   string
+	printer   string
+	items     int
+	job       []byte
+}
+
+// loadKitchenTickets renders the tickets of the order named in the path, one per station in the
+// order of the restaurant's stations and items not routed to any station last, answering the
+// request when it can't
+func loadKitchenTickets(ctx context.Context, c *gin.Context) ([]kitchenTicket, bool) {
+	id, err := parseID(c.Param("order_id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
+		return nil, false
+	}
+	var stationID uint
+	if raw := c.Query("station_id"); raw != "" {
+		if stationID, err = parseID(raw); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid station ID"})
+			return nil, false
+		}
+	}
+
+	order, err := Repos.Orders.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+			return nil, false
+		}
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order", "details": err.Error()})
+		return nil, false
+	}
+	items, err := Repos.OrderItems.ListTicketsByOrder(ctx, order.ID)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch order items", "details": err.Error()})
+		return nil, false
+	}
+	stations, err := Repos.Stations.List(ctx, order.RestaurantID)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stations", "details": err.Error()})
+		return nil, false
+	}
+	template, err := Repos.Templates.Get(ctx, order.RestaurantID)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoice template", "details": err.Error()})
+		return nil, false
+	}
+
+	table := ""
+	if order.TableID != 0 {
+		table = "Table #" + strconv.FormatUint(uint64(order.TableID), 10)
+		if found, err := Repos.Tables.Get(ctx, order.TableID); err == nil {
+			table = "Table " + found.Name
+		}
+	}
+
+	byStation := map[uint][]models.StationTicket{}
+	for _, item := range items {
+		if stationID == 0 || item.StationID == stationID {
+			byStation[item.StationID] = append(byStation[item.StationID], item)
+		}
+	}
+
+	printedAt := time.Now()
+	tickets := []kitchenTicket{}
+	addTicket := func(id uint, name, address string) {
+		items := byStation[id]
+		if len(items) == 0 {
+			return
+		}
+		if address == "" {
+			address = template.ReceiptPrinter
+		}
+		tickets = append(tickets, kitchenTicket{
+			stationID: id,
+			station:   name,
+			printer:   address,
+			items:     len(items),
+			job:       helpers.GenerateKitchenTicket(name, table, order, items, printedAt),
+		})
+	}
+	for _, station := range stations {
+		addTicket(station.ID, station.Name, station.PrinterAddress)
+	}
+	addTicket(0, unassignedStation, "")
+
+	if len(tickets) == 0 {
+		message := "The order has no items to print"
+		if stationID != 0 {
+			message = fmt.Sprintf("The order has no items for station %d", stationID)
+		}
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": message})
+		return nil, false
+	}
+	return tickets, true
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"restaurant-management/models"
+)
+
+// listenPrinter stands in for a network printer, handing back what a connection sent once it closes
+func listenPrinter(t *testing.T) (string, <-chan []byte) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	jobs := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		job, _ := io.ReadAll(conn)
+		jobs <- job
+	}()
+	return listener.Addr().String(), jobs
+}
+
+func TestPrintReceiptPushesTheReceiptToThePrinter(t *testing.T) {
+	setup(t)
+	invoice := invoicedOrder(t)
+	address, jobs := listenPrinter(t)
+	if _, err := Repos.Templates.Upsert(context.Background(), models.InvoiceTemplate{RestaurantID: invoice.RestaurantID, ReceiptPrinter: address}); err != nil {
+		t.Fatalf("setting the printer up: %v", err)
+	}
+
+	path := fmt.Sprintf("/invoice-receipt/%d", invoice.ID)
+	receipt := serve(t, GetReceipt(), http.MethodGet, "/invoice-receipt/:invoice_id", path, nil, nil)
+	if receipt.Code != http.StatusOK || receipt.Body.Len() == 0 {
+		t.Fatalf("fetching the receipt answered %d with %d bytes", receipt.Code, receipt.Body.Len())
+	}
+	if recorder := serve(t, PrintReceipt(), http.MethodPost, "/invoice-receipt/:invoice_id/print", path+"/print", nil, nil); recorder.Code != http.StatusOK {
+		t.Fatalf("printing the receipt answered %d: %s", recorder.Code, recorder.Body)
+	}
+
+	select {
+	case job := <-jobs:
+		if !bytes.Equal(job, receipt.Body.Bytes()) {
+			t.Errorf("the printer received %d bytes, not the %d byte receipt", len(job), receipt.Body.Len())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the printer received nothing")
+	}
+}
+
+func TestPrintReceiptNeedsAPrinter(t *testing.T) {
+	setup(t)
+	invoice := invoicedOrder(t)
+
+	path := fmt.Sprintf("/invoice-receipt/%d/print", invoice.ID)
+	if code := serve(t, PrintReceipt(), http.MethodPost, "/invoice-receipt/:invoice_id/print", path, nil, nil).Code; code != http.StatusConflict {
+		t.Fatalf("printing without a printer answered %d, want %d", code, http.StatusConflict)
+	}
+}
@@ -0,0 +1,464 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// defaultReservationMinutes is the slot length when a booking does not give one
+const defaultReservationMinutes = 90
+
+// reservationHold is how long before its slot a booking shows its table as reserved
+func reservationHold() time.Duration {
+	return envMinutes("RESERVATION_HOLD_MINUTES", 30)
+}
+
+// reservationGrace is how long a late party keeps its table before it is released as a no-show
+func reservationGrace() time.Duration {
+	return envMinutes("RESERVATION_GRACE_MINUTES", 15)
+}
+
+func envMinutes(name string, fallback int) time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || minutes < 0 {
+		minutes = fallback
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func GetReservations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		// An optional date (YYYY-MM-DD, UTC) narrows the list to one day
+		var from, to time.Time
+		if date := c.Query("date"); date != "" {
+			if from, err = time.Parse(time.DateOnly, date); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Date must be formatted as YYYY-MM-DD", "details": err.Error()})
+				return
+			}
+			to = from.AddDate(0, 0, 1)
+		}
+
+		reservations, err := Repos.Reservations.List(ctx, id, from, to)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reservations from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Reservations fetched successfully", "reservations": reservations})
+	}
+}
+
+func GetReservation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("reservation_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Reservation ID is required"})
+			return
+		}
+
+		reservation, err := Repos.Reservations.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No reservation found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reservation from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Reservation fetched successfully", "reservation": reservation})
+	}
+}
+
+// SearchAvailability lists the tables that seat the party and are free for the whole slot, smallest first
+func SearchAvailability() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		partySize, err := strconv.Atoi(c.Query("party_size"))
+		if err != nil || partySize < 1 {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Party size must be a positive number"})
+			return
+		}
+
+		slot := models.Reservation{PartySize: partySize}
+		if slot.StartsAt, err = time.Parse(time.RFC3339, c.Query("starts_at")); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Start time must be an RFC 3339 timestamp", "details": err.Error()})
+			return
+		}
+		if duration := c.Query("duration_minutes"); duration != "" {
+			if slot.DurationMinutes, err = strconv.Atoi(duration); err != nil || slot.DurationMinutes < 1 {
+				c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Duration must be a positive number of minutes"})
+				return
+			}
+		}
+		normaliseSlot(&slot)
+
+		tables, err := Repos.Reservations.AvailableTables(ctx, id, partySize, slot.StartsAt, slot.EndsAt())
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to search available tables", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Available tables fetched successfully", "starts_at": slot.StartsAt, "ends_at": slot.EndsAt(), "tables": tables})
+	}
+}
+
+func CreateReservation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		var reservation models.Reservation
+		if err := c.BindJSON(&reservation); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for creating reservation", "details": err.Error()})
+			return
+		}
+
+		if err := validate.Struct(reservation); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+
+		normaliseSlot(&reservation)
+		now := time.Now().UTC()
+		if !reservation.StartsAt.After(now) {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Reservations must start in the future"})
+			return
+		}
+		reservation.ID = 0
+		reservation.Status = models.ReservationStatusBooked
+
+		err := UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			// Without a table the smallest free one that seats the party is picked
+			if reservation.TableID == 0 {
+				tables, err := repos.Reservations.AvailableTables(ctx, reservation.RestaurantID, reservation.PartySize, reservation.StartsAt, reservation.EndsAt())
+				if err != nil {
+					return fmt.Errorf("searching available tables: %w", err)
+				}
+				if len(tables) == 0 {
+					return abortWith(http.StatusConflict, gin.H{"error": "No table is free for this party and slot"})
+				}
+				reservation.TableID = tables[0].ID
+			}
+
+			if err := claimTable(ctx, repos, reservation); err != nil {
+				return err
+			}
+
+			var err error
+			if reservation, err = repos.Reservations.Create(ctx, reservation); err != nil {
+				return fmt.Errorf("creating the reservation: %w", err)
+			}
+			return refreshTableStatus(ctx, repos, reservation.TableID, now)
+		})
+		if err != nil {
+			respondError(c, err, "Failed to create reservation in database")
+			return
+		}
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Reservation created successfully", "reservation": reservation})
+	}
+}
+
+func UpdateReservation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("reservation_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Reservation ID is required"})
+			return
+		}
+
+		var reservation models.Reservation
+		if err := c.BindJSON(&reservation); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for updating reservation", "details": err.Error()})
+			return
+		}
+
+		if err := validate.Struct(reservation); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+
+		normaliseSlot(&reservation)
+		now := time.Now().UTC()
+
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			current, err := repos.Reservations.Get(ctx, id)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return abortWith(http.StatusNotFound, gin.H{"error": "No reservation found with given ID"})
+				}
+				return fmt.Errorf("fetching the reservation: %w", err)
+			}
+			if current.Status != models.ReservationStatusBooked {
+				return abortWith(http.StatusConflict, gin.H{"error": "Only booked reservations can be changed", "status": current.Status})
+			}
+
+			// Reservations stay with their restaurant and keep their table unless another is given
+			reservation.ID, reservation.RestaurantID, reservation.Status = id, current.RestaurantID, current.Status
+			if reservation.TableID == 0 {
+				reservation.TableID = current.TableID
+			}
+
+			// Lock both tables in ID order so two moves between the same tables cannot deadlock
+			tableIDs := []uint{current.TableID, reservation.TableID}
+			slices.Sort(tableIDs)
+			for _, tableID := range slices.Compact(tableIDs) {
+				if _, err := repos.Tables.GetForUpdate(ctx, tableID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+					return fmt.Errorf("locking the table: %w", err)
+				}
+			}
+
+			if err := claimTable(ctx, repos, reservation); err != nil {
+				return err
+			}
+			if reservation, err = repos.Reservations.Update(ctx, reservation); err != nil {
+				return fmt.Errorf("updating the reservation: %w", err)
+			}
+
+			if current.TableID != reservation.TableID {
+				if err := refreshTableStatus(ctx, repos, current.TableID, now); err != nil {
+					return err
+				}
+			}
+			return refreshTableStatus(ctx, repos, reservation.TableID, now)
+		})
+		if err != nil {
+			respondError(c, err, "Failed to update reservation in database")
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Reservation updated successfully", "reservation": reservation})
+	}
+}
+
+// UpdateReservationStatus seats, completes, cancels or releases a reservation and updates its table to match
+func UpdateReservationStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("reservation_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Reservation ID is required"})
+			return
+		}
+
+		var reservationStatus models.ReservationStatus
+		if err := c.BindJSON(&reservationStatus); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Please provide the correct status to update reservation status", "details": err.Error()})
+			return
+		}
+
+		var reservation models.Reservation
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			current, err := repos.Reservations.Get(ctx, id)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return abortWith(http.StatusNotFound, gin.H{"error": "No reservation found with given ID"})
+				}
+				return fmt.Errorf("fetching the reservation: %w", err)
+			}
+			if _, err := repos.Tables.GetForUpdate(ctx, current.TableID); err != nil {
+				return fmt.Errorf("locking the table: %w", err)
+			}
+
+			reservation, err = changeReservationStatus(ctx, repos, current, reservationStatus.Status, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			respondError(c, err, "Failed to update reservation status in database")
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Reservation status updated successfully", "reservation": reservation})
+	}
+}
+
+// SweepReservations releases booked tables whose party is later than the grace period and
+// marks tables as reserved once a booking is within the hold window. It runs as a background job.
+func SweepReservations(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	due, err := Repos.Reservations.BookedBefore(ctx, now.Add(reservationHold()))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	held := map[uint]bool{}
+	for _, reservation := range due {
+		if reservation.StartsAt.Add(reservationGrace()).After(now) {
+			held[reservation.TableID] = true
+			continue
+		}
+
+		err := UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			if _, err := repos.Tables.GetForUpdate(ctx, reservation.TableID); err != nil {
+				return err
+			}
+			// Staff may have seated or cancelled the party since it was listed
+			current, err := repos.Reservations.Get(ctx, reservation.ID)
+			if err != nil || current.Status != models.ReservationStatusBooked {
+				return err
+			}
+			_, err = changeReservationStatus(ctx, repos, current, models.ReservationStatusNoShow, now)
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("releasing reservation %d: %w", reservation.ID, err))
+		}
+	}
+
+	for tableID := range held {
+		err := UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			if _, err := repos.Tables.GetForUpdate(ctx, tableID); err != nil {
+				return err
+			}
+			return refreshTableStatus(ctx, repos, tableID, now)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("holding table %d: %w", tableID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// changeReservationStatus moves a reservation along its lifecycle and updates its table.
+// Callers must hold the table lock.
+func changeReservationStatus(ctx context.Context, repos repository.Repositories, current models.Reservation, to string, now time.Time) (models.Reservation, error) {
+	if err := helpers.CanTransitionReservation(current.Status, to); err != nil {
+		return current, abortWith(http.StatusConflict, gin.H{"error": "Illegal reservation status transition", "details": err})
+	}
+
+	reservation, err := repos.Reservations.UpdateStatus(ctx, current.ID, to)
+	if err != nil {
+		return reservation, fmt.Errorf("updating the reservation status: %w", err)
+	}
+
+	switch to {
+	case models.ReservationStatusSeated:
+		_, err = repos.Tables.UpdateStatus(ctx, reservation.TableID, models.TableStatusOccupied)
+	case models.ReservationStatusCompleted:
+		if _, err = repos.Tables.UpdateStatus(ctx, reservation.TableID, models.TableStatusAvailable); err == nil {
+			err = refreshTableStatus(ctx, repos, reservation.TableID, now)
+		}
+	default:
+		err = refreshTableStatus(ctx, repos, reservation.TableID, now)
+	}
+	if err != nil {
+		return reservation, fmt.Errorf("updating the table status: %w", err)
+	}
+	return reservation, nil
+}
+
+// claimTable checks that the reservation's table belongs to its restaurant, seats the
+// party and has no other booking overlapping the slot. Callers must hold the table lock.
+func claimTable(ctx context.Context, repos repository.Repositories, reservation models.Reservation) error {
+	table, err := repos.Tables.GetForUpdate(ctx, reservation.TableID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return abortWith(http.StatusBadRequest, gin.H{"error": "No table found with given ID", "table_id": reservation.TableID})
+		}
+		return fmt.Errorf("locking the table: %w", err)
+	}
+
+	if uint(table.RestaurantID) != reservation.RestaurantID {
+		return abortWith(http.StatusBadRequest, gin.H{"error": "Table does not belong to this restaurant", "table_id": table.ID})
+	}
+	if table.Capacity < reservation.PartySize {
+		return abortWith(http.StatusConflict, gin.H{"error": "Table seats fewer guests than the party", "table_id": table.ID, "capacity": table.Capacity})
+	}
+
+	conflicts, err := repos.Reservations.Overlapping(ctx, table.ID, reservation.StartsAt, reservation.EndsAt(), reservation.ID)
+	if err != nil {
+		return fmt.Errorf("checking overlapping reservations: %w", err)
+	}
+	if len(conflicts) > 0 {
+		return abortWith(http.StatusConflict, gin.H{"error": "Table is already booked for this slot", "table_id": table.ID, "conflicts": conflicts})
+	}
+	return nil
+}
+
+// refreshTableStatus shows a table as reserved while a booking is due within the hold
+// window and as available otherwise. Occupied tables are left alone until their party
+// leaves. Callers must hold the table lock.
+func refreshTableStatus(ctx context.Context, repos repository.Repositories, tableID uint, now time.Time) error {
+	table, err := repos.Tables.Get(ctx, tableID)
+	if err != nil {
+		return err
+	}
+	if table.Status == models.TableStatusOccupied {
+		return nil
+	}
+
+	upcoming, err := repos.Reservations.Overlapping(ctx, tableID, now, now.Add(reservationHold()), 0)
+	if err != nil {
+		return err
+	}
+
+	status := models.TableStatusAvailable
+	for _, reservation := range upcoming {
+		if reservation.Status == models.ReservationStatusBooked && reservation.StartsAt.Add(reservationGrace()).After(now) {
+			status = models.TableStatusReserved
+			break
+		}
+	}
+
+	if table.Status != status {
+		_, err = repos.Tables.UpdateStatus(ctx, tableID, status)
+	}
+	return err
+}
+
+// normaliseSlot stores slots in UTC and fills in the default length
+func normaliseSlot(reservation *models.Reservation) {
+	reservation.StartsAt = reservation.StartsAt.UTC()
+	if reservation.DurationMinutes == 0 {
+		reservation.DurationMinutes = defaultReservationMinutes
+	}
+}
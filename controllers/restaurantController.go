@@ -2,9 +2,12 @@ package controllers
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"net/http"
+	"restaurant-management/helpers"
 	"restaurant-management/models"
+	"restaurant-management/repository"
+	"restaurant-management/utils"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,23 +18,11 @@ func GetRestaurants() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		var restaurants []models.Restaurant
-
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM restaurants")
+		restaurants, err := Repos.Restaurants.List(ctx)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurants from database", "details": err.Error()})
 			return
 		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var restaurant models.Restaurant
-			if err := rows.Scan(&restaurant.ID, &restaurant.Name, &restaurant.OwnerID, &restaurant.Logo, &restaurant.Address, &restaurant.Description, &restaurant.CreatedAt, &restaurant.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan restaurants", "details": err.Error()})
-				return
-			}
-			restaurants = append(restaurants, restaurant)
-		}
 
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Restaurants fetched successfully", "restaurants": restaurants})
 	}
@@ -42,15 +33,15 @@ func GetRestaurant() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("restaurant_id")
-		if id == "" {
+		id, err := parseID(c.Param("restaurant_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
 			return
 		}
 
-		var restaurant models.Restaurant
-		if err := Db.QueryRowContext(ctx, "SELECT * FROM restaurants WHERE id = $1", id).Scan(&restaurant.ID, &restaurant.Name, &restaurant.OwnerID, &restaurant.Logo, &restaurant.Address, &restaurant.Description, &restaurant.CreatedAt, &restaurant.UpdatedAt); err != nil {
-			if err == sql.ErrNoRows {
+		restaurant, err := Repos.Restaurants.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
 				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
 			} else {
 				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant", "details": err.Error()})
@@ -77,15 +68,30 @@ func CreateRestaurant() gin.HandlerFunc {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Name, OwnerID, and Address are required"})
 			return
 		}
+		if !checkTimeZone(c, &restaurant) {
+			return
+		}
 
-		err := Db.QueryRowContext(ctx, "INSERT INTO restaurants (name, owner_id, logo, address, description) VALUES ($1, $2, $3, $4, $5) RETURNING id",
-			restaurant.Name, restaurant.OwnerID, restaurant.Logo, restaurant.Address, restaurant.Description).Scan(&restaurant.ID)
+		restaurant, err := Repos.Restaurants.Create(ctx, restaurant)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create restaurant", "details": err.Error()})
 			return
 		}
 
-		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Restaurant created successfully", "restaurant": restaurant})
+		// The caller now owns a new restaurant, hand back a token that carries the membership
+		claims, _ := helpers.GetClaims(c)
+		refreshed, err := buildClaims(ctx, models.User{ID: claims.UserID, Username: claims.Username, Role: claims.Role})
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to load restaurant memberships for the user", "details": err.Error()})
+			return
+		}
+		tokens, err := utils.IssueTokenPair(ctx, refreshed, claims.FamilyID)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create token for the user", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Restaurant created successfully", "restaurant": restaurant, "token": tokens.AccessToken, "refresh_token": tokens.RefreshToken, "expires_in": tokens.ExpiresIn})
 	}
 }
 
@@ -94,8 +100,8 @@ func UpdateRestaurant() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("restaurant_id")
-		if id == "" {
+		id, err := parseID(c.Param("restaurant_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
 			return
 		}
@@ -110,9 +116,17 @@ func UpdateRestaurant() gin.HandlerFunc {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Name, OwnerID, and Address are required"})
 			return
 		}
+		if !checkTimeZone(c, &restaurant) {
+			return
+		}
 
-		if err := Db.QueryRowContext(ctx, "UPDATE restaurants SET name = $1, owner_id = $2, logo = $3, address = $4, description = $5 WHERE id = $6 RETURNING id, name, owner_id, logo, address, description, created_at, updated_at",
-			restaurant.Name, restaurant.OwnerID, restaurant.Logo, restaurant.Address, restaurant.Description, id).Scan(&restaurant.ID, &restaurant.Name, &restaurant.OwnerID, &restaurant.Logo, &restaurant.Address, &restaurant.Description, &restaurant.CreatedAt, &restaurant.UpdatedAt); err != nil {
+		restaurant.ID = id
+		restaurant, err = Repos.Restaurants.Update(ctx, restaurant)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+				return
+			}
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update restaurant", "details": err.Error()})
 			return
 		}
@@ -126,24 +140,21 @@ func DeleteRestaurant() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("restaurant_id")
-		if id == "" {
+		id, err := parseID(c.Param("restaurant_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
 			return
 		}
 
-		result, err := Db.ExecContext(ctx, "DELETE FROM restaurants WHERE id = $1", id)
-		if err != nil {
+		if err := Repos.Restaurants.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+				return
+			}
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete restaurant", "details": err.Error()})
 			return
 		}
 
-		rowsAffected, _ := result.RowsAffected()
-		if rowsAffected == 0 {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
-			return
-		}
-
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Restaurant deleted successfully"})
 	}
 }
@@ -153,30 +164,31 @@ func GetRestaurantsByOwner() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		ownerID := c.Param("owner_id")
-		if ownerID == "" {
+		ownerID, err := parseID(c.Param("owner_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Owner ID is required"})
 			return
 		}
 
-		var restaurants []models.Restaurant
-
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM restaurants WHERE owner_id = $1 ORDER BY id ASC", ownerID)
+		restaurants, err := Repos.Restaurants.ListByOwner(ctx, ownerID)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurants from database", "details": err.Error()})
 			return
 		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var restaurant models.Restaurant
-			if err := rows.Scan(&restaurant.ID, &restaurant.Name, &restaurant.OwnerID, &restaurant.Logo, &restaurant.Address, &restaurant.Description, &restaurant.CreatedAt, &restaurant.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan restaurants", "details": err.Error()})
-				return
-			}
-			restaurants = append(restaurants, restaurant)
-		}
 
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Restaurants fetched successfully", "restaurants": restaurants})
 	}
 }
+
+// checkTimeZone defaults a restaurant's time zone to UTC and makes sure it is a known IANA
+// name, answering the request when it isn't
+func checkTimeZone(c *gin.Context, restaurant *models.Restaurant) bool {
+	if restaurant.TimeZone == "" {
+		restaurant.TimeZone = "UTC"
+	}
+	if err := validate.Var(restaurant.TimeZone, "timezone"); err != nil || restaurant.TimeZone == "Local" {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "The time zone must be an IANA name like Europe/Paris", "time_zone": restaurant.TimeZone})
+		return false
+	}
+	return true
+}
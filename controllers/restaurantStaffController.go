@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"restaurant-management/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetRestaurantStaff() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		staff, err := Repos.Restaurants.ListStaff(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant staff from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Restaurant staff fetched successfully", "staff": staff})
+	}
+}
+
+func AddRestaurantStaff() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		var member models.RestaurantStaff
+		if err := c.BindJSON(&member); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide user_id and role to add staff", "details": err.Error()})
+			return
+		}
+
+		if err := utils.ValidateStruct(member); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+			return
+		}
+
+		member.RestaurantID = id
+		member, err = Repos.Restaurants.UpsertStaff(ctx, member)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to add staff to the restaurant", "details": err.Error()})
+			return
+		}
+		// Memberships travel in the tokens, so the user signs in again to pick up the new one
+		if err := utils.RevokeUserTokens(ctx, member.UserID); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke existing sessions for the user", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Staff added successfully", "staff": member})
+	}
+}
+
+func RemoveRestaurantStaff() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		restaurantID, err := parseID(c.Param("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID and User ID are required"})
+			return
+		}
+		userID, err := parseID(c.Param("user_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID and User ID are required"})
+			return
+		}
+
+		if err := Repos.Restaurants.RemoveStaff(ctx, restaurantID, userID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "User is not a staff member of this restaurant"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove staff from the restaurant", "details": err.Error()})
+			return
+		}
+		// Tokens already issued still carry the removed membership, so they must go
+		if err := utils.RevokeUserTokens(ctx, userID); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke existing sessions for the user", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Staff removed successfully", "user_id": userID})
+	}
+}
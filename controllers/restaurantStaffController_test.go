@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"restaurant-management/config"
+	"restaurant-management/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// sessionStore stands in for Redis: it answers without a server, records every command it
+// gets and holds one token family for every user
+type sessionStore struct {
+	commands []string
+}
+
+func (s *sessionStore) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (s *sessionStore) ProcessHook(redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		s.commands = append(s.commands, fmt.Sprint(cmd.Args()...))
+		if members, ok := cmd.(*redis.StringSliceCmd); ok {
+			members.SetVal([]string{"family-1"})
+		}
+		return nil
+	}
+}
+
+func (s *sessionStore) ProcessPipelineHook(redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			s.commands = append(s.commands, fmt.Sprint(cmd.Args()...))
+		}
+		return nil
+	}
+}
+
+// revoked tells whether the user's token family was blacklisted
+func (s *sessionStore) revoked() bool {
+	return slices.ContainsFunc(s.commands, func(command string) bool { return strings.Contains(command, "family:family-1") })
+}
+
+func useSessionStore(t *testing.T) *sessionStore {
+	t.Helper()
+	store := &sessionStore{}
+	previous := config.RedisClient
+	config.RedisClient = redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})
+	config.RedisClient.AddHook(store)
+	t.Cleanup(func() { config.RedisClient = previous })
+	return store
+}
+
+func TestStaffChangesRevokeTheUsersSessions(t *testing.T) {
+	setup(t)
+	ctx := context.Background()
+	restaurant, err := Repos.Restaurants.Create(ctx, models.Restaurant{Name: "Test Kitchen", OwnerID: 1, Address: "1 Test Street"})
+	if err != nil {
+		t.Fatalf("creating the restaurant: %v", err)
+	}
+	user, err := Repos.Users.Create(ctx, models.User{Username: "cook", Email: "cook@example.com", Phone: "+15550101", Role: models.RoleUser})
+	if err != nil {
+		t.Fatalf("creating the user: %v", err)
+	}
+
+	store := useSessionStore(t)
+	path := fmt.Sprintf("/restaurant/%d/staff", restaurant.ID)
+	member := models.RestaurantStaff{UserID: user.ID, Role: models.MembershipStaff}
+	if recorder := serve(t, AddRestaurantStaff(), http.MethodPost, "/restaurant/:restaurant_id/staff", path, member, nil); recorder.Code != http.StatusCreated {
+		t.Fatalf("adding the staff member answered %d: %s", recorder.Code, recorder.Body)
+	}
+	if !store.revoked() {
+		t.Errorf("adding the staff member left their sessions alone: %v", store.commands)
+	}
+
+	store.commands = nil
+	path = fmt.Sprintf("/restaurant/%d/staff/%d", restaurant.ID, user.ID)
+	if recorder := serve(t, RemoveRestaurantStaff(), http.MethodDelete, "/restaurant/:restaurant_id/staff/:user_id", path, nil, nil); recorder.Code != http.StatusOK {
+		t.Fatalf("removing the staff member answered %d: %s", recorder.Code, recorder.Body)
+	}
+	if !store.revoked() {
+		t.Errorf("removing the staff member left their sessions alone: %v", store.commands)
+	}
+
+	// Nothing is revoked for a membership that didn't exist
+	store.commands = nil
+	if code := serve(t, RemoveRestaurantStaff(), http.MethodDelete, "/restaurant/:restaurant_id/staff/:user_id", path, nil, nil).Code; code != http.StatusNotFound || store.revoked() {
+		t.Errorf("removing a missing member answered %d and revoked %v", code, store.revoked())
+	}
+}
@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func GetServiceCharges() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		charges, err := Repos.ServiceCharges.List(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service charges from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Service charges fetched successfully", "service_charges": charges})
+	}
+}
+
+func GetServiceCharge() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("service_charge_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Service charge ID is required"})
+			return
+		}
+
+		charge, err := Repos.ServiceCharges.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No service charge found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service charge from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Service charge fetched successfully", "service_charge": charge})
+	}
+}
+
+func CreateServiceCharge() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		var charge models.ServiceCharge
+		if err := c.BindJSON(&charge); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for creating service charge", "details": err.Error()})
+			return
+		}
+
+		if !validateServiceCharge(c, charge) {
+			return
+		}
+
+		charge, err := Repos.ServiceCharges.Create(ctx, charge)
+		if err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.IndentedJSON(http.StatusConflict, gin.H{"error": "The restaurant already has a service charge with this name"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create service charge in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Service charge created successfully", "service_charge": charge})
+	}
+}
+
+func UpdateServiceCharge() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("service_charge_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Service charge ID is required"})
+			return
+		}
+
+		var charge models.ServiceCharge
+		if err := c.BindJSON(&charge); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for updating service charge", "details": err.Error()})
+			return
+		}
+
+		// Charges never move to another restaurant
+		current, err := Repos.ServiceCharges.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No service charge found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch service charge from database", "details": err.Error()})
+			return
+		}
+		charge.ID, charge.RestaurantID = id, current.RestaurantID
+
+		if !validateServiceCharge(c, charge) {
+			return
+		}
+
+		charge, err = Repos.ServiceCharges.Update(ctx, charge)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No service charge found with given ID"})
+				return
+			}
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.IndentedJSON(http.StatusConflict, gin.H{"error": "The restaurant already has a service charge with this name"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service charge in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Service charge updated successfully", "service_charge": charge})
+	}
+}
+
+func DeleteServiceCharge() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("service_charge_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Service charge ID is required"})
+			return
+		}
+
+		// Invoices that carry the charge keep their copy of it
+		if err := Repos.ServiceCharges.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No service charge found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete service charge from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Service charge deleted successfully", "service_charge_id": id})
+	}
+}
+
+// validateServiceCharge responds with the validation errors of the charge, if any
+func validateServiceCharge(c *gin.Context, charge models.ServiceCharge) bool {
+	if err := validate.Struct(charge); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+		}
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func GetStations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		stations, err := Repos.Stations.List(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stations from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Stations fetched successfully", "stations": stations})
+	}
+}
+
+func GetStation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("station_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Station ID is required"})
+			return
+		}
+
+		station, err := Repos.Stations.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No station found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch station from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Station fetched successfully", "station": station})
+	}
+}
+
+func CreateStation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		var station models.Station
+		if err := c.BindJSON(&station); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for creating station", "details": err.Error()})
+			return
+		}
+
+		if err := validate.Struct(station); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+
+		station, err := Repos.Stations.Create(ctx, station)
+		if err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.IndentedJSON(http.StatusConflict, gin.H{"error": "The restaurant already has a station with this name"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create station in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Station created successfully", "station": station})
+	}
+}
+
+func UpdateStation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("station_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Station ID is required"})
+			return
+		}
+
+		var station models.Station
+		if err := c.BindJSON(&station); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for updating station", "details": err.Error()})
+			return
+		}
+
+		if err := validate.Var(station.PrinterAddress, "omitempty,hostname_port,max=255"); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "The printer address must be host:port", "details": err.Error()})
+			return
+		}
+
+		// Stations can be renamed and moved to another printer, never to another restaurant
+		station.ID = id
+		station, err = Repos.Stations.Update(ctx, station)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No station found with given ID"})
+				return
+			}
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.IndentedJSON(http.StatusConflict, gin.H{"error": "The restaurant already has a station with this name"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update station in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Station updated successfully", "station": station})
+	}
+}
+
+func DeleteStation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("station_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Station ID is required"})
+			return
+		}
+
+		// Foods and menus routed here fall back to no station
+		if err := Repos.Stations.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No station found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete station from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Station deleted successfully", "station_id": id})
+	}
+}
+
+// checkStation makes sure foods and menus are only routed to stations of their own restaurant
+func checkStation(ctx context.Context, c *gin.Context, stationID, restaurantID uint) bool {
+	if stationID == 0 {
+		return true
+	}
+
+	station, err := Repos.Stations.Get(ctx, stationID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch station from database", "details": err.Error()})
+		return false
+	}
+	if err != nil || station.RestaurantID != restaurantID {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Station does not belong to this restaurant", "station_id": stationID})
+		return false
+	}
+	return true
+}
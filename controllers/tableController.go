@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,23 +17,11 @@ func GetTables() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM tables")
+		tables, err := Repos.Tables.List(ctx, 0)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tables from database", "details": err.Error()})
 			return
 		}
-		defer rows.Close()
-
-		var tables []models.Table
-
-		for rows.Next() {
-			var table models.Table
-			if err := rows.Scan(&table.ID, &table.Name, &table.Capacity, &table.RestaurantID, &table.Location, &table.Status, &table.CreatedAt, &table.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan table data", "details": err.Error()})
-				return
-			}
-			tables = append(tables, table)
-		}
 
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Tables fetched successfully", "tables": tables})
 	}
@@ -42,16 +32,18 @@ func GetTable() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("table_id")
-		if id == "" {
+		id, err := parseID(c.Param("table_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Table ID is required"})
 			return
 		}
 
-		var table models.Table
-		if err := Db.QueryRowContext(ctx, `
-			SELECT id, name, capacity, restaurant_id, location, status, created_at, updated_at FROM tables WHERE id = $1
-		`, id).Scan(&table.ID, &table.Name, &table.Capacity, &table.RestaurantID, &table.Location, &table.Status, &table.CreatedAt, &table.UpdatedAt); err != nil {
+		table, err := Repos.Tables.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No table found with given ID"})
+				return
+			}
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch table from database", "details": err.Error()})
 			return
 		}
@@ -65,29 +57,17 @@ func GetTablesByRestaurantId() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Query("restaurant_id")
-		if id == "" {
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required."})
 			return
 		}
 
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM tables WHERE restaurant_id = $1", id)
+		tables, err := Repos.Tables.List(ctx, id)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tables from Database", "details": err.Error()})
 			return
 		}
-		defer rows.Close()
-
-		var tables []models.Table
-
-		for rows.Next() {
-			var table models.Table
-			if err := rows.Scan(&table.ID, &table.Name, &table.Capacity, &table.RestaurantID, &table.Location, &table.Status, &table.CreatedAt, &table.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to Scan the table from Database", "details": err.Error()})
-				return
-			}
-			tables = append(tables, table)
-		}
 
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Tables fetched successfully", "tables": tables})
 	}
@@ -115,12 +95,8 @@ func CreateTable() gin.HandlerFunc {
 			return
 		}
 
-		if err := Db.QueryRowContext(ctx, `
-			INSERT INTO tables (name, capacity, restaurant_id, location, status)
-			VALUES ($1, $2, $3, $4, $5)
-			RETURNING id, name, capacity, restaurant_id, location, status, created_at, updated_at
-		`, table.Name, table.Capacity, table.RestaurantID, table.Location, table.Status).Scan(
-			&table.ID, &table.Name, &table.Capacity, &table.RestaurantID, &table.Location, &table.Status, &table.CreatedAt, &table.UpdatedAt); err != nil {
+		table, err = Repos.Tables.Create(ctx, table)
+		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create table in database", "details": err.Error()})
 			return
 		}
@@ -134,8 +110,8 @@ func UpdateTable() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("table_id")
-		if id == "" {
+		id, err := parseID(c.Param("table_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Table ID is required"})
 			return
 		}
@@ -157,11 +133,13 @@ func UpdateTable() gin.HandlerFunc {
 			return
 		}
 
-		if err := Db.QueryRowContext(ctx, `
-			UPDATE tables
-			SET name = $1, capacity = $2, restaurant_id = $3, location = $4, status = $5, updated_at = CURRENT_TIMESTAMP
-			WHERE id = $6 RETURNING id, name, capacity, restaurant_id, location, status, created_at, updated_at
-		`, table.Name, table.Capacity, table.RestaurantID, table.Location, table.Status, id).Scan(&table.ID, &table.Name, &table.Capacity, &table.RestaurantID, &table.Location, &table.Status, &table.CreatedAt, &table.UpdatedAt); err != nil {
+		table.ID = id
+		table, err = Repos.Tables.Update(ctx, table)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No table found with given ID"})
+				return
+			}
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update the table in database", "details": err.Error()})
 			return
 		}
@@ -175,26 +153,20 @@ func DeleteTable() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("table_id")
-		if id == "" {
+		id, err := parseID(c.Param("table_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Table ID is required"})
 			return
 		}
 
-		result, err := Db.ExecContext(ctx, "DELETE FROM tables WHERE id = $1", id)
-		if err != nil {
+		if err := Repos.Tables.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No table found with given ID"})
+				return
+			}
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete table from database", "details": err.Error()})
 			return
 		}
-		rowsAffected, err := result.RowsAffected()
-		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve affected rows", "details": err.Error()})
-			return
-		}
-		if rowsAffected == 0 {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No table found with given ID"})
-			return
-		}
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Table deleted successfully", "table_id": id})
 	}
 }
@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"restaurant-management/models"
+	"restaurant-management/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func GetTaxRates() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		rates, err := Repos.TaxRates.List(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tax rates from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Tax rates fetched successfully", "tax_rates": rates})
+	}
+}
+
+func GetTaxRate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("tax_rate_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Tax rate ID is required"})
+			return
+		}
+
+		rate, err := Repos.TaxRates.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No tax rate found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tax rate from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Tax rate fetched successfully", "tax_rate": rate})
+	}
+}
+
+func CreateTaxRate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		var rate models.TaxRate
+		if err := c.BindJSON(&rate); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for creating tax rate", "details": err.Error()})
+			return
+		}
+
+		if !validateTaxRate(c, rate) {
+			return
+		}
+
+		rate, err := Repos.TaxRates.Create(ctx, rate)
+		if err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.IndentedJSON(http.StatusConflict, gin.H{"error": "The restaurant already has a tax rate with this name for the category"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tax rate in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Tax rate created successfully", "tax_rate": rate})
+	}
+}
+
+func UpdateTaxRate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("tax_rate_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Tax rate ID is required"})
+			return
+		}
+
+		var rate models.TaxRate
+		if err := c.BindJSON(&rate); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for updating tax rate", "details": err.Error()})
+			return
+		}
+
+		// Rates never move to another restaurant
+		current, err := Repos.TaxRates.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No tax rate found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tax rate from database", "details": err.Error()})
+			return
+		}
+		rate.ID, rate.RestaurantID = id, current.RestaurantID
+
+		if !validateTaxRate(c, rate) {
+			return
+		}
+
+		rate, err = Repos.TaxRates.Update(ctx, rate)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No tax rate found with given ID"})
+				return
+			}
+			if errors.Is(err, repository.ErrDuplicate) {
+				c.IndentedJSON(http.StatusConflict, gin.H{"error": "The restaurant already has a tax rate with this name for the category"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tax rate in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Tax rate updated successfully", "tax_rate": rate})
+	}
+}
+
+func DeleteTaxRate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("tax_rate_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Tax rate ID is required"})
+			return
+		}
+
+		// Invoices that charged the rate keep their copy of it
+		if err := Repos.TaxRates.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No tax rate found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tax rate from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Tax rate deleted successfully", "tax_rate_id": id})
+	}
+}
+
+// validateTaxRate responds with the validation errors of the rate, if any
+func validateTaxRate(c *gin.Context, rate models.TaxRate) bool {
+	if err := validate.Struct(rate); err != nil {
+		var validationErrors []string
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+		}
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+		return false
+	}
+	return true
+}
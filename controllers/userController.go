@@ -2,10 +2,13 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"restaurant-management/helpers"
 	"restaurant-management/models"
+	"restaurant-management/repository"
 	"restaurant-management/utils"
 	"time"
 
@@ -18,24 +21,15 @@ func GetUsers() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		var users []models.User
-
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM users")
+		users, err := Repos.Users.List(ctx)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users from database", "details": err.Error()})
 			return
 		}
-		defer rows.Close()
 
-		for rows.Next() {
-			var user models.User
-			if err := rows.Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Phone, &user.Role, &user.Token, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
-				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan user data", "details": err.Error()})
-				return
-			}
-			user.Password = "" // Clear password before sending response
-			user.Token = ""    // Clear token before sending response
-			users = append(users, user)
+		for i := range users {
+			users[i].Password = "" // Clear password before sending response
+			users[i].Token = ""    // Clear token before sending response
 		}
 		c.IndentedJSON(http.StatusOK, gin.H{"users": users})
 	}
@@ -46,15 +40,14 @@ func GetUser() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("user_id")
-		if id == "" {
+		id, err := parseID(c.Param("user_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Provide user ID"})
 			return
 		}
 
-		var user models.User
-
-		if err := Db.QueryRowContext(ctx, "SELECT * FROM users WHERE id = $1", id).Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Phone, &user.Role, &user.Token, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		user, err := Repos.Users.Get(ctx, id)
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Unable to find the user with the given indentifier", "details": err.Error()})
 			return
 		}
@@ -76,6 +69,8 @@ func SignUp() gin.HandlerFunc {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct details to create user", "details": err.Error()})
 			return
 		}
+		// Everyone signs up as a User, only admins make other admins
+		user.Role = models.RoleUser
 
 		// Validate the user struct
 		if err := utils.ValidateStruct(user); err != nil {
@@ -84,13 +79,12 @@ func SignUp() gin.HandlerFunc {
 		}
 
 		// Check if username, email or phone already exists
-		rows, err := Db.QueryContext(ctx, "SELECT * FROM users WHERE username = $1 OR email = $2 OR phone = $3", user.Username, user.Email, user.Phone)
+		exists, err := Repos.Users.Exists(ctx, user.Username, user.Email, user.Phone)
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing user", "details": err.Error()})
 			return
 		}
-		defer rows.Close()
-		if rows.Next() {
+		if exists {
 			c.IndentedJSON(http.StatusConflict, gin.H{"error": "User with this username, email or phone already exists"})
 			return
 		}
@@ -102,22 +96,31 @@ func SignUp() gin.HandlerFunc {
 			return
 		}
 
-		// Generate token
-		token, err := helpers.CreateToken(user.Username)
+		// Create user in database
+		user.Password = hashedPassword
+		user.Token = ""
+		user, err = Repos.Users.Create(ctx, user)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user in database", "details": err.Error()})
+			return
+		}
+
+		// Generate tokens, a brand new user doesn't belong to any restaurant yet
+		tokens, err := utils.IssueTokenPair(ctx, helpers.Claims{UserID: user.ID, Username: user.Username, Role: user.Role}, "")
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to token for the user", "details": err.Error()})
 			return
 		}
-		// Create user in database
-		if err := Db.QueryRowContext(ctx, "INSERT INTO users (username, password, email, phone, role, token, avatar_url) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, username, email, phone, role, token, avatar_url, created_at, updated_at", user.Username, hashedPassword, user.Email, user.Phone, user.Role, token, user.AvatarURL).Scan(&user.ID, &user.Username, &user.Email, &user.Phone, &user.Role, &user.Token, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user in database", "details": err.Error()})
+
+		if _, err := Repos.Users.UpdateToken(ctx, user.ID, tokens.AccessToken); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to store token for the user in database", "details": err.Error()})
 			return
 		}
 
-		user.Password = "" // Clear password before sending response
-		user.Token = token // Set the generated token
+		user.Password = ""              // Clear password before sending response
+		user.Token = tokens.AccessToken // Set the generated token
 
-		c.IndentedJSON(http.StatusCreated, gin.H{"message": "User created successfully", "user": user})
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "User created successfully", "user": user, "refresh_token": tokens.RefreshToken, "expires_in": tokens.ExpiresIn})
 	}
 }
 
@@ -130,14 +133,13 @@ func Login() gin.HandlerFunc {
 			Identifier string `json:"identifier" validate:"required"`
 			Password   string `json:"password" validate:"required,min=6,max=100"`
 		}
-		var user models.User
-
 		if err := c.BindJSON(&input); err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide identifier(username, email or phone) and password(min=6,max=100)", "details": err.Error()})
 			return
 		}
 
-		if err := Db.QueryRowContext(ctx, "SELECT * FROM users WHERE username = $1 OR email = $2 OR phone = $3", input.Identifier, input.Identifier, input.Identifier).Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Phone, &user.Role, &user.Token, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		user, err := Repos.Users.FindByIdentifier(ctx, input.Identifier)
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Unable to find the user with the given indentifier", "identifier": input.Identifier, "details": err.Error()})
 			return
 		}
@@ -149,20 +151,25 @@ func Login() gin.HandlerFunc {
 		}
 
 		//Generate Token
-		token, err := helpers.CreateToken(user.Username)
+		claims, err := buildClaims(ctx, user)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to load restaurant memberships for the user", "details": err.Error()})
+			return
+		}
+		tokens, err := utils.IssueTokenPair(ctx, claims, "")
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create token for the user", "details": err.Error()})
 			return
 		}
 
-		if err := Db.QueryRowContext(ctx, "UPDATE users SET token = $1 WHERE id = $2 RETURNING id, username, email, phone, role, token, avatar_url, created_at, updated_at", token, user.ID).Scan(&user.ID, &user.Username, &user.Email, &user.Phone, &user.Role, &user.Token, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		if user, err = Repos.Users.UpdateToken(ctx, user.ID, tokens.AccessToken); err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update token for the user in database", "username": user.Username, "details": err.Error()})
 			return
 		}
 
 		user.Password = ""
 
-		c.IndentedJSON(http.StatusOK, gin.H{"message": "User login successfully", "user": user})
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "User login successfully", "user": user, "refresh_token": tokens.RefreshToken, "expires_in": tokens.ExpiresIn})
 	}
 }
 
@@ -171,21 +178,21 @@ func UpdateUser() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("user_id")
-		if id == "" {
+		id, err := parseID(c.Param("user_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Provide correct details"})
 			return
 		}
 
 		var user models.UserWithOldPassword
-		var previousUser models.User
 
 		if err := c.BindJSON(&user); err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct details", "details": err.Error()})
 			return
 		}
 
-		if err := Db.QueryRowContext(ctx, "SELECT * FROM users WHERE id = $1", id).Scan(&previousUser.ID, &previousUser.Username, &previousUser.Password, &previousUser.Email, &previousUser.Phone, &previousUser.Role, &previousUser.Token, &previousUser.AvatarURL, &previousUser.CreatedAt, &previousUser.UpdatedAt); err != nil {
+		previousUser, err := Repos.Users.Get(ctx, id)
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Failed to fetch user from database", "details": err.Error()})
 			return
 		}
@@ -204,43 +211,135 @@ func UpdateUser() gin.HandlerFunc {
 		}
 
 		//Generate Token
-		token, err := helpers.CreateToken(user.Username)
+		// The role stays as it is, admins change it through UpdateUserRole
+		previousUser.Username = user.Username
+		user.Role = previousUser.Role
+		claims, err := buildClaims(ctx, previousUser)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to load restaurant memberships for the user", "details": err.Error()})
+			return
+		}
+		// The password changed, so every existing session must go before the new one starts
+		if err := utils.RevokeUserTokens(ctx, previousUser.ID); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke existing sessions for the user", "details": err.Error()})
+			return
+		}
+		tokens, err := utils.IssueTokenPair(ctx, claims, "")
 		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create token for the user", "details": err.Error()})
 			return
 		}
 
-		if err := Db.QueryRowContext(ctx, "UPDATE users SET username = $1, password = $2, email = $3, phone = $4, role = $5, token = $6, avatar_url = $7, updated_at = CURRENT_TIMESTAMP WHERE id = $8 RETURNING id, username, email, phone, role, token, avatar_url, created_at, updated_at", user.Username, hashedPassword, user.Email, user.Phone, user.Role, token, user.AvatarURL, id).Scan(&user.ID, &user.Username, &user.Email, &user.Phone, &user.Role, &user.Token, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		updated, err := Repos.Users.Update(ctx, models.User{
+			ID:        id,
+			Username:  user.Username,
+			Password:  hashedPassword,
+			Email:     user.Email,
+			Phone:     user.Phone,
+			Role:      previousUser.Role,
+			Token:     tokens.AccessToken,
+			AvatarURL: user.AvatarURL,
+		})
+		if err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update token for the user in database", "username": user.Username, "details": err.Error()})
 			return
 		}
+		user.ID, user.Token, user.CreatedAt, user.UpdatedAt = updated.ID, updated.Token, updated.CreatedAt, updated.UpdatedAt
 
 		user.Password = ""
 
-		c.IndentedJSON(http.StatusOK, gin.H{"message": "User updated successfully", "user": user})
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "User updated successfully", "user": user, "refresh_token": tokens.RefreshToken, "expires_in": tokens.ExpiresIn})
 	}
 }
 
+// UpdateUserRole makes an account an Admin or a User. The account is signed out everywhere,
+// so its next session carries the new role.
+func UpdateUserRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("user_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+			return
+		}
+
+		var role models.UserRole
+		if err := c.BindJSON(&role); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct details", "details": err.Error()})
+			return
+		}
+		if err := utils.ValidateStruct(role); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+			return
+		}
+
+		user, err := Repos.Users.UpdateRole(ctx, id, role.Role)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No user found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role of the user in database", "details": err.Error()})
+			return
+		}
+		if err := utils.RevokeUserTokens(ctx, id); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke existing sessions for the user", "details": err.Error()})
+			return
+		}
+
+		user.Password, user.Token = "", ""
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "User role updated successfully", "user": user})
+	}
+}
+
+// BootstrapAdmin makes the user signed up with email an admin, so a fresh install, where
+// everyone signs up as a user, gets its first admin. An empty email or a user who already
+// is an admin changes nothing. Sessions pick up the role on their next token refresh.
+func BootstrapAdmin(ctx context.Context, email string) error {
+	if email == "" {
+		return nil
+	}
+
+	user, err := Repos.Users.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("no user has signed up with %s yet", email)
+		}
+		return fmt.Errorf("fetching the user: %w", err)
+	}
+	if user.Role == models.RoleAdmin {
+		return nil
+	}
+
+	if _, err := Repos.Users.UpdateRole(ctx, user.ID, models.RoleAdmin); err != nil {
+		return fmt.Errorf("updating the role: %w", err)
+	}
+	log.Printf("Made %s an admin", email)
+	return nil
+}
+
 func DeleteUser() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		id := c.Param("user_id")
-		if id == "" {
+		id, err := parseID(c.Param("user_id"))
+		if err != nil {
 			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Provide User ID"})
 			return
 		}
 
 		var confirmUserPassword models.ConfirmPassword
-		var user models.User
 
 		if err := c.BindJSON(&confirmUserPassword); err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Please provide password", "details": err.Error()})
 			return
 		}
 
-		if err := Db.QueryRow("SELECT * FROM users WHERE id = $1", id).Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Phone, &user.Role, &user.Token, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		user, err := Repos.Users.Get(ctx, id)
+		if err != nil {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Failed to fetch user from database", "details": err.Error()})
 			return
 		}
@@ -250,18 +349,17 @@ func DeleteUser() gin.HandlerFunc {
 			return
 		}
 
-		result, err := Db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id)
-		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete order from database", "details": err.Error()})
-			return
-		}
-		rowsAffected, err := result.RowsAffected()
-		if err != nil {
-			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve affected rows for user", "details": err.Error()})
+		if err := Repos.Users.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No user found with given ID"})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user from database", "details": err.Error()})
 			return
 		}
-		if rowsAffected == 0 {
-			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No user found with given ID"})
+
+		if err := utils.RevokeUserTokens(ctx, user.ID); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions of the deleted user", "details": err.Error()})
 			return
 		}
 
@@ -280,8 +378,8 @@ func SendPasswordResetEmail() gin.HandlerFunc {
 			return
 		}
 
-		var user models.User
-		if err := Db.QueryRowContext(ctx, "SELECT * FROM users WHERE email = $1", input.Email).Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Phone, &user.Role, &user.Token, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		user, err := Repos.Users.FindByEmail(ctx, input.Email)
+		if err != nil {
 			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No user found with the provided email"})
 			return
 		}
@@ -294,8 +392,7 @@ func SendPasswordResetEmail() gin.HandlerFunc {
 		}
 
 		// send the otp to user through email
-		err := utils.SendOTP(otp, user.Email)
-		if err != nil {
+		if err := utils.SendOTP(otp, user.Email); err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to send OTP to user: " + user.Email, "details": err.Error()})
 			return
 		}
@@ -315,16 +412,14 @@ func PasswordReset() gin.HandlerFunc {
 			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide email, otp and new password to reset the password", "details": err.Error()})
 			return
 		}
-		fmt.Println("Input:", input)
-		var user models.User
-		if err := Db.QueryRowContext(ctx, "SELECT * FROM users WHERE email = $1", input.Email).Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Phone, &user.Role, &user.Token, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		user, err := Repos.Users.FindByEmail(ctx, input.Email)
+		if err != nil {
 			c.IndentedJSON(http.StatusNotFound, gin.H{"error": "No user found with the provided email"})
 			return
 		}
 
 		// Verify OTP from Redis
-		err, isInternalErr := utils.VerifyOTP(input.OTP, input.Email, c)
-		if err != nil {
+		if err, isInternalErr := utils.VerifyOTP(input.OTP, input.Email, c); err != nil {
 			var code int
 			if isInternalErr {
 				code = http.StatusInternalServerError
@@ -342,15 +437,106 @@ func PasswordReset() gin.HandlerFunc {
 			return
 		}
 
-		if _, err := Db.ExecContext(ctx, "UPDATE users SET password = $1 WHERE email = $2", hashedPassword, input.Email); err != nil {
+		if err := Repos.Users.UpdatePasswordByEmail(ctx, input.Email, hashedPassword); err != nil {
 			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password in database", "details": err.Error()})
 			return
 		}
 
+		// Anyone holding a token from before the reset is signed out
+		if err := utils.RevokeUserTokens(ctx, user.ID); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke existing sessions for the user", "details": err.Error()})
+			return
+		}
+
 		c.IndentedJSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
 	}
 }
 
+func RefreshToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		var input struct {
+			RefreshToken string `json:"refresh_token" validate:"required"`
+		}
+		if err := c.BindJSON(&input); err != nil || input.RefreshToken == "" {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide the refresh_token to refresh the session"})
+			return
+		}
+
+		previous, err := utils.RotateRefreshToken(ctx, input.RefreshToken)
+		if err != nil {
+			if errors.Is(err, utils.ErrInvalidRefreshToken) || errors.Is(err, utils.ErrRefreshTokenReused) {
+				c.IndentedJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate the refresh token", "details": err.Error()})
+			return
+		}
+
+		// Reload the user so role and membership changes show up in the new access token
+		user, err := Repos.Users.Get(ctx, previous.UserID)
+		if err != nil {
+			c.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User for this session no longer exists"})
+			return
+		}
+
+		claims, err := buildClaims(ctx, user)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to load restaurant memberships for the user", "details": err.Error()})
+			return
+		}
+
+		tokens, err := utils.IssueTokenPair(ctx, claims, previous.FamilyID)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create token for the user", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Token refreshed successfully", "token": tokens.AccessToken, "refresh_token": tokens.RefreshToken, "expires_in": tokens.ExpiresIn})
+	}
+}
+
+func Logout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		claims, _ := helpers.GetClaims(c)
+
+		// Blacklist the presented access token and kill its refresh token family
+		if err := utils.RevokeToken(ctx, claims); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke the access token", "details": err.Error()})
+			return
+		}
+		if err := utils.RevokeTokenFamily(ctx, claims.FamilyID); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke the refresh token", "details": err.Error()})
+			return
+		}
+
+		if _, err := Repos.Users.UpdateToken(ctx, claims.UserID, ""); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear token for the user in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "User logged out successfully"})
+	}
+}
+
+// buildClaims collects the user's identity and every restaurant they own or work in
+func buildClaims(ctx context.Context, user models.User) (helpers.Claims, error) {
+	claims := helpers.Claims{UserID: user.ID, Username: user.Username, Role: user.Role}
+
+	memberships, err := Repos.Restaurants.Memberships(ctx, user.ID)
+	if err != nil {
+		return claims, err
+	}
+	claims.Memberships = memberships
+
+	return claims, nil
+}
+
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
 	return string(bytes), err
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"restaurant-management/models"
+)
+
+func TestBootstrapAdmin(t *testing.T) {
+	setup(t)
+	ctx := context.Background()
+	user, err := Repos.Users.Create(ctx, models.User{Username: "owner", Email: "owner@example.com", Phone: "+15550100", Role: models.RoleUser})
+	if err != nil {
+		t.Fatalf("creating the user: %v", err)
+	}
+
+	if err := BootstrapAdmin(ctx, ""); err != nil {
+		t.Fatalf("no ADMIN_EMAIL gave %v", err)
+	}
+	if user, _ = Repos.Users.Get(ctx, user.ID); user.Role != models.RoleUser {
+		t.Fatalf("without ADMIN_EMAIL the user became %s", user.Role)
+	}
+
+	if err := BootstrapAdmin(ctx, "nobody@example.com"); err == nil {
+		t.Error("an email nobody signed up with was accepted")
+	}
+
+	// Promoting again on the next restart is a no-op
+	for range 2 {
+		if err := BootstrapAdmin(ctx, user.Email); err != nil {
+			t.Fatalf("BootstrapAdmin: %v", err)
+		}
+	}
+	if user, err = Repos.Users.Get(ctx, user.ID); err != nil || user.Role != models.RoleAdmin {
+		t.Errorf("the user is %s, %v, want an admin", user.Role, err)
+	}
+}
@@ -0,0 +1,349 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+	"restaurant-management/notify"
+	"restaurant-management/repository"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// turnLookback is how far back paid orders count towards the average seating duration
+const turnLookback = 30 * 24 * time.Hour
+
+// defaultTurn is the seating duration assumed until a restaurant has paid orders to learn from
+func defaultTurn() time.Duration {
+	return envMinutes("WAITLIST_DEFAULT_TURN_MINUTES", 60)
+}
+
+func GetWaitlist() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		entries, err := Repos.Waitlist.ListActive(ctx, id)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch waitlist from database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Waitlist fetched successfully", "waitlist": entries})
+	}
+}
+
+// GetWaitEstimate quotes the wait a party of the given size would have if it joined the waitlist now
+func GetWaitEstimate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Query("restaurant_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Restaurant ID is required"})
+			return
+		}
+
+		partySize, err := strconv.Atoi(c.Query("party_size"))
+		if err != nil || partySize < 1 {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Party size must be a positive number"})
+			return
+		}
+
+		minutes, err := quoteWait(ctx, Repos, id, partySize)
+		if err != nil {
+			respondError(c, err, "Failed to estimate the wait")
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Wait estimated successfully", "party_size": partySize, "quoted_minutes": minutes})
+	}
+}
+
+func CreateWaitlistEntry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		var entry models.WaitlistEntry
+		if err := c.BindJSON(&entry); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide correct data for joining the waitlist", "details": err.Error()})
+			return
+		}
+
+		if err := validate.Struct(entry); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+
+		var err error
+		if entry.QuotedMinutes, err = quoteWait(ctx, Repos, entry.RestaurantID, entry.PartySize); err != nil {
+			respondError(c, err, "Failed to estimate the wait")
+			return
+		}
+		entry.Status = models.WaitlistStatusWaiting
+
+		if entry, err = Repos.Waitlist.Create(ctx, entry); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to add party to the waitlist", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusCreated, gin.H{"message": "Party added to the waitlist successfully", "entry": entry})
+	}
+}
+
+// NotifyWaitlistEntry tells a waiting party its table is ready. The entry is only marked
+// notified once the notifier accepted the message, so a failed send can be retried.
+func NotifyWaitlistEntry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("entry_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Waitlist entry ID is required"})
+			return
+		}
+
+		entry, err := activeWaitlistEntry(ctx, Repos, id)
+		if err != nil {
+			respondError(c, err, "Failed to fetch waitlist entry from database")
+			return
+		}
+
+		restaurant, err := Repos.Restaurants.Get(ctx, entry.RestaurantID)
+		if err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch restaurant", "details": err.Error()})
+			return
+		}
+
+		message := notify.Message{
+			To:   entry.GuestPhone,
+			Body: fmt.Sprintf("Hi %s, your table for %d at %s is ready. Please come to the host stand.", entry.GuestName, entry.PartySize, restaurant.Name),
+		}
+		if err := Notifier.Notify(ctx, message); err != nil {
+			c.IndentedJSON(http.StatusBadGateway, gin.H{"error": "Failed to notify the guest", "details": err.Error()})
+			return
+		}
+
+		notifiedAt := time.Now().UTC()
+		entry.Status, entry.NotifiedAt = models.WaitlistStatusNotified, &notifiedAt
+		if entry, err = Repos.Waitlist.Update(ctx, entry); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update waitlist entry in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Guest notified successfully", "entry": entry})
+	}
+}
+
+// SeatWaitlistEntry seats a waiting party at an available table, opening its order and
+// marking the table occupied
+func SeatWaitlistEntry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("entry_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Waitlist entry ID is required"})
+			return
+		}
+
+		var seat models.SeatParty
+		if err := c.BindJSON(&seat); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Provide the table to seat the party at", "details": err.Error()})
+			return
+		}
+
+		if err := validate.Struct(seat); err != nil {
+			var validationErrors []string
+			for _, err := range err.(validator.ValidationErrors) {
+				validationErrors = append(validationErrors, err.Field()+" failed on the '"+err.Tag()+"' tag")
+			}
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": validationErrors})
+			return
+		}
+
+		var entry models.WaitlistEntry
+		var order models.Order
+		err = UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			table, err := repos.Tables.GetForUpdate(ctx, seat.TableID)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					return abortWith(http.StatusBadRequest, gin.H{"error": "No table found with given ID", "table_id": seat.TableID})
+				}
+				return fmt.Errorf("locking the table: %w", err)
+			}
+
+			// Read the entry under the table lock so two hosts cannot seat the same party
+			if entry, err = activeWaitlistEntry(ctx, repos, id); err != nil {
+				return err
+			}
+
+			if uint(table.RestaurantID) != entry.RestaurantID {
+				return abortWith(http.StatusBadRequest, gin.H{"error": "Table does not belong to this restaurant", "table_id": table.ID})
+			}
+			if table.Capacity < entry.PartySize {
+				return abortWith(http.StatusConflict, gin.H{"error": "Table seats fewer guests than the party", "table_id": table.ID, "capacity": table.Capacity})
+			}
+			if table.Status != models.TableStatusAvailable {
+				return abortWith(http.StatusConflict, gin.H{"error": "Table is not available", "table_id": table.ID, "status": table.Status})
+			}
+
+			now := time.Now().UTC()
+			order, err = repos.Orders.Create(ctx, models.Order{
+				TableID:      table.ID,
+				RestaurantID: entry.RestaurantID,
+				OrderDate:    now,
+				GuestCount:   entry.PartySize,
+				Status:       models.OrderStatusPending,
+				Notes:        entry.Notes,
+			})
+			if err != nil {
+				return fmt.Errorf("creating the order: %w", err)
+			}
+
+			if _, err := repos.Tables.UpdateStatus(ctx, table.ID, models.TableStatusOccupied); err != nil {
+				return fmt.Errorf("updating the table status: %w", err)
+			}
+
+			entry.Status, entry.TableID, entry.OrderID, entry.SeatedAt = models.WaitlistStatusSeated, table.ID, order.ID, &now
+			if entry, err = repos.Waitlist.Update(ctx, entry); err != nil {
+				return fmt.Errorf("updating the waitlist entry: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			respondError(c, err, "Failed to seat the party")
+			return
+		}
+
+		publishKitchenEvent(models.KitchenEvent{Type: models.KitchenEventOrderCreated, RestaurantID: order.RestaurantID, OrderID: order.ID, Status: order.Status})
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Party seated successfully", "entry": entry, "order": order})
+	}
+}
+
+// DeleteWaitlistEntry takes a party that gave up off the waitlist. The entry is kept as left.
+func DeleteWaitlistEntry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		id, err := parseID(c.Param("entry_id"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Waitlist entry ID is required"})
+			return
+		}
+
+		entry, err := activeWaitlistEntry(ctx, Repos, id)
+		if err != nil {
+			respondError(c, err, "Failed to fetch waitlist entry from database")
+			return
+		}
+
+		entry.Status = models.WaitlistStatusLeft
+		if entry, err = Repos.Waitlist.Update(ctx, entry); err != nil {
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Failed to update waitlist entry in database", "details": err.Error()})
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, gin.H{"message": "Party removed from the waitlist successfully", "entry": entry})
+	}
+}
+
+// activeWaitlistEntry loads an entry that is still waiting or notified
+func activeWaitlistEntry(ctx context.Context, repos repository.Repositories, id uint) (models.WaitlistEntry, error) {
+	entry, err := repos.Waitlist.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return entry, abortWith(http.StatusNotFound, gin.H{"error": "No waitlist entry found with given ID"})
+		}
+		return entry, err
+	}
+	if entry.Status != models.WaitlistStatusWaiting && entry.Status != models.WaitlistStatusNotified {
+		return entry, abortWith(http.StatusConflict, gin.H{"error": "Party is no longer waiting", "status": entry.Status})
+	}
+	return entry, nil
+}
+
+// quoteWait estimates in whole minutes, rounded up to the next five, how long a party
+// joining now waits behind the parties already queued
+func quoteWait(ctx context.Context, repos repository.Repositories, restaurantID uint, partySize int) (int, error) {
+	now := time.Now().UTC()
+
+	turn, paidOrders, err := repos.Orders.AverageTurnTime(ctx, restaurantID, now.Add(-turnLookback))
+	if err != nil {
+		return 0, fmt.Errorf("averaging seating durations: %w", err)
+	}
+	if paidOrders == 0 || turn <= 0 {
+		turn = defaultTurn()
+	}
+
+	tables, err := repos.Tables.List(ctx, restaurantID)
+	if err != nil {
+		return 0, fmt.Errorf("fetching tables: %w", err)
+	}
+	openOrders, err := repos.Orders.ListOpen(ctx, restaurantID)
+	if err != nil {
+		return 0, fmt.Errorf("fetching open orders: %w", err)
+	}
+	queued, err := repos.Waitlist.ListActive(ctx, restaurantID)
+	if err != nil {
+		return 0, fmt.Errorf("fetching the waitlist: %w", err)
+	}
+
+	// An occupied table frees up one turn after its oldest open order was placed
+	seatedSince := map[uint]time.Time{}
+	for _, order := range openOrders {
+		if _, ok := seatedSince[order.TableID]; !ok {
+			seatedSince[order.TableID] = order.OrderDate
+		}
+	}
+
+	turns := make([]helpers.TableTurn, 0, len(tables))
+	for _, table := range tables {
+		var freeIn time.Duration
+		switch table.Status {
+		case models.TableStatusOccupied:
+			freeIn = turn / 2 // occupied without an order, assume it is half way through
+			if since, ok := seatedSince[table.ID]; ok {
+				freeIn = max(turn-now.Sub(since), 0)
+			}
+		case models.TableStatusReserved:
+			freeIn = turn
+		}
+		turns = append(turns, helpers.TableTurn{Capacity: table.Capacity, FreeIn: freeIn})
+	}
+
+	ahead := make([]int, 0, len(queued))
+	for _, entry := range queued {
+		ahead = append(ahead, entry.PartySize)
+	}
+
+	wait, ok := helpers.EstimateWait(turns, ahead, partySize, turn)
+	if !ok {
+		return 0, abortWith(http.StatusConflict, gin.H{"error": "No table seats a party of this size", "party_size": partySize})
+	}
+
+	minutes := int((wait + time.Minute - 1) / time.Minute)
+	return (minutes + 4) / 5 * 5, nil
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"restaurant-management/models"
+	"restaurant-management/notify"
+)
+
+// seedWaitingParty stores a restaurant with a free table for four and a party of three waiting for it
+func seedWaitingParty(t *testing.T) (models.WaitlistEntry, models.Table) {
+	t.Helper()
+	ctx := context.Background()
+
+	restaurant, err := Repos.Restaurants.Create(ctx, models.Restaurant{Name: "Test Kitchen", OwnerID: 1, Address: "1 Test Street"})
+	if err != nil {
+		t.Fatalf("creating the restaurant: %v", err)
+	}
+	table, err := Repos.Tables.Create(ctx, models.Table{Name: "T4", RestaurantID: int(restaurant.ID), Capacity: 4, Status: models.TableStatusAvailable})
+	if err != nil {
+		t.Fatalf("creating the table: %v", err)
+	}
+
+	var created struct {
+		Entry models.WaitlistEntry `json:"entry"`
+	}
+	party := models.WaitlistEntry{RestaurantID: restaurant.ID, GuestName: "Ada", GuestPhone: "+15550100", PartySize: 3}
+	if recorder := serve(t, CreateWaitlistEntry(), http.MethodPost, "/waitlist", "/waitlist", party, &created); recorder.Code != http.StatusCreated {
+		t.Fatalf("adding the party answered %d: %s", recorder.Code, recorder.Body)
+	}
+	return created.Entry, table
+}
+
+func TestNotifyWaitlistEntrySendsThroughTheNotifier(t *testing.T) {
+	setup(t)
+	path := filepath.Join(t.TempDir(), "notifications.jsonl")
+	Notifier = notify.NewFileNotifier(path)
+	entry, _ := seedWaitingParty(t)
+
+	notifyPath := fmt.Sprintf("/waitlist/%d/notify", entry.ID)
+	if recorder := serve(t, NotifyWaitlistEntry(), http.MethodPost, "/waitlist/:entry_id/notify", notifyPath, nil, nil); recorder.Code != http.StatusOK {
+		t.Fatalf("notifying the party answered %d: %s", recorder.Code, recorder.Body)
+	}
+
+	sent, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading the notifications: %v", err)
+	}
+	if !strings.Contains(string(sent), entry.GuestPhone) || !strings.Contains(string(sent), "Test Kitchen") {
+		t.Errorf("the notification %s doesn't go to the guest or name the restaurant", sent)
+	}
+	entry, err = Repos.Waitlist.Get(context.Background(), entry.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if entry.Status != models.WaitlistStatusNotified || entry.NotifiedAt == nil {
+		t.Errorf("the entry is %s, want it notified", entry.Status)
+	}
+}
+
+func TestNotifyWaitlistEntryKeepsThePartyWaitingWhenSendingFails(t *testing.T) {
+	setup(t)
+	Notifier = notify.NewFileNotifier(filepath.Join(t.TempDir(), "missing", "notifications.jsonl"))
+	entry, _ := seedWaitingParty(t)
+
+	path := fmt.Sprintf("/waitlist/%d/notify", entry.ID)
+	if code := serve(t, NotifyWaitlistEntry(), http.MethodPost, "/waitlist/:entry_id/notify", path, nil, nil).Code; code != http.StatusBadGateway {
+		t.Fatalf("a failed notification answered %d, want %d", code, http.StatusBadGateway)
+	}
+
+	entry, err := Repos.Waitlist.Get(context.Background(), entry.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if entry.Status != models.WaitlistStatusWaiting {
+		t.Errorf("the entry is %s, want it still waiting so the notification can be retried", entry.Status)
+	}
+}
+
+func TestSeatWaitlistEntryOpensAnOrder(t *testing.T) {
+	setup(t)
+	ctx := context.Background()
+	entry, table := seedWaitingParty(t)
+
+	var seated struct {
+		Entry models.WaitlistEntry `json:"entry"`
+		Order models.Order         `json:"order"`
+	}
+	path := fmt.Sprintf("/waitlist/%d/seat", entry.ID)
+	if recorder := serve(t, SeatWaitlistEntry(), http.MethodPost, "/waitlist/:entry_id/seat", path, models.SeatParty{TableID: table.ID}, &seated); recorder.Code != http.StatusOK {
+		t.Fatalf("seating the party answered %d: %s", recorder.Code, recorder.Body)
+	}
+
+	order, err := Repos.Orders.Get(ctx, seated.Entry.OrderID)
+	if err != nil {
+		t.Fatalf("the party has no order: %v", err)
+	}
+	if order.TableID != table.ID || order.GuestCount != entry.PartySize {
+		t.Errorf("the order is for table %d and %d guests, want table %d and %d", order.TableID, order.GuestCount, table.ID, entry.PartySize)
+	}
+	if table, err = Repos.Tables.Get(ctx, table.ID); err != nil || table.Status != models.TableStatusOccupied {
+		t.Errorf("the table is %s, %v, want it occupied", table.Status, err)
+	}
+
+	// The party is seated, so it can't be seated again
+	if code := serve(t, SeatWaitlistEntry(), http.MethodPost, "/waitlist/:entry_id/seat", path, models.SeatParty{TableID: table.ID}, nil).Code; code == http.StatusOK {
+		t.Error("the party was seated twice")
+	}
+}
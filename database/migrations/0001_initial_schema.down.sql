@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS notes;
+DROP TABLE IF EXISTS orderitems;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS foods;
+DROP TABLE IF EXISTS tables;
+DROP TABLE IF EXISTS menus;
+DROP TABLE IF EXISTS restaurant_staff;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS restaurants;
@@ -0,0 +1,119 @@
+-- Baseline schema. Every statement is idempotent so databases created by the
+-- old SetupTables bootstrap can be brought under version control unchanged.
+
+CREATE TABLE IF NOT EXISTS restaurants (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	owner_id INTEGER NOT NULL,
+	logo VARCHAR(255),
+	address VARCHAR(500) NOT NULL,
+	description TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS users (
+	id SERIAL PRIMARY KEY,
+	username VARCHAR(50) NOT NULL,
+	password VARCHAR(60) NOT NULL,
+	email VARCHAR(50) NOT NULL,
+	phone VARCHAR(20) NOT NULL,
+	role VARCHAR(10) NOT NULL,
+	token TEXT,
+	avatar_url TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS restaurant_staff (
+	id SERIAL PRIMARY KEY,
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	role VARCHAR(10) NOT NULL CHECK (role IN ('manager', 'staff')),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (restaurant_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS menus (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(50) NOT NULL CHECK (name IN ('appetizer', 'main_course', 'dessert', 'beverage')),
+	restaurant_id INTEGER NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tables (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	capacity INTEGER NOT NULL,
+	restaurant_id INTEGER NOT NULL,
+	location VARCHAR(255),
+	status VARCHAR(10) NOT NULL CHECK (status IN ('available', 'occupied', 'reserved')),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS foods (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(100) NOT NULL,
+	price NUMERIC(10, 2) NOT NULL,
+	description TEXT,
+	image_url VARCHAR(255),
+	menu_id INTEGER NOT NULL,
+	restaurant_id INTEGER NOT NULL,
+	ingredients TEXT,
+	prep_time INTEGER DEFAULT NULL,
+	calories INTEGER DEFAULT NULL,
+	spicy_level INTEGER DEFAULT NULL,
+	vegetarian BOOLEAN DEFAULT TRUE,
+	available BOOLEAN DEFAULT TRUE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS orders (
+	id SERIAL PRIMARY KEY,
+	table_id INTEGER REFERENCES tables(id) ON DELETE CASCADE,
+	restaurant_id INTEGER REFERENCES restaurants(id) ON DELETE CASCADE,
+	order_date TIMESTAMP,
+	total_price NUMERIC(10, 2),
+	status VARCHAR(10) CHECK (status IN ('pending', 'preparing', 'ready', 'served', 'paid', 'cancelled')),
+	notes TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS orderitems (
+	id SERIAL PRIMARY KEY,
+	order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+	food_id INTEGER NOT NULL REFERENCES foods(id) ON DELETE CASCADE,
+	quantity INTEGER DEFAULT 1,
+	unit_price NUMERIC(5, 2),
+	subtotal NUMERIC(6, 2),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS notes (
+	id SERIAL PRIMARY KEY,
+	title VARCHAR(100) NOT NULL,
+	content TEXT NOT NULL,
+	priority VARCHAR(10) NOT NULL CHECK (priority IN ('low', 'medium', 'high')),
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+	id SERIAL PRIMARY KEY,
+	order_id INTEGER UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+	restaurant_id INTEGER REFERENCES restaurants(id) ON DELETE CASCADE,
+	amount NUMERIC(10, 2),
+	tax NUMERIC(10, 2),
+	total NUMERIC(10, 2),
+	status VARCHAR(10) CHECK (status IN ('pending', 'paid')),
+	payment_method VARCHAR(20) CHECK (payment_method IN ('cash', 'credit_card', 'debit_card', 'online')),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,15 @@
+-- Every status change of an order, oldest first. changed_by is NULL for changes
+-- made through the public customer routes.
+CREATE TABLE order_status_history (
+	id SERIAL PRIMARY KEY,
+	order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+	from_status VARCHAR(10) NOT NULL,
+	to_status VARCHAR(10) NOT NULL,
+	changed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX order_status_history_order_id_idx ON order_status_history (order_id, changed_at);
+
+-- Orders reserved through CreateOrderId start out without a status
+UPDATE orders SET status = 'pending' WHERE status IS NULL;
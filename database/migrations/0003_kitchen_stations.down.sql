@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS orderitems_prep_status_idx;
+ALTER TABLE orderitems DROP COLUMN IF EXISTS bumped_at;
+ALTER TABLE orderitems DROP COLUMN IF EXISTS prep_status;
+ALTER TABLE foods DROP COLUMN IF EXISTS station_id;
+ALTER TABLE menus DROP COLUMN IF EXISTS station_id;
+DROP TABLE IF EXISTS stations;
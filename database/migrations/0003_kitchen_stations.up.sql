@@ -0,0 +1,19 @@
+-- Kitchen stations (grill, bar, dessert, ...) that menus and foods are routed to.
+-- A food's own station wins over the station of its menu.
+CREATE TABLE stations (
+	id SERIAL PRIMARY KEY,
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	name VARCHAR(50) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (restaurant_id, name)
+);
+
+ALTER TABLE menus ADD COLUMN station_id INTEGER REFERENCES stations(id) ON DELETE SET NULL;
+ALTER TABLE foods ADD COLUMN station_id INTEGER REFERENCES stations(id) ON DELETE SET NULL;
+
+-- Each order item is prepared and bumped on its own
+ALTER TABLE orderitems ADD COLUMN prep_status VARCHAR(10) NOT NULL DEFAULT 'queued' CHECK (prep_status IN ('queued', 'done'));
+ALTER TABLE orderitems ADD COLUMN bumped_at TIMESTAMP;
+
+CREATE INDEX orderitems_prep_status_idx ON orderitems (prep_status, order_id);
@@ -0,0 +1,21 @@
+-- Table bookings. Only booked and seated reservations hold their table; overlaps are
+-- rejected by the application while it holds the table row lock.
+CREATE TABLE reservations (
+	id SERIAL PRIMARY KEY,
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	table_id INTEGER NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
+	guest_name VARCHAR(100) NOT NULL,
+	guest_phone VARCHAR(20),
+	guest_email VARCHAR(100),
+	party_size INTEGER NOT NULL CHECK (party_size > 0),
+	starts_at TIMESTAMP NOT NULL,
+	duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0),
+	status VARCHAR(10) NOT NULL DEFAULT 'booked' CHECK (status IN ('booked', 'seated', 'completed', 'cancelled', 'no_show')),
+	notes TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX reservations_table_slot_idx ON reservations (table_id, starts_at);
+CREATE INDEX reservations_restaurant_slot_idx ON reservations (restaurant_id, starts_at);
+CREATE INDEX reservations_status_slot_idx ON reservations (status, starts_at);
@@ -0,0 +1,19 @@
+-- Walk-in parties waiting for a table. Seating a party records the table and the order opened for it.
+CREATE TABLE waitlist_entries (
+	id SERIAL PRIMARY KEY,
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	guest_name VARCHAR(100) NOT NULL,
+	guest_phone VARCHAR(20) NOT NULL,
+	party_size INTEGER NOT NULL CHECK (party_size > 0),
+	quoted_minutes INTEGER NOT NULL DEFAULT 0,
+	status VARCHAR(10) NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'notified', 'seated', 'left')),
+	table_id INTEGER REFERENCES tables(id) ON DELETE SET NULL,
+	order_id INTEGER REFERENCES orders(id) ON DELETE SET NULL,
+	notes TEXT,
+	notified_at TIMESTAMP,
+	seated_at TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX waitlist_entries_active_idx ON waitlist_entries (restaurant_id, status, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS invoice_taxes;
+ALTER TABLE foods DROP COLUMN IF EXISTS tax_category;
+DROP TABLE IF EXISTS tax_rates;
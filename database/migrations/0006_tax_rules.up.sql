@@ -0,0 +1,31 @@
+-- Taxes a restaurant charges, each on one category of foods. Rates are percentages;
+-- inclusive rates are already part of the menu price, exclusive ones are added on top.
+CREATE TABLE tax_rates (
+	id SERIAL PRIMARY KEY,
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	name VARCHAR(50) NOT NULL,
+	category VARCHAR(20) NOT NULL CHECK (category IN ('food', 'alcohol', 'takeaway')),
+	rate NUMERIC(7, 4) NOT NULL CHECK (rate > 0 AND rate < 100),
+	inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (restaurant_id, category, name)
+);
+
+ALTER TABLE foods ADD COLUMN tax_category VARCHAR(20) NOT NULL DEFAULT 'food' CHECK (tax_category IN ('food', 'alcohol', 'takeaway'));
+
+-- Per-rate breakdown of an invoice's tax. Name, rate and inclusiveness are copied so
+-- the invoice keeps printing what was charged after the rate is edited or deleted.
+CREATE TABLE invoice_taxes (
+	id SERIAL PRIMARY KEY,
+	invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+	tax_rate_id INTEGER REFERENCES tax_rates(id) ON DELETE SET NULL,
+	name VARCHAR(50) NOT NULL,
+	category VARCHAR(20) NOT NULL,
+	rate NUMERIC(7, 4) NOT NULL,
+	inclusive BOOLEAN NOT NULL,
+	taxable_amount NUMERIC(10, 2) NOT NULL,
+	tax_amount NUMERIC(10, 2) NOT NULL
+);
+
+CREATE INDEX invoice_taxes_invoice_id_idx ON invoice_taxes (invoice_id);
@@ -0,0 +1,4 @@
+ALTER TABLE invoices DROP COLUMN IF EXISTS discount;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_total;
+DROP TABLE IF EXISTS order_discounts;
+DROP TABLE IF EXISTS promotions;
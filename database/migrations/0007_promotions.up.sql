@@ -0,0 +1,53 @@
+-- Promotions a restaurant runs. Percentage and fixed promotions take value off the
+-- items in scope; buy_x_get_y gives the cheapest get_quantity of every
+-- buy_quantity + get_quantity items in scope for free. Promotions with a code are
+-- coupons, usage_limit caps how many orders may use them (NULL for no cap).
+CREATE TABLE promotions (
+	id SERIAL PRIMARY KEY,
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	name VARCHAR(100) NOT NULL,
+	code VARCHAR(40),
+	kind VARCHAR(20) NOT NULL CHECK (kind IN ('percentage', 'fixed', 'buy_x_get_y')),
+	value NUMERIC(10, 2) NOT NULL DEFAULT 0,
+	buy_quantity INTEGER NOT NULL DEFAULT 0,
+	get_quantity INTEGER NOT NULL DEFAULT 0,
+	scope VARCHAR(10) NOT NULL DEFAULT 'order' CHECK (scope IN ('order', 'menu', 'food')),
+	menu_id INTEGER REFERENCES menus(id) ON DELETE CASCADE,
+	food_id INTEGER REFERENCES foods(id) ON DELETE CASCADE,
+	usage_limit INTEGER CHECK (usage_limit > 0),
+	times_used INTEGER NOT NULL DEFAULT 0,
+	starts_at TIMESTAMP,
+	ends_at TIMESTAMP,
+	disabled BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (restaurant_id, code)
+);
+
+-- Discounts applied to an order. The rule is copied from the promotion so editing
+-- the promotion does not change orders it was already applied to; amount is
+-- recalculated whenever the order's items change. Manual discounts stay pending
+-- until a manager approves them and only approved discounts count.
+CREATE TABLE order_discounts (
+	id SERIAL PRIMARY KEY,
+	order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+	promotion_id INTEGER REFERENCES promotions(id) ON DELETE SET NULL,
+	description VARCHAR(200) NOT NULL,
+	kind VARCHAR(20) NOT NULL CHECK (kind IN ('percentage', 'fixed', 'buy_x_get_y')),
+	value NUMERIC(10, 2) NOT NULL DEFAULT 0,
+	buy_quantity INTEGER NOT NULL DEFAULT 0,
+	get_quantity INTEGER NOT NULL DEFAULT 0,
+	scope VARCHAR(10) NOT NULL DEFAULT 'order' CHECK (scope IN ('order', 'menu', 'food')),
+	menu_id INTEGER,
+	food_id INTEGER,
+	amount NUMERIC(10, 2) NOT NULL DEFAULT 0,
+	status VARCHAR(10) NOT NULL CHECK (status IN ('pending', 'approved')),
+	requested_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	approved_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (order_id, promotion_id)
+);
+
+ALTER TABLE orders ADD COLUMN discount_total NUMERIC(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE invoices ADD COLUMN discount NUMERIC(10, 2) NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS invoice_items;
+DELETE FROM invoices WHERE parent_id IS NOT NULL;
+DROP INDEX IF EXISTS invoices_order_id_key;
+ALTER TABLE invoices DROP COLUMN IF EXISTS amount_paid;
+ALTER TABLE invoices DROP COLUMN IF EXISTS split_mode;
+ALTER TABLE invoices DROP COLUMN IF EXISTS parent_id;
+ALTER TABLE invoices ADD CONSTRAINT invoices_order_id_key UNIQUE (order_id);
@@ -0,0 +1,30 @@
+-- A check may be split into parts that are paid separately. Parts are invoices of the
+-- same order pointing at the order's invoice through parent_id, so only the order's
+-- own invoice has to be unique per order. split_mode records how the parts were made.
+ALTER TABLE invoices DROP CONSTRAINT IF EXISTS invoices_order_id_key;
+ALTER TABLE invoices ADD COLUMN parent_id INTEGER REFERENCES invoices(id) ON DELETE CASCADE;
+ALTER TABLE invoices ADD COLUMN split_mode VARCHAR(10) CHECK (split_mode IN ('items', 'even'));
+ALTER TABLE invoices ADD COLUMN amount_paid NUMERIC(10, 2) NOT NULL DEFAULT 0;
+CREATE UNIQUE INDEX invoices_order_id_key ON invoices (order_id) WHERE parent_id IS NULL;
+CREATE INDEX invoices_parent_id_idx ON invoices (parent_id);
+
+-- Order items a part split by items covers
+CREATE TABLE invoice_items (
+	invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+	order_item_id INTEGER NOT NULL REFERENCES orderitems(id) ON DELETE CASCADE,
+	PRIMARY KEY (invoice_id, order_item_id)
+);
+
+-- Payments taken against an invoice or one of its parts. amount_paid of an invoice
+-- is the sum of its payments, and of its parts' payments for the order's invoice.
+CREATE TABLE payments (
+	id SERIAL PRIMARY KEY,
+	invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+	amount NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+	method VARCHAR(20) NOT NULL CHECK (method IN ('cash', 'credit_card', 'debit_card', 'online')),
+	reference VARCHAR(100) NOT NULL DEFAULT '',
+	recorded_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX payments_invoice_id_idx ON payments (invoice_id);
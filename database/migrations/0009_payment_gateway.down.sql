@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS webhook_events;
+DROP TABLE IF EXISTS payment_intents;
+DROP INDEX IF EXISTS payments_transaction_id_key;
+ALTER TABLE payments DROP COLUMN IF EXISTS transaction_id;
+ALTER TABLE payments DROP COLUMN IF EXISTS provider;
@@ -0,0 +1,34 @@
+-- Payments taken through a payment gateway keep the provider and its transaction ID,
+-- which is unique per provider so a transaction is never recorded twice.
+ALTER TABLE payments ADD COLUMN provider VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE payments ADD COLUMN transaction_id VARCHAR(100);
+CREATE UNIQUE INDEX payments_transaction_id_key ON payments (provider, transaction_id) WHERE transaction_id IS NOT NULL;
+
+-- Online payments in progress. An intent holds the amount with the provider until it
+-- is captured, payment_id points at the payment recorded once it succeeded.
+CREATE TABLE payment_intents (
+	id SERIAL PRIMARY KEY,
+	invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+	provider VARCHAR(20) NOT NULL,
+	provider_intent_id VARCHAR(100) NOT NULL,
+	amount NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+	currency VARCHAR(3) NOT NULL,
+	status VARCHAR(20) NOT NULL CHECK (status IN ('requires_capture', 'succeeded', 'failed', 'cancelled')),
+	failure_reason VARCHAR(200) NOT NULL DEFAULT '',
+	payment_id INTEGER REFERENCES payments(id) ON DELETE SET NULL,
+	created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (provider, provider_intent_id)
+);
+
+CREATE INDEX payment_intents_invoice_id_idx ON payment_intents (invoice_id);
+
+-- Webhook events already handled, providers deliver at least once
+CREATE TABLE webhook_events (
+	provider VARCHAR(20) NOT NULL,
+	event_id VARCHAR(100) NOT NULL,
+	event_type VARCHAR(50) NOT NULL,
+	received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (provider, event_id)
+);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS payments_tip_staff_id_idx;
+ALTER TABLE payment_intents DROP COLUMN IF EXISTS tip_staff_id;
+ALTER TABLE payment_intents DROP COLUMN IF EXISTS tip;
+ALTER TABLE payments DROP COLUMN IF EXISTS tip_staff_id;
+ALTER TABLE payments DROP COLUMN IF EXISTS tip;
+DROP TABLE IF EXISTS invoice_service_charges;
+ALTER TABLE invoices DROP COLUMN IF EXISTS tip;
+ALTER TABLE invoices DROP COLUMN IF EXISTS service_charge;
+ALTER TABLE orders DROP COLUMN IF EXISTS guest_count;
+DROP TABLE IF EXISTS service_charges;
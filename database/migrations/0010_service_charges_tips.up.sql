@@ -0,0 +1,39 @@
+-- Service charges a restaurant adds to the bill automatically, such as 10% for parties of
+-- 7 or more. Rates are percentages of what the items cost after discounts, before tax.
+CREATE TABLE service_charges (
+	id SERIAL PRIMARY KEY,
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	name VARCHAR(50) NOT NULL,
+	rate NUMERIC(7, 4) NOT NULL CHECK (rate > 0 AND rate < 100),
+	min_guests INTEGER NOT NULL DEFAULT 0 CHECK (min_guests >= 0),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (restaurant_id, name)
+);
+
+-- How many guests an order is for, the table's capacity stands in while it is unknown
+ALTER TABLE orders ADD COLUMN guest_count INTEGER CHECK (guest_count > 0);
+
+ALTER TABLE invoices ADD COLUMN service_charge NUMERIC(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE invoices ADD COLUMN tip NUMERIC(10, 2) NOT NULL DEFAULT 0;
+
+-- Per-charge breakdown of an invoice's service charge, copied like the tax breakdown
+CREATE TABLE invoice_service_charges (
+	id SERIAL PRIMARY KEY,
+	invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+	service_charge_id INTEGER REFERENCES service_charges(id) ON DELETE SET NULL,
+	name VARCHAR(50) NOT NULL,
+	rate NUMERIC(7, 4) NOT NULL,
+	base_amount NUMERIC(10, 2) NOT NULL,
+	amount NUMERIC(10, 2) NOT NULL
+);
+
+CREATE INDEX invoice_service_charges_invoice_id_idx ON invoice_service_charges (invoice_id);
+
+-- Tips are paid on top of the bill and credited to one staff member
+ALTER TABLE payments ADD COLUMN tip NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (tip >= 0);
+ALTER TABLE payments ADD COLUMN tip_staff_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE payment_intents ADD COLUMN tip NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (tip >= 0);
+ALTER TABLE payment_intents ADD COLUMN tip_staff_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX payments_tip_staff_id_idx ON payments (tip_staff_id) WHERE tip > 0;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS refunds;
+DROP TABLE IF EXISTS credit_note_items;
+DROP TABLE IF EXISTS credit_notes;
+ALTER TABLE invoices DROP COLUMN IF EXISTS credited;
+DROP TABLE IF EXISTS document_sequences;
@@ -0,0 +1,70 @@
+-- Gapless numbering of a restaurant's documents, one counter per kind of document. The
+-- counter row stays locked until the transaction taking a number ends.
+CREATE TABLE document_sequences (
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	kind VARCHAR(20) NOT NULL,
+	last_value INTEGER NOT NULL,
+	PRIMARY KEY (restaurant_id, kind)
+);
+
+-- What credit notes have given back of an order's invoice, the invoice itself never changes once paid
+ALTER TABLE invoices ADD COLUMN credited NUMERIC(10, 2) NOT NULL DEFAULT 0;
+
+-- Credit notes correct a paid invoice, crediting voided items or an amount. Amounts are
+-- split like the invoice's: net, tax and service charge add up to the total.
+CREATE TABLE credit_notes (
+	id SERIAL PRIMARY KEY,
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+	order_id INTEGER NOT NULL,
+	sequence INTEGER NOT NULL,
+	number VARCHAR(20) NOT NULL,
+	reason_code VARCHAR(30) NOT NULL CHECK (reason_code IN ('customer_complaint', 'wrong_item', 'quality', 'overcharge', 'goodwill', 'other')),
+	notes VARCHAR(500) NOT NULL DEFAULT '',
+	amount NUMERIC(10, 2) NOT NULL,
+	tax NUMERIC(10, 2) NOT NULL,
+	service_charge NUMERIC(10, 2) NOT NULL,
+	total NUMERIC(10, 2) NOT NULL CHECK (total > 0),
+	created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (restaurant_id, sequence)
+);
+
+CREATE INDEX credit_notes_invoice_id_idx ON credit_notes (invoice_id);
+
+-- Items voided by a credit note. Name and unit price are copied so the note reads the
+-- same after the food changes.
+CREATE TABLE credit_note_items (
+	id SERIAL PRIMARY KEY,
+	credit_note_id INTEGER NOT NULL REFERENCES credit_notes(id) ON DELETE CASCADE,
+	order_item_id INTEGER REFERENCES orderitems(id) ON DELETE SET NULL,
+	food_name VARCHAR(100) NOT NULL,
+	quantity INTEGER NOT NULL CHECK (quantity > 0),
+	unit_price NUMERIC(10, 2) NOT NULL,
+	amount NUMERIC(10, 2) NOT NULL,
+	reason_code VARCHAR(30) NOT NULL
+);
+
+CREATE INDEX credit_note_items_credit_note_id_idx ON credit_note_items (credit_note_id);
+CREATE INDEX credit_note_items_order_item_id_idx ON credit_note_items (order_item_id);
+
+-- Money a credit note gives back, against the payments it was taken with. Refunds of
+-- online payments go through the provider and are pending until it confirms them.
+CREATE TABLE refunds (
+	id SERIAL PRIMARY KEY,
+	credit_note_id INTEGER NOT NULL REFERENCES credit_notes(id) ON DELETE CASCADE,
+	payment_id INTEGER NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+	amount NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+	method VARCHAR(20) NOT NULL,
+	provider VARCHAR(20) NOT NULL DEFAULT '',
+	provider_refund_id VARCHAR(100),
+	status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'succeeded', 'failed')),
+	failure_reason VARCHAR(200) NOT NULL DEFAULT '',
+	created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX refunds_credit_note_id_idx ON refunds (credit_note_id);
+CREATE INDEX refunds_payment_id_idx ON refunds (payment_id);
+CREATE UNIQUE INDEX refunds_provider_refund_id_key ON refunds (provider, provider_refund_id) WHERE provider_refund_id IS NOT NULL;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS invoices_restaurant_id_number_key;
+ALTER TABLE invoices DROP COLUMN IF EXISTS issued_at;
+ALTER TABLE invoices DROP COLUMN IF EXISTS number;
+ALTER TABLE credit_notes DROP CONSTRAINT IF EXISTS credit_notes_restaurant_id_period_sequence_key;
+DELETE FROM credit_notes WHERE period <> 0;
+ALTER TABLE credit_notes DROP COLUMN IF EXISTS period;
+ALTER TABLE credit_notes ADD CONSTRAINT credit_notes_restaurant_id_sequence_key UNIQUE (restaurant_id, sequence);
+DELETE FROM document_sequences WHERE period <> 0;
+ALTER TABLE document_sequences DROP CONSTRAINT document_sequences_pkey;
+ALTER TABLE document_sequences DROP COLUMN IF EXISTS period;
+ALTER TABLE document_sequences ADD PRIMARY KEY (restaurant_id, kind);
+DROP TABLE IF EXISTS number_series;
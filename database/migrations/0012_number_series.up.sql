@@ -0,0 +1,28 @@
+-- How a restaurant numbers each kind of document. Yearly series start again at 1 every
+-- fiscal year, which starts in the month given, and carry the year in their numbers.
+CREATE TABLE number_series (
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	kind VARCHAR(20) NOT NULL CHECK (kind IN ('invoice', 'credit_note')),
+	prefix VARCHAR(20) NOT NULL DEFAULT '',
+	padding INTEGER NOT NULL CHECK (padding BETWEEN 1 AND 12),
+	reset VARCHAR(10) NOT NULL CHECK (reset IN ('never', 'yearly')),
+	fiscal_year_start INTEGER NOT NULL DEFAULT 1 CHECK (fiscal_year_start BETWEEN 1 AND 12),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (restaurant_id, kind)
+);
+
+-- Counters are kept per fiscal year, 0 for series that never reset
+ALTER TABLE document_sequences ADD COLUMN period INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE document_sequences DROP CONSTRAINT document_sequences_pkey;
+ALTER TABLE document_sequences ADD PRIMARY KEY (restaurant_id, kind, period);
+
+ALTER TABLE credit_notes ADD COLUMN period INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE credit_notes DROP CONSTRAINT credit_notes_restaurant_id_sequence_key;
+ALTER TABLE credit_notes ADD CONSTRAINT credit_notes_restaurant_id_period_sequence_key UNIQUE (restaurant_id, period, sequence);
+
+-- An order's invoice is numbered once its order is paid and keeps that number for good.
+-- Invoices paid before numbering existed stay without one.
+ALTER TABLE invoices ADD COLUMN number VARCHAR(40);
+ALTER TABLE invoices ADD COLUMN issued_at TIMESTAMP;
+CREATE UNIQUE INDEX invoices_restaurant_id_number_key ON invoices (restaurant_id, number) WHERE number IS NOT NULL;
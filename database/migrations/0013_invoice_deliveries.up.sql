@@ -0,0 +1,17 @@
+-- Invoices emailed to customers. Each send is one delivery, tried a few times before it
+-- is given up as failed; last_error keeps what the mail server said the last time.
+CREATE TABLE invoice_deliveries (
+	id SERIAL PRIMARY KEY,
+	invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	recipient VARCHAR(100) NOT NULL,
+	status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NOT NULL DEFAULT '',
+	sent_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	sent_at TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX invoice_deliveries_invoice_id_idx ON invoice_deliveries (invoice_id);
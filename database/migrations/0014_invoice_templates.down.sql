@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS invoice_template_tax_ids;
+DROP TABLE IF EXISTS invoice_templates;
@@ -0,0 +1,22 @@
+-- How a restaurant's invoices and credit notes are printed, restaurants without a row use the defaults
+CREATE TABLE invoice_templates (
+	restaurant_id INTEGER PRIMARY KEY REFERENCES restaurants(id) ON DELETE CASCADE,
+	show_logo BOOLEAN NOT NULL DEFAULT TRUE,
+	show_address BOOLEAN NOT NULL DEFAULT TRUE,
+	footer_text VARCHAR(500) NOT NULL DEFAULT '',
+	accent_color VARCHAR(7) NOT NULL DEFAULT '',
+	paper_size VARCHAR(20) NOT NULL DEFAULT 'a4' CHECK (paper_size IN ('a4', 'receipt_80mm')),
+	payment_qr BOOLEAN NOT NULL DEFAULT FALSE,
+	payment_url VARCHAR(300) NOT NULL DEFAULT '',
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Registration numbers printed under the address, in order
+CREATE TABLE invoice_template_tax_ids (
+	restaurant_id INTEGER NOT NULL REFERENCES invoice_templates(restaurant_id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	label VARCHAR(30) NOT NULL,
+	value VARCHAR(50) NOT NULL,
+	PRIMARY KEY (restaurant_id, position)
+);
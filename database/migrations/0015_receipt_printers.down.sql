@@ -0,0 +1,2 @@
+ALTER TABLE stations DROP COLUMN IF EXISTS printer_address;
+ALTER TABLE invoice_templates DROP COLUMN IF EXISTS receipt_printer;
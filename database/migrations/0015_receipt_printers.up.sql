@@ -0,0 +1,3 @@
+-- Network addresses (host:port) of the thermal printers receipts and kitchen tickets are pushed to
+ALTER TABLE invoice_templates ADD COLUMN receipt_printer VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE stations ADD COLUMN printer_address VARCHAR(255) NOT NULL DEFAULT '';
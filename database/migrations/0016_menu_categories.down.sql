@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS menus_restaurant_id_parent_id_idx;
+UPDATE menus SET name = CASE name WHEN 'Appetizers' THEN 'appetizer' WHEN 'Main Courses' THEN 'main_course' WHEN 'Desserts' THEN 'dessert' WHEN 'Beverages' THEN 'beverage' ELSE name END;
+ALTER TABLE menus DROP COLUMN IF EXISTS parent_id;
+ALTER TABLE menus DROP COLUMN IF EXISTS position;
+ALTER TABLE menus DROP COLUMN IF EXISTS image;
+ALTER TABLE menus DROP COLUMN IF EXISTS description;
+-- Categories created since keep their names, foods are still listed under them
+ALTER TABLE menus ADD CONSTRAINT menus_name_check CHECK (name IN ('appetizer', 'main_course', 'dessert', 'beverage')) NOT VALID;
@@ -0,0 +1,15 @@
+-- Menus become restaurant-defined categories: any name, a description and image, a display
+-- order and sub-categories. The four fixed names are mapped to display names in their old order.
+ALTER TABLE menus DROP CONSTRAINT IF EXISTS menus_name_check;
+ALTER TABLE menus ALTER COLUMN name TYPE VARCHAR(100);
+ALTER TABLE menus ADD COLUMN description VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE menus ADD COLUMN image VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE menus ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+-- Sub-categories of a deleted category move up to the top level
+ALTER TABLE menus ADD COLUMN parent_id INTEGER REFERENCES menus(id) ON DELETE SET NULL;
+
+UPDATE menus SET
+	position = CASE name WHEN 'appetizer' THEN 1 WHEN 'main_course' THEN 2 WHEN 'dessert' THEN 3 WHEN 'beverage' THEN 4 ELSE 0 END,
+	name = CASE name WHEN 'appetizer' THEN 'Appetizers' WHEN 'main_course' THEN 'Main Courses' WHEN 'dessert' THEN 'Desserts' WHEN 'beverage' THEN 'Beverages' ELSE name END;
+
+CREATE INDEX menus_restaurant_id_parent_id_idx ON menus (restaurant_id, parent_id);
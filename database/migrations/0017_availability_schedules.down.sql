@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS food_availability;
+DROP TABLE IF EXISTS menu_availability;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS time_zone;
@@ -0,0 +1,29 @@
+-- Menus are scheduled in the restaurant's own time zone
+ALTER TABLE restaurants ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- When menus and foods can be ordered, in any of their windows. Days is a comma separated
+-- list of mon..sun, an end_time before start_time runs past midnight. Without windows a
+-- menu or food can always be ordered.
+CREATE TABLE menu_availability (
+	menu_id INTEGER NOT NULL REFERENCES menus(id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	days VARCHAR(27) NOT NULL DEFAULT '',
+	start_time TIME,
+	end_time TIME,
+	start_date DATE,
+	end_date DATE,
+	PRIMARY KEY (menu_id, position),
+	CHECK ((start_time IS NULL) = (end_time IS NULL))
+);
+
+CREATE TABLE food_availability (
+	food_id INTEGER NOT NULL REFERENCES foods(id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	days VARCHAR(27) NOT NULL DEFAULT '',
+	start_time TIME,
+	end_time TIME,
+	start_date DATE,
+	end_date DATE,
+	PRIMARY KEY (food_id, position),
+	CHECK ((start_time IS NULL) = (end_time IS NULL))
+);
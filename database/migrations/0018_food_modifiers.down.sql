@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS orderitem_modifiers;
+ALTER TABLE orderitems DROP COLUMN IF EXISTS variant_name;
+ALTER TABLE orderitems DROP COLUMN IF EXISTS variant_id;
+DROP TABLE IF EXISTS modifiers;
+DROP TABLE IF EXISTS modifier_groups;
+DROP TABLE IF EXISTS food_variants;
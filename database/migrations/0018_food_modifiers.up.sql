@@ -0,0 +1,45 @@
+-- Sizes or versions of a food, each with its own price
+CREATE TABLE food_variants (
+	id SERIAL PRIMARY KEY,
+	food_id INTEGER NOT NULL REFERENCES foods(id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	name VARCHAR(50) NOT NULL,
+	price NUMERIC(10, 2) NOT NULL CHECK (price >= 0)
+);
+CREATE INDEX food_variants_food_id_idx ON food_variants (food_id);
+
+-- Choices made when ordering a food and their options, max_selections 0 is no limit
+CREATE TABLE modifier_groups (
+	id SERIAL PRIMARY KEY,
+	food_id INTEGER NOT NULL REFERENCES foods(id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	name VARCHAR(50) NOT NULL,
+	required BOOLEAN NOT NULL DEFAULT FALSE,
+	min_selections INTEGER NOT NULL DEFAULT 0 CHECK (min_selections >= 0),
+	max_selections INTEGER NOT NULL DEFAULT 0 CHECK (max_selections >= 0)
+);
+CREATE INDEX modifier_groups_food_id_idx ON modifier_groups (food_id);
+
+CREATE TABLE modifiers (
+	id SERIAL PRIMARY KEY,
+	group_id INTEGER NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	name VARCHAR(50) NOT NULL,
+	price_delta NUMERIC(10, 2) NOT NULL DEFAULT 0
+);
+CREATE INDEX modifiers_group_id_idx ON modifiers (group_id);
+
+-- Order items keep the variant and modifiers they were ordered with, by name and price, when
+-- the food changes later
+ALTER TABLE orderitems ADD COLUMN variant_id INTEGER REFERENCES food_variants(id) ON DELETE SET NULL;
+ALTER TABLE orderitems ADD COLUMN variant_name VARCHAR(50) NOT NULL DEFAULT '';
+
+CREATE TABLE orderitem_modifiers (
+	order_item_id INTEGER NOT NULL REFERENCES orderitems(id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	modifier_id INTEGER REFERENCES modifiers(id) ON DELETE SET NULL,
+	group_name VARCHAR(50) NOT NULL,
+	name VARCHAR(50) NOT NULL,
+	price_delta NUMERIC(10, 2) NOT NULL DEFAULT 0,
+	PRIMARY KEY (order_item_id, position)
+);
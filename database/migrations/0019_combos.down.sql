@@ -0,0 +1,10 @@
+DELETE FROM orderitems WHERE combo_id IS NOT NULL;
+DROP INDEX IF EXISTS orderitems_parent_id_idx;
+ALTER TABLE orderitems DROP CONSTRAINT IF EXISTS orderitems_food_or_combo_check;
+ALTER TABLE orderitems DROP COLUMN IF EXISTS slot_name;
+ALTER TABLE orderitems DROP COLUMN IF EXISTS parent_id;
+ALTER TABLE orderitems DROP COLUMN IF EXISTS combo_id;
+ALTER TABLE orderitems ALTER COLUMN food_id SET NOT NULL;
+DROP TABLE IF EXISTS combo_slot_choices;
+DROP TABLE IF EXISTS combo_slots;
+DROP TABLE IF EXISTS combos;
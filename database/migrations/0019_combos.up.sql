@@ -0,0 +1,43 @@
+-- Meals sold at a bundle price, made of slots each filled with a food
+CREATE TABLE combos (
+	id SERIAL PRIMARY KEY,
+	restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+	name VARCHAR(100) NOT NULL,
+	description VARCHAR(500) NOT NULL DEFAULT '',
+	image_url VARCHAR(255) NOT NULL DEFAULT '',
+	price NUMERIC(10, 2) NOT NULL CHECK (price > 0),
+	tax_category VARCHAR(20) NOT NULL DEFAULT 'food' CHECK (tax_category IN ('food', 'alcohol', 'takeaway')),
+	available BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX combos_restaurant_id_idx ON combos (restaurant_id);
+
+-- A slot comes with its food unless one of its choices is picked instead, for price_delta more
+CREATE TABLE combo_slots (
+	id SERIAL PRIMARY KEY,
+	combo_id INTEGER NOT NULL REFERENCES combos(id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	name VARCHAR(50) NOT NULL,
+	quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+	food_id INTEGER NOT NULL REFERENCES foods(id) ON DELETE CASCADE
+);
+CREATE INDEX combo_slots_combo_id_idx ON combo_slots (combo_id);
+
+CREATE TABLE combo_slot_choices (
+	slot_id INTEGER NOT NULL REFERENCES combo_slots(id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	food_id INTEGER NOT NULL REFERENCES foods(id) ON DELETE CASCADE,
+	price_delta NUMERIC(10, 2) NOT NULL DEFAULT 0,
+	PRIMARY KEY (slot_id, food_id)
+);
+
+-- An ordered combo is one order item with a component item per slot under it. Components
+-- are prepared like any item and carry the combo's revenue attributed to them, their
+-- subtotals add up to the combo's and are left out of the order total.
+ALTER TABLE orderitems ALTER COLUMN food_id DROP NOT NULL;
+ALTER TABLE orderitems ADD COLUMN combo_id INTEGER REFERENCES combos(id) ON DELETE CASCADE;
+ALTER TABLE orderitems ADD COLUMN parent_id INTEGER REFERENCES orderitems(id) ON DELETE CASCADE;
+ALTER TABLE orderitems ADD COLUMN slot_name VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE orderitems ADD CONSTRAINT orderitems_food_or_combo_check CHECK ((food_id IS NULL) <> (combo_id IS NULL));
+CREATE INDEX orderitems_parent_id_idx ON orderitems (parent_id);
@@ -0,0 +1,3 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS allergens;
+ALTER TABLE foods DROP COLUMN IF EXISTS dietary_tags;
+ALTER TABLE foods DROP COLUMN IF EXISTS allergens;
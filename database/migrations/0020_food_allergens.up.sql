@@ -0,0 +1,7 @@
+-- Allergens are slugs of the 14 EU allergens, dietary tags the diets a food suits
+ALTER TABLE foods ADD COLUMN IF NOT EXISTS allergens TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE foods ADD COLUMN IF NOT EXISTS dietary_tags TEXT[] NOT NULL DEFAULT '{}';
+UPDATE foods SET dietary_tags = '{vegetarian}' WHERE vegetarian;
+
+-- The allergens the guests at an order declared
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS allergens TEXT[] NOT NULL DEFAULT '{}';
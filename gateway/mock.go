@@ -0,0 +1,164 @@
+package gateway
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// signatureTolerance is how old a webhook signature may be before it is refused as a replay
+const signatureTolerance = 5 * time.Minute
+
+// Mock is a payment provider that keeps everything in memory and never moves real money,
+// for development and tests. Its webhooks are signed like a real provider's, so they
+// can be sent to the webhook endpoint with a signature from Sign.
+type Mock struct {
+	secret []byte
+
+	mu       sync.Mutex
+	intents  map[string]Intent
+	captured map[string]float64 // by transaction ID, what is left to refund
+}
+
+func NewMock(secret string) *Mock {
+	return &Mock{secret: []byte(secret), intents: map[string]Intent{}, captured: map[string]float64{}}
+}
+
+func (m *Mock) Name() string {
+	return "mock"
+}
+
+func (m *Mock) CreateIntent(ctx context.Context, request IntentRequest) (Intent, error) {
+	if request.Amount <= 0 {
+		return Intent{}, fmt.Errorf("intent amount must be positive")
+	}
+
+	id := "pi_mock_" + randomHex(12)
+	intent := Intent{
+		ID:           id,
+		Amount:       request.Amount,
+		Currency:     request.Currency,
+		Status:       IntentRequiresCapture,
+		ClientSecret: id + "_secret_" + randomHex(12),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.intents[id] = intent
+	return intent, nil
+}
+
+func (m *Mock) GetIntent(ctx context.Context, intentID string) (Intent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	intent, ok := m.intents[intentID]
+	if !ok {
+		return Intent{}, fmt.Errorf("no such intent %q", intentID)
+	}
+	intent.ClientSecret = ""
+	return intent, nil
+}
+
+func (m *Mock) Capture(ctx context.Context, intentID string) (Intent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	intent, ok := m.intents[intentID]
+	if !ok {
+		return Intent{}, fmt.Errorf("no such intent %q", intentID)
+	}
+	// Capturing twice is harmless, like with real providers
+	if intent.Status == IntentSucceeded {
+		return intent, nil
+	}
+	if intent.Status != IntentRequiresCapture {
+		return intent, fmt.Errorf("%w: intent is %s", ErrDeclined, intent.Status)
+	}
+
+	intent.Status, intent.TransactionID = IntentSucceeded, "ch_mock_"+randomHex(12)
+	m.intents[intentID] = intent
+	m.captured[intent.TransactionID] = intent.Amount
+	return intent, nil
+}
+
+func (m *Mock) Refund(ctx context.Context, transactionID string, amount float64) (Refund, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	left, ok := m.captured[transactionID]
+	if !ok {
+		return Refund{}, fmt.Errorf("no such transaction %q", transactionID)
+	}
+	if amount <= 0 || math.Round(amount*100) > math.Round(left*100) {
+		return Refund{}, fmt.Errorf("%w: refund of %.2f is more than the %.2f left on the transaction", ErrDeclined, amount, left)
+	}
+
+	m.captured[transactionID] = math.Round((left-amount)*100) / 100
+	return Refund{ID: "re_mock_" + randomHex(12), TransactionID: transactionID, Amount: amount, Status: IntentSucceeded}, nil
+}
+
+// VerifyWebhook accepts a signature of the form t=<unix seconds>,v1=<hex HMAC-SHA256 of "t.payload">
+func (m *Mock) VerifyWebhook(payload []byte, signature string) (Event, error) {
+	var timestamp, digest string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			digest = value
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || digest == "" {
+		return Event{}, ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > signatureTolerance || age < -signatureTolerance {
+		return Event{}, ErrInvalidSignature
+	}
+	expected, err := hex.DecodeString(digest)
+	if err != nil || !hmac.Equal(expected, m.mac(timestamp, payload)) {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, fmt.Errorf("decoding webhook event: %w", err)
+	}
+	if event.ID == "" || event.Type == "" {
+		return Event{}, fmt.Errorf("webhook event needs an id and a type")
+	}
+	return event, nil
+}
+
+// Sign returns the signature header the mock provider would send a payload with at the given time
+func (m *Mock) Sign(payload []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(m.mac(timestamp, payload))
+}
+
+func (m *Mock) mac(timestamp string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func randomHex(bytes int) string {
+	buf := make([]byte, bytes)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,97 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMockCaptureAndRefund(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMock("secret")
+
+	intent, err := mock.CreateIntent(ctx, IntentRequest{Amount: 20, Currency: "usd"})
+	if err != nil {
+		t.Fatalf("CreateIntent: %v", err)
+	}
+	if intent.Status != IntentRequiresCapture || intent.ClientSecret == "" {
+		t.Fatalf("new intent is %s with client secret %q, want it waiting for capture with one", intent.Status, intent.ClientSecret)
+	}
+
+	captured, err := mock.Capture(ctx, intent.ID)
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	if captured.Status != IntentSucceeded || captured.TransactionID == "" {
+		t.Fatalf("captured intent is %s with transaction %q", captured.Status, captured.TransactionID)
+	}
+	if again, err := mock.Capture(ctx, intent.ID); err != nil || again.TransactionID != captured.TransactionID {
+		t.Errorf("capturing twice gave %+v, %v, want the same transaction", again, err)
+	}
+
+	fetched, err := mock.GetIntent(ctx, intent.ID)
+	if err != nil {
+		t.Fatalf("GetIntent: %v", err)
+	}
+	if fetched.Status != IntentSucceeded || fetched.TransactionID != captured.TransactionID || fetched.ClientSecret != "" {
+		t.Errorf("GetIntent gave %+v, want the captured intent without its client secret", fetched)
+	}
+	if _, err := mock.GetIntent(ctx, "pi_unknown"); err == nil {
+		t.Error("GetIntent found an unknown intent")
+	}
+
+	if _, err := mock.Refund(ctx, captured.TransactionID, 15); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if _, err := mock.Refund(ctx, captured.TransactionID, 5.01); !errors.Is(err, ErrDeclined) {
+		t.Errorf("refunding more than is left gave %v, want ErrDeclined", err)
+	}
+	if _, err := mock.Refund(ctx, captured.TransactionID, 5); err != nil {
+		t.Errorf("refunding what is left: %v", err)
+	}
+}
+
+func TestMockVerifyWebhook(t *testing.T) {
+	mock := NewMock("secret")
+	payload := []byte(`{"id":"evt_1","type":"payment_intent.succeeded","intent_id":"pi_1","amount":20}`)
+
+	event, err := mock.VerifyWebhook(payload, mock.Sign(payload, time.Now()))
+	if err != nil {
+		t.Fatalf("VerifyWebhook: %v", err)
+	}
+	if event.ID != "evt_1" || event.Type != EventIntentSucceeded || event.Amount != 20 {
+		t.Errorf("decoded %+v", event)
+	}
+
+	tampered := []byte(`{"id":"evt_1","type":"payment_intent.succeeded","intent_id":"pi_1","amount":2000}`)
+	forged := NewMock("other secret")
+	for name, signature := range map[string]string{
+		"tampered payload": mock.Sign(payload, time.Now()),
+		"other secret":     forged.Sign(tampered, time.Now()),
+		"stale":            mock.Sign(tampered, time.Now().Add(-time.Hour)),
+		"missing":          "",
+	} {
+		if _, err := mock.VerifyWebhook(tampered, signature); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s signature gave %v, want ErrInvalidSignature", name, err)
+		}
+	}
+}
+
+func TestFromEnvNeedsAWebhookSecret(t *testing.T) {
+	t.Setenv("PAYMENT_PROVIDER", "")
+	t.Setenv("PAYMENT_WEBHOOK_SECRET", "")
+	if _, err := FromEnv(); err == nil {
+		t.Error("a provider was built without a webhook secret")
+	}
+
+	t.Setenv("PAYMENT_WEBHOOK_SECRET", "secret")
+	if provider, err := FromEnv(); err != nil || provider.Name() != "mock" {
+		t.Errorf("FromEnv gave %v, %v, want the mock provider", provider, err)
+	}
+
+	t.Setenv("PAYMENT_PROVIDER", "cash-in-a-drawer")
+	if _, err := FromEnv(); err == nil {
+		t.Error("an unknown provider was built")
+	}
+}
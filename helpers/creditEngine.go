@@ -0,0 +1,114 @@
+package helpers
+
+import (
+	"fmt"
+
+	"restaurant-management/models"
+)
+
+// CreditItems works out what voiding quantities of order items gives back of an order's
+// paid invoice. A voided unit is credited what it cost after discounts, given by item ID
+// in discounts, with its share of the tax lines of its category and of the service
+// charges. voided holds the quantities earlier credit notes voided, which can't be voided
+// again, and voids without a reason of their own take the credit note's.
+func CreditItems(invoice models.Invoice, items []models.OrderItem, discounts map[uint]float64, voided map[uint]uint, voids []models.VoidItem, reason string) (models.CreditNote, error) {
+	byID := map[uint]models.OrderItem{}
+	byCategory := map[string]int64{}
+	var gross int64
+	for _, item := range items {
+		byID[item.ID] = item
+		amount := ToCents(item.SubTotal) - ToCents(discounts[item.ID])
+		byCategory[item.TaxCategory] += amount
+		gross += amount
+	}
+
+	requested := map[uint]uint{}
+	creditedByCategory := map[string]int64{}
+	var credited int64
+	lines := make([]models.CreditNoteItem, 0, len(voids))
+	for _, void := range voids {
+		item, ok := byID[void.OrderItemID]
+		if !ok {
+			return models.CreditNote{}, fmt.Errorf("order item %d is not on this order", void.OrderItemID)
+		}
+		requested[item.ID] += void.Quantity
+		if left := item.Quantity - voided[item.ID]; requested[item.ID] > left {
+			return models.CreditNote{}, fmt.Errorf("only %d of order item %d are left to void", left, item.ID)
+		}
+
+		cost := ToCents(item.SubTotal) - ToCents(discounts[item.ID])
+		amount := divRound(cost*int64(void.Quantity), int64(item.Quantity))
+		creditedByCategory[item.TaxCategory] += amount
+		credited += amount
+
+		lineReason := void.ReasonCode
+		if lineReason == "" {
+			lineReason = reason
+		}
+		lines = append(lines, models.CreditNoteItem{
+			OrderItemID: item.ID,
+			FoodName:    item.DisplayName(),
+			Quantity:    void.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      FromCents(amount),
+			ReasonCode:  lineReason,
+		})
+	}
+
+	// Inclusive taxes are already part of what the items cost, exclusive ones and service come on top
+	total := credited
+	var tax, service int64
+	for _, line := range invoice.Taxes {
+		if byCategory[line.Category] == 0 {
+			continue
+		}
+		share := divRound(ToCents(line.TaxAmount)*creditedByCategory[line.Category], byCategory[line.Category])
+		tax += share
+		if !line.Inclusive {
+			total += share
+		}
+	}
+	if gross != 0 {
+		for _, charge := range invoice.ServiceCharges {
+			service += divRound(ToCents(charge.Amount)*credited, gross)
+		}
+	}
+	total += service
+
+	note := creditNote(invoice, total, tax, service)
+	note.ReasonCode, note.Items = reason, lines
+	return note, nil
+}
+
+// CreditAmount splits an amount given back of an order's paid invoice into net, tax and
+// service charge, in the proportions the invoice charged them
+func CreditAmount(invoice models.Invoice, amount float64, reason string) models.CreditNote {
+	total := ToCents(amount)
+	var tax, service int64
+	if invoiceTotal := ToCents(invoice.Total); invoiceTotal != 0 {
+		tax = divRound(ToCents(invoice.Tax)*total, invoiceTotal)
+		service = divRound(ToCents(invoice.ServiceCharge)*total, invoiceTotal)
+	}
+
+	note := creditNote(invoice, total, tax, service)
+	note.ReasonCode = reason
+	return note
+}
+
+// creditNote fills in the totals of a credit note on the invoice. A credit is never more
+// than is left to credit of the invoice, tax and service shrink with it in proportion.
+func creditNote(invoice models.Invoice, total, tax, service int64) models.CreditNote {
+	if left := max(ToCents(invoice.Total)-ToCents(invoice.Credited), 0); total > left {
+		tax, service = divRound(tax*left, total), divRound(service*left, total)
+		total = left
+	}
+	return models.CreditNote{
+		RestaurantID:  invoice.RestaurantID,
+		InvoiceID:     invoice.ID,
+		OrderID:       invoice.OrderID,
+		Amount:        FromCents(total - tax - service),
+		Tax:           FromCents(tax),
+		ServiceCharge: FromCents(service),
+		Total:         FromCents(total),
+	}
+}
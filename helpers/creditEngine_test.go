@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"testing"
+
+	"restaurant-management/models"
+)
+
+func TestCreditNotes(t *testing.T) {
+	items := []models.OrderItem{
+		{ID: 1, FoodName: "Bread", Quantity: 2, UnitPrice: 5, SubTotal: 10, TaxCategory: "food"},
+		{ID: 2, FoodName: "Wine", Quantity: 1, UnitPrice: 20, SubTotal: 20, TaxCategory: "alcohol"},
+	}
+	invoice := models.Invoice{
+		ID:            1,
+		Amount:        26.67,
+		Tax:           4.33,
+		ServiceCharge: 3,
+		Total:         34,
+		Taxes: []models.InvoiceTax{
+			{Category: "food", Rate: 10, TaxableAmount: 10, TaxAmount: 1},
+			{Category: "alcohol", Rate: 20, Inclusive: true, TaxableAmount: 16.67, TaxAmount: 3.33},
+		},
+		ServiceCharges: []models.InvoiceServiceCharge{{Rate: 10, BaseAmount: 30, Amount: 3}},
+	}
+	credited := invoice
+	credited.Credited = 30
+
+	voids := func(invoice models.Invoice, voided map[uint]uint, voids ...models.VoidItem) func() (models.CreditNote, error) {
+		return func() (models.CreditNote, error) {
+			return CreditItems(invoice, items, nil, voided, voids, "wrong_item")
+		}
+	}
+	amount := func(invoice models.Invoice, amount float64) func() (models.CreditNote, error) {
+		return func() (models.CreditNote, error) { return CreditAmount(invoice, amount, "goodwill"), nil }
+	}
+
+	tests := []struct {
+		name                        string
+		credit                      func() (models.CreditNote, error)
+		total, tax, service, amount float64
+		fails                       bool
+	}{
+		{"an exclusive tax is credited on top", voids(invoice, nil, models.VoidItem{OrderItemID: 1, Quantity: 1}), 6, 0.5, 0.5, 5, false},
+		{"an inclusive tax is part of the item's price", voids(invoice, nil, models.VoidItem{OrderItemID: 2, Quantity: 1}), 22, 3.33, 2, 16.67, false},
+		{"an amount is split like the invoice", amount(invoice, 17), 17, 2.17, 1.5, 13.33, false},
+		{"voided items never credit more than is left", voids(credited, nil, models.VoidItem{OrderItemID: 2, Quantity: 1}), 4, 0.61, 0.36, 3.03, false},
+		{"an amount never credits more than is left", amount(credited, 17), 4, 0.51, 0.35, 3.14, false},
+		{"a fully credited invoice gives nothing back", amount(models.Invoice{Total: 34, Tax: 4.33, Credited: 34}, 10), 0, 0, 0, 0, false},
+		{"units voided before can't be voided again", voids(invoice, map[uint]uint{1: 2}, models.VoidItem{OrderItemID: 1, Quantity: 1}), 0, 0, 0, 0, true},
+		{"more units than were ordered can't be voided", voids(invoice, nil, models.VoidItem{OrderItemID: 1, Quantity: 3}), 0, 0, 0, 0, true},
+		{"items of other orders can't be voided", voids(invoice, nil, models.VoidItem{OrderItemID: 9, Quantity: 1}), 0, 0, 0, 0, true},
+	}
+	for _, test := range tests {
+		note, err := test.credit()
+		if test.fails {
+			if err == nil {
+				t.Errorf("%s: the credit note was made", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if note.Total != test.total || note.Tax != test.tax || note.ServiceCharge != test.service || note.Amount != test.amount {
+			t.Errorf("%s: credited %v with %v tax, %v service and %v net, want %v, %v, %v and %v",
+				test.name, note.Total, note.Tax, note.ServiceCharge, note.Amount, test.total, test.tax, test.service, test.amount)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package helpers
+
+import (
+	"sort"
+
+	"restaurant-management/models"
+)
+
+// DiscountResult is what an order's discounts take off, in currency units
+type DiscountResult struct {
+	Amounts map[uint]float64 // by order discount ID
+	PerItem map[uint]float64 // by order item ID, what the item's subtotal is reduced by
+	Total   float64
+}
+
+// ComputeDiscounts works out the approved discounts of an order against its items. Discounts
+// apply in the order they were added, each to what earlier ones left of the items in its
+// scope, so together they never take an item below zero. Every cent of a discount is
+// assigned to an item so taxes can be charged on what each item really costs.
+func ComputeDiscounts(items []models.OrderItem, discounts []models.OrderDiscount) DiscountResult {
+	remaining := map[uint]int64{}
+	for _, item := range items {
+		remaining[item.ID] = ToCents(item.SubTotal)
+	}
+
+	ordered := append([]models.OrderDiscount{}, discounts...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].ID < ordered[j].ID })
+
+	result := DiscountResult{Amounts: map[uint]float64{}, PerItem: map[uint]float64{}}
+	perItem := map[uint]int64{}
+	var total int64
+	for _, discount := range ordered {
+		if discount.Status != models.DiscountStatusApproved {
+			result.Amounts[discount.ID] = 0
+			continue
+		}
+
+		var eligible []models.OrderItem
+		var base int64
+		for _, item := range items {
+			if inScope(discount, item) && remaining[item.ID] > 0 {
+				eligible = append(eligible, item)
+				base += remaining[item.ID]
+			}
+		}
+
+		var shares map[uint]int64
+		switch discount.Kind {
+		case models.DiscountPercentage:
+			shares = spread(eligible, remaining, divRound(base*toPPM(min(discount.Value, 100)), rateScale))
+		case models.DiscountFixed:
+			shares = spread(eligible, remaining, min(ToCents(discount.Value), base))
+		case models.DiscountBuyXGetY:
+			shares = freeUnits(eligible, remaining, discount.BuyQuantity, discount.GetQuantity)
+		}
+
+		var amount int64
+		for itemID, share := range shares {
+			remaining[itemID] -= share
+			perItem[itemID] += share
+			amount += share
+		}
+		result.Amounts[discount.ID] = FromCents(amount)
+		total += amount
+	}
+
+	for itemID, cents := range perItem {
+		result.PerItem[itemID] = FromCents(cents)
+	}
+	result.Total = FromCents(total)
+	return result
+}
+
+func inScope(discount models.OrderDiscount, item models.OrderItem) bool {
+	switch discount.Scope {
+	case models.DiscountScopeMenu:
+		return item.MenuID == discount.MenuID
+	case models.DiscountScopeFood:
+		return item.FoodID == discount.FoodID
+	}
+	return true
+}
+
+// spread splits an amount over the items in proportion to what is left of them. Cents that
+// do not divide evenly go to the items with the largest remainders, so no item gets more
+// than is left of it.
+func spread(items []models.OrderItem, remaining map[uint]int64, amount int64) map[uint]int64 {
+	var base int64
+	for _, item := range items {
+		base += remaining[item.ID]
+	}
+	if base == 0 || amount <= 0 {
+		return nil
+	}
+
+	shares := map[uint]int64{}
+	fractions := make([]int64, len(items))
+	leftover := amount
+	for i, item := range items {
+		shares[item.ID] = amount * remaining[item.ID] / base
+		fractions[i] = amount * remaining[item.ID] % base
+		leftover -= shares[item.ID]
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return fractions[order[a]] > fractions[order[b]] })
+	for _, i := range order[:leftover] {
+		shares[items[i].ID]++
+	}
+	return shares
+}
+
+// freeUnits gives away the cheapest get of every buy + get units in scope, dearest units
+// counted first so the guest always pays for the more expensive ones
+func freeUnits(items []models.OrderItem, remaining map[uint]int64, buy, get int) map[uint]int64 {
+	if buy < 1 || get < 1 {
+		return nil
+	}
+
+	type unit struct {
+		itemID uint
+		price  int64
+	}
+	var units []unit
+	for _, item := range items {
+		for range item.Quantity {
+			units = append(units, unit{item.ID, ToCents(item.UnitPrice)})
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool { return units[i].price > units[j].price })
+
+	shares := map[uint]int64{}
+	group := buy + get
+	for i, unit := range units {
+		if i%group < buy || i-i%group+group > len(units) {
+			continue
+		}
+		shares[unit.itemID] = min(shares[unit.itemID]+unit.price, remaining[unit.itemID])
+	}
+	return shares
+}
@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"maps"
+	"testing"
+
+	"restaurant-management/models"
+)
+
+func TestComputeDiscounts(t *testing.T) {
+	bread := models.OrderItem{ID: 1, FoodID: 1, MenuID: 1, Quantity: 1, UnitPrice: 4, SubTotal: 4}
+	soup := models.OrderItem{ID: 2, FoodID: 2, MenuID: 2, Quantity: 2, UnitPrice: 3, SubTotal: 6}
+	items := []models.OrderItem{bread, soup}
+
+	approved := func(id uint, kind string, value float64, scope string) models.OrderDiscount {
+		return models.OrderDiscount{ID: id, Kind: kind, Value: value, Scope: scope, FoodID: 1, MenuID: 2, Status: models.DiscountStatusApproved}
+	}
+
+	tests := []struct {
+		name      string
+		items     []models.OrderItem
+		discounts []models.OrderDiscount
+		total     float64
+		perItem   map[uint]float64
+	}{
+		{
+			"a percentage is spread by what each item costs",
+			items,
+			[]models.OrderDiscount{approved(1, models.DiscountPercentage, 10, models.DiscountScopeOrder)},
+			1, map[uint]float64{1: 0.4, 2: 0.6},
+		},
+		{
+			"a discount larger than its item stops at the item's price",
+			items,
+			[]models.OrderDiscount{approved(1, models.DiscountFixed, 20, models.DiscountScopeFood)},
+			4, map[uint]float64{1: 4},
+		},
+		{
+			"discounts apply in the order they were added, on what earlier ones left",
+			items,
+			[]models.OrderDiscount{approved(2, models.DiscountPercentage, 50, models.DiscountScopeOrder), approved(1, models.DiscountFixed, 9, models.DiscountScopeOrder)},
+			9.5, map[uint]float64{1: 3.8, 2: 5.7},
+		},
+		{
+			"a menu discount leaves other menus alone",
+			items,
+			[]models.OrderDiscount{approved(1, models.DiscountPercentage, 150, models.DiscountScopeMenu)},
+			6, map[uint]float64{2: 6},
+		},
+		{
+			"cents that don't divide evenly still all land on an item",
+			[]models.OrderItem{{ID: 1, SubTotal: 1}, {ID: 2, SubTotal: 1}, {ID: 3, SubTotal: 1}},
+			[]models.OrderDiscount{approved(1, models.DiscountFixed, 1, models.DiscountScopeOrder)},
+			1, map[uint]float64{1: 0.34, 2: 0.33, 3: 0.33},
+		},
+		{
+			"buy one get one gives the second unit away",
+			items,
+			[]models.OrderDiscount{{ID: 1, Kind: models.DiscountBuyXGetY, BuyQuantity: 1, GetQuantity: 1, Scope: models.DiscountScopeFood, FoodID: 2, Status: models.DiscountStatusApproved}},
+			3, map[uint]float64{2: 3},
+		},
+		{
+			"discounts waiting for approval take nothing off",
+			items,
+			[]models.OrderDiscount{{ID: 1, Kind: models.DiscountFixed, Value: 5, Scope: models.DiscountScopeOrder, Status: models.DiscountStatusPending}},
+			0, map[uint]float64{},
+		},
+	}
+	for _, test := range tests {
+		result := ComputeDiscounts(test.items, test.discounts)
+		if result.Total != test.total {
+			t.Errorf("%s: took off %v, want %v", test.name, result.Total, test.total)
+		}
+		if !maps.Equal(result.PerItem, test.perItem) {
+			t.Errorf("%s: split the discount as %v, want %v", test.name, result.PerItem, test.perItem)
+		}
+	}
+}
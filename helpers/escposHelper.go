@@ -0,0 +1,256 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"restaurant-management/models"
+)
+
+// receiptColumns is how many characters of the printer's standard font fit on a line of 80mm paper
+const receiptColumns = 48
+
+// ESC/POS alignments
+const (
+	alignLeft   = 0
+	alignCenter = 1
+)
+
+// escpos builds a print job for thermal receipt printers speaking ESC/POS
+type escpos struct {
+	buf bytes.Buffer
+}
+
+// newEscpos starts a job on a freshly reset printer printing Latin-1 text
+func newEscpos() *escpos {
+	e := &escpos{}
+	e.buf.Write([]byte{0x1b, '@'})     // initialize
+	e.buf.Write([]byte{0x1b, 't', 16}) // WPC1252 code page
+	return e
+}
+
+func (e *escpos) align(a byte) {
+	e.buf.Write([]byte{0x1b, 'a', a})
+}
+
+func (e *escpos) bold(on bool) {
+	e.buf.Write([]byte{0x1b, 'E', boolByte(on)})
+}
+
+// large doubles the width and height of the text that follows, or goes back to normal
+func (e *escpos) large(on bool) {
+	e.buf.Write([]byte{0x1d, '!', boolByte(on) * 0x11})
+}
+
+func boolByte(on bool) byte {
+	if on {
+		return 1
+	}
+	return 0
+}
+
+// line prints text and moves to the next line. Characters the code page can't print come out
+// as '?' and control characters as spaces, so text can't smuggle commands to the printer.
+func (e *escpos) line(text string) {
+	for _, r := range text {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			e.buf.WriteByte(' ')
+		case r < 0x80 || r >= 0xa0 && r <= 0xff:
+			e.buf.WriteByte(byte(r))
+		case r == '€':
+			e.buf.WriteByte(0x80)
+		default:
+			e.buf.WriteByte('?')
+		}
+	}
+	e.buf.WriteByte('\n')
+}
+
+// wrapped prints text over as many lines of width characters as it needs
+func (e *escpos) wrapped(text string, width int) {
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					e.line(line)
+					line = ""
+				}
+				cut := []rune(word)
+				e.line(string(cut[:width]))
+				word = string(cut[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				e.line(line)
+				line = word
+			}
+		}
+		if line != "" {
+			e.line(line)
+		}
+	}
+}
+
+// columns prints a label on the left and a value on the right of a line of width characters,
+// the label wraps when both don't fit
+func (e *escpos) columns(label, value string, width int) {
+	room := width - utf8.RuneCountInString(value) - 1
+	if room < 1 {
+		e.line(label)
+		e.line(value)
+		return
+	}
+	labelRunes := []rune(label)
+	for len(labelRunes) > room {
+		e.line(string(labelRunes[:room]))
+		labelRunes = labelRunes[room:]
+	}
+	e.line(string(labelRunes) + strings.Repeat(" ", width-len(labelRunes)-utf8.RuneCountInString(value)) + value)
+}
+
+func (e *escpos) rule() {
+	e.line(strings.Repeat("-", receiptColumns))
+}
+
+// qr prints a QR code of data, about 4cm wide
+func (e *escpos) qr(data string) {
+	payload := []byte(data)
+	size := len(payload) + 3
+	e.buf.Write([]byte{0x1d, '(', 'k', 4, 0, '1', 'A', '2', 0})                     // model 2
+	e.buf.Write([]byte{0x1d, '(', 'k', 3, 0, '1', 'C', 6})                          // module size
+	e.buf.Write([]byte{0x1d, '(', 'k', 3, 0, '1', 'E', '1'})                        // error correction M
+	e.buf.Write([]byte{0x1d, '(', 'k', byte(size), byte(size >> 8), '1', 'P', '0'}) // store the data
+	e.buf.Write(payload)
+	e.buf.Write([]byte{0x1d, '(', 'k', 3, 0, '1', 'Q', '0'}) // print
+	e.buf.WriteByte('\n')
+}
+
+// cut feeds the paper past the cutter and cuts it, leaving a hinge
+func (e *escpos) cut() {
+	e.buf.Write([]byte{0x1d, 'V', 66, 3})
+}
+
+func (e *escpos) bytes() []byte {
+	return e.buf.Bytes()
+}
+
+// GenerateReceipt prints an invoice or one part of a split check as ESC/POS commands for an
+// 80mm thermal printer, with the restaurant's address, tax IDs, footer and payment QR code as
+// its template says. Logos are left out, thermal printers keep their own.
+func GenerateReceipt(invoice models.Invoice, order models.Order, restaurant models.Restaurant, template models.InvoiceTemplate) []byte {
+	e := newEscpos()
+
+	e.align(alignCenter)
+	e.bold(true)
+	e.large(true)
+	e.wrapped(restaurant.Name, receiptColumns/2)
+	e.large(false)
+	e.bold(false)
+	if template.ShowAddress && restaurant.Address != "" {
+		e.wrapped(restaurant.Address, receiptColumns)
+	}
+	for _, taxID := range template.TaxIDs {
+		e.line(taxID.Label + ": " + taxID.Value)
+	}
+	e.line("")
+
+	title, date := invoiceTitle(invoice, order)
+	e.bold(true)
+	e.line(title)
+	e.bold(false)
+	e.align(alignLeft)
+	e.columns("Date: "+date.Format("02 Jan 2006"), fmt.Sprintf("Order ID: %d", invoice.OrderID), receiptColumns)
+	if invoice.ParentID != 0 {
+		e.line(fmt.Sprintf("Part of the split check for order #%d", invoice.OrderID))
+	}
+	e.rule()
+
+	for _, item := range invoiceItems(invoice, order) {
+		e.columns(fmt.Sprintf("%d x %s", item.Quantity, item.DisplayName()), fmt.Sprintf("%.2f", item.SubTotal), receiptColumns)
+		for _, detail := range detailLines(item) {
+			e.line("    " + detail)
+		}
+		if item.Quantity > 1 {
+			e.line(fmt.Sprintf("    @ %.2f", item.UnitPrice))
+		}
+	}
+	e.rule()
+
+	for _, line := range invoiceTotals(invoice, order) {
+		e.bold(line.strong)
+		e.columns(line.label, line.value, receiptColumns)
+	}
+	e.bold(false)
+
+	e.align(alignCenter)
+	if template.PaymentQR && template.PaymentURL != "" && invoice.Balance > 0 {
+		e.line("")
+		e.qr(PaymentLink(template, invoice))
+		e.line("Scan to pay")
+	}
+	if footer := strings.TrimSpace(template.FooterText); footer != "" {
+		e.line("")
+		e.wrapped(footer, receiptColumns)
+	}
+	e.cut()
+
+	return e.bytes()
+}
+
+// GenerateKitchenTicket prints the items of an order routed to one kitchen station as ESC/POS
+// commands, large enough to be read from across the pass
+func GenerateKitchenTicket(station, table string, order models.Order, tickets []models.StationTicket, printedAt time.Time) []byte {
+	e := newEscpos()
+
+	e.align(alignCenter)
+	e.bold(true)
+	e.large(true)
+	e.wrapped(strings.ToUpper(station), receiptColumns/2)
+	e.large(false)
+	e.bold(false)
+	e.align(alignLeft)
+	e.columns(fmt.Sprintf("Order #%d", order.ID), table, receiptColumns)
+	e.columns("Ordered "+order.OrderDate.Format("15:04"), "Printed "+printedAt.Format("15:04"), receiptColumns)
+	e.rule()
+
+	e.large(true)
+	for _, ticket := range tickets {
+		name := ticket.FoodName
+		if ticket.VariantName != "" {
+			name += " (" + ticket.VariantName + ")"
+		}
+		if ticket.ComboName != "" {
+			name += " - " + ticket.ComboName
+		}
+		e.wrapped(fmt.Sprintf("%d x %s", ticket.Quantity, name), receiptColumns/2)
+		for _, modifier := range ticket.Modifiers {
+			e.wrapped("+ "+modifier, receiptColumns/2)
+		}
+		// The allergens the guests declared that the item contains stand out from the rest
+		if len(ticket.Allergens) > 0 {
+			e.bold(true)
+			e.wrapped("!! ALLERGY: "+strings.ToUpper(strings.Join(ticket.Allergens, ", ")), receiptColumns/2)
+			e.bold(false)
+		}
+	}
+	e.large(false)
+
+	if notes := strings.TrimSpace(order.Notes); notes != "" {
+		e.rule()
+		e.bold(true)
+		e.wrapped("Notes: "+notes, receiptColumns)
+		e.bold(false)
+	}
+	e.cut()
+
+	return e.bytes()
+}
@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"restaurant-management/models"
+)
+
+var invoiceEmailTemplate = template.Must(template.New("invoice").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; color: #333;">
+	<h2>{{.Restaurant.Name}}</h2>
+	<p>Thank you for dining with us. Please find your {{.Title}} for order #{{.Invoice.OrderID}} attached.</p>
+	{{- if .Message}}
+	<p>{{range $i, $line := .Message}}{{if $i}}<br>{{end}}{{$line}}{{end}}</p>
+	{{- end}}
+	<table style="border-collapse: collapse;">
+		<tr><td style="padding: 2px 12px 2px 0;">Total</td><td style="text-align: right;">{{printf "%.2f" .Invoice.Total}}</td></tr>
+		{{- if .Invoice.Tip}}
+		<tr><td style="padding: 2px 12px 2px 0;">Tip</td><td style="text-align: right;">{{printf "%.2f" .Invoice.Tip}}</td></tr>
+		{{- end}}
+		<tr><td style="padding: 2px 12px 2px 0;">Paid</td><td style="text-align: right;">{{printf "%.2f" .Invoice.AmountPaid}}</td></tr>
+		<tr><td style="padding: 2px 12px 2px 0;"><strong>Balance</strong></td><td style="text-align: right;"><strong>{{printf "%.2f" .Invoice.Balance}}</strong></td></tr>
+	</table>
+	<p style="color: #777; font-size: 12px;">{{.Restaurant.Address}}</p>
+</body>
+</html>
+`))
+
+// InvoiceEmail is the subject and HTML body of the email an invoice's PDF is sent with.
+// The note from the restaurant, if any, is escaped and keeps its line breaks.
+func InvoiceEmail(invoice models.Invoice, restaurant models.Restaurant, note string) (string, string, error) {
+	title := "invoice"
+	if invoice.Number != "" {
+		title = "invoice " + invoice.Number
+	}
+
+	var message []string
+	if note = strings.TrimSpace(note); note != "" {
+		message = strings.Split(note, "\n")
+	}
+
+	var body bytes.Buffer
+	err := invoiceEmailTemplate.Execute(&body, struct {
+		Invoice    models.Invoice
+		Restaurant models.Restaurant
+		Title      string
+		Message    []string
+	}{invoice, restaurant, title, message})
+	if err != nil {
+		return "", "", err
+	}
+
+	subject := fmt.Sprintf("Your %s from %s", title, restaurant.Name)
+	return subject, body.String(), nil
+}
@@ -0,0 +1,53 @@
+package helpers
+
+import (
+	"fmt"
+
+	"restaurant-management/models"
+)
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Orders only move forward, and can be cancelled up to the point they are served.
+var orderTransitions = map[string][]string{
+	models.OrderStatusPending:   {models.OrderStatusPreparing, models.OrderStatusCancelled},
+	models.OrderStatusPreparing: {models.OrderStatusReady, models.OrderStatusCancelled},
+	models.OrderStatusReady:     {models.OrderStatusServed, models.OrderStatusCancelled},
+	models.OrderStatusServed:    {models.OrderStatusPaid},
+	models.OrderStatusPaid:      {},
+	models.OrderStatusCancelled: {},
+}
+
+// IllegalTransitionError is returned for a status change a lifecycle does not allow
+type IllegalTransitionError struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Allowed []string `json:"allowed"`
+	subject string
+}
+
+func (e *IllegalTransitionError) Error() string {
+	return fmt.Sprintf("%s cannot move from %q to %q", e.subject, e.From, e.To)
+}
+
+// OrderStatus normalises the stored status, orders reserved through CreateOrderId have none yet and count as pending
+func OrderStatus(status string) string {
+	if status == "" {
+		return models.OrderStatusPending
+	}
+	return status
+}
+
+// AllowedOrderTransitions returns the statuses an order in the given status may move to
+func AllowedOrderTransitions(from string) []string {
+	return append([]string{}, orderTransitions[OrderStatus(from)]...)
+}
+
+// CanTransitionOrder returns nil when an order may move from one status to the other
+func CanTransitionOrder(from, to string) error {
+	for _, allowed := range orderTransitions[OrderStatus(from)] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return &IllegalTransitionError{From: OrderStatus(from), To: to, Allowed: AllowedOrderTransitions(from), subject: "order"}
+}
@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+
+	"restaurant-management/models"
+)
+
+func TestCanTransitionOrder(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{models.OrderStatusPending, models.OrderStatusPreparing, true},
+		{"", models.OrderStatusPreparing, true}, // reserved orders count as pending
+		{models.OrderStatusPending, models.OrderStatusServed, false},
+		{models.OrderStatusReady, models.OrderStatusCancelled, true},
+		{models.OrderStatusServed, models.OrderStatusCancelled, false},
+		{models.OrderStatusServed, models.OrderStatusPaid, true},
+		{models.OrderStatusPaid, models.OrderStatusPending, false},
+		{models.OrderStatusCancelled, models.OrderStatusPreparing, false},
+		{models.OrderStatusPreparing, models.OrderStatusPreparing, false},
+	}
+	for _, test := range tests {
+		err := CanTransitionOrder(test.from, test.to)
+		if test.allowed && err != nil {
+			t.Errorf("%q to %q was refused: %v", test.from, test.to, err)
+		}
+		if !test.allowed {
+			var illegal *IllegalTransitionError
+			if !errors.As(err, &illegal) {
+				t.Errorf("%q to %q gave %v, want an IllegalTransitionError", test.from, test.to, err)
+			} else if illegal.From != OrderStatus(test.from) {
+				t.Errorf("%q to %q names %q as the current status", test.from, test.to, illegal.From)
+			}
+		}
+	}
+}
@@ -3,93 +3,259 @@ package helpers
 import (
 	"fmt"
 	"restaurant-management/models"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/johnfercher/maroto/v2"
-	// "github.com/johnfercher/maroto/v2/pkg/components/code"
 	"github.com/johnfercher/maroto/v2/pkg/components/line"
-	"github.com/johnfercher/maroto/v2/pkg/components/signature"
-	"github.com/johnfercher/maroto/v2/pkg/components/text"
-	"github.com/johnfercher/maroto/v2/pkg/config"
 	"github.com/johnfercher/maroto/v2/pkg/consts/align"
-	"github.com/johnfercher/maroto/v2/pkg/consts/fontfamily"
 	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
-	"github.com/johnfercher/maroto/v2/pkg/consts/orientation"
-	"github.com/johnfercher/maroto/v2/pkg/consts/pagesize"
 	"github.com/johnfercher/maroto/v2/pkg/core"
 	"github.com/johnfercher/maroto/v2/pkg/props"
 )
 
-func GeneratePdfFromData(invoice models.Invoice, order models.Order, restaurant models.Restaurant) (core.Document, error) {
-	config := config.NewBuilder().
-		WithOrientation(orientation.Vertical).
-		WithPageSize(pagesize.A4).
-		WithLeftMargin(15).
-		WithRightMargin(15).
-		WithBottomMargin(15).
-		WithTopMargin(15).
-		Build()
-
-	m := maroto.New(config)
+// GeneratePdfFromData prints an invoice or one part of a split check in the restaurant's layout
+func GeneratePdfFromData(invoice models.Invoice, order models.Order, restaurant models.Restaurant, layout Layout) (core.Document, error) {
+	d := newDocument(layout)
 
 	// Header: Restaurant Name & Invoice #
-	m.AddRow(15,
-		text.NewCol(12, restaurant.Name, props.Text{
-			Align: align.Center, Size: 16, Style: fontstyle.Bold,
-		}),
-	)
+	d.header(restaurant)
 
-	m.AddRow(10,
-		text.NewCol(12, fmt.Sprintf("Invoice #%d", invoice.ID), props.Text{
-			Align: align.Center, Style: fontstyle.Bold, Size: 12,
-		}),
-	)
+	title, date := invoiceTitle(invoice, order)
+	d.add(10, d.text(12, title, props.Text{Align: align.Center, Style: fontstyle.Bold, Size: 12}))
 
 	// Invoice Meta Info
-	m.AddRow(10,
-		text.NewCol(6, fmt.Sprintf("Date: %s", invoice.CreatedAt.Format("02 Jan 2006")), props.Text{Size: 10}),
-		text.NewCol(6, fmt.Sprintf("Order ID: %d", invoice.OrderID), props.Text{Align: align.Right, Size: 10}),
+	d.add(10,
+		d.text(6, fmt.Sprintf("Date: %s", date.Format("02 Jan 2006")), props.Text{Size: 10}),
+		d.text(6, fmt.Sprintf("Order ID: %d", invoice.OrderID), props.Text{Align: align.Right, Size: 10}),
 	)
+	if invoice.ParentID != 0 {
+		d.add(8, d.text(12, fmt.Sprintf("Part of the split check for order #%d", invoice.OrderID), props.Text{Size: 10}))
+	}
 
 	// Table Header
-	m.AddRow(10,
-		text.NewCol(4, "Item", props.Text{Style: fontstyle.Bold, Left: 1}),
-		text.NewCol(2, "Qty", props.Text{Style: fontstyle.Bold, Align: align.Center}),
-		text.NewCol(3, "Unit Price", props.Text{Style: fontstyle.Bold, Align: align.Center}),
-		text.NewCol(3, "Subtotal", props.Text{Style: fontstyle.Bold, Align: align.Right, Right: 1}),
+	nameCols, qtyCols, priceCols, subtotalCols := d.itemColumns()
+	d.add(10,
+		d.text(nameCols, "Item", props.Text{Style: fontstyle.Bold, Left: 1}),
+		d.text(qtyCols, "Qty", props.Text{Style: fontstyle.Bold, Align: align.Center}),
+		d.text(priceCols, "Unit Price", props.Text{Style: fontstyle.Bold, Align: align.Center}),
+		d.text(subtotalCols, "Subtotal", props.Text{Style: fontstyle.Bold, Align: align.Right, Right: 1}),
 	)
 
 	// Items
-	for i, item := range order.OrderItems {
+	for i, item := range invoiceItems(invoice, order) {
 		background := &props.Color{Red: 245, Green: 245, Blue: 245}
 		style := &props.Cell{}
 		if i%2 == 0 {
 			style.BackgroundColor = background
 		}
 
-		m.AddRow(8,
-			text.NewCol(4, item.FoodName, props.Text{Top: 2, VerticalPadding: 2, Left: 1}).WithStyle(style),
-			text.NewCol(2, fmt.Sprintf("%d", item.Quantity), props.Text{Top: 2, Align: align.Center, VerticalPadding: 2}).WithStyle(style),
-			text.NewCol(3, fmt.Sprintf("%.2f", item.UnitPrice), props.Text{Top: 2, Align: align.Center, VerticalPadding: 2}).WithStyle(style),
-			text.NewCol(3, fmt.Sprintf("%.2f", item.SubTotal), props.Text{Top: 2, Align: align.Right, VerticalPadding: 2, Right: 1}).WithStyle(style),
+		d.add(8,
+			d.text(nameCols, item.DisplayName(), props.Text{Top: 2, VerticalPadding: 2, Left: 1}).WithStyle(style),
+			d.text(qtyCols, fmt.Sprintf("%d", item.Quantity), props.Text{Top: 2, Align: align.Center, VerticalPadding: 2}).WithStyle(style),
+			d.text(priceCols, fmt.Sprintf("%.2f", item.UnitPrice), props.Text{Top: 2, Align: align.Center, VerticalPadding: 2}).WithStyle(style),
+			d.text(subtotalCols, fmt.Sprintf("%.2f", item.SubTotal), props.Text{Top: 2, Align: align.Right, VerticalPadding: 2, Right: 1}).WithStyle(style),
 		)
+		if details := detailLines(item); len(details) > 0 {
+			d.add(6,
+				d.text(nameCols, strings.Join(details, ", "), props.Text{Size: 8, Left: 4}).WithStyle(style),
+				d.text(qtyCols+priceCols+subtotalCols, "", props.Text{}).WithStyle(style),
+			)
+		}
 	}
 
-	m.AddRow(10, line.NewCol(12))
+	d.add(10, line.NewCol(12))
 
 	// Totals
-	m.AddRow(8,
-		text.NewCol(10, "Total:", props.Text{Align: align.Right, Style: fontstyle.Bold}),
-		text.NewCol(2, fmt.Sprintf("%.2f", invoice.Total), props.Text{Align: align.Right, Style: fontstyle.Bold, Right: 1}),
-	)
+	for _, line := range invoiceTotals(invoice, order) {
+		switch {
+		case line.strong:
+			d.amount(8, line.label, line.value, props.Text{Style: fontstyle.Bold})
+		case line.detail:
+			d.amount(6, line.label, line.value, props.Text{Size: 9})
+		default:
+			d.amount(8, line.label, line.value, props.Text{})
+		}
+	}
+
+	// Footer, with a QR code to pay what is left
+	paymentLink := ""
+	if layout.Template.PaymentQR && layout.Template.PaymentURL != "" && invoice.Balance > 0 {
+		paymentLink = PaymentLink(layout.Template, invoice)
+	}
+	d.footer(paymentLink)
+
+	return d.generate()
+}
+
+// invoiceTitle heads an invoice with its number and issue date. Invoices only carry a legal
+// number once their order is paid, until then they are pro forma. Invoices paid before
+// numbering was introduced are still known by their ID.
+func invoiceTitle(invoice models.Invoice, order models.Order) (string, time.Time) {
+	switch {
+	case invoice.Number != "" && invoice.IssuedAt != nil:
+		return "Invoice " + invoice.Number, *invoice.IssuedAt
+	case invoice.ParentID == 0 && order.Status == models.OrderStatusPaid:
+		return fmt.Sprintf("Invoice #%d", invoice.ID), invoice.CreatedAt
+	default:
+		return "Pro Forma Invoice", invoice.CreatedAt
+	}
+}
+
+// invoiceItems lists the items an invoice covers, a part split by items only lists its own
+func invoiceItems(invoice models.Invoice, order models.Order) []models.OrderItem {
+	if len(invoice.OrderItemIDs) == 0 {
+		return order.OrderItems
+	}
+	var items []models.OrderItem
+	for _, item := range order.OrderItems {
+		if slices.Contains(invoice.OrderItemIDs, item.ID) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// detailLines describe the modifiers an item was ordered with, with what each added to its
+// price, and the foods a combo was made of
+func detailLines(item models.OrderItem) []string {
+	lines := make([]string, len(item.Modifiers))
+	for i, modifier := range item.Modifiers {
+		lines[i] = modifier.Name
+		if modifier.PriceDelta != 0 {
+			lines[i] += fmt.Sprintf(" (%+.2f)", modifier.PriceDelta)
+		}
+	}
+	for _, component := range item.Components {
+		line := fmt.Sprintf("%d x %s", component.Quantity, component.DisplayName())
+		if modifiers := detailLines(component); len(modifiers) > 0 {
+			line += " with " + strings.Join(modifiers, ", ")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// totalLine is one line of an invoice's totals, strong lines are the amounts due and
+// detail lines break the one above them down
+type totalLine struct {
+	label, value   string
+	strong, detail bool
+}
+
+// invoiceTotals lists an invoice's totals, with one line per discount, per tax rate and per service charge
+func invoiceTotals(invoice models.Invoice, order models.Order) []totalLine {
+	var lines []totalLine
+	if invoice.Discount > 0 && invoice.ParentID != 0 {
+		lines = append(lines, totalLine{label: "Discounts:", value: fmt.Sprintf("-%.2f", invoice.Discount)})
+	} else if invoice.Discount > 0 {
+		lines = append(lines, totalLine{label: "Items:", value: fmt.Sprintf("%.2f", order.TotalPrice)})
+		for _, discount := range order.Discounts {
+			if discount.Status != models.DiscountStatusApproved || discount.Amount == 0 {
+				continue
+			}
+			lines = append(lines, totalLine{label: discount.Description + ":", value: fmt.Sprintf("-%.2f", discount.Amount), detail: true})
+		}
+	}
+	lines = append(lines, totalLine{label: "Subtotal:", value: fmt.Sprintf("%.2f", invoice.Amount)})
+	for _, tax := range invoice.Taxes {
+		label := fmt.Sprintf("%s %s%% on %.2f (%s):", tax.Name, strconv.FormatFloat(tax.Rate, 'f', -1, 64), tax.TaxableAmount, tax.Category)
+		if tax.Inclusive {
+			label = fmt.Sprintf("%s %s%% included in %s prices:", tax.Name, strconv.FormatFloat(tax.Rate, 'f', -1, 64), tax.Category)
+		}
+		lines = append(lines, totalLine{label: label, value: fmt.Sprintf("%.2f", tax.TaxAmount), detail: true})
+	}
+	for _, charge := range invoice.ServiceCharges {
+		label := fmt.Sprintf("%s %s%% on %.2f:", charge.Name, strconv.FormatFloat(charge.Rate, 'f', -1, 64), charge.BaseAmount)
+		lines = append(lines, totalLine{label: label, value: fmt.Sprintf("%.2f", charge.Amount), detail: true})
+	}
+	lines = append(lines, totalLine{label: "Total:", value: fmt.Sprintf("%.2f", invoice.Total), strong: true})
+	if invoice.AmountPaid > 0 {
+		lines = append(lines,
+			totalLine{label: "Paid:", value: fmt.Sprintf("%.2f", invoice.AmountPaid), detail: true},
+			totalLine{label: "Balance due:", value: fmt.Sprintf("%.2f", invoice.Balance), strong: true},
+		)
+	}
+	if invoice.Tip > 0 {
+		lines = append(lines,
+			totalLine{label: "Tip:", value: fmt.Sprintf("%.2f", invoice.Tip), detail: true},
+			totalLine{label: "Total with tip:", value: fmt.Sprintf("%.2f", invoice.Total+invoice.Tip), strong: true},
+		)
+	}
+	if invoice.Credited > 0 {
+		lines = append(lines, totalLine{label: "Credited through credit notes:", value: fmt.Sprintf("-%.2f", invoice.Credited), detail: true})
+	}
+	return lines
+}
+
+// invoiceReference is how other documents refer to an invoice, by its number unless it was paid before invoices were numbered
+func invoiceReference(invoice models.Invoice) string {
+	if invoice.Number != "" && invoice.IssuedAt != nil {
+		return fmt.Sprintf("invoice %s of %s", invoice.Number, invoice.IssuedAt.Format("02 Jan 2006"))
+	}
+	return fmt.Sprintf("invoice #%d of %s", invoice.ID, invoice.CreatedAt.Format("02 Jan 2006"))
+}
+
+// GenerateCreditNotePdf prints a credit note with the items it voids, what it credits and
+// how the money was given back, in the restaurant's layout. It refers to the invoice it
+// corrects, which is not reprinted.
+func GenerateCreditNotePdf(note models.CreditNote, invoice models.Invoice, restaurant models.Restaurant, layout Layout) (core.Document, error) {
+	d := newDocument(layout)
 
-	// Add Footer
-	m.AddRow(40,
-		signature.NewCol(6, "Authorized Signature", props.Signature{FontFamily: fontfamily.Courier}),
-		// code.NewQrCol(6, "https://codeheim.io", props.Rect{ // QR Code
-		// 	Percent: 75,
-		// 	Center:  true,
-		// }),
+	d.header(restaurant)
+
+	d.add(10, d.text(12, "Credit Note "+note.Number, props.Text{Align: align.Center, Style: fontstyle.Bold, Size: 12}))
+
+	d.add(10,
+		d.text(6, fmt.Sprintf("Date: %s", note.CreatedAt.Format("02 Jan 2006")), props.Text{Size: 10}),
+		d.text(6, fmt.Sprintf("Order ID: %d", note.OrderID), props.Text{Align: align.Right, Size: 10}),
 	)
+	d.add(8, d.text(12, fmt.Sprintf("Credits %s, total %.2f", invoiceReference(invoice), invoice.Total), props.Text{Size: 10}))
+	d.add(8, d.text(12, "Reason: "+strings.ReplaceAll(note.ReasonCode, "_", " "), props.Text{Size: 10}))
+	if note.Notes != "" {
+		d.add(8, d.text(12, note.Notes, props.Text{Size: 9}))
+	}
+
+	if len(note.Items) > 0 {
+		nameCols, qtyCols, priceCols, creditedCols := d.itemColumns()
+		d.add(10,
+			d.text(nameCols, "Voided item", props.Text{Style: fontstyle.Bold, Left: 1}),
+			d.text(qtyCols, "Qty", props.Text{Style: fontstyle.Bold, Align: align.Center}),
+			d.text(priceCols, "Unit Price", props.Text{Style: fontstyle.Bold, Align: align.Center}),
+			d.text(creditedCols, "Credited", props.Text{Style: fontstyle.Bold, Align: align.Right, Right: 1}),
+		)
+		for i, item := range note.Items {
+			style := &props.Cell{}
+			if i%2 == 0 {
+				style.BackgroundColor = &props.Color{Red: 245, Green: 245, Blue: 245}
+			}
+
+			d.add(8,
+				d.text(nameCols, fmt.Sprintf("%s (%s)", item.FoodName, strings.ReplaceAll(item.ReasonCode, "_", " ")), props.Text{Top: 2, VerticalPadding: 2, Left: 1}).WithStyle(style),
+				d.text(qtyCols, fmt.Sprintf("%d", item.Quantity), props.Text{Top: 2, Align: align.Center, VerticalPadding: 2}).WithStyle(style),
+				d.text(priceCols, fmt.Sprintf("%.2f", item.UnitPrice), props.Text{Top: 2, Align: align.Center, VerticalPadding: 2}).WithStyle(style),
+				d.text(creditedCols, fmt.Sprintf("%.2f", item.Amount), props.Text{Top: 2, Align: align.Right, VerticalPadding: 2, Right: 1}).WithStyle(style),
+			)
+		}
+	}
+
+	d.add(10, line.NewCol(12))
+
+	d.amount(8, "Subtotal:", fmt.Sprintf("%.2f", note.Amount), props.Text{})
+	if note.Tax != 0 {
+		d.amount(6, "Tax:", fmt.Sprintf("%.2f", note.Tax), props.Text{Size: 9})
+	}
+	if note.ServiceCharge != 0 {
+		d.amount(6, "Service charge:", fmt.Sprintf("%.2f", note.ServiceCharge), props.Text{Size: 9})
+	}
+	d.amount(8, "Total credited:", fmt.Sprintf("%.2f", note.Total), props.Text{Style: fontstyle.Bold})
+	for _, refund := range note.Refunds {
+		d.amount(6, fmt.Sprintf("Refunded to %s payment #%d (%s):", strings.ReplaceAll(refund.Method, "_", " "), refund.PaymentID, refund.Status),
+			fmt.Sprintf("%.2f", refund.Amount), props.Text{Size: 9})
+	}
+
+	d.footer("")
 
-	return m.Generate()
+	return d.generate()
 }
@@ -0,0 +1,284 @@
+package helpers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"restaurant-management/models"
+
+	"github.com/johnfercher/maroto/v2"
+	"github.com/johnfercher/maroto/v2/pkg/components/code"
+	"github.com/johnfercher/maroto/v2/pkg/components/image"
+	"github.com/johnfercher/maroto/v2/pkg/components/row"
+	"github.com/johnfercher/maroto/v2/pkg/components/signature"
+	"github.com/johnfercher/maroto/v2/pkg/components/text"
+	"github.com/johnfercher/maroto/v2/pkg/config"
+	"github.com/johnfercher/maroto/v2/pkg/consts/align"
+	"github.com/johnfercher/maroto/v2/pkg/consts/extension"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontfamily"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/consts/orientation"
+	"github.com/johnfercher/maroto/v2/pkg/consts/pagesize"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"github.com/johnfercher/maroto/v2/pkg/props"
+)
+
+// maxLogoBytes caps the size of a logo image downloaded for a document
+const maxLogoBytes = 2 << 20
+
+// receiptWidth is the width of a till roll in millimetres
+const receiptWidth = 80
+
+// Layout is how a restaurant's documents are printed: its template and the logo image it
+// shows, if any
+type Layout struct {
+	Template models.InvoiceTemplate
+	Logo     []byte
+	LogoType extension.Type
+}
+
+// LoadLayout prepares the restaurant's template for printing. A logo that can't be loaded
+// is left out rather than failing the document, the error says why.
+func LoadLayout(ctx context.Context, template models.InvoiceTemplate, restaurant models.Restaurant) (Layout, error) {
+	layout := Layout{Template: template}
+	if !template.ShowLogo || restaurant.Logo == "" {
+		return layout, nil
+	}
+	logo, logoType, err := loadLogo(ctx, restaurant.Logo)
+	if err != nil {
+		return layout, fmt.Errorf("loading the logo: %w", err)
+	}
+	layout.Logo, layout.LogoType = logo, logoType
+	return layout, nil
+}
+
+// logoClient only reaches public addresses, logos are set by restaurants and must not
+// point the server at its own network
+var logoClient = &http.Client{
+	Timeout: 5 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 3 * time.Second,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				ip := net.ParseIP(host)
+				if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+					return fmt.Errorf("logo address %s is not public", host)
+				}
+				return nil
+			},
+		}).DialContext,
+	},
+}
+
+// loadLogo reads a PNG or JPEG image from a data URI or an http(s) URL
+func loadLogo(ctx context.Context, source string) ([]byte, extension.Type, error) {
+	var data []byte
+	switch {
+	case strings.HasPrefix(source, "data:"):
+		header, payload, ok := strings.Cut(source, ",")
+		if !ok || !strings.HasSuffix(header, ";base64") {
+			return nil, "", errors.New("only base64 data URIs are supported")
+		}
+		decoded, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, "", err
+		}
+		data = decoded
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		response, err := logoClient.Do(request)
+		if err != nil {
+			return nil, "", err
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("logo request answered %s", response.Status)
+		}
+		if data, err = io.ReadAll(io.LimitReader(response.Body, maxLogoBytes+1)); err != nil {
+			return nil, "", err
+		}
+	default:
+		return nil, "", errors.New("the logo must be an http(s) URL or a data URI")
+	}
+
+	if len(data) > maxLogoBytes {
+		return nil, "", fmt.Errorf("the logo is larger than %d bytes", maxLogoBytes)
+	}
+	switch http.DetectContentType(data) {
+	case "image/png":
+		return data, extension.Png, nil
+	case "image/jpeg":
+		return data, extension.Jpg, nil
+	default:
+		return nil, "", errors.New("the logo must be a PNG or JPEG image")
+	}
+}
+
+// PaymentLink fills the invoice's details into the template's payment URL
+func PaymentLink(template models.InvoiceTemplate, invoice models.Invoice) string {
+	return strings.NewReplacer(
+		"{invoice_id}", strconv.FormatUint(uint64(invoice.ID), 10),
+		"{order_id}", strconv.FormatUint(uint64(invoice.OrderID), 10),
+		"{number}", invoice.Number,
+		"{amount}", fmt.Sprintf("%.2f", invoice.Balance),
+	).Replace(template.PaymentURL)
+}
+
+// document collects the rows of a PDF so receipts can be cut to the length of their content
+type document struct {
+	layout  Layout
+	receipt bool
+	accent  *props.Color
+	rows    []core.Row
+	height  float64
+}
+
+func newDocument(layout Layout) *document {
+	return &document{
+		layout:  layout,
+		receipt: layout.Template.PaperSize == models.PaperReceipt,
+		accent:  hexColor(layout.Template.AccentColor),
+	}
+}
+
+// hexColor parses a #rgb or #rrggbb colour, nil for none
+func hexColor(value string) *props.Color {
+	value = strings.TrimPrefix(value, "#")
+	if len(value) == 3 {
+		value = string([]byte{value[0], value[0], value[1], value[1], value[2], value[2]})
+	}
+	rgb, err := strconv.ParseUint(value, 16, 32)
+	if len(value) != 6 || err != nil {
+		return nil
+	}
+	return &props.Color{Red: int(rgb >> 16 & 0xff), Green: int(rgb >> 8 & 0xff), Blue: int(rgb & 0xff)}
+}
+
+func (d *document) add(height float64, cols ...core.Col) {
+	if d.receipt {
+		height *= 0.75
+	}
+	d.rows = append(d.rows, row.New(height).Add(cols...))
+	d.height += height
+}
+
+// size scales a font size down for receipts
+func (d *document) size(points float64) float64 {
+	if d.receipt {
+		return max(points-3, 6)
+	}
+	return points
+}
+
+// text is a column of text sized for the paper, headings in the accent colour
+func (d *document) text(cols int, value string, style props.Text) core.Col {
+	if style.Size == 0 {
+		style.Size = 10
+	}
+	style.Size = d.size(style.Size)
+	if style.Style == fontstyle.Bold && style.Color == nil {
+		style.Color = d.accent
+	}
+	return text.NewCol(cols, value, style)
+}
+
+// columns splits the width between a label and an amount
+func (d *document) columns() (int, int) {
+	if d.receipt {
+		return 8, 4
+	}
+	return 10, 2
+}
+
+// itemColumns splits the width between an item's name, quantity, unit price and subtotal
+func (d *document) itemColumns() (int, int, int, int) {
+	if d.receipt {
+		return 5, 1, 3, 3
+	}
+	return 4, 2, 3, 3
+}
+
+// amount adds a line of the totals
+func (d *document) amount(height float64, label, value string, style props.Text) {
+	labelCols, valueCols := d.columns()
+	labelStyle, valueStyle := style, style
+	labelStyle.Align, valueStyle.Align, valueStyle.Right = align.Right, align.Right, 1
+	d.add(height, d.text(labelCols, label, labelStyle), d.text(valueCols, value, valueStyle))
+}
+
+// header prints the logo, the restaurant's name and, as the template says, its address and tax IDs
+func (d *document) header(restaurant models.Restaurant) {
+	if d.layout.Logo != nil {
+		d.add(25, image.NewFromBytesCol(12, d.layout.Logo, d.layout.LogoType, props.Rect{Center: true, Percent: 90}))
+	}
+	d.add(15, d.text(12, restaurant.Name, props.Text{Align: align.Center, Size: 16, Style: fontstyle.Bold}))
+	if d.layout.Template.ShowAddress && restaurant.Address != "" {
+		d.add(6, d.text(12, restaurant.Address, props.Text{Align: align.Center, Size: 9}))
+	}
+	for _, taxID := range d.layout.Template.TaxIDs {
+		d.add(5, d.text(12, taxID.Label+": "+taxID.Value, props.Text{Align: align.Center, Size: 8}))
+	}
+}
+
+// footer prints the payment QR code, the signature line on full pages and the template's footer text
+func (d *document) footer(paymentLink string) {
+	switch {
+	case d.receipt && paymentLink != "":
+		d.add(45, code.NewQrCol(12, paymentLink, props.Rect{Percent: 90, Center: true}))
+		d.add(6, d.text(12, "Scan to pay", props.Text{Align: align.Center, Size: 9}))
+	case paymentLink != "":
+		d.add(40,
+			signature.NewCol(6, "Authorized Signature", props.Signature{FontFamily: fontfamily.Courier}),
+			code.NewQrCol(6, paymentLink, props.Rect{Percent: 75, Center: true}),
+		)
+		d.add(6, text.NewCol(6, ""), d.text(6, "Scan to pay", props.Text{Align: align.Center, Size: 9}))
+	case !d.receipt:
+		d.add(40, signature.NewCol(6, "Authorized Signature", props.Signature{FontFamily: fontfamily.Courier}))
+	}
+	for _, line := range strings.Split(strings.TrimSpace(d.layout.Template.FooterText), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			d.add(5, d.text(12, line, props.Text{Align: align.Center, Size: 8}))
+		}
+	}
+}
+
+// generate lays the rows out on A4 pages, or on one page as long as a receipt needs
+func (d *document) generate() (core.Document, error) {
+	builder := config.NewBuilder().WithOrientation(orientation.Vertical)
+	if d.receipt {
+		margin := 4.0
+		builder = builder.
+			WithDimensions(receiptWidth, d.height+2*margin+5).
+			WithLeftMargin(margin).
+			WithRightMargin(margin).
+			WithTopMargin(margin).
+			WithBottomMargin(margin)
+	} else {
+		builder = builder.
+			WithPageSize(pagesize.A4).
+			WithLeftMargin(15).
+			WithRightMargin(15).
+			WithBottomMargin(15).
+			WithTopMargin(15)
+	}
+
+	m := maroto.New(builder.Build())
+	m.AddRows(d.rows...)
+	return m.Generate()
+}
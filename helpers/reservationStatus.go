@@ -0,0 +1,28 @@
+package helpers
+
+import "restaurant-management/models"
+
+// reservationTransitions lists the statuses a reservation may move to from each status.
+// A booked party is either seated, cancelled or released as a no-show.
+var reservationTransitions = map[string][]string{
+	models.ReservationStatusBooked:    {models.ReservationStatusSeated, models.ReservationStatusCancelled, models.ReservationStatusNoShow},
+	models.ReservationStatusSeated:    {models.ReservationStatusCompleted},
+	models.ReservationStatusCompleted: {},
+	models.ReservationStatusCancelled: {},
+	models.ReservationStatusNoShow:    {},
+}
+
+// ReservationHoldsTable reports whether a reservation in the given status still claims its table
+func ReservationHoldsTable(status string) bool {
+	return status == models.ReservationStatusBooked || status == models.ReservationStatusSeated
+}
+
+// CanTransitionReservation returns nil when a reservation may move from one status to the other
+func CanTransitionReservation(from, to string) error {
+	for _, allowed := range reservationTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return &IllegalTransitionError{From: from, To: to, Allowed: append([]string{}, reservationTransitions[from]...), subject: "reservation"}
+}
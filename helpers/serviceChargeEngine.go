@@ -0,0 +1,40 @@
+package helpers
+
+import (
+	"sort"
+
+	"restaurant-management/models"
+)
+
+// ServiceChargeResult is what an order is charged for service, in currency units
+type ServiceChargeResult struct {
+	Total float64
+	Lines []models.InvoiceServiceCharge
+}
+
+// ComputeServiceCharges applies every service charge of the restaurant that the party of
+// guests reaches to the order's net amount, what its items cost after discounts and before
+// tax. Each charge is rounded to cents half away from zero on its own.
+func ComputeServiceCharges(net float64, guests int, charges []models.ServiceCharge) ServiceChargeResult {
+	applied := append([]models.ServiceCharge{}, charges...)
+	sort.Slice(applied, func(i, j int) bool { return applied[i].ID < applied[j].ID })
+
+	base := ToCents(net)
+	var total int64
+	var lines []models.InvoiceServiceCharge
+	for _, charge := range applied {
+		if guests < charge.MinGuests || base <= 0 {
+			continue
+		}
+		amount := divRound(base*toPPM(charge.Rate), rateScale)
+		total += amount
+		lines = append(lines, models.InvoiceServiceCharge{
+			ServiceChargeID: charge.ID,
+			Name:            charge.Name,
+			Rate:            charge.Rate,
+			BaseAmount:      FromCents(base),
+			Amount:          FromCents(amount),
+		})
+	}
+	return ServiceChargeResult{Total: FromCents(total), Lines: lines}
+}
@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"testing"
+
+	"restaurant-management/models"
+)
+
+func TestComputeServiceCharges(t *testing.T) {
+	service := models.ServiceCharge{ID: 1, Name: "Service", Rate: 12.5}
+	largeParty := models.ServiceCharge{ID: 2, Name: "Large party", Rate: 5, MinGuests: 6}
+
+	tests := []struct {
+		name    string
+		net     float64
+		guests  int
+		charges []models.ServiceCharge
+		total   float64
+		lines   int
+	}{
+		{"a charge applies to the net amount", 40, 2, []models.ServiceCharge{service}, 5, 1},
+		{"each charge is rounded half away from zero", 10.1, 2, []models.ServiceCharge{service}, 1.26, 1},
+		{"a party below the minimum isn't charged", 40, 5, []models.ServiceCharge{service, largeParty}, 5, 1},
+		{"a party at the minimum is charged", 40, 6, []models.ServiceCharge{largeParty, service}, 7, 2},
+		{"an order with nothing to pay isn't charged", 0, 8, []models.ServiceCharge{service, largeParty}, 0, 0},
+	}
+	for _, test := range tests {
+		result := ComputeServiceCharges(test.net, test.guests, test.charges)
+		if result.Total != test.total || len(result.Lines) != test.lines {
+			t.Errorf("%s: charged %v over %d lines, want %v over %d", test.name, result.Total, len(result.Lines), test.total, test.lines)
+		}
+		for i := 1; i < len(result.Lines); i++ {
+			if result.Lines[i-1].ServiceChargeID > result.Lines[i].ServiceChargeID {
+				t.Errorf("%s: the lines aren't in the order the charges were created", test.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+
+	"restaurant-management/models"
+)
+
+// SplitEvenly divides an invoice into parts of equal value. Amounts are shared out in
+// cents, the first parts take the cents that do not divide evenly, so the parts always
+// add up to the invoice.
+func SplitEvenly(invoice models.Invoice, parts int) []models.Invoice {
+	weights := make([]int64, parts)
+	for i := range weights {
+		weights[i] = 1
+	}
+
+	splits := newSplits(invoice, parts)
+	totals := allocate(ToCents(invoice.Total), weights)
+	discounts := allocate(ToCents(invoice.Discount), weights)
+	taxes := splitTaxes(invoice.Taxes, parts, func(models.InvoiceTax) []int64 { return weights })
+	charges := splitServiceCharges(invoice.ServiceCharges, weights)
+	for i := range splits {
+		splits[i].Discount = FromCents(discounts[i])
+		settleSplit(&splits[i], totals[i], taxes[i], charges[i])
+	}
+	return splits
+}
+
+// SplitByItems divides an invoice into one part per group of order items. Every item
+// must be in exactly one group. A part is charged what its items cost after discounts,
+// given by item ID in discounts, and each tax line is shared out over the parts in
+// proportion to what they hold of the line's category. Service charges are shared out
+// in proportion to what the parts' items cost.
+func SplitByItems(invoice models.Invoice, items []models.OrderItem, discounts map[uint]float64, groups [][]uint) ([]models.Invoice, error) {
+	byID := map[uint]models.OrderItem{}
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	seen := map[uint]bool{}
+	for _, group := range groups {
+		for _, id := range group {
+			if _, ok := byID[id]; !ok {
+				return nil, fmt.Errorf("order item %d is not on this order", id)
+			}
+			if seen[id] {
+				return nil, fmt.Errorf("order item %d is in more than one group", id)
+			}
+			seen[id] = true
+		}
+	}
+	for _, item := range items {
+		if !seen[item.ID] {
+			return nil, fmt.Errorf("order item %d is not in any group", item.ID)
+		}
+	}
+
+	splits := newSplits(invoice, len(groups))
+	gross := make([]int64, len(groups))
+	byCategory := make([]map[string]int64, len(groups))
+	for i, group := range groups {
+		byCategory[i] = map[string]int64{}
+		var discount int64
+		for _, id := range group {
+			item := byID[id]
+			amount := ToCents(item.SubTotal) - ToCents(discounts[id])
+			byCategory[i][item.TaxCategory] += amount
+			gross[i] += amount
+			discount += ToCents(discounts[id])
+		}
+		splits[i].Discount = FromCents(discount)
+		splits[i].OrderItemIDs = append([]uint{}, group...)
+		sort.Slice(splits[i].OrderItemIDs, func(a, b int) bool { return splits[i].OrderItemIDs[a] < splits[i].OrderItemIDs[b] })
+	}
+
+	taxes := splitTaxes(invoice.Taxes, len(groups), func(line models.InvoiceTax) []int64 {
+		weights := make([]int64, len(groups))
+		for i := range groups {
+			weights[i] = byCategory[i][line.Category]
+		}
+		return weights
+	})
+	charges := splitServiceCharges(invoice.ServiceCharges, gross)
+	for i := range splits {
+		// Inclusive taxes are already part of what the items cost, exclusive ones and service come on top
+		total := gross[i]
+		for _, line := range taxes[i] {
+			if !line.Inclusive {
+				total += ToCents(line.TaxAmount)
+			}
+		}
+		for _, line := range charges[i] {
+			total += ToCents(line.Amount)
+		}
+		settleSplit(&splits[i], total, taxes[i], charges[i])
+	}
+	return splits, nil
+}
+
+func newSplits(invoice models.Invoice, parts int) []models.Invoice {
+	splits := make([]models.Invoice, parts)
+	for i := range splits {
+		splits[i] = models.Invoice{
+			OrderID:       invoice.OrderID,
+			RestaurantID:  invoice.RestaurantID,
+			ParentID:      invoice.ID,
+			Status:        models.InvoiceStatusPending,
+			PaymentMethod: invoice.PaymentMethod,
+		}
+	}
+	return splits
+}
+
+// splitTaxes shares every tax line out over the parts by the weights given for it,
+// returning the lines of each part. Lines a part holds nothing of are left out.
+func splitTaxes(lines []models.InvoiceTax, count int, weights func(models.InvoiceTax) []int64) [][]models.InvoiceTax {
+	parts := make([][]models.InvoiceTax, count)
+	for _, line := range lines {
+		lineWeights := weights(line)
+		taxable := allocate(ToCents(line.TaxableAmount), lineWeights)
+		tax := allocate(ToCents(line.TaxAmount), lineWeights)
+		for i := range lineWeights {
+			if taxable[i] == 0 && tax[i] == 0 {
+				continue
+			}
+			part := line
+			part.TaxableAmount, part.TaxAmount = FromCents(taxable[i]), FromCents(tax[i])
+			parts[i] = append(parts[i], part)
+		}
+	}
+	return parts
+}
+
+// splitServiceCharges shares every service charge line out over the parts by the weights,
+// returning the lines of each part. Lines a part holds nothing of are left out.
+func splitServiceCharges(lines []models.InvoiceServiceCharge, weights []int64) [][]models.InvoiceServiceCharge {
+	parts := make([][]models.InvoiceServiceCharge, len(weights))
+	for _, line := range lines {
+		base := allocate(ToCents(line.BaseAmount), weights)
+		amount := allocate(ToCents(line.Amount), weights)
+		for i := range weights {
+			if base[i] == 0 && amount[i] == 0 {
+				continue
+			}
+			part := line
+			part.BaseAmount, part.Amount = FromCents(base[i]), FromCents(amount[i])
+			parts[i] = append(parts[i], part)
+		}
+	}
+	return parts
+}
+
+// settleSplit fills in the totals of a part from its total in cents, its tax lines and its service charges
+func settleSplit(split *models.Invoice, total int64, taxes []models.InvoiceTax, charges []models.InvoiceServiceCharge) {
+	var tax, service int64
+	for _, line := range taxes {
+		tax += ToCents(line.TaxAmount)
+	}
+	for _, line := range charges {
+		service += ToCents(line.Amount)
+	}
+	split.Taxes, split.ServiceCharges = taxes, charges
+	split.Tax = FromCents(tax)
+	split.ServiceCharge = FromCents(service)
+	split.Total = FromCents(total)
+	split.Amount = FromCents(total - tax - service)
+	split.Balance = split.Total
+}
+
+// allocate shares an amount out in proportion to the weights. Cents that do not divide
+// evenly go to the largest remainders, earlier shares first on a tie. With no weight at
+// all the amount is shared out evenly.
+func allocate(amount int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+	if len(weights) == 0 {
+		return shares
+	}
+
+	var base int64
+	for _, weight := range weights {
+		base += weight
+	}
+	if base == 0 {
+		weights = make([]int64, len(weights))
+		for i := range weights {
+			weights[i] = 1
+		}
+		base = int64(len(weights))
+	}
+
+	fractions := make([]int64, len(weights))
+	leftover := amount
+	for i, weight := range weights {
+		shares[i] = amount * weight / base
+		fractions[i] = amount * weight % base
+		leftover -= shares[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return fractions[order[a]] > fractions[order[b]] })
+	for _, i := range order[:leftover] {
+		shares[i]++
+	}
+	return shares
+}
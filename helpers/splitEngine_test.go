@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"slices"
+	"testing"
+
+	"restaurant-management/models"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		weights []int64
+		shares  []int64
+	}{
+		{"even shares", 900, []int64{1, 1, 1}, []int64{300, 300, 300}},
+		{"the remainder goes to the earlier shares on a tie", 1000, []int64{1, 1, 1}, []int64{334, 333, 333}},
+		{"two cents left over go to two shares", 200, []int64{1, 1, 1}, []int64{67, 67, 66}},
+		{"the largest remainder gets the cent first", 100, []int64{1, 2}, []int64{33, 67}},
+		{"proportional shares", 1000, []int64{400, 600}, []int64{400, 600}},
+		{"no weight at all shares out evenly", 101, []int64{0, 0}, []int64{51, 50}},
+		{"a share without weight gets nothing", 99, []int64{0, 3}, []int64{0, 99}},
+		{"nothing to share", 0, []int64{1, 1}, []int64{0, 0}},
+		{"no shares", 100, nil, []int64{}},
+	}
+	for _, test := range tests {
+		shares := allocate(test.amount, test.weights)
+		if !slices.Equal(shares, test.shares) {
+			t.Errorf("%s: allocating %d over %v gave %v, want %v", test.name, test.amount, test.weights, shares, test.shares)
+		}
+	}
+}
+
+func TestSplitEvenlyAddsUpToTheInvoice(t *testing.T) {
+	invoice := models.Invoice{
+		ID:     1,
+		Amount: 10,
+		Tax:    0.89,
+		Total:  10.89,
+		Taxes:  []models.InvoiceTax{{Name: "Sales tax", Category: "food", Rate: 8.875, TaxableAmount: 10, TaxAmount: 0.89}},
+	}
+
+	var total, tax float64
+	for _, split := range SplitEvenly(invoice, 3) {
+		total += split.Total
+		for _, line := range split.Taxes {
+			tax += line.TaxAmount
+		}
+	}
+	if ToCents(total) != ToCents(invoice.Total) || ToCents(tax) != ToCents(invoice.Tax) {
+		t.Errorf("the parts charge %v with %v tax, want %v with %v", total, tax, invoice.Total, invoice.Tax)
+	}
+}
@@ -0,0 +1,109 @@
+package helpers
+
+import (
+	"math"
+	"sort"
+
+	"restaurant-management/models"
+)
+
+// rateScale turns a percentage into an integer: 8.875% is 88750 parts per million
+const rateScale = 1_000_000
+
+// TaxResult is an order's priced amount split into net amount and tax, in currency units
+type TaxResult struct {
+	Net   float64
+	Tax   float64
+	Total float64
+	Lines []models.InvoiceTax
+}
+
+// ComputeTax applies the restaurant's rates to the amounts charged per tax category, as
+// priced on the menu. Inclusive rates of a category are first taken out of the price to
+// find the net amount, then every rate of the category is charged on that net amount.
+// All maths is done in cents and each rate's total is rounded half away from zero once,
+// so the lines always add up to the invoice's tax. Categories without rates are untaxed.
+func ComputeTax(amounts map[string]float64, rates []models.TaxRate) TaxResult {
+	byCategory := map[string][]models.TaxRate{}
+	for _, rate := range rates {
+		byCategory[rate.Category] = append(byCategory[rate.Category], rate)
+	}
+
+	categories := make([]string, 0, len(amounts))
+	for category := range amounts {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var net, tax int64
+	var lines []models.InvoiceTax
+	for _, category := range categories {
+		gross := ToCents(amounts[category])
+		categoryRates := append([]models.TaxRate{}, byCategory[category]...)
+		sort.Slice(categoryRates, func(i, j int) bool { return categoryRates[i].ID < categoryRates[j].ID })
+
+		var inclusiveCount int
+		var inclusivePPM int64
+		for _, rate := range categoryRates {
+			if rate.Inclusive {
+				inclusiveCount++
+				inclusivePPM += toPPM(rate.Rate)
+			}
+		}
+
+		categoryNet := divRound(gross*rateScale, rateScale+inclusivePPM)
+
+		// The inclusive tax is whatever the price holds beyond the net amount, the last
+		// inclusive rate absorbs the rounding so nothing is lost between the lines
+		remaining := gross - categoryNet
+		for _, rate := range categoryRates {
+			amount := divRound(categoryNet*toPPM(rate.Rate), rateScale)
+			if rate.Inclusive {
+				if inclusiveCount--; inclusiveCount == 0 {
+					amount = remaining
+				}
+				remaining -= amount
+			}
+
+			tax += amount
+			lines = append(lines, models.InvoiceTax{
+				TaxRateID:     rate.ID,
+				Name:          rate.Name,
+				Category:      category,
+				Rate:          rate.Rate,
+				Inclusive:     rate.Inclusive,
+				TaxableAmount: FromCents(categoryNet),
+				TaxAmount:     FromCents(amount),
+			})
+		}
+		net += categoryNet
+	}
+
+	return TaxResult{Net: FromCents(net), Tax: FromCents(tax), Total: FromCents(net + tax), Lines: lines}
+}
+
+// ToCents turns a currency amount into whole cents, rounding half away from zero, so amounts
+// are added and compared without float rounding getting in the way
+func ToCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// FromCents turns whole cents back into a currency amount
+func FromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+func toPPM(percent float64) int64 {
+	return int64(math.Round(percent * rateScale / 100))
+}
+
+// divRound divides rounding half away from zero
+func divRound(numerator, denominator int64) int64 {
+	if denominator < 0 {
+		numerator, denominator = -numerator, -denominator
+	}
+	if numerator < 0 {
+		return -((-numerator*2 + denominator) / (denominator * 2))
+	}
+	return (numerator*2 + denominator) / (denominator * 2)
+}
@@ -0,0 +1,48 @@
+package helpers
+
+import (
+	"testing"
+
+	"restaurant-management/models"
+)
+
+func TestComputeTax(t *testing.T) {
+	vat := models.TaxRate{ID: 1, Name: "VAT", Category: "food", Rate: 20, Inclusive: true}
+	salesTax := models.TaxRate{ID: 2, Name: "Sales tax", Category: "food", Rate: 10}
+	cityTax := models.TaxRate{ID: 3, Name: "City tax", Category: "food", Rate: 5}
+
+	tests := []struct {
+		name            string
+		amounts         map[string]float64
+		rates           []models.TaxRate
+		net, tax, total float64
+		lineTaxes       []float64
+	}{
+		{"exclusive rates are added on top", map[string]float64{"food": 100}, []models.TaxRate{salesTax}, 100, 10, 110, []float64{10}},
+		{"inclusive rates are taken out of the price", map[string]float64{"food": 120}, []models.TaxRate{vat}, 100, 20, 120, []float64{20}},
+		{"exclusive rates apply to the net of inclusive ones", map[string]float64{"food": 120}, []models.TaxRate{cityTax, vat}, 100, 25, 125, []float64{20, 5}},
+		{"categories without rates are untaxed", map[string]float64{"food": 100, "alcohol": 50}, []models.TaxRate{salesTax}, 150, 10, 160, []float64{10}},
+		{"the inclusive tax never changes the price", map[string]float64{"food": 10}, []models.TaxRate{{ID: 4, Category: "food", Rate: 8.875, Inclusive: true}}, 9.18, 0.82, 10, []float64{0.82}},
+		{
+			"the last inclusive rate absorbs the rounding",
+			map[string]float64{"food": 10},
+			[]models.TaxRate{{ID: 5, Category: "food", Rate: 7, Inclusive: true}, {ID: 6, Category: "food", Rate: 3, Inclusive: true}},
+			9.09, 0.91, 10, []float64{0.64, 0.27},
+		},
+	}
+	for _, test := range tests {
+		result := ComputeTax(test.amounts, test.rates)
+		if result.Net != test.net || result.Tax != test.tax || result.Total != test.total {
+			t.Errorf("%s: got net %v, tax %v, total %v, want %v, %v, %v", test.name, result.Net, result.Tax, result.Total, test.net, test.tax, test.total)
+		}
+		if len(result.Lines) != len(test.lineTaxes) {
+			t.Errorf("%s: got %d tax lines, want %d", test.name, len(result.Lines), len(test.lineTaxes))
+			continue
+		}
+		for i, line := range result.Lines {
+			if line.TaxAmount != test.lineTaxes[i] {
+				t.Errorf("%s: line %q charges %v, want %v", test.name, line.Name, line.TaxAmount, test.lineTaxes[i])
+			}
+		}
+	}
+}
@@ -1,14 +1,39 @@
 package helpers
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strconv"
 	"time"
 
+	"restaurant-management/models"
+
+	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// claimsContextKey is where the Authentication middleware stores the verified claims
+const claimsContextKey = "claims"
+
+// Token types, an access token authenticates requests and a refresh token only buys a new pair
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// Claims is the payload carried by every access token
+type Claims struct {
+	UserID      uint                `json:"user_id"`
+	Username    string              `json:"username"`
+	Role        string              `json:"role"`
+	Memberships []models.Membership `json:"memberships,omitempty"`
+	TokenType   string              `json:"typ"`
+	FamilyID    string              `json:"fid"` // shared by every token descending from one login
+	jwt.RegisteredClaims
+}
+
 func getSecretKey() []byte {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
@@ -17,45 +42,75 @@ func getSecretKey() []byte {
 	return []byte(secret)
 }
 
-func getJWTExpiryHours() int {
-	expiryStr := os.Getenv("JWT_EXPIRY_HOURS")
-	if expiryStr == "" {
-		return 24 // default 24 hours
+// NewTokenID returns a random identifier used for token IDs and token families
+func NewTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
 	}
+	return hex.EncodeToString(buf), nil
+}
 
-	expiry, err := strconv.Atoi(expiryStr)
+// CreateToken signs claims with a fresh token ID that expires after expiresIn
+func CreateToken(claims Claims, expiresIn time.Duration) (string, *Claims, error) {
+	tokenID, err := NewTokenID()
 	if err != nil {
-		return 24 // fallback to 24 hours if parsing fails
+		return "", nil, err
 	}
-	return expiry
-}
 
-func CreateToken(username string) (string, error) {
-	expiryHours := getJWTExpiryHours()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"username": username,
-		"exp":      time.Now().Add(time.Hour * time.Duration(expiryHours)).Unix(),
-	})
+	now := time.Now()
+	claims.ID = tokenID
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(expiresIn))
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.Subject = strconv.FormatUint(uint64(claims.UserID), 10)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := token.SignedString(getSecretKey())
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	return tokenString, nil
+	return tokenString, &claims, nil
 }
 
-func VerifyToken(tokenString string) error {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+func VerifyToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		return getSecretKey(), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !token.Valid {
-		return fmt.Errorf("invalid token")
+		return nil, fmt.Errorf("invalid token")
 	}
 
-	return nil
+	return claims, nil
+}
+
+// SetClaims stores the verified claims on the request context
+func SetClaims(c *gin.Context, claims *Claims) {
+	c.Set(claimsContextKey, claims)
+}
+
+// GetClaims returns the claims of the authenticated caller, if any
+func GetClaims(c *gin.Context) (*Claims, bool) {
+	value, exists := c.Get(claimsContextKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*Claims)
+	return claims, ok
+}
+
+// MembershipRole returns the caller's role in the given restaurant, or "" when they are not a member
+func (claims *Claims) MembershipRole(restaurantID uint) string {
+	for _, membership := range claims.Memberships {
+		if membership.RestaurantID == restaurantID {
+			return membership.Role
+		}
+	}
+	return ""
 }
@@ -0,0 +1,44 @@
+package helpers
+
+import (
+	"sort"
+	"time"
+)
+
+// TableTurn is a table as the wait estimate sees it: how many guests it seats and how long until it frees up
+type TableTurn struct {
+	Capacity int
+	FreeIn   time.Duration
+}
+
+// EstimateWait predicts how long a party of partySize waits for a table. The parties
+// ahead of it, given by size in queue order, each take the table that frees up first
+// among those seating them and hold it for one turn. ok is false when no table seats the party.
+func EstimateWait(tables []TableTurn, ahead []int, partySize int, turn time.Duration) (wait time.Duration, ok bool) {
+	tables = append([]TableTurn{}, tables...)
+	// smallest tables first, so on ties a party takes the snuggest fit
+	sort.SliceStable(tables, func(i, j int) bool { return tables[i].Capacity < tables[j].Capacity })
+
+	for _, size := range ahead {
+		if next := earliestFree(tables, size); next >= 0 {
+			tables[next].FreeIn += turn
+		}
+	}
+
+	next := earliestFree(tables, partySize)
+	if next < 0 {
+		return 0, false
+	}
+	return tables[next].FreeIn, true
+}
+
+// earliestFree returns the index of the table seating size that frees up first, or -1
+func earliestFree(tables []TableTurn, size int) int {
+	best := -1
+	for i, table := range tables {
+		if table.Capacity >= size && (best < 0 || table.FreeIn < tables[best].FreeIn) {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every runs fn right away and then once per interval until ctx is cancelled. A failed
+// run is logged and retried on the next tick. Each run must finish within the interval.
+// Jobs run on every API instance, so fn has to be safe to run concurrently with itself.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, interval)
+		if err := fn(runCtx); err != nil {
+			log.Printf("Job %s failed: %v", name, err)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
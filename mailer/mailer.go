@@ -0,0 +1,199 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"restaurant-management/config"
+)
+
+// Attachment is a file sent along with a message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Message is an HTML email to one recipient
+type Message struct {
+	To          string
+	Subject     string
+	HTML        string
+	Attachments []Attachment
+}
+
+// Mailer sends email. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// FromEnv builds the mailer named by MAILER: "smtp" (the default) sends through the SMTP
+// server configured for the application and "file" writes every message to the directory
+// named by MAILER_DIR.
+func FromEnv() (Mailer, error) {
+	switch kind := os.Getenv("MAILER"); kind {
+	case "", "smtp":
+		return NewSMTPMailer(config.SMTPSettingsFromEnv()), nil
+	case "file":
+		dir := os.Getenv("MAILER_DIR")
+		if dir == "" {
+			return nil, fmt.Errorf("MAILER_DIR is required for the file mailer")
+		}
+		return NewFileMailer(dir), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", kind)
+	}
+}
+
+// Permanent reports whether the mail server refused a message for good, such as an
+// unknown recipient, so sending it again would not help
+func Permanent(err error) bool {
+	var protocolErr *textproto.Error
+	return errors.As(err, &protocolErr) && protocolErr.Code >= 500
+}
+
+// SMTPMailer sends every message in a session of its own, so concurrent sends don't
+// share a connection and a dropped connection only fails one message
+type SMTPMailer struct {
+	settings config.SMTPSettings
+}
+
+func NewSMTPMailer(settings config.SMTPSettings) *SMTPMailer {
+	return &SMTPMailer{settings: settings}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message Message) error {
+	body, err := Compose(m.settings.Email, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := config.DialSMTP(ctx, m.settings)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Mail(m.settings.Email); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return err
+	}
+	writeCloser, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writeCloser.Write(body); err != nil {
+		writeCloser.Close()
+		return err
+	}
+	if err := writeCloser.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// FileMailer writes every message to a file of its own as the server would receive it, a
+// stand-in for a real mail server in development and tests
+type FileMailer struct {
+	dir string
+	mu  sync.Mutex
+	n   int
+}
+
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{dir: dir}
+}
+
+var unsafeFilename = regexp.MustCompile(`[^A-Za-z0-9@._-]+`)
+
+func (m *FileMailer) Send(ctx context.Context, message Message) error {
+	sentAt := time.Now().UTC()
+	body, err := Compose("restaurant@localhost", message, sentAt)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	m.n++
+	name := fmt.Sprintf("%s-%03d-%s.eml", sentAt.Format("20060102T150405"), m.n, unsafeFilename.ReplaceAllString(message.To, "_"))
+	return os.WriteFile(filepath.Join(m.dir, name), body, 0o644)
+}
+
+// Compose renders a message as MIME: the HTML body followed by its attachments, base64
+// encoded, in a multipart/mixed message
+func Compose(from string, message Message, date time.Time) ([]byte, error) {
+	if strings.ContainsAny(message.To, "\r\n") {
+		return nil, fmt.Errorf("invalid recipient %q", message.To)
+	}
+
+	var buf bytes.Buffer
+	parts := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", parts.Boundary())
+
+	html, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := html.Write(wrapBase64([]byte(message.HTML))); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range message.Attachments {
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(attachment.ContentType, map[string]string{"name": attachment.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(wrapBase64(attachment.Data)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// wrapBase64 encodes data in lines of 76 characters, the most MIME allows
+func wrapBase64(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var lines strings.Builder
+	for len(encoded) > 76 {
+		lines.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	lines.WriteString(encoded + "\r\n")
+	return []byte(lines.String())
+}
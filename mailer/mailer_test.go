@@ -0,0 +1,219 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"restaurant-management/config"
+)
+
+var testMessage = Message{
+	To:          "guest@example.com",
+	Subject:     "Your invoice from Test Kitchen",
+	HTML:        "<p>Thank you for dining with us</p>",
+	Attachments: []Attachment{{Filename: "invoice-1.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.4 not really")}},
+}
+
+// readMessage parses a composed message and returns its headers and the decoded parts, by content type
+func readMessage(t *testing.T, raw []byte) (mail.Header, map[string][]byte, map[string]string) {
+	t.Helper()
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parsing the message: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("the message is %s, %v, want multipart/mixed", mediaType, err)
+	}
+
+	parts, filenames := map[string][]byte{}, map[string]string{}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading a part: %v", err)
+		}
+		data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+		if err != nil {
+			t.Fatalf("decoding a part: %v", err)
+		}
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType], filenames[contentType] = data, part.FileName()
+	}
+	return message.Header, parts, filenames
+}
+
+func TestComposeAttachesFilesToTheHTMLBody(t *testing.T) {
+	raw, err := Compose("restaurant@example.com", testMessage, time.Now())
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+
+	header, parts, filenames := readMessage(t, raw)
+	if header.Get("To") != testMessage.To || header.Get("From") != "restaurant@example.com" {
+		t.Errorf("the message goes from %q to %q", header.Get("From"), header.Get("To"))
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(header.Get("Subject")); subject != testMessage.Subject {
+		t.Errorf("the subject is %q, want %q", subject, testMessage.Subject)
+	}
+	if string(parts["text/html"]) != testMessage.HTML {
+		t.Errorf("the body is %q, want %q", parts["text/html"], testMessage.HTML)
+	}
+	if !bytes.Equal(parts["application/pdf"], testMessage.Attachments[0].Data) || filenames["application/pdf"] != "invoice-1.pdf" {
+		t.Errorf("the attachment is %q named %q", parts["application/pdf"], filenames["application/pdf"])
+	}
+}
+
+func TestComposeRefusesHeadersInTheRecipient(t *testing.T) {
+	message := testMessage
+	message.To = "guest@example.com\r\nBcc: everyone@example.com"
+	if _, err := Compose("restaurant@example.com", message, time.Now()); err == nil {
+		t.Fatal("a recipient with a header in it was composed")
+	}
+}
+
+func TestFileMailerWritesOneFilePerMessage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	mailer := NewFileMailer(dir)
+	for range 2 {
+		if err := mailer.Send(context.Background(), testMessage); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("the outbox holds %v, %v, want 2 messages", files, err)
+	}
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("reading the message: %v", err)
+	}
+	if header, _, _ := readMessage(t, raw); header.Get("To") != testMessage.To {
+		t.Errorf("the message goes to %q", header.Get("To"))
+	}
+}
+
+// smtpStandIn is a local SMTP server taking one message per session, refusing recipients in refuse
+type smtpStandIn struct {
+	listener net.Listener
+	refuse   string
+	received chan []byte
+}
+
+func newSMTPStandIn(t *testing.T, refuse string) *smtpStandIn {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &smtpStandIn{listener: listener, refuse: refuse, received: make(chan []byte, 1)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *smtpStandIn) settings() config.SMTPSettings {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return config.SMTPSettings{Host: host, Port: port, Email: "restaurant@example.com"}
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	text := textproto.NewConn(conn)
+	defer text.Close()
+
+	text.PrintfLine("220 localhost ready")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			text.PrintfLine("250 localhost")
+		case strings.HasPrefix(command, "RCPT") && s.refuse != "" && strings.Contains(line, s.refuse):
+			text.PrintfLine("550 no such user")
+		case strings.HasPrefix(command, "MAIL"), strings.HasPrefix(command, "RCPT"):
+			text.PrintfLine("250 OK")
+		case command == "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+			s.received <- data
+			text.PrintfLine("250 queued")
+		case command == "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPMailerSendsToTheServer(t *testing.T) {
+	server := newSMTPStandIn(t, "")
+	if err := NewSMTPMailer(server.settings()).Send(context.Background(), testMessage); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	select {
+	case raw := <-server.received:
+		if _, parts, _ := readMessage(t, raw); string(parts["text/html"]) != testMessage.HTML {
+			t.Errorf("the server received the body %q", parts["text/html"])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the server received nothing")
+	}
+}
+
+func TestSMTPMailerRefusedRecipientIsPermanent(t *testing.T) {
+	server := newSMTPStandIn(t, testMessage.To)
+	err := NewSMTPMailer(server.settings()).Send(context.Background(), testMessage)
+	if err == nil {
+		t.Fatal("a refused recipient was sent to")
+	}
+	if !Permanent(err) {
+		t.Errorf("the refusal %v isn't permanent", err)
+	}
+}
+
+func TestSMTPMailerUnreachableServerIsNotPermanent(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	err = NewSMTPMailer(config.SMTPSettings{Host: host, Port: port}).Send(context.Background(), testMessage)
+	if err == nil || Permanent(err) {
+		t.Errorf("sending with nothing listening gave %v, want an error worth retrying", err)
+	}
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
+	_ "time/tzdata" // restaurant time zones without relying on the host's zoneinfo
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -11,6 +14,7 @@ import (
 	"restaurant-management/config"
 	"restaurant-management/controllers"
 	"restaurant-management/database"
+	"restaurant-management/jobs"
 	"restaurant-management/middlewares"
 	"restaurant-management/routes"
 )
@@ -31,12 +35,30 @@ func main() {
 	database.InitializeDB()
 	defer database.CloseDBConnection() // Ensure the database connection is closed when the application exits
 
-	// Set up database tables
-	database.SetupTables()
+	// CLI mode: `go run main.go migrate up|down [steps]|status` manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunMigrateCommand(os.Args[2:]); err != nil {
+			fmt.Println("Migration failed:", err)
+			database.CloseDBConnection()
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Bring the database schema up to date
+	database.SetupSchema()
 
 	// Initialize controllers
 	controllers.InitControllers()
 
+	// Make the user named by ADMIN_EMAIL an admin, the only way to get the first one
+	if err := controllers.BootstrapAdmin(context.Background(), os.Getenv("ADMIN_EMAIL")); err != nil {
+		fmt.Println("Admin bootstrap failed:", err)
+	}
+
+	// Background jobs
+	go jobs.Every(context.Background(), "reservation sweep", time.Minute, controllers.SweepReservations)
+
 	// Create a new Gin router
 	router := gin.New()
 	router.Use(gin.Logger())
@@ -70,15 +92,29 @@ func main() {
 	// Public Routes
 	routes.PublicUserRoutes(router)
 	routes.PublicCustomerRoutes(router)
+	routes.PublicPaymentRoutes(router)
 
 	// Private Routes
 	routes.ProtectedUserRoutes(authGroup)
 	routes.RestaurantRoutes(authGroup)
 	routes.FoodRoutes(authGroup)
+	routes.ComboRoutes(authGroup)
 	routes.MenuRoutes(authGroup)
 	routes.TableRoutes(authGroup)
 	routes.OrderRoutes(authGroup)
 	routes.OrderItemRoutes(authGroup)
+	routes.KitchenRoutes(authGroup)
+	routes.StationRoutes(authGroup)
+	routes.ReservationRoutes(authGroup)
+	routes.WaitlistRoutes(authGroup)
+	routes.TaxRateRoutes(authGroup)
+	routes.ServiceChargeRoutes(authGroup)
+	routes.PromotionRoutes(authGroup)
+	routes.PaymentRoutes(authGroup)
+	routes.CreditNoteRoutes(authGroup)
+	routes.NumberSeriesRoutes(authGroup)
+	routes.InvoiceTemplateRoutes(authGroup)
+	routes.ReceiptRoutes(authGroup)
 	routes.InvoiceRoutes(authGroup)
 	routes.NoteRoutes(authGroup)
 
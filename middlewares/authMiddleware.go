@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"restaurant-management/helpers"
+	"restaurant-management/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -35,12 +36,26 @@ func Authentication() gin.HandlerFunc {
 			return
 		}
 
-		err := helpers.VerifyToken(tokenString)
-		if err != nil {
+		claims, err := helpers.VerifyToken(tokenString)
+		if err != nil || claims.TokenType != helpers.TokenTypeAccess {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			return
 		}
 
+		// 4. Reject tokens that were logged out or belong to a revoked family
+		revoked, err := utils.IsTokenRevoked(c, claims)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify token status", "details": err.Error()})
+			return
+		}
+		if revoked {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			return
+		}
+
+		// 5. Make the caller's identity available to the policy layer and controllers
+		helpers.SetClaims(c, claims)
+
 		c.Next()
 	}
 }
@@ -0,0 +1,241 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"restaurant-management/database"
+	"restaurant-management/helpers"
+	"restaurant-management/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Denial describes why a request was refused by the policy layer
+type Denial struct {
+	Reason       string `json:"reason"`
+	Required     string `json:"required,omitempty"`
+	RestaurantID uint   `json:"restaurant_id,omitempty"`
+	status       int
+}
+
+// Rule inspects the request and the caller's claims. Returning nil allows the request.
+type Rule func(c *gin.Context, claims *helpers.Claims) *Denial
+
+// RestaurantResolver finds the restaurant a request acts on. A zero ID with a nil
+// error means the target exists but is not attached to any restaurant yet.
+type RestaurantResolver func(c *gin.Context) (uint, error)
+
+var (
+	errMissingScope = errors.New("restaurant scope is missing from the request")
+	errNotFound     = errors.New("resource not found")
+)
+
+var membershipRank = map[string]int{
+	models.MembershipStaff:   1,
+	models.MembershipManager: 2,
+	models.MembershipOwner:   3,
+}
+
+// Authorize runs every rule in order and aborts with a 403 on the first denial
+func Authorize(rules ...Rule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := helpers.GetClaims(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		for _, rule := range rules {
+			if denial := rule(c, claims); denial != nil {
+				Forbid(c, denial)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// Forbid writes the standard denial payload shared by every protected route
+func Forbid(c *gin.Context, denial *Denial) {
+	status := denial.status
+	if status == 0 {
+		status = http.StatusForbidden
+	}
+	c.AbortWithStatusJSON(status, gin.H{"error": http.StatusText(status), "details": denial})
+}
+
+// RequireRole allows callers whose account role is one of roles
+func RequireRole(roles ...string) Rule {
+	return func(c *gin.Context, claims *helpers.Claims) *Denial {
+		for _, role := range roles {
+			if claims.Role == role {
+				return nil
+			}
+		}
+		return &Denial{Reason: "Your account role is not allowed to perform this action", Required: roles[0]}
+	}
+}
+
+// Self allows callers acting on their own user record, identified by a path parameter
+func Self(param string) Rule {
+	return func(c *gin.Context, claims *helpers.Claims) *Denial {
+		id, err := strconv.ParseUint(c.Param(param), 10, 64)
+		if err != nil || uint(id) != claims.UserID {
+			return &Denial{Reason: "You can only act on your own account", Required: "self"}
+		}
+		return nil
+	}
+}
+
+// SelfInBody allows requests whose JSON body field references the caller's user ID
+func SelfInBody(field string) Rule {
+	return func(c *gin.Context, claims *helpers.Claims) *Denial {
+		id, err := bodyID(c, field)
+		if err != nil || id != claims.UserID {
+			return &Denial{Reason: "You can only act on your own account", Required: "self"}
+		}
+		return nil
+	}
+}
+
+// Member allows callers holding at least role in the restaurant found by resolve. Restaurant
+// 0 is no restaurant at all and is refused.
+func Member(resolve RestaurantResolver, role string) Rule {
+	return member(resolve, role, false)
+}
+
+// MemberOrBlank is Member for routes that also work on records not attached to a restaurant
+// yet, such as blank order IDs, which are open to a member of any restaurant
+func MemberOrBlank(resolve RestaurantResolver, role string) Rule {
+	return member(resolve, role, true)
+}
+
+func member(resolve RestaurantResolver, role string, allowBlank bool) Rule {
+	return func(c *gin.Context, claims *helpers.Claims) *Denial {
+		restaurantID, err := resolve(c)
+		if err != nil {
+			if errors.Is(err, errNotFound) {
+				return &Denial{Reason: "The requested resource does not exist", status: http.StatusNotFound}
+			}
+			if errors.Is(err, errMissingScope) {
+				return &Denial{Reason: "A restaurant must be specified for this action", Required: role}
+			}
+			return &Denial{Reason: "Unable to resolve the restaurant for this request: " + err.Error(), status: http.StatusInternalServerError}
+		}
+
+		if restaurantID == 0 {
+			if !allowBlank {
+				return &Denial{Reason: "A restaurant must be specified for this action", Required: role}
+			}
+			if len(claims.Memberships) == 0 {
+				return &Denial{Reason: "You are not a member of any restaurant", Required: role}
+			}
+			return nil
+		}
+
+		if membershipRank[claims.MembershipRole(restaurantID)] < membershipRank[role] {
+			return &Denial{Reason: "You don't have the required role in this restaurant", Required: role, RestaurantID: restaurantID}
+		}
+		return nil
+	}
+}
+
+// FromParam reads the restaurant ID straight from a path parameter
+func FromParam(param string) RestaurantResolver {
+	return func(c *gin.Context) (uint, error) {
+		return parseScope(c.Param(param))
+	}
+}
+
+// FromQuery reads the restaurant ID from a query string parameter
+func FromQuery(param string) RestaurantResolver {
+	return func(c *gin.Context) (uint, error) {
+		return parseScope(c.Query(param))
+	}
+}
+
+// FromBody reads the restaurant ID from a JSON body field
+func FromBody(field string) RestaurantResolver {
+	return func(c *gin.Context) (uint, error) {
+		id, err := bodyID(c, field)
+		if err != nil || id == 0 {
+			return 0, errMissingScope
+		}
+		return id, nil
+	}
+}
+
+// LookupByParam runs query with a path parameter to find the owning restaurant of a record.
+// The query must select a single, possibly NULL, restaurant_id.
+func LookupByParam(query, param string) RestaurantResolver {
+	return func(c *gin.Context) (uint, error) {
+		return lookupRestaurant(query, c.Param(param))
+	}
+}
+
+// LookupByBody is LookupByParam for IDs sent in the JSON body
+func LookupByBody(query, field string) RestaurantResolver {
+	return func(c *gin.Context) (uint, error) {
+		id, err := bodyID(c, field)
+		if err != nil || id == 0 {
+			return 0, errMissingScope
+		}
+		return lookupRestaurant(query, id)
+	}
+}
+
+func parseScope(value string) (uint, error) {
+	if value == "" {
+		return 0, errMissingScope
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errMissingScope
+	}
+	return uint(id), nil
+}
+
+func lookupRestaurant(query string, arg any) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var restaurantID sql.NullInt64
+	if err := database.Client.QueryRowContext(ctx, query, arg).Scan(&restaurantID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, errNotFound
+		}
+		return 0, err
+	}
+	return uint(restaurantID.Int64), nil
+}
+
+// bodyID peeks at a numeric field of the JSON body and restores the body for the controller
+func bodyID(c *gin.Context, field string) (uint, error) {
+	if c.Request.Body == nil {
+		return 0, errMissingScope
+	}
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return 0, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	var body map[string]any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return 0, err
+	}
+	value, ok := body[field].(float64)
+	if !ok || value < 0 {
+		return 0, errMissingScope
+	}
+	return uint(value), nil
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+// The 14 allergens EU food law requires to be declared, as foods and guests name them
+const (
+	AllergenCelery      = "celery"
+	AllergenGluten      = "gluten" // cereals containing gluten
+	AllergenCrustaceans = "crustaceans"
+	AllergenEggs        = "eggs"
+	AllergenFish        = "fish"
+	AllergenLupin       = "lupin"
+	AllergenMilk        = "milk"
+	AllergenMolluscs    = "molluscs"
+	AllergenMustard     = "mustard"
+	AllergenNuts        = "nuts" // tree nuts
+	AllergenPeanuts     = "peanuts"
+	AllergenSesame      = "sesame"
+	AllergenSoya        = "soya"
+	AllergenSulphites   = "sulphites" // sulphur dioxide and sulphites
+)
+
+// Diets a food can be tagged as suiting
+const (
+	DietVegetarian = "vegetarian"
+	DietVegan      = "vegan"
+	DietGlutenFree = "gluten_free"
+	DietHalal      = "halal"
+	DietJain       = "jain"
+)
+
+// Allergens and Diets list every allergen and diet, in the order they are listed to guests
+var (
+	Allergens = []string{AllergenCelery, AllergenGluten, AllergenCrustaceans, AllergenEggs, AllergenFish, AllergenLupin, AllergenMilk,
+		AllergenMolluscs, AllergenMustard, AllergenNuts, AllergenPeanuts, AllergenSesame, AllergenSoya, AllergenSulphites}
+	Diets = []string{DietVegetarian, DietVegan, DietGlutenFree, DietHalal, DietJain}
+)
+
+// dietExcludes are the allergens a food tagged with a diet can't contain
+var dietExcludes = map[string][]string{
+	DietVegetarian: {AllergenFish, AllergenCrustaceans, AllergenMolluscs},
+	DietVegan:      {AllergenFish, AllergenCrustaceans, AllergenMolluscs, AllergenMilk, AllergenEggs},
+	DietGlutenFree: {AllergenGluten},
+	DietJain:       {AllergenFish, AllergenCrustaceans, AllergenMolluscs, AllergenEggs},
+}
+
+// AllergenWarning flags an ordered item containing allergens the guest declared
+type AllergenWarning struct {
+	OrderItemID uint     `json:"order_item_id"`
+	FoodName    string   `json:"food_name"`
+	Allergens   []string `json:"allergens"`
+}
+
+// NormalizeDiet sorts and dedupes the food's allergens and dietary tags, and keeps Vegetarian
+// and the vegetarian tag in step: vegan and Jain foods are vegetarian too
+func (f *Food) NormalizeDiet() {
+	f.Allergens = NormalizeAllergens(f.Allergens)
+	if f.Vegetarian || slices.ContainsFunc(f.DietaryTags, func(tag string) bool { return tag == DietVegan || tag == DietJain }) {
+		f.DietaryTags = append(f.DietaryTags, DietVegetarian)
+	}
+	f.DietaryTags = normalizeNames(f.DietaryTags, Diets)
+	f.Vegetarian = slices.Contains(f.DietaryTags, DietVegetarian)
+}
+
+// DietConflict says why the food's dietary tags don't go with its allergens, like a vegan
+// food containing milk, "" when they do
+func (f Food) DietConflict() string {
+	for _, tag := range f.DietaryTags {
+		for _, allergen := range dietExcludes[tag] {
+			if slices.Contains(f.Allergens, allergen) {
+				return f.Name + " can't be " + strings.ReplaceAll(tag, "_", "-") + " and contain " + allergen
+			}
+		}
+	}
+	return ""
+}
+
+// Suits tells whether the food is tagged with every one of diets and contains none of allergens
+func (f Food) Suits(diets, allergens []string) bool {
+	for _, diet := range diets {
+		if !slices.Contains(f.DietaryTags, diet) {
+			return false
+		}
+	}
+	return len(SharedAllergens(allergens, f.Allergens)) == 0
+}
+
+// SharedAllergens returns the declared allergens that contained holds, in the order they are listed to guests
+func SharedAllergens(declared, contained []string) []string {
+	shared := []string{}
+	for _, allergen := range Allergens {
+		if slices.Contains(declared, allergen) && slices.Contains(contained, allergen) {
+			shared = append(shared, allergen)
+		}
+	}
+	return shared
+}
+
+// AllergenWarnings flags the items containing any of the allergens a guest declared, a combo
+// through the foods it is made of
+func AllergenWarnings(declared []string, items []OrderItem) []AllergenWarning {
+	var warnings []AllergenWarning
+	for _, item := range items {
+		if shared := SharedAllergens(declared, item.Allergens); len(shared) > 0 {
+			warnings = append(warnings, AllergenWarning{OrderItemID: item.ID, FoodName: item.DisplayName(), Allergens: shared})
+		}
+	}
+	return warnings
+}
+
+// ComponentAllergens are the allergens of a combo item, all those of the foods it is made of
+func ComponentAllergens(components []OrderItem) []string {
+	var allergens []string
+	for _, component := range components {
+		allergens = append(allergens, component.Allergens...)
+	}
+	return NormalizeAllergens(allergens)
+}
+
+// NormalizeAllergens puts allergens in the order they are listed to guests, once each
+func NormalizeAllergens(allergens []string) []string {
+	return normalizeNames(allergens, Allergens)
+}
+
+// UnknownName returns the first of names that known doesn't list, "" when known lists them all
+func UnknownName(names, known []string) string {
+	for _, name := range names {
+		if !slices.Contains(known, name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// normalizeNames puts names in the order known lists them, once each, leaving out unknown ones
+func normalizeNames(names, known []string) []string {
+	normalized := []string{}
+	for _, name := range known {
+		if slices.Contains(names, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAllergenWarnings(t *testing.T) {
+	items := []OrderItem{
+		{ID: 1, FoodName: "Pesto pasta", Allergens: []string{AllergenGluten, AllergenMilk, AllergenNuts}},
+		{ID: 2, FoodName: "Latte", VariantName: "Large", Allergens: []string{AllergenMilk}},
+		{ID: 3, FoodName: "Salad"},
+		{ID: 4, FoodName: "Burger meal", Allergens: ComponentAllergens([]OrderItem{
+			{Allergens: []string{AllergenSesame, AllergenGluten}},
+			{Allergens: []string{AllergenGluten, AllergenMustard}},
+		})},
+	}
+
+	tests := []struct {
+		name     string
+		declared []string
+		warnings []AllergenWarning
+	}{
+		{"no declared allergens, no warnings", nil, nil},
+		{"an allergen nothing contains", []string{AllergenFish}, nil},
+		{
+			"every item containing a declared allergen",
+			[]string{AllergenMilk},
+			[]AllergenWarning{{OrderItemID: 1, FoodName: "Pesto pasta", Allergens: []string{AllergenMilk}}, {OrderItemID: 2, FoodName: "Latte (Large)", Allergens: []string{AllergenMilk}}},
+		},
+		{
+			"shared allergens in the order guests see them",
+			[]string{AllergenNuts, AllergenGluten},
+			[]AllergenWarning{{OrderItemID: 1, FoodName: "Pesto pasta", Allergens: []string{AllergenGluten, AllergenNuts}}, {OrderItemID: 4, FoodName: "Burger meal", Allergens: []string{AllergenGluten}}},
+		},
+		{"a combo through its components", []string{AllergenMustard}, []AllergenWarning{{OrderItemID: 4, FoodName: "Burger meal", Allergens: []string{AllergenMustard}}}},
+	}
+	for _, test := range tests {
+		if warnings := AllergenWarnings(test.declared, items); !reflect.DeepEqual(warnings, test.warnings) {
+			t.Errorf("%s: got %+v, want %+v", test.name, warnings, test.warnings)
+		}
+	}
+}
+
+func TestFoodDiet(t *testing.T) {
+	tests := []struct {
+		name       string
+		food       Food
+		tags       []string
+		vegetarian bool
+		conflicts  bool
+	}{
+		{"vegan foods are vegetarian", Food{DietaryTags: []string{DietVegan}}, []string{DietVegetarian, DietVegan}, true, false},
+		{"the flag tags the food", Food{Vegetarian: true}, []string{DietVegetarian}, true, false},
+		{"unknown tags are dropped", Food{DietaryTags: []string{"keto", DietHalal, DietHalal}}, []string{DietHalal}, false, false},
+		{"a vegan food can't contain milk", Food{DietaryTags: []string{DietVegan}, Allergens: []string{AllergenMilk}}, []string{DietVegetarian, DietVegan}, true, true},
+		{"a gluten-free food can't contain gluten", Food{DietaryTags: []string{DietGlutenFree}, Allergens: []string{AllergenGluten}}, []string{DietGlutenFree}, false, true},
+	}
+	for _, test := range tests {
+		food := test.food
+		food.NormalizeDiet()
+		if !reflect.DeepEqual(food.DietaryTags, test.tags) || food.Vegetarian != test.vegetarian {
+			t.Errorf("%s: tagged %v, vegetarian %v, want %v, %v", test.name, food.DietaryTags, food.Vegetarian, test.tags, test.vegetarian)
+		}
+		if conflict := food.DietConflict(); (conflict != "") != test.conflicts {
+			t.Errorf("%s: conflict %q", test.name, conflict)
+		}
+	}
+
+	nutty := Food{DietaryTags: []string{DietVegetarian}, Allergens: []string{AllergenNuts}}
+	if !nutty.Suits([]string{DietVegetarian}, []string{AllergenMilk}) || nutty.Suits(nil, []string{AllergenNuts}) || nutty.Suits([]string{DietVegan}, nil) {
+		t.Error("Suits doesn't match the food's diets and allergens")
+	}
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// weekdays are the days availability windows name, indexed by time.Weekday
+var weekdays = [...]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// clockPattern is a time of day as availability windows take it, HH:MM on the 24-hour clock
+var clockPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// AvailabilityWindow is a time a menu or food can be ordered in, read in the restaurant's time
+// zone. Days limits it to days of the week, StartTime and EndTime (HH:MM) to part of the day,
+// an end before the start running past midnight into the next day, and StartDate and EndDate
+// (YYYY-MM-DD, both included) to a season. What is left empty doesn't limit the window.
+type AvailabilityWindow struct {
+	Days      []string `json:"days,omitempty" validate:"max=7,dive,oneof=mon tue wed thu fri sat sun"`
+	StartTime string   `json:"start_time,omitempty" validate:"required_with=EndTime,omitempty,len=5,datetime=15:04"`
+	EndTime   string   `json:"end_time,omitempty" validate:"required_with=StartTime,omitempty,len=5,datetime=15:04"`
+	StartDate string   `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string   `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// ClockMinutes reads a HH:MM time of day as minutes since midnight, false when it isn't one
+func ClockMinutes(clock string) (int, bool) {
+	if !clockPattern.MatchString(clock) {
+		return 0, false
+	}
+	return int(clock[0]-'0')*600 + int(clock[1]-'0')*60 + int(clock[3]-'0')*10 + int(clock[4]-'0'), true
+}
+
+// Open tells whether the window is open at a time given in the restaurant's time zone. A window
+// running past midnight belongs to the day and season it starts in. Hours that aren't HH:MM
+// keep the window closed.
+func (w AvailabilityWindow) Open(at time.Time) bool {
+	day := at
+	if w.StartTime != "" || w.EndTime != "" {
+		start, startOK := ClockMinutes(w.StartTime)
+		end, endOK := ClockMinutes(w.EndTime)
+		if !startOK || !endOK {
+			return false
+		}
+		clock := at.Hour()*60 + at.Minute()
+		switch {
+		case start < end:
+			if clock < start || clock >= end {
+				return false
+			}
+		case start > end:
+			if clock < end {
+				day = at.AddDate(0, 0, -1)
+			} else if clock < start {
+				return false
+			}
+		}
+	}
+
+	if len(w.Days) > 0 {
+		open := false
+		for _, name := range w.Days {
+			open = open || name == weekdays[day.Weekday()]
+		}
+		if !open {
+			return false
+		}
+	}
+	date := day.Format("2006-01-02")
+	return (w.StartDate == "" || date >= w.StartDate) && (w.EndDate == "" || date <= w.EndDate)
+}
+
+// String describes the window the way a guest would be told, like "mon, tue 07:00-11:00"
+func (w AvailabilityWindow) String() string {
+	var parts []string
+	if len(w.Days) > 0 {
+		parts = append(parts, strings.Join(w.Days, ", "))
+	}
+	if w.StartTime != "" {
+		parts = append(parts, w.StartTime+"-"+w.EndTime)
+	}
+	switch {
+	case w.StartDate != "" && w.EndDate != "":
+		parts = append(parts, "from "+w.StartDate+" to "+w.EndDate)
+	case w.StartDate != "":
+		parts = append(parts, "from "+w.StartDate)
+	case w.EndDate != "":
+		parts = append(parts, "until "+w.EndDate)
+	}
+	if len(parts) == 0 {
+		return "at any time"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Schedule is when a menu or food can be ordered, in any of its windows. An empty schedule is always open.
+type Schedule []AvailabilityWindow
+
+// Open tells whether any window of the schedule is open at a time given in the restaurant's time zone
+func (s Schedule) Open(at time.Time) bool {
+	for _, window := range s {
+		if window.Open(at) {
+			return true
+		}
+	}
+	return len(s) == 0
+}
+
+// closedReason tells guests when something named name is served
+func (s Schedule) closedReason(name string) string {
+	described := make([]string, len(s))
+	for i, window := range s {
+		described[i] = window.String()
+	}
+	return fmt.Sprintf("%s is only served %s", name, strings.Join(described, " or "))
+}
+
+// MenuUnavailableReason says why a menu can't be ordered from at a time given in the restaurant's
+// time zone, "" when it can. menus are the restaurant's menus, a category is closed whenever one
+// of the categories it is nested under is.
+func MenuUnavailableReason(menuID uint, menus []Menu, at time.Time) string {
+	byID := make(map[uint]Menu, len(menus))
+	for _, menu := range menus {
+		byID[menu.ID] = menu
+	}
+	seen := map[uint]bool{}
+	for id := menuID; id != 0 && !seen[id]; {
+		menu, ok := byID[id]
+		if !ok {
+			break
+		}
+		if !menu.Availability.Open(at) {
+			return menu.Availability.closedReason(menu.Name)
+		}
+		seen[id] = true
+		id = menu.ParentID
+	}
+	return ""
+}
+
+// FoodUnavailableReason says why a food can't be ordered at a time given in the restaurant's
+// time zone, "" when it can: it is marked unavailable, or it or its menu is out of its hours
+func FoodUnavailableReason(food Food, menus []Menu, at time.Time) string {
+	if !food.Available {
+		return food.Name + " is not available"
+	}
+	if !food.Availability.Open(at) {
+		return food.Availability.closedReason(food.Name)
+	}
+	return MenuUnavailableReason(food.MenuID, menus, at)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAvailabilityWindowOpen(t *testing.T) {
+	// 2026-10-16 is a Friday
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, time.October, day, hour, minute, 0, 0, time.UTC) }
+	breakfast := AvailabilityWindow{StartTime: "07:00", EndTime: "11:30"}
+	lateNight := AvailabilityWindow{Days: []string{"fri"}, StartTime: "22:00", EndTime: "02:00"}
+	autumn := AvailabilityWindow{StartTime: "20:00", EndTime: "01:00", StartDate: "2026-10-01", EndDate: "2026-10-16"}
+
+	tests := []struct {
+		name   string
+		window AvailabilityWindow
+		at     time.Time
+		open   bool
+	}{
+		{"same day, at the start", breakfast, at(16, 7, 0), true},
+		{"same day, within", breakfast, at(16, 10, 59), true},
+		{"same day, at the end", breakfast, at(16, 11, 30), false},
+		{"same day, before the start", breakfast, at(16, 6, 59), false},
+		{"same day, from a morning hour", AvailabilityWindow{StartTime: "09:00", EndTime: "17:00"}, at(16, 12, 0), true},
+		{"overnight, before midnight", AvailabilityWindow{StartTime: "22:00", EndTime: "02:00"}, at(16, 23, 0), true},
+		{"overnight, after midnight", AvailabilityWindow{StartTime: "22:00", EndTime: "02:00"}, at(17, 1, 59), true},
+		{"overnight, at the end", AvailabilityWindow{StartTime: "22:00", EndTime: "02:00"}, at(17, 2, 0), false},
+		{"overnight, in the afternoon", AvailabilityWindow{StartTime: "22:00", EndTime: "02:00"}, at(16, 15, 0), false},
+		{"the night rolls over into saturday", lateNight, at(17, 1, 0), true},
+		{"saturday's own night isn't friday's", lateNight, at(17, 23, 0), false},
+		{"friday's small hours belong to thursday", lateNight, at(16, 1, 0), false},
+		{"the season's last day is included", autumn, at(16, 21, 0), true},
+		{"the last night runs past the season's end", autumn, at(17, 0, 30), true},
+		{"the day after the season", autumn, at(17, 21, 0), false},
+		{"the season's first small hours belong to the day before", autumn, at(1, 0, 30), false},
+		{"unpadded hours keep the window closed", AvailabilityWindow{StartTime: "9:00", EndTime: "17:00"}, at(16, 12, 0), false},
+		{"hours out of range keep the window closed", AvailabilityWindow{StartTime: "07:00", EndTime: "24:00"}, at(16, 12, 0), false},
+		{"a window without limits is always open", AvailabilityWindow{}, at(16, 3, 0), true},
+	}
+	for _, test := range tests {
+		if open := test.window.Open(test.at); open != test.open {
+			t.Errorf("%s: %v at %s is open %v, want %v", test.name, test.window, test.at.Format("Mon 15:04"), open, test.open)
+		}
+	}
+}
+
+func TestClockMinutes(t *testing.T) {
+	tests := []struct {
+		clock   string
+		minutes int
+		ok      bool
+	}{
+		{"00:00", 0, true},
+		{"09:05", 545, true},
+		{"23:59", 1439, true},
+		{"9:05", 0, false},
+		{"09:5", 0, false},
+		{"24:00", 0, false},
+		{"12:60", 0, false},
+		{" 9:05", 0, false},
+		{"", 0, false},
+	}
+	for _, test := range tests {
+		if minutes, ok := ClockMinutes(test.clock); minutes != test.minutes || ok != test.ok {
+			t.Errorf("%q read as %d, %v, want %d, %v", test.clock, minutes, ok, test.minutes, test.ok)
+		}
+	}
+}
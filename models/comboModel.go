@@ -0,0 +1,191 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// Combo is a meal of several foods sold together at a bundle price. Each slot comes with its
+// food, which guests may swap for one of the slot's choices.
+type Combo struct {
+	ID           uint      `json:"id"`
+	RestaurantID uint      `json:"restaurant_id" validate:"required"`
+	Name         string    `json:"name" validate:"required,max=100,min=2"`
+	Description  string    `json:"description" validate:"max=500"`
+	ImageURL     string    `json:"image_url" validate:"max=255"`
+	Price        float64   `json:"price" validate:"gt=0"`
+	TaxCategory  string    `json:"tax_category" validate:"omitempty,oneof=food alcohol takeaway"`
+	Available    bool      `json:"available"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	Slots []ComboSlot `json:"slots" validate:"min=1,max=10,dive"`
+}
+
+// ComboSlot is a part of a combo, like its main or its drink, served Quantity times
+type ComboSlot struct {
+	ID       uint          `json:"id"`
+	Name     string        `json:"name" validate:"required,max=50"`
+	Quantity uint          `json:"quantity"` // 0 is taken as 1
+	FoodID   uint          `json:"food_id" validate:"required"`
+	Choices  []ComboChoice `json:"choices" validate:"max=20,dive"`
+}
+
+// ComboChoice is a food that may fill a slot instead of the slot's own, for PriceDelta more
+type ComboChoice struct {
+	FoodID     uint    `json:"food_id" validate:"required"`
+	PriceDelta float64 `json:"price_delta"`
+}
+
+// ComboPick is how a guest fills one slot of the combo they order: the food, 0 for the slot's
+// own, in a variant with modifiers. A food with variants comes in its first one unless told
+// otherwise. Slots left without a pick get their own food.
+type ComboPick struct {
+	SlotID      uint   `json:"slot_id"`
+	FoodID      uint   `json:"food_id"`
+	VariantID   uint   `json:"variant_id"`
+	ModifierIDs []uint `json:"modifier_ids"`
+}
+
+// FoodSales is what a food sold over a period, on its own and as part of combos
+type FoodSales struct {
+	FoodID        uint    `json:"food_id"`
+	Name          string  `json:"name"`
+	Quantity      uint    `json:"quantity"`
+	Revenue       float64 `json:"revenue"`
+	ComboQuantity uint    `json:"combo_quantity"` // how much of Quantity was sold in combos
+	ComboRevenue  float64 `json:"combo_revenue"`  // the share of the combos' price attributed to it
+}
+
+// SlotQuantity is how many of the slot's food one combo comes with
+func (s ComboSlot) SlotQuantity() uint {
+	return max(s.Quantity, 1)
+}
+
+// listPrice is what a food costs on its own, in its first variant when it has variants
+func listPrice(food Food) float64 {
+	if len(food.Variants) > 0 {
+		return food.Variants[0].Price
+	}
+	return food.Price
+}
+
+// Expand fills the slots of one combo with the guests' picks, giving a component order item
+// per slot. The bundle price is shared between the slots by what their own foods cost on
+// their own, to the cent, and each component adds what its pick costs over that: the choice's
+// price delta, a dearer variant and the modifiers. Components sum to the combo's unit price,
+// which is returned with them. foods holds every food the combo may be filled with.
+func (c Combo) Expand(foods map[uint]Food, picks []ComboPick) ([]OrderItem, float64, error) {
+	bySlot := map[uint]ComboPick{}
+	for _, pick := range picks {
+		if !slices.ContainsFunc(c.Slots, func(slot ComboSlot) bool { return slot.ID == pick.SlotID }) {
+			return nil, 0, fmt.Errorf("slot %d is not a slot of %s", pick.SlotID, c.Name)
+		}
+		if _, ok := bySlot[pick.SlotID]; ok {
+			return nil, 0, fmt.Errorf("slot %d is picked more than once", pick.SlotID)
+		}
+		bySlot[pick.SlotID] = pick
+	}
+
+	// The share of each slot, by the list price of its own food
+	weights := make([]float64, len(c.Slots))
+	var total float64
+	for i, slot := range c.Slots {
+		weights[i] = listPrice(foods[slot.FoodID]) * float64(slot.SlotQuantity())
+		total += weights[i]
+	}
+	price := int64(math.Round(c.Price * 100))
+	shares := make([]int64, len(c.Slots))
+	left := price
+	for i := range c.Slots {
+		switch {
+		case i == len(c.Slots)-1:
+			shares[i] = left
+		case total > 0:
+			shares[i] = int64(math.Floor(float64(price) * weights[i] / total))
+		default:
+			shares[i] = price / int64(len(c.Slots))
+		}
+		left -= shares[i]
+	}
+
+	components := make([]OrderItem, len(c.Slots))
+	var unitPrice int64
+	for i, slot := range c.Slots {
+		pick := bySlot[slot.ID]
+		foodID, delta := slot.FoodID, 0.0
+		if pick.FoodID != 0 && pick.FoodID != slot.FoodID {
+			j := slices.IndexFunc(slot.Choices, func(choice ComboChoice) bool { return choice.FoodID == pick.FoodID })
+			if j < 0 {
+				return nil, 0, fmt.Errorf("food %d is not a choice for the %s of %s", pick.FoodID, slot.Name, c.Name)
+			}
+			foodID, delta = pick.FoodID, slot.Choices[j].PriceDelta
+		}
+		food, ok := foods[foodID]
+		if !ok {
+			return nil, 0, fmt.Errorf("food %d of %s no longer exists", foodID, c.Name)
+		}
+
+		variantID := pick.VariantID
+		if variantID == 0 && len(food.Variants) > 0 {
+			variantID = food.Variants[0].ID
+		}
+		selection, err := food.Select(variantID, pick.ModifierIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// What the pick costs over the slot's own food, for every unit the slot comes with
+		extra := (delta + selection.UnitPrice - listPrice(food)) * float64(slot.SlotQuantity())
+		revenue := shares[i] + int64(math.Round(extra*100))
+		if revenue < 0 {
+			return nil, 0, fmt.Errorf("the %s takes the price of %s below zero", food.Name, c.Name)
+		}
+		unitPrice += revenue
+
+		components[i] = OrderItem{
+			FoodID:      foodID,
+			SlotName:    slot.Name,
+			VariantID:   selection.VariantID,
+			VariantName: selection.VariantName,
+			Quantity:    slot.SlotQuantity(),
+			UnitPrice:   math.Round(float64(revenue)/float64(slot.SlotQuantity())) / 100,
+			SubTotal:    float64(revenue) / 100,
+			Modifiers:   selection.Modifiers,
+		}
+	}
+	return components, float64(unitPrice) / 100, nil
+}
+
+// ScaleComponents turns the components of one combo into those of quantity combos
+func ScaleComponents(components []OrderItem, quantity uint) []OrderItem {
+	scaled := slices.Clone(components)
+	for i := range scaled {
+		scaled[i].Quantity *= quantity
+		scaled[i].SubTotal = math.Round(scaled[i].SubTotal*float64(quantity)*100) / 100
+	}
+	return scaled
+}
+
+// ComboUnavailableReason says why a combo can't be ordered at a time given in the restaurant's
+// time zone, "" when it can: it is marked unavailable, or no food that may fill one of its slots
+// can be ordered. foods and menus are the restaurant's.
+func ComboUnavailableReason(combo Combo, foods map[uint]Food, menus []Menu, at time.Time) string {
+	if !combo.Available {
+		return combo.Name + " is not available"
+	}
+	for _, slot := range combo.Slots {
+		reason := FoodUnavailableReason(foods[slot.FoodID], menus, at)
+		for _, choice := range slot.Choices {
+			if reason != "" && FoodUnavailableReason(foods[choice.FoodID], menus, at) == "" {
+				reason = ""
+			}
+		}
+		if reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package models
+
+import "testing"
+
+func TestComboExpand(t *testing.T) {
+	foods := map[uint]Food{
+		1: {ID: 1, Name: "Burger", Price: 8, ModifierGroups: []ModifierGroup{{ID: 1, Name: "Extras", Options: []Modifier{{ID: 1, Name: "Cheese", PriceDelta: 1}}}}},
+		2: {ID: 2, Name: "Fries", Price: 3},
+		3: {ID: 3, Name: "Cola", Variants: []FoodVariant{{ID: 1, Name: "Small", Price: 2}, {ID: 2, Name: "Large", Price: 2.5}}},
+		4: {ID: 4, Name: "Salad", Price: 4},
+		5: {ID: 5, Name: "Water", Price: 1},
+	}
+	combo := func(price float64) Combo {
+		return Combo{Name: "Burger meal", Price: price, Slots: []ComboSlot{
+			{ID: 1, Name: "Main", FoodID: 1},
+			{ID: 2, Name: "Side", FoodID: 2, Quantity: 2, Choices: []ComboChoice{{FoodID: 4, PriceDelta: 1}}},
+			{ID: 3, Name: "Drink", FoodID: 3, Choices: []ComboChoice{{FoodID: 5, PriceDelta: -3}}},
+		}}
+	}
+
+	tests := []struct {
+		name      string
+		combo     Combo
+		picks     []ComboPick
+		unitPrice float64
+		subTotals []float64
+		fails     bool
+	}{
+		{"the bundle price is shared by list price", combo(10), nil, 10, []float64{5, 3.75, 1.25}, false},
+		{"cents that don't divide evenly go to the last slot", combo(9.99), nil, 9.99, []float64{4.99, 3.74, 1.26}, false},
+		{"a choice's delta counts for every unit of the slot", combo(10), []ComboPick{{SlotID: 2, FoodID: 4}}, 12, []float64{5, 5.75, 1.25}, false},
+		{
+			"a dearer variant and modifiers add to their slot",
+			combo(10),
+			[]ComboPick{{SlotID: 1, ModifierIDs: []uint{1}}, {SlotID: 3, VariantID: 2}},
+			11.5, []float64{6, 3.75, 1.75}, false,
+		},
+		{
+			"every change adds up",
+			combo(9.99),
+			[]ComboPick{{SlotID: 1, ModifierIDs: []uint{1}}, {SlotID: 2, FoodID: 4}, {SlotID: 3, VariantID: 2}},
+			13.49, []float64{5.99, 5.74, 1.76}, false,
+		},
+		{"a choice can't take a slot below zero", combo(10), []ComboPick{{SlotID: 3, FoodID: 5}}, 0, nil, true},
+		{"the food must be a choice of the slot", combo(10), []ComboPick{{SlotID: 1, FoodID: 4}}, 0, nil, true},
+		{"the slot must be the combo's", combo(10), []ComboPick{{SlotID: 9}}, 0, nil, true},
+		{"a slot is picked once", combo(10), []ComboPick{{SlotID: 1}, {SlotID: 1}}, 0, nil, true},
+	}
+	for _, test := range tests {
+		components, unitPrice, err := test.combo.Expand(foods, test.picks)
+		if test.fails {
+			if err == nil {
+				t.Errorf("%s: the combo was filled at %v", test.name, unitPrice)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if unitPrice != test.unitPrice {
+			t.Errorf("%s: priced at %v, want %v", test.name, unitPrice, test.unitPrice)
+		}
+
+		var sum int64
+		for i, component := range components {
+			sum += int64(component.SubTotal*100 + 0.5)
+			if component.SubTotal != test.subTotals[i] {
+				t.Errorf("%s: the %s comes to %v, want %v", test.name, component.SlotName, component.SubTotal, test.subTotals[i])
+			}
+		}
+		if want := int64(unitPrice*100 + 0.5); sum != want {
+			t.Errorf("%s: the components add up to %d cents, want %d", test.name, sum, want)
+		}
+		if components[1].Quantity != 2 {
+			t.Errorf("%s: the side comes %d times, want 2", test.name, components[1].Quantity)
+		}
+	}
+}
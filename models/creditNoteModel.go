@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+// Reasons a credit note is issued or an item voided for
+const (
+	CreditReasonCustomerComplaint = "customer_complaint"
+	CreditReasonWrongItem         = "wrong_item"
+	CreditReasonQuality           = "quality"
+	CreditReasonOvercharge        = "overcharge"
+	CreditReasonGoodwill          = "goodwill"
+	CreditReasonOther             = "other"
+)
+
+// Refund statuses, refunds through a payment gateway are pending until the provider confirms them
+const (
+	RefundStatusPending   = "pending"
+	RefundStatusSucceeded = "succeeded"
+	RefundStatusFailed    = "failed"
+)
+
+// SequenceCreditNote is the kind of document credit notes are numbered as
+const SequenceCreditNote = "credit_note"
+
+// CreditNote corrects a paid invoice without changing it, crediting voided items or an
+// amount. Amount is net of tax and service charge like an invoice's, Total adds them up.
+// What it gives back is paid out through its refunds.
+type CreditNote struct {
+	ID            uint             `json:"id"`
+	Number        string           `json:"number"`
+	Period        int              `json:"period"`   // fiscal year of the sequence, 0 when the series never resets
+	Sequence      int              `json:"sequence"` // per restaurant and period, without gaps
+	RestaurantID  uint             `json:"restaurant_id"`
+	InvoiceID     uint             `json:"invoice_id"`
+	OrderID       uint             `json:"order_id"`
+	ReasonCode    string           `json:"reason_code"`
+	Notes         string           `json:"notes"`
+	Amount        float64          `json:"amount"`
+	Tax           float64          `json:"tax"`
+	ServiceCharge float64          `json:"service_charge"`
+	Total         float64          `json:"total"`
+	Items         []CreditNoteItem `json:"items"`
+	Refunds       []Refund         `json:"refunds"`
+	CreatedBy     *uint            `json:"created_by"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// CreditNoteItem is a quantity of an order item voided by a credit note. Amount is what
+// the voided units cost after discounts, before exclusive taxes and service charges.
+// OrderItemID is 0 once the order item has been deleted.
+type CreditNoteItem struct {
+	OrderItemID uint    `json:"order_item_id"`
+	FoodName    string  `json:"food_name"`
+	Quantity    uint    `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+	ReasonCode  string  `json:"reason_code"`
+}
+
+// Refund is money a credit note gives back against one payment, through the payment
+// gateway for payments taken online
+type Refund struct {
+	ID               uint      `json:"id"`
+	CreditNoteID     uint      `json:"credit_note_id"`
+	PaymentID        uint      `json:"payment_id"`
+	Amount           float64   `json:"amount"`
+	Method           string    `json:"method"`
+	Provider         string    `json:"provider,omitempty"`
+	ProviderRefundID string    `json:"provider_refund_id,omitempty"`
+	Status           string    `json:"status"`
+	FailureReason    string    `json:"failure_reason,omitempty"`
+	CreatedBy        *uint     `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// IssueCreditNote asks for a credit note on a paid invoice, voiding Items or crediting an
+// Amount; with neither, whatever is left of the invoice is credited. Refunds says which
+// payments give the money back, the latest payments do unless it is given.
+type IssueCreditNote struct {
+	ReasonCode string          `json:"reason_code" validate:"required,oneof=customer_complaint wrong_item quality overcharge goodwill other"`
+	Notes      string          `json:"notes" validate:"max=500"`
+	Items      []VoidItem      `json:"items" validate:"omitempty,dive"`
+	Amount     float64         `json:"amount" validate:"gte=0"`
+	Refunds    []RefundPayment `json:"refunds" validate:"omitempty,dive"`
+}
+
+// VoidItem voids a quantity of an order item, for the credit note's reason unless it has its own
+type VoidItem struct {
+	OrderItemID uint   `json:"order_item_id" validate:"required"`
+	Quantity    uint   `json:"quantity" validate:"required,min=1"`
+	ReasonCode  string `json:"reason_code" validate:"omitempty,oneof=customer_complaint wrong_item quality overcharge goodwill other"`
+}
+
+// RefundPayment gives back an amount of one payment
+type RefundPayment struct {
+	PaymentID uint    `json:"payment_id" validate:"required"`
+	Amount    float64 `json:"amount" validate:"required,gt=0"`
+}
@@ -12,12 +12,22 @@ type Food struct {
 	ImageURL     string    `json:"image_url" validate:"max=255"`
 	MenuID       uint      `json:"menu_id" validate:"required"`
 	RestaurantID uint      `json:"restaurant_id" validate:"required"`
+	StationID    uint      `json:"station_id"` // 0 falls back to the menu's station
+	TaxCategory  string    `json:"tax_category" validate:"omitempty,oneof=food alcohol takeaway"`
 	Ingredients  string    `json:"ingredients"`
 	PrepTime     int       `json:"prep_time"` // Preparation time in minutes
 	Calories     int       `json:"calories" validate:"min=0"`
 	SpicyLevel   int       `json:"spicy_level" validate:"min=0,max=5"`
-	Vegetarian   bool      `json:"vegetarian"`
+	Vegetarian   bool      `json:"vegetarian"` // kept in step with the vegetarian dietary tag
+	Allergens    []string  `json:"allergens" validate:"max=14,dive,oneof=celery gluten crustaceans eggs fish lupin milk molluscs mustard nuts peanuts sesame soya sulphites"`
+	DietaryTags  []string  `json:"dietary_tags" validate:"max=5,dive,oneof=vegetarian vegan gluten_free halal jain"`
 	Available    bool      `json:"available"`
+	Availability Schedule  `json:"availability" validate:"max=20,dive"` // when it can be ordered, always when empty
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Variants are the food's sizes or versions, each with its own price, and ModifierGroups
+	// the choices made when ordering it. Options keep their IDs when the food is updated.
+	Variants       []FoodVariant   `json:"variants" validate:"max=20,dive"`
+	ModifierGroups []ModifierGroup `json:"modifier_groups" validate:"max=20,dive"`
 }
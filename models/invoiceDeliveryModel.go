@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Delivery statuses, a delivery is pending while it is being tried
+const (
+	DeliveryStatusPending = "pending"
+	DeliveryStatusSent    = "sent"
+	DeliveryStatusFailed  = "failed"
+)
+
+// InvoiceDelivery is one send of an invoice's PDF to a customer by email. Attempts counts
+// how often the mail server was tried and LastError keeps why the last try failed.
+type InvoiceDelivery struct {
+	ID           uint       `json:"id"`
+	InvoiceID    uint       `json:"invoice_id"`
+	RestaurantID uint       `json:"restaurant_id"`
+	Recipient    string     `json:"recipient"`
+	Status       string     `json:"status"`
+	Attempts     int        `json:"attempts"`
+	LastError    string     `json:"last_error,omitempty"`
+	SentBy       *uint      `json:"sent_by"`
+	SentAt       *time.Time `json:"sent_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// SendInvoice asks for an invoice to be emailed, with an optional note for the customer
+type SendInvoice struct {
+	Email   string `json:"email" validate:"required,email,max=100"`
+	Message string `json:"message" validate:"max=1000"`
+}
@@ -4,15 +4,73 @@ import (
 	"time"
 )
 
+// Invoice statuses, an invoice is paid once its balance reaches zero
+const (
+	InvoiceStatusPending = "pending"
+	InvoiceStatusPaid    = "paid"
+)
+
+// Ways a check can be split into parts
+const (
+	SplitByItems = "items"
+	SplitEvenly  = "even"
+)
+
+// Invoice is what an order is charged. An order has one invoice, which may be split into
+// parts paid separately; parts point at the order's invoice through ParentID. The total
+// includes the service charge but not tips, which are paid on top of it. The order's
+// invoice takes the next number of the restaurant's invoice series when the order is paid,
+// until then it is a pro forma without one.
 type Invoice struct {
+	ID             uint                   `json:"id"`
+	Number         string                 `json:"number,omitempty"`
+	IssuedAt       *time.Time             `json:"issued_at,omitempty"`
+	OrderID        uint                   `json:"order_id" validate:"required"`
+	ParentID       uint                   `json:"parent_id,omitempty"`
+	SplitMode      string                 `json:"split_mode,omitempty"`
+	Amount         float64                `json:"amount" validate:"required"` // net of discounts and tax
+	Discount       float64                `json:"discount"`
+	Tax            float64                `json:"tax"`
+	ServiceCharge  float64                `json:"service_charge"`
+	Total          float64                `json:"total"`
+	AmountPaid     float64                `json:"amount_paid"`
+	Balance        float64                `json:"balance"`
+	Tip            float64                `json:"tip"`
+	Credited       float64                `json:"credited"` // given back by credit notes, the rest of the invoice is left as it was
+	Taxes          []InvoiceTax           `json:"taxes"`
+	ServiceCharges []InvoiceServiceCharge `json:"service_charges"`
+	OrderItemIDs   []uint                 `json:"order_item_ids,omitempty"` // items a part split by items covers
+	Splits         []Invoice              `json:"splits,omitempty"`
+	Payments       []Payment              `json:"payments,omitempty"`
+	Status         string                 `json:"status" validate:"required,oneof=pending paid"`
+	PaymentMethod  string                 `json:"payment_method" validate:"required,oneof=cash credit_card debit_card online"` // of the latest payment
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+	RestaurantID   uint                   `json:"restaurant_id" validate:"required"`
+}
+
+// Payment is money taken against an invoice or one of its parts. Amount goes towards the
+// balance, Tip is paid on top of it and credited to TipStaffID, the staff member taking
+// the payment unless another is named. Payments taken through a payment gateway carry
+// the provider and its transaction ID. Refunded is what refunds that have not failed gave back of the amount.
+type Payment struct {
 	ID            uint      `json:"id"`
-	OrderID       uint      `json:"order_id" validate:"required"`
-	Amount        float64   `json:"amount" validate:"required"`
-	Tax           float64   `json:"tax"`
-	Total         float64   `json:"total"`
-	Status        string    `json:"status" validate:"required,oneof=pending paid"`
-	PaymentMethod string    `json:"payment_method" validate:"required,oneof=cash credit_card debit_card online"`
+	InvoiceID     uint      `json:"invoice_id"`
+	Amount        float64   `json:"amount" validate:"required,gt=0"`
+	Tip           float64   `json:"tip" validate:"gte=0"`
+	TipStaffID    *uint     `json:"tip_staff_id"`
+	Refunded      float64   `json:"refunded"`
+	Method        string    `json:"method" validate:"required,oneof=cash credit_card debit_card online"`
+	Reference     string    `json:"reference" validate:"max=100"`
+	Provider      string    `json:"provider,omitempty"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	RecordedBy    *uint     `json:"recorded_by"`
 	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	RestaurantID  uint      `json:"restaurant_id" validate:"required"`
+}
+
+// SplitInvoice asks for an invoice to be split, evenly into Parts or by groups of order items
+type SplitInvoice struct {
+	Mode   string   `json:"mode" validate:"required,oneof=items even"`
+	Parts  int      `json:"parts" validate:"omitempty,min=2,max=50"`
+	Groups [][]uint `json:"groups" validate:"omitempty,min=2,dive,min=1"`
 }
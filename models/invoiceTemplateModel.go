@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Paper sizes documents are printed on
+const (
+	PaperA4      = "a4"
+	PaperReceipt = "receipt_80mm"
+)
+
+// InvoiceTemplate is how a restaurant's invoices and credit notes are printed. The logo is
+// the restaurant's own, an http(s) URL or data URI of a PNG or JPEG image. AccentColor
+// (#rrggbb) colours the headings. With PaymentQR set, invoices with a balance carry a QR
+// code of PaymentURL, in which {invoice_id}, {order_id}, {number} and {amount} are replaced
+// by the invoice's. Thermal receipts are pushed to the printer at ReceiptPrinter (host:port),
+// which also prints the kitchen tickets of stations without a printer of their own.
+type InvoiceTemplate struct {
+	RestaurantID   uint      `json:"restaurant_id" validate:"required"`
+	ShowLogo       bool      `json:"show_logo"`
+	ShowAddress    bool      `json:"show_address"`
+	TaxIDs         []TaxID   `json:"tax_ids" validate:"max=5,dive"`
+	FooterText     string    `json:"footer_text" validate:"max=500"`
+	AccentColor    string    `json:"accent_color" validate:"omitempty,hexcolor"`
+	PaperSize      string    `json:"paper_size" validate:"required,oneof=a4 receipt_80mm"`
+	PaymentQR      bool      `json:"payment_qr"`
+	PaymentURL     string    `json:"payment_url" validate:"required_if=PaymentQR true,omitempty,url,max=300"`
+	ReceiptPrinter string    `json:"receipt_printer" validate:"omitempty,hostname_port,max=255"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TaxID is a registration number printed under the restaurant's address, such as a VAT number
+type TaxID struct {
+	Label string `json:"label" validate:"required,max=30"`
+	Value string `json:"value" validate:"required,max=50"`
+}
+
+// DefaultInvoiceTemplate is how a restaurant's documents are printed until it sets a template up
+func DefaultInvoiceTemplate(restaurantID uint) InvoiceTemplate {
+	return InvoiceTemplate{RestaurantID: restaurantID, ShowLogo: true, ShowAddress: true, PaperSize: PaperA4}
+}
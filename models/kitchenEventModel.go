@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Kitchen feed event types
+const (
+	KitchenEventOrderCreated  = "order_created"
+	KitchenEventItemAdded     = "item_added"
+	KitchenEventItemBumped    = "item_bumped"
+	KitchenEventStatusChanged = "status_changed"
+)
+
+// KitchenEvent is one change pushed to the kitchen feed of a restaurant.
+// ID is assigned by the feed when the event is published and is what clients resume from.
+type KitchenEvent struct {
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	RestaurantID uint       `json:"restaurant_id"`
+	OrderID      uint       `json:"order_id"`
+	Status       string     `json:"status,omitempty"`
+	FromStatus   string     `json:"from_status,omitempty"`
+	OrderItem    *OrderItem `json:"order_item,omitempty"`
+	OccurredAt   time.Time  `json:"occurred_at"`
+}
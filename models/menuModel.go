@@ -4,10 +4,48 @@ import (
 	"time"
 )
 
+// Menu is one of a restaurant's menu categories, such as "Sides" or "Chef's Specials", that
+// foods are listed under. Categories nest under ParentID, 0 for a top-level one, and are
+// shown by Position, lowest first. Children is only filled in for menu trees.
 type Menu struct {
 	ID           uint      `json:"id"`
-	Name         string    `json:"name" validate:"required,oneof=appetizer main_course dessert beverage"`
+	Name         string    `json:"name" validate:"required,max=100"`
+	Description  string    `json:"description" validate:"max=500"`
+	Image        string    `json:"image" validate:"omitempty,url,max=500"`
+	Position     int       `json:"position" validate:"gte=0"`
+	ParentID     uint      `json:"parent_id"`
 	RestaurantID uint      `json:"restaurant_id" validate:"required"`
+	StationID    uint      `json:"station_id"` // kitchen station its foods go to, 0 for none
+	Children     []Menu    `json:"children,omitempty"`
+	Availability Schedule  `json:"availability" validate:"max=20,dive"` // when its foods can be ordered, always when empty
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
+
+// MenuTree nests a restaurant's menus under their parents, keeping the order they come in.
+// Menus whose parent is not among them are shown at the top level.
+func MenuTree(menus []Menu) []Menu {
+	known := make(map[uint]bool, len(menus))
+	children := map[uint][]Menu{}
+	for _, menu := range menus {
+		known[menu.ID] = true
+	}
+	for _, menu := range menus {
+		parent := menu.ParentID
+		if !known[parent] {
+			parent = 0
+		}
+		children[parent] = append(children[parent], menu)
+	}
+
+	var build func(parentID uint) []Menu
+	build = func(parentID uint) []Menu {
+		nodes := children[parentID]
+		delete(children, parentID) // a cycle can't send the walk round forever
+		for i := range nodes {
+			nodes[i].Children = build(nodes[i].ID)
+		}
+		return nodes
+	}
+	return build(0)
+}
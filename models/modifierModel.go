@@ -0,0 +1,119 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// FoodVariant is a version of a food with its own price, such as a small or large latte
+type FoodVariant struct {
+	ID    uint    `json:"id"`
+	Name  string  `json:"name" validate:"required,max=50"`
+	Price float64 `json:"price" validate:"gte=0"`
+}
+
+// ModifierGroup is a choice made when ordering a food, like its milk or its extras. Guests
+// pick at least MinSelections of its options and at most MaxSelections, 0 for no limit.
+// A required group needs at least one.
+type ModifierGroup struct {
+	ID            uint       `json:"id"`
+	Name          string     `json:"name" validate:"required,max=50"`
+	Required      bool       `json:"required"`
+	MinSelections int        `json:"min_selections" validate:"gte=0"`
+	MaxSelections int        `json:"max_selections" validate:"gte=0"`
+	Options       []Modifier `json:"options" validate:"min=1,max=30,dive"`
+}
+
+// Modifier is one option of a modifier group, changing the food's price by PriceDelta
+type Modifier struct {
+	ID         uint    `json:"id"`
+	Name       string  `json:"name" validate:"required,max=50"`
+	PriceDelta float64 `json:"price_delta"`
+}
+
+// OrderItemModifier is a modifier picked for an order item, named and priced as it was when ordered
+type OrderItemModifier struct {
+	ModifierID uint    `json:"modifier_id"` // 0 once the modifier is removed from the food
+	Group      string  `json:"group"`
+	Name       string  `json:"name"`
+	PriceDelta float64 `json:"price_delta"`
+}
+
+// Selection is a food as ordered: in one of its variants, with modifiers, at a unit price
+type Selection struct {
+	VariantID   uint
+	VariantName string
+	Modifiers   []OrderItemModifier
+	UnitPrice   float64
+}
+
+// Minimum is how many options of the group must be picked
+func (g ModifierGroup) Minimum() int {
+	if g.Required && g.MinSelections == 0 {
+		return 1
+	}
+	return g.MinSelections
+}
+
+// Select prices the food in a variant with modifiers, making sure the variant is one of the
+// food's, as is every modifier, and each modifier group gets as many options as it takes. A
+// food with variants must be ordered in one of them and its price replaces the food's.
+func (f Food) Select(variantID uint, modifierIDs []uint) (Selection, error) {
+	selection := Selection{UnitPrice: f.Price}
+	if variantID != 0 || len(f.Variants) > 0 {
+		i := slices.IndexFunc(f.Variants, func(variant FoodVariant) bool { return variant.ID == variantID })
+		switch {
+		case len(f.Variants) == 0:
+			return selection, fmt.Errorf("%s comes in no variants", f.Name)
+		case variantID == 0:
+			return selection, fmt.Errorf("pick a variant of %s", f.Name)
+		case i < 0:
+			return selection, fmt.Errorf("variant %d is not a variant of %s", variantID, f.Name)
+		}
+		selection.VariantID, selection.VariantName, selection.UnitPrice = variantID, f.Variants[i].Name, f.Variants[i].Price
+	}
+
+	picked := map[uint]bool{}
+	for _, id := range modifierIDs {
+		if picked[id] {
+			return selection, fmt.Errorf("modifier %d is picked more than once", id)
+		}
+		picked[id] = true
+	}
+	for _, group := range f.ModifierGroups {
+		count := 0
+		for _, option := range group.Options {
+			if !picked[option.ID] {
+				continue
+			}
+			delete(picked, option.ID)
+			count++
+			selection.UnitPrice += option.PriceDelta
+			selection.Modifiers = append(selection.Modifiers, OrderItemModifier{ModifierID: option.ID, Group: group.Name, Name: option.Name, PriceDelta: option.PriceDelta})
+		}
+		if count < group.Minimum() {
+			return selection, fmt.Errorf("pick at least %d of %s", group.Minimum(), group.Name)
+		}
+		if group.MaxSelections > 0 && count > group.MaxSelections {
+			return selection, fmt.Errorf("pick at most %d of %s", group.MaxSelections, group.Name)
+		}
+	}
+	for id := range picked {
+		return selection, fmt.Errorf("modifier %d is not an option of %s", id, f.Name)
+	}
+
+	selection.UnitPrice = math.Round(selection.UnitPrice*100) / 100
+	if selection.UnitPrice < 0 {
+		return selection, fmt.Errorf("the modifiers take the price of %s below zero", f.Name)
+	}
+	return selection, nil
+}
+
+// DisplayName is the item's food with the variant it was ordered in, like "Latte (Large)"
+func (i OrderItem) DisplayName() string {
+	if i.VariantName == "" {
+		return i.FoodName
+	}
+	return i.FoodName + " (" + i.VariantName + ")"
+}
@@ -0,0 +1,62 @@
+package models
+
+import "testing"
+
+func TestFoodSelect(t *testing.T) {
+	latte := Food{
+		Name:     "Latte",
+		Price:    3,
+		Variants: []FoodVariant{{ID: 1, Name: "Small", Price: 3}, {ID: 2, Name: "Large", Price: 4.2}},
+		ModifierGroups: []ModifierGroup{
+			{ID: 1, Name: "Milk", Required: true, MaxSelections: 1, Options: []Modifier{{ID: 1, Name: "Whole"}, {ID: 2, Name: "Oat", PriceDelta: 0.6}}},
+			{ID: 2, Name: "Extras", MaxSelections: 2, Options: []Modifier{{ID: 3, Name: "Shot", PriceDelta: 0.8}, {ID: 4, Name: "Syrup", PriceDelta: 0.5}, {ID: 5, Name: "Cream", PriceDelta: 0.4}}},
+		},
+	}
+	soup := Food{
+		Name:  "Soup",
+		Price: 2,
+		ModifierGroups: []ModifierGroup{
+			{ID: 3, Name: "Toppings", MinSelections: 2, Options: []Modifier{{ID: 6, Name: "Croutons", PriceDelta: 0.3}, {ID: 7, Name: "Herbs"}, {ID: 8, Name: "Small bowl", PriceDelta: -2.5}}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		food      Food
+		variantID uint
+		modifiers []uint
+		unitPrice float64
+		fails     bool
+	}{
+		{"the variant's price replaces the food's", latte, 2, []uint{1}, 4.2, false},
+		{"modifier deltas go on top of the variant's price", latte, 2, []uint{2, 3, 4}, 6.1, false},
+		{"a food with variants needs one", latte, 0, []uint{1}, 0, true},
+		{"the variant must be the food's", latte, 9, []uint{1}, 0, true},
+		{"a food without variants takes none", soup, 1, []uint{6, 7}, 0, true},
+		{"a required group needs an option", latte, 1, []uint{3}, 0, true},
+		{"a group takes at most its maximum", latte, 1, []uint{1, 2}, 0, true},
+		{"extras up to the maximum are fine", latte, 1, []uint{1, 3, 5}, 4.2, false},
+		{"extras over the maximum are refused", latte, 1, []uint{1, 3, 4, 5}, 0, true},
+		{"a group takes at least its minimum", soup, 0, []uint{6}, 0, true},
+		{"the minimum is enough", soup, 0, []uint{6, 7}, 2.3, false},
+		{"a modifier can't be picked twice", soup, 0, []uint{6, 6, 7}, 0, true},
+		{"the modifier must be the food's", soup, 0, []uint{6, 7, 1}, 0, true},
+		{"modifiers can't take the price below zero", soup, 0, []uint{7, 8}, 0, true},
+	}
+	for _, test := range tests {
+		selection, err := test.food.Select(test.variantID, test.modifiers)
+		if test.fails {
+			if err == nil {
+				t.Errorf("%s: the selection was accepted at %v", test.name, selection.UnitPrice)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if selection.UnitPrice != test.unitPrice || len(selection.Modifiers) != len(test.modifiers) {
+			t.Errorf("%s: priced %v with %d modifiers, want %v with %d", test.name, selection.UnitPrice, len(selection.Modifiers), test.unitPrice, len(test.modifiers))
+		}
+	}
+}
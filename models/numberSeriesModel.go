@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// SequenceInvoice is the kind of document invoices are numbered as
+const SequenceInvoice = "invoice"
+
+// DocumentKinds are the kinds of documents numbered in series
+var DocumentKinds = []string{SequenceInvoice, SequenceCreditNote}
+
+// When a number series starts again at 1
+const (
+	SeriesResetNever  = "never"
+	SeriesResetYearly = "yearly"
+)
+
+// NumberSeries is how a restaurant numbers one kind of document. Numbers are the prefix
+// followed by the sequence padded with zeros to Padding digits, such as INV-000042. Series
+// reset yearly start again every fiscal year, which starts in the month FiscalYearStart,
+// and carry the year the fiscal year starts in, such as INV-2025-000042. A number once
+// issued never changes, whatever the series is changed to later.
+type NumberSeries struct {
+	RestaurantID    uint      `json:"restaurant_id" validate:"required"`
+	Kind            string    `json:"kind" validate:"required,oneof=invoice credit_note"`
+	Prefix          string    `json:"prefix" validate:"max=20"`
+	Padding         int       `json:"padding" validate:"min=1,max=12"`
+	Reset           string    `json:"reset" validate:"required,oneof=never yearly"`
+	FiscalYearStart int       `json:"fiscal_year_start" validate:"min=1,max=12"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// DefaultNumberSeries is how a restaurant's documents are numbered until it sets a series up
+func DefaultNumberSeries(restaurantID uint, kind string) NumberSeries {
+	prefix := "INV-"
+	if kind == SequenceCreditNote {
+		prefix = "CN-"
+	}
+	return NumberSeries{RestaurantID: restaurantID, Kind: kind, Prefix: prefix, Padding: 6, Reset: SeriesResetNever, FiscalYearStart: 1}
+}
+
+// Period is the counter a document issued at the given time takes its number from, the
+// year its fiscal year starts in for yearly series and 0 for series that never reset
+func (s NumberSeries) Period(at time.Time) int {
+	if s.Reset != SeriesResetYearly {
+		return 0
+	}
+	if int(at.Month()) < s.FiscalYearStart {
+		return at.Year() - 1
+	}
+	return at.Year()
+}
+
+// Format is the number of the document taking the given sequence of a period
+func (s NumberSeries) Format(period, sequence int) string {
+	if s.Reset == SeriesResetYearly {
+		return fmt.Sprintf("%s%d-%0*d", s.Prefix, period, s.Padding, sequence)
+	}
+	return fmt.Sprintf("%s%0*d", s.Prefix, s.Padding, sequence)
+}
@@ -2,16 +2,41 @@ package models
 
 import "time"
 
+// Preparation statuses of an order item, items are queued until a station bumps them
+const (
+	PrepStatusQueued = "queued"
+	PrepStatusDone   = "done"
+)
+
 type OrderItem struct {
-	ID        uint      `json:"id"`
-	OrderID   uint      `json:"order_id" validate:"required"`
-	FoodID    uint      `json:"food_id" validate:"required"`
-	FoodName  string    `json:"food_name"`
-	Quantity  uint      `json:"quantity"`
-	UnitPrice float64   `json:"unit_price"`
-	SubTotal  float64   `json:"subtotal"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uint       `json:"id"`
+	OrderID     uint       `json:"order_id" validate:"required"`
+	FoodID      uint       `json:"food_id" validate:"required_without=ComboID"`
+	ComboID     uint       `json:"combo_id"`
+	ParentID    uint       `json:"parent_id"` // the combo item a component belongs to
+	SlotName    string     `json:"slot_name"` // the combo slot a component fills
+	FoodName    string     `json:"food_name"`
+	VariantID   uint       `json:"variant_id"`
+	VariantName string     `json:"variant_name"`
+	MenuID      uint       `json:"menu_id"`
+	TaxCategory string     `json:"tax_category"`
+	Allergens   []string   `json:"allergens"` // of its food, of all their foods for combos
+	Quantity    uint       `json:"quantity"`
+	UnitPrice   float64    `json:"unit_price"`
+	SubTotal    float64    `json:"subtotal"`
+	PrepStatus  string     `json:"prep_status"`
+	BumpedAt    *time.Time `json:"bumped_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// ModifierIDs picks the food's modifiers when the item is ordered, Modifiers are those it was ordered with
+	ModifierIDs []uint              `json:"modifier_ids,omitempty"`
+	Modifiers   []OrderItemModifier `json:"modifiers"`
+
+	// ComboPicks fill the slots of a combo when it is ordered, Components are the items it
+	// expands into for the kitchen, each carrying its share of the combo's subtotal
+	ComboPicks []ComboPick `json:"combo_picks,omitempty"`
+	Components []OrderItem `json:"components,omitempty"`
 }
 
 type UpdateOrderItem struct {
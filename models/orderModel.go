@@ -4,19 +4,47 @@ import (
 	"time"
 )
 
+// Order lifecycle statuses, see helpers.CanTransitionOrder for the allowed moves between them
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPreparing = "preparing"
+	OrderStatusReady     = "ready"
+	OrderStatusServed    = "served"
+	OrderStatusPaid      = "paid"
+	OrderStatusCancelled = "cancelled"
+)
+
 type Order struct {
-	ID           uint        `json:"id"`
-	TableID      uint        `json:"table_id" validate:"required"`
-	RestaurantID uint        `json:"restaurant_id" validate:"required"`
-	OrderDate    time.Time   `json:"order_date" validate:"required"`
-	TotalPrice   float64     `json:"total_price"`
-	Status       string      `json:"status" validate:"required,oneof=pending preparing ready served paid cancelled"`
-	Notes        string      `json:"notes"`
-	OrderItems   []OrderItem `json:"order_items"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
+	ID            uint            `json:"id"`
+	TableID       uint            `json:"table_id" validate:"required"`
+	RestaurantID  uint            `json:"restaurant_id" validate:"required"`
+	OrderDate     time.Time       `json:"order_date" validate:"required"`
+	TotalPrice    float64         `json:"total_price"`
+	DiscountTotal float64         `json:"discount_total"`
+	GuestCount    int             `json:"guest_count" validate:"omitempty,min=1,max=1000"` // the table's capacity is used while unknown
+	Status        string          `json:"status" validate:"required,oneof=pending preparing ready served paid cancelled"`
+	Notes         string          `json:"notes"`
+	Allergens     []string        `json:"allergens"` // declared by the guests, see AllergenWarnings
+	OrderItems    []OrderItem     `json:"order_items"`
+	Discounts     []OrderDiscount `json:"discounts,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+
+	// AllergenWarnings flag the items containing any of the allergens the guests declared
+	AllergenWarnings []AllergenWarning `json:"allergen_warnings,omitempty"`
 }
 
 type OrderStatus struct {
 	Status string `json:"status" validate:"required,oneof=pending preparing ready served paid cancelled"`
 }
+
+// OrderStatusChange is one recorded transition of an order's status.
+// ChangedBy is nil when the change came through the public customer routes.
+type OrderStatusChange struct {
+	ID         uint      `json:"id"`
+	OrderID    uint      `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ChangedBy  *uint     `json:"changed_by"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
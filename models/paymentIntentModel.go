@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// PaymentIntent is an online payment in progress with the payment gateway. Status follows
+// the provider's intent, PaymentID is set once the money has been recorded as a payment.
+type PaymentIntent struct {
+	ID               uint      `json:"id"`
+	InvoiceID        uint      `json:"invoice_id"`
+	Provider         string    `json:"provider"`
+	ProviderIntentID string    `json:"provider_intent_id"`
+	Amount           float64   `json:"amount" validate:"omitempty,gt=0"` // the invoice's balance unless given
+	Tip              float64   `json:"tip" validate:"gte=0"`             // charged on top of the amount
+	TipStaffID       *uint     `json:"tip_staff_id"`
+	Currency         string    `json:"currency"`
+	Status           string    `json:"status"`
+	FailureReason    string    `json:"failure_reason,omitempty"`
+	ClientSecret     string    `json:"client_secret,omitempty"` // only returned when the intent is created
+	PaymentID        uint      `json:"payment_id,omitempty"`
+	CreatedBy        *uint     `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// WebhookEvent is a payment gateway notification that has been handled
+type WebhookEvent struct {
+	Provider   string    `json:"provider"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	ReceivedAt time.Time `json:"received_at"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Discount kinds
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+	DiscountBuyXGetY   = "buy_x_get_y"
+)
+
+// Discount scopes, which items of an order a discount applies to
+const (
+	DiscountScopeOrder = "order"
+	DiscountScopeMenu  = "menu"
+	DiscountScopeFood  = "food"
+)
+
+// Order discount statuses. Manual discounts wait for a manager, promotions are approved when applied.
+const (
+	DiscountStatusPending  = "pending"
+	DiscountStatusApproved = "approved"
+)
+
+// Promotion is a discount rule a restaurant offers. Value is a percentage for percentage
+// promotions and an amount for fixed ones. Promotions with a Code are coupons.
+type Promotion struct {
+	ID           uint       `json:"id"`
+	RestaurantID uint       `json:"restaurant_id" validate:"required"`
+	Name         string     `json:"name" validate:"required,max=100"`
+	Code         string     `json:"code" validate:"omitempty,max=40"`
+	Kind         string     `json:"kind" validate:"required,oneof=percentage fixed buy_x_get_y"`
+	Value        float64    `json:"value" validate:"gte=0"`
+	BuyQuantity  int        `json:"buy_quantity" validate:"gte=0"`
+	GetQuantity  int        `json:"get_quantity" validate:"gte=0"`
+	Scope        string     `json:"scope" validate:"omitempty,oneof=order menu food"`
+	MenuID       uint       `json:"menu_id"`
+	FoodID       uint       `json:"food_id"`
+	UsageLimit   int        `json:"usage_limit" validate:"gte=0"` // 0 for no limit
+	TimesUsed    int        `json:"times_used"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	Disabled     bool       `json:"disabled"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// OrderDiscount is a discount applied to an order, with the rule copied from its promotion
+// or entered by hand. Amount is what it takes off the order's current items.
+type OrderDiscount struct {
+	ID          uint      `json:"id"`
+	OrderID     uint      `json:"order_id"`
+	PromotionID uint      `json:"promotion_id"` // 0 for manual discounts
+	Description string    `json:"description"`
+	Kind        string    `json:"kind"`
+	Value       float64   `json:"value"`
+	BuyQuantity int       `json:"buy_quantity"`
+	GetQuantity int       `json:"get_quantity"`
+	Scope       string    `json:"scope"`
+	MenuID      uint      `json:"menu_id"`
+	FoodID      uint      `json:"food_id"`
+	Amount      float64   `json:"amount"`
+	Status      string    `json:"status"`
+	RequestedBy *uint     `json:"requested_by"`
+	ApprovedBy  *uint     `json:"approved_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ApplyDiscount applies a coupon by Code, a promotion by PromotionID, or a manual
+// percentage or fixed discount on the whole order with a Reason
+type ApplyDiscount struct {
+	Code        string  `json:"code"`
+	PromotionID uint    `json:"promotion_id"`
+	Kind        string  `json:"kind" validate:"omitempty,oneof=percentage fixed"`
+	Value       float64 `json:"value" validate:"gte=0"`
+	Reason      string  `json:"reason" validate:"max=200"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Reservation lifecycle statuses, see helpers.CanTransitionReservation for the allowed moves
+const (
+	ReservationStatusBooked    = "booked"
+	ReservationStatusSeated    = "seated"
+	ReservationStatusCompleted = "completed"
+	ReservationStatusCancelled = "cancelled"
+	ReservationStatusNoShow    = "no_show"
+)
+
+// Reservation books a table for a party over a time slot. A zero TableID on
+// creation lets the API pick the smallest free table that seats the party.
+type Reservation struct {
+	ID              uint      `json:"id"`
+	RestaurantID    uint      `json:"restaurant_id" validate:"required"`
+	TableID         uint      `json:"table_id"`
+	GuestName       string    `json:"guest_name" validate:"required,max=100"`
+	GuestPhone      string    `json:"guest_phone" validate:"omitempty,max=20"`
+	GuestEmail      string    `json:"guest_email" validate:"omitempty,email,max=100"`
+	PartySize       int       `json:"party_size" validate:"required,min=1"`
+	StartsAt        time.Time `json:"starts_at" validate:"required"`
+	DurationMinutes int       `json:"duration_minutes" validate:"omitempty,min=15,max=720"`
+	Status          string    `json:"status"`
+	Notes           string    `json:"notes"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// EndsAt is when the reservation releases its table
+func (r Reservation) EndsAt() time.Time {
+	return r.StartsAt.Add(time.Duration(r.DurationMinutes) * time.Minute)
+}
+
+type ReservationStatus struct {
+	Status string `json:"status" validate:"required,oneof=booked seated completed cancelled no_show"`
+}
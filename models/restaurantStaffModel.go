@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Membership roles inside a restaurant, from most to least privileged.
+// Owners are derived from restaurants.owner_id, the rest from restaurant_staff.
+const (
+	MembershipOwner   = "owner"
+	MembershipManager = "manager"
+	MembershipStaff   = "staff"
+)
+
+// Membership ties a user to a restaurant with one of the roles above
+type Membership struct {
+	RestaurantID uint   `json:"restaurant_id"`
+	Role         string `json:"role"`
+}
+
+type RestaurantStaff struct {
+	ID           uint      `json:"id"`
+	RestaurantID uint      `json:"restaurant_id"`
+	UserID       uint      `json:"user_id" validate:"required"`
+	Role         string    `json:"role" validate:"required,oneof=manager staff"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -4,11 +4,21 @@ import "time"
 
 type Restaurant struct {
 	ID          uint      `json:"id"`
-	Name        string    `json:"name" validate="required,min=3"`
-	OwnerID     uint      `json:"owner_id" validate="required"`
+	Name        string    `json:"name" validate:"required,min=3"`
+	OwnerID     uint      `json:"owner_id" validate:"required"`
 	Logo        string    `json:"logo"`
-	Address     string    `json:"address" validate="required"`
+	Address     string    `json:"address" validate:"required"`
 	Description string    `json:"description"`
+	TimeZone    string    `json:"time_zone" validate:"omitempty,timezone"` // IANA name menus are scheduled in, UTC when empty
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+// Location is the time zone the restaurant's menus are scheduled in
+func (r Restaurant) Location() *time.Location {
+	location, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// ServiceCharge is a charge a restaurant adds to every bill of a party of at least
+// MinGuests, such as 10% for parties of 7 or more. Rate is a percentage of what the
+// items cost after discounts, before tax. A MinGuests of 0 charges every party.
+type ServiceCharge struct {
+	ID           uint      `json:"id"`
+	RestaurantID uint      `json:"restaurant_id" validate:"required"`
+	Name         string    `json:"name" validate:"required,max=50"`
+	Rate         float64   `json:"rate" validate:"gt=0,lt=100"`
+	MinGuests    int       `json:"min_guests" validate:"min=0,max=1000"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// InvoiceServiceCharge is one line of an invoice's service charge. BaseAmount is what
+// the rate was applied to.
+type InvoiceServiceCharge struct {
+	ServiceChargeID uint    `json:"service_charge_id"` // 0 once the charge has been deleted
+	Name            string  `json:"name"`
+	Rate            float64 `json:"rate"`
+	BaseAmount      float64 `json:"base_amount"`
+	Amount          float64 `json:"amount"`
+}
+
+// TipSummary is what one staff member was tipped over a period. StaffID is nil for tips
+// no staff member was credited with.
+type TipSummary struct {
+	StaffID  *uint   `json:"staff_id"`
+	Username string  `json:"username"`
+	Tips     float64 `json:"tips"`
+	Payments int     `json:"payments"`
+}
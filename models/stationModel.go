@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Station is a kitchen section such as the grill or the bar that order items are routed to.
+// Its kitchen tickets are pushed to the thermal printer at PrinterAddress (host:port), if any.
+type Station struct {
+	ID             uint      `json:"id"`
+	RestaurantID   uint      `json:"restaurant_id" validate:"required"`
+	Name           string    `json:"name" validate:"required,max=50"`
+	PrinterAddress string    `json:"printer_address" validate:"omitempty,hostname_port,max=255"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// StationTicket is one queued order item as a kitchen station sees it.
+// StationID is 0 for items whose food and menu are not routed to any station.
+type StationTicket struct {
+	OrderItemID uint      `json:"order_item_id"`
+	OrderID     uint      `json:"order_id"`
+	TableID     uint      `json:"table_id"`
+	StationID   uint      `json:"station_id"`
+	FoodID      uint      `json:"food_id"`
+	FoodName    string    `json:"food_name"`
+	VariantName string    `json:"variant_name"`
+	ComboName   string    `json:"combo_name"` // the combo the item is part of, if any
+	Modifiers   []string  `json:"modifiers"`  // names of the modifiers picked, in order
+	Allergens   []string  `json:"allergens"`  // the allergens the guests declared that the item contains
+	Quantity    uint      `json:"quantity"`
+	PrepTime    int       `json:"prep_time"`
+	PrepStatus  string    `json:"prep_status"`
+	OrderNotes  string    `json:"order_notes"`
+	OrderDate   time.Time `json:"order_date"`
+}
+
+// KitchenTicketPrint is how pushing one station's kitchen ticket for an order to its printer went
+type KitchenTicketPrint struct {
+	StationID uint   `json:"station_id"` // 0 for items not routed to any station
+	Station   string `json:"station"`
+	Printer   string `json:"printer"`
+	Items     int    `json:"items"`
+	Printed   bool   `json:"printed"`
+	Error     string `json:"error,omitempty"`
+}
@@ -4,6 +4,13 @@ import (
 	"time"
 )
 
+// Table statuses. Reservations flip tables to reserved shortly before their slot.
+const (
+	TableStatusAvailable = "available"
+	TableStatusOccupied  = "occupied"
+	TableStatusReserved  = "reserved"
+)
+
 type Table struct {
 	ID           uint      `json:"id"`
 	Name         string    `json:"name" validate:"required,max=100,min=1"`
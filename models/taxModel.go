@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Tax categories a food is taxed under
+const (
+	TaxCategoryFood     = "food"
+	TaxCategoryAlcohol  = "alcohol"
+	TaxCategoryTakeaway = "takeaway"
+)
+
+// TaxRate is one tax a restaurant charges on a category of foods, such as 5% GST on food.
+// Rate is a percentage. Inclusive rates are already part of the menu price, exclusive
+// rates are added on top of it.
+type TaxRate struct {
+	ID           uint      `json:"id"`
+	RestaurantID uint      `json:"restaurant_id" validate:"required"`
+	Name         string    `json:"name" validate:"required,max=50"`
+	Category     string    `json:"category" validate:"required,oneof=food alcohol takeaway"`
+	Rate         float64   `json:"rate" validate:"gt=0,lt=100"`
+	Inclusive    bool      `json:"inclusive"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// InvoiceTax is one line of an invoice's tax breakdown. TaxableAmount is the net amount
+// of the category the rate was applied to.
+type InvoiceTax struct {
+	TaxRateID     uint    `json:"tax_rate_id"` // 0 once the rate has been deleted
+	Name          string  `json:"name"`
+	Category      string  `json:"category"`
+	Rate          float64 `json:"rate"`
+	Inclusive     bool    `json:"inclusive"`
+	TaxableAmount float64 `json:"taxable_amount"`
+	TaxAmount     float64 `json:"tax_amount"`
+}
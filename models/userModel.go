@@ -4,6 +4,12 @@ import (
 	"time"
 )
 
+// Account roles. Admin accounts may open restaurants, User accounts work in them.
+const (
+	RoleAdmin = "Admin"
+	RoleUser  = "User"
+)
+
 type User struct {
 	ID        uint      `json:"id"`
 	Username  string    `json:"username" validate:"required,min=3,max=20"`
@@ -23,7 +29,7 @@ type UserWithOldPassword struct {
 	Password    string    `json:"password,omitempty" validate:"required,min=6,max=100"`
 	Email       string    `json:"email" validate:"required,email"`
 	Phone       string    `json:"phone" validate:"required,min=10,max=15"`
-	Role        string    `json:"role" validate:"required,oneof=Admin User"`
+	Role        string    `json:"role"` // read only, see UserRole
 	Token       string    `json:"token,omitempty"`
 	AvatarURL   string    `json:"avatar_url" validate:"omitempty,url"`
 	CreatedAt   time.Time `json:"created_at"`
@@ -31,6 +37,11 @@ type UserWithOldPassword struct {
 	OldPassword string    `json:"old_password,omitempty" validate:"required,min=6,max=100"`
 }
 
+// UserRole changes the role of an account, which only admins may do
+type UserRole struct {
+	Role string `json:"role" validate:"required,oneof=Admin User"`
+}
+
 type ConfirmPassword struct {
 	Password string `json:"password" validate:"required,min=6,max=100"`
 }
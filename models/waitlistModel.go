@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Waitlist statuses. Waiting and notified parties are still in the queue.
+const (
+	WaitlistStatusWaiting  = "waiting"
+	WaitlistStatusNotified = "notified"
+	WaitlistStatusSeated   = "seated"
+	WaitlistStatusLeft     = "left"
+)
+
+// WaitlistEntry is a walk-in party queued for a table. QuotedMinutes is the wait
+// estimated when the party joined; TableID and OrderID are set once it is seated.
+type WaitlistEntry struct {
+	ID            uint       `json:"id"`
+	RestaurantID  uint       `json:"restaurant_id" validate:"required"`
+	GuestName     string     `json:"guest_name" validate:"required,max=100"`
+	GuestPhone    string     `json:"guest_phone" validate:"required,min=6,max=20"`
+	PartySize     int        `json:"party_size" validate:"required,min=1"`
+	QuotedMinutes int        `json:"quoted_minutes"`
+	Status        string     `json:"status"`
+	TableID       uint       `json:"table_id"`
+	OrderID       uint       `json:"order_id"`
+	Notes         string     `json:"notes"`
+	NotifiedAt    *time.Time `json:"notified_at"`
+	SeatedAt      *time.Time `json:"seated_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// SeatParty is the table a waiting party is seated at
+type SeatParty struct {
+	TableID uint `json:"table_id" validate:"required"`
+}
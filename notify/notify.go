@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Message is a notification for one guest
+type Message struct {
+	To   string `json:"to"` // guest phone number
+	Body string `json:"body"`
+}
+
+// Notifier delivers guest notifications. Implementations must be safe for concurrent use.
+type Notifier interface {
+	Notify(ctx context.Context, message Message) error
+}
+
+// FromEnv builds the notifier named by NOTIFIER: "log" (the default) writes to the
+// application log and "file" appends to the file named by NOTIFIER_FILE.
+func FromEnv() (Notifier, error) {
+	switch kind := os.Getenv("NOTIFIER"); kind {
+	case "", "log":
+		return LogNotifier{}, nil
+	case "file":
+		path := os.Getenv("NOTIFIER_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("NOTIFIER_FILE is required for the file notifier")
+		}
+		return NewFileNotifier(path), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", kind)
+	}
+}
+
+// LogNotifier writes notifications to the application log instead of sending them
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, message Message) error {
+	log.Printf("Notify %s: %s", message.To, message.Body)
+	return nil
+}
+
+// FileNotifier appends every notification to a file as one JSON line, a stand-in for
+// a real provider in development and tests
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) Notify(ctx context.Context, message Message) error {
+	line, err := json.Marshal(struct {
+		Message
+		SentAt time.Time `json:"sent_at"`
+	}{message, time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
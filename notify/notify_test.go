@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileNotifierAppendsOneLinePerMessage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.jsonl")
+	notifier := NewFileNotifier(path)
+
+	messages := []Message{{To: "+15550100", Body: "Your table is ready"}, {To: "+15550101", Body: "Your table is ready too"}}
+	for _, message := range messages {
+		if err := notifier.Notify(context.Background(), message); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("opening the notifications: %v", err)
+	}
+	defer file.Close()
+
+	var sent []Message
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line struct {
+			Message
+			SentAt string `json:"sent_at"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("decoding %q: %v", scanner.Text(), err)
+		}
+		if line.SentAt == "" {
+			t.Errorf("%q has no sent_at", scanner.Text())
+		}
+		sent = append(sent, line.Message)
+	}
+	if len(sent) != len(messages) {
+		t.Fatalf("the file holds %d messages, want %d", len(sent), len(messages))
+	}
+	for i := range messages {
+		if sent[i] != messages[i] {
+			t.Errorf("message %d is %+v, want %+v", i, sent[i], messages[i])
+		}
+	}
+}
+
+func TestFileNotifierFailsWhenTheFileCantBeWritten(t *testing.T) {
+	notifier := NewFileNotifier(filepath.Join(t.TempDir(), "missing", "notifications.jsonl"))
+	if err := notifier.Notify(context.Background(), Message{To: "+15550100", Body: "Your table is ready"}); err == nil {
+		t.Fatal("Notify succeeded without a directory to write to")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("NOTIFIER", "")
+	if notifier, err := FromEnv(); err != nil || notifier != (LogNotifier{}) {
+		t.Errorf("the default notifier is %T, %v, want the log notifier", notifier, err)
+	}
+
+	t.Setenv("NOTIFIER", "file")
+	t.Setenv("NOTIFIER_FILE", "")
+	if _, err := FromEnv(); err == nil {
+		t.Error("the file notifier was built without NOTIFIER_FILE")
+	}
+	t.Setenv("NOTIFIER_FILE", filepath.Join(t.TempDir(), "notifications.jsonl"))
+	if notifier, err := FromEnv(); err != nil {
+		t.Errorf("building the file notifier: %v", err)
+	} else if _, ok := notifier.(*FileNotifier); !ok {
+		t.Errorf("NOTIFIER=file built a %T", notifier)
+	}
+
+	t.Setenv("NOTIFIER", "pigeon")
+	if _, err := FromEnv(); err == nil {
+		t.Error("an unknown notifier was built")
+	}
+}
@@ -0,0 +1,35 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// timeout bounds connecting to a printer and handing it a job, a printer that is off or out
+// of paper must not hold the request up
+const timeout = 5 * time.Second
+
+// Send pushes a raw print job to a network printer listening on address (host:port), such as
+// a thermal printer's port 9100. The job is sent as is, printers don't answer.
+func Send(ctx context.Context, address string, job []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("connecting to printer %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	if _, err := conn.Write(job); err != nil {
+		return fmt.Errorf("sending to printer %s: %w", address, err)
+	}
+	return nil
+}
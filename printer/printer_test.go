@@ -0,0 +1,63 @@
+package printer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// listen stands in for a network printer, handing back everything a connection sent once it closes
+func listen(t *testing.T) (string, <-chan []byte) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	jobs := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		job, _ := io.ReadAll(conn)
+		jobs <- job
+	}()
+	return listener.Addr().String(), jobs
+}
+
+func TestSendPushesTheJobToThePrinter(t *testing.T) {
+	address, jobs := listen(t)
+	job := []byte("\x1b@Hello kitchen\n\x1dV\x00")
+
+	if err := Send(context.Background(), address, job); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	select {
+	case received := <-jobs:
+		if !bytes.Equal(received, job) {
+			t.Errorf("the printer received %q, want %q", received, job)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the printer received nothing")
+	}
+}
+
+func TestSendFailsWithoutAPrinter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	if err := Send(context.Background(), address, []byte("\x1b@")); err == nil {
+		t.Fatal("Send succeeded with nothing listening")
+	}
+}
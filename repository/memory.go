@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"restaurant-management/models"
+)
+
+// memoryStore holds every aggregate of the in-memory repositories behind one lock,
+// so the repositories built by NewMemory see each other's writes like tables in one database
+type memoryStore struct {
+	mu     sync.RWMutex
+	txMu   sync.Mutex // held for the whole of a memory unit of work
+	nextID map[string]uint
+
+	orders         map[uint]models.Order
+	history        map[uint]models.OrderStatusChange
+	orderItems     map[uint]models.OrderItem
+	foods          map[uint]models.Food
+	menus          map[uint]models.Menu
+	combos         map[uint]models.Combo // with their slots
+	stations       map[uint]models.Station
+	tables         map[uint]models.Table
+	reservations   map[uint]models.Reservation
+	waitlist       map[uint]models.WaitlistEntry
+	taxRates       map[uint]models.TaxRate
+	serviceCharges map[uint]models.ServiceCharge
+	promotions     map[uint]models.Promotion
+	discounts      map[uint]models.OrderDiscount
+	invoices       map[uint]models.Invoice
+	payments       map[uint]models.Payment
+	paymentIntents map[uint]models.PaymentIntent
+	webhookEvents  map[string]models.WebhookEvent // by provider and event ID
+	creditNotes    map[uint]models.CreditNote     // with their items, refunds are kept apart
+	refunds        map[uint]models.Refund
+	deliveries     map[uint]models.InvoiceDelivery
+	sequences      map[string]int                  // last number taken, by restaurant, kind of document and period
+	numberSeries   map[string]models.NumberSeries  // by restaurant and kind of document
+	templates      map[uint]models.InvoiceTemplate // by restaurant
+	restaurants    map[uint]models.Restaurant
+	staff          map[uint]models.RestaurantStaff
+	notes          map[uint]models.Note
+	users          map[uint]models.User
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		nextID:         map[string]uint{},
+		orders:         map[uint]models.Order{},
+		history:        map[uint]models.OrderStatusChange{},
+		orderItems:     map[uint]models.OrderItem{},
+		foods:          map[uint]models.Food{},
+		menus:          map[uint]models.Menu{},
+		combos:         map[uint]models.Combo{},
+		stations:       map[uint]models.Station{},
+		tables:         map[uint]models.Table{},
+		reservations:   map[uint]models.Reservation{},
+		waitlist:       map[uint]models.WaitlistEntry{},
+		taxRates:       map[uint]models.TaxRate{},
+		serviceCharges: map[uint]models.ServiceCharge{},
+		promotions:     map[uint]models.Promotion{},
+		discounts:      map[uint]models.OrderDiscount{},
+		invoices:       map[uint]models.Invoice{},
+		payments:       map[uint]models.Payment{},
+		paymentIntents: map[uint]models.PaymentIntent{},
+		webhookEvents:  map[string]models.WebhookEvent{},
+		creditNotes:    map[uint]models.CreditNote{},
+		refunds:        map[uint]models.Refund{},
+		deliveries:     map[uint]models.InvoiceDelivery{},
+		sequences:      map[string]int{},
+		numberSeries:   map[string]models.NumberSeries{},
+		templates:      map[uint]models.InvoiceTemplate{},
+		restaurants:    map[uint]models.Restaurant{},
+		staff:          map[uint]models.RestaurantStaff{},
+		notes:          map[uint]models.Note{},
+		users:          map[uint]models.User{},
+	}
+}
+
+// newID plays the role of a SERIAL column, callers must hold the write lock
+func (s *memoryStore) newID(table string) uint {
+	s.nextID[table]++
+	return s.nextID[table]
+}
+
+// sortedValues returns the map's values ordered by ID, the default order of the Postgres queries
+func sortedValues[T any](records map[uint]T) []T {
+	ids := make([]uint, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	values := make([]T, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, records[id])
+	}
+	return values
+}
+
+// textArray copies a slice the way a TEXT[] column defaulting to '{}' stores it, never as nil
+func textArray(values []string) []string {
+	return append([]string{}, values...)
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"restaurant-management/models"
+)
+
+type memoryFoodRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryFoodRepository) List(ctx context.Context, restaurantID uint) ([]models.Food, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var foods []models.Food
+	for _, food := range sortedValues(r.store.foods) {
+		if restaurantID == 0 || food.RestaurantID == restaurantID {
+			foods = append(foods, food)
+		}
+	}
+	return foods, nil
+}
+
+func (r *memoryFoodRepository) Get(ctx context.Context, id uint) (models.Food, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	food, ok := r.store.foods[id]
+	if !ok {
+		return models.Food{}, ErrNotFound
+	}
+	return food, nil
+}
+
+func (r *memoryFoodRepository) Create(ctx context.Context, food models.Food) (models.Food, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	food.ID = r.store.newID("foods")
+	if food.TaxCategory == "" {
+		food.TaxCategory = models.TaxCategoryFood
+	}
+	food.Availability = slices.Clone(food.Availability)
+	food.Allergens, food.DietaryTags = textArray(food.Allergens), textArray(food.DietaryTags)
+	food.Variants, food.ModifierGroups = newFoodOptions(food)
+	r.store.saveFoodOptions(models.Food{}, &food)
+	food.CreatedAt, food.UpdatedAt = now(), now()
+	r.store.foods[food.ID] = food
+	return food, nil
+}
+
+func (r *memoryFoodRepository) Update(ctx context.Context, food models.Food) (models.Food, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.foods[food.ID]
+	if !ok {
+		return models.Food{}, ErrNotFound
+	}
+	if food.TaxCategory == "" {
+		food.TaxCategory = models.TaxCategoryFood
+	}
+	food.Availability = slices.Clone(food.Availability)
+	food.Allergens, food.DietaryTags = textArray(food.Allergens), textArray(food.DietaryTags)
+	if !r.store.saveFoodOptions(existing, &food) {
+		return models.Food{}, ErrNotFound
+	}
+	food.CreatedAt, food.UpdatedAt = existing.CreatedAt, now()
+	r.store.foods[food.ID] = food
+	return food, nil
+}
+
+func (r *memoryFoodRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.foods[id]; !ok {
+		return ErrNotFound
+	}
+	r.store.saveFoodOptions(r.store.foods[id], &models.Food{})
+	delete(r.store.foods, id)
+	// mirror ON DELETE CASCADE of food promotions
+	for promotionID, promotion := range r.store.promotions {
+		if promotion.FoodID == id {
+			delete(r.store.promotions, promotionID)
+		}
+	}
+	// and of the combo slots and choices the food fills
+	for comboID, combo := range r.store.combos {
+		var slots []models.ComboSlot
+		for _, slot := range combo.Slots {
+			if slot.FoodID != id {
+				slot.Choices = slices.DeleteFunc(slices.Clone(slot.Choices), func(choice models.ComboChoice) bool { return choice.FoodID == id })
+				slots = append(slots, slot)
+			}
+		}
+		combo.Slots = slots
+		r.store.combos[comboID] = combo
+	}
+	return nil
+}
+
+// saveFoodOptions gives the new variants, modifier groups and options of a food an ID, the way
+// the Postgres repository does, after checking the ones with an ID were the food's already. It
+// mirrors ON DELETE SET NULL on the order items of the variants and modifiers it drops.
+func (s *memoryStore) saveFoodOptions(existing models.Food, food *models.Food) bool {
+	variants, modifiers := map[uint]bool{}, map[uint]bool{}
+	groups := map[uint]map[uint]bool{}
+	for _, variant := range existing.Variants {
+		variants[variant.ID] = true
+	}
+	for _, group := range existing.ModifierGroups {
+		groups[group.ID] = map[uint]bool{}
+		for _, option := range group.Options {
+			groups[group.ID][option.ID] = true
+			modifiers[option.ID] = true
+		}
+	}
+
+	food.Variants = slices.Clone(food.Variants)
+	for i := range food.Variants {
+		switch id := food.Variants[i].ID; {
+		case id == 0:
+			food.Variants[i].ID = s.newID("food_variants")
+		case !variants[id]:
+			return false
+		default:
+			delete(variants, id)
+		}
+	}
+	food.ModifierGroups = slices.Clone(food.ModifierGroups)
+	for i := range food.ModifierGroups {
+		group := &food.ModifierGroups[i]
+		switch {
+		case group.ID == 0:
+			group.ID = s.newID("modifier_groups")
+		case groups[group.ID] == nil:
+			return false
+		}
+		group.Options = slices.Clone(group.Options)
+		for j := range group.Options {
+			switch id := group.Options[j].ID; {
+			case id == 0:
+				group.Options[j].ID = s.newID("modifiers")
+			case !groups[group.ID][id]:
+				return false
+			default:
+				delete(modifiers, id)
+			}
+		}
+	}
+
+	// what is left was dropped
+	for itemID, item := range s.orderItems {
+		if variants[item.VariantID] || slices.ContainsFunc(item.Modifiers, func(m models.OrderItemModifier) bool { return modifiers[m.ModifierID] }) {
+			item.Modifiers = slices.Clone(item.Modifiers)
+			for i := range item.Modifiers {
+				if modifiers[item.Modifiers[i].ModifierID] {
+					item.Modifiers[i].ModifierID = 0
+				}
+			}
+			if variants[item.VariantID] {
+				item.VariantID = 0
+			}
+			s.orderItems[itemID] = item
+		}
+	}
+	return true
+}
+
+type memoryMenuRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryMenuRepository) List(ctx context.Context, restaurantID uint) ([]models.Menu, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var menus []models.Menu
+	for _, menu := range sortedValues(r.store.menus) {
+		if restaurantID == 0 || menu.RestaurantID == restaurantID {
+			menus = append(menus, menu)
+		}
+	}
+	sort.SliceStable(menus, func(i, j int) bool { return menus[i].Position < menus[j].Position })
+	return menus, nil
+}
+
+func (r *memoryMenuRepository) Get(ctx context.Context, id uint) (models.Menu, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	menu, ok := r.store.menus[id]
+	if !ok {
+		return models.Menu{}, ErrNotFound
+	}
+	return menu, nil
+}
+
+func (r *memoryMenuRepository) Create(ctx context.Context, menu models.Menu) (models.Menu, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	menu.ID = r.store.newID("menus")
+	menu.Children, menu.Availability = nil, slices.Clone(menu.Availability)
+	menu.CreatedAt, menu.UpdatedAt = now(), now()
+	r.store.menus[menu.ID] = menu
+	return menu, nil
+}
+
+func (r *memoryMenuRepository) Update(ctx context.Context, menu models.Menu) (models.Menu, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.menus[menu.ID]
+	if !ok {
+		return models.Menu{}, ErrNotFound
+	}
+	menu.Children, menu.Availability = nil, slices.Clone(menu.Availability)
+	menu.CreatedAt, menu.UpdatedAt = existing.CreatedAt, now()
+	r.store.menus[menu.ID] = menu
+	return menu, nil
+}
+
+func (r *memoryMenuRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.menus[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.menus, id)
+	// mirror ON DELETE SET NULL of sub-categories
+	for childID, child := range r.store.menus {
+		if child.ParentID == id {
+			child.ParentID = 0
+			r.store.menus[childID] = child
+		}
+	}
+	// mirror ON DELETE CASCADE of menu promotions
+	for promotionID, promotion := range r.store.promotions {
+		if promotion.MenuID == id {
+			delete(r.store.promotions, promotionID)
+		}
+	}
+	return nil
+}
+
+type memoryTableRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryTableRepository) List(ctx context.Context, restaurantID uint) ([]models.Table, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tables []models.Table
+	for _, table := range sortedValues(r.store.tables) {
+		if restaurantID == 0 || uint(table.RestaurantID) == restaurantID {
+			tables = append(tables, table)
+		}
+	}
+	return tables, nil
+}
+
+func (r *memoryTableRepository) Get(ctx context.Context, id uint) (models.Table, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	table, ok := r.store.tables[id]
+	if !ok {
+		return models.Table{}, ErrNotFound
+	}
+	return table, nil
+}
+
+// GetForUpdate needs no row lock, memory units of work already run one at a time
+func (r *memoryTableRepository) GetForUpdate(ctx context.Context, id uint) (models.Table, error) {
+	return r.Get(ctx, id)
+}
+
+func (r *memoryTableRepository) Create(ctx context.Context, table models.Table) (models.Table, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	table.ID = r.store.newID("tables")
+	table.CreatedAt, table.UpdatedAt = now(), now()
+	r.store.tables[table.ID] = table
+	return table, nil
+}
+
+func (r *memoryTableRepository) Update(ctx context.Context, table models.Table) (models.Table, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.tables[table.ID]
+	if !ok {
+		return models.Table{}, ErrNotFound
+	}
+	table.CreatedAt, table.UpdatedAt = existing.CreatedAt, now()
+	r.store.tables[table.ID] = table
+	return table, nil
+}
+
+func (r *memoryTableRepository) UpdateStatus(ctx context.Context, id uint, status string) (models.Table, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	table, ok := r.store.tables[id]
+	if !ok {
+		return models.Table{}, ErrNotFound
+	}
+	table.Status, table.UpdatedAt = status, now()
+	r.store.tables[id] = table
+	return table, nil
+}
+
+func (r *memoryTableRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tables[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.tables, id)
+	// mirror ON DELETE CASCADE
+	for reservationID, reservation := range r.store.reservations {
+		if reservation.TableID == id {
+			delete(r.store.reservations, reservationID)
+		}
+	}
+	return nil
+}
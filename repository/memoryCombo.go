@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"restaurant-management/models"
+)
+
+type memoryComboRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryComboRepository) List(ctx context.Context, restaurantID uint) ([]models.Combo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var combos []models.Combo
+	for _, combo := range sortedValues(r.store.combos) {
+		if combo.RestaurantID == restaurantID {
+			combos = append(combos, combo)
+		}
+	}
+	return combos, nil
+}
+
+func (r *memoryComboRepository) Get(ctx context.Context, id uint) (models.Combo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	combo, ok := r.store.combos[id]
+	if !ok {
+		return models.Combo{}, ErrNotFound
+	}
+	return combo, nil
+}
+
+func (r *memoryComboRepository) Create(ctx context.Context, combo models.Combo) (models.Combo, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	combo.ID = r.store.newID("combos")
+	if combo.TaxCategory == "" {
+		combo.TaxCategory = models.TaxCategoryFood
+	}
+	combo.Slots = r.store.newComboSlots(combo.Slots)
+	combo.CreatedAt, combo.UpdatedAt = now(), now()
+	r.store.combos[combo.ID] = combo
+	return combo, nil
+}
+
+func (r *memoryComboRepository) Update(ctx context.Context, combo models.Combo) (models.Combo, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.combos[combo.ID]
+	if !ok {
+		return models.Combo{}, ErrNotFound
+	}
+	if combo.TaxCategory == "" {
+		combo.TaxCategory = models.TaxCategoryFood
+	}
+	combo.RestaurantID = existing.RestaurantID
+	combo.Slots = r.store.newComboSlots(combo.Slots)
+	combo.CreatedAt, combo.UpdatedAt = existing.CreatedAt, now()
+	r.store.combos[combo.ID] = combo
+	return combo, nil
+}
+
+func (r *memoryComboRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.combos[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.combos, id)
+	// mirror ON DELETE CASCADE of the combo's order items
+	for itemID, item := range r.store.orderItems {
+		if item.ComboID == id {
+			r.store.deleteOrderItem(itemID)
+		}
+	}
+	return nil
+}
+
+// newComboSlots copies slots under new IDs, the way the Postgres repository replaces them,
+// callers must hold the write lock
+func (s *memoryStore) newComboSlots(slots []models.ComboSlot) []models.ComboSlot {
+	slots = slices.Clone(slots)
+	for i := range slots {
+		slots[i].ID = s.newID("combo_slots")
+		slots[i].Quantity = slots[i].SlotQuantity()
+		slots[i].Choices = slices.Clone(slots[i].Choices)
+	}
+	return slots
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"restaurant-management/models"
+)
+
+type memoryCreditNoteRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryCreditNoteRepository) ListByInvoice(ctx context.Context, invoiceID uint) ([]models.CreditNote, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var notes []models.CreditNote
+	for _, note := range sortedValues(r.store.creditNotes) {
+		if note.InvoiceID == invoiceID {
+			notes = append(notes, r.store.withRefunds(note))
+		}
+	}
+	return notes, nil
+}
+
+func (r *memoryCreditNoteRepository) Get(ctx context.Context, id uint) (models.CreditNote, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	note, ok := r.store.creditNotes[id]
+	if !ok {
+		return models.CreditNote{}, ErrNotFound
+	}
+	return r.store.withRefunds(note), nil
+}
+
+// withRefunds adds the refunds of a credit note, callers must hold the lock
+func (s *memoryStore) withRefunds(note models.CreditNote) models.CreditNote {
+	note.Refunds = nil
+	for _, refund := range sortedValues(s.refunds) {
+		if refund.CreditNoteID == note.ID {
+			note.Refunds = append(note.Refunds, refund)
+		}
+	}
+	return note
+}
+
+func (r *memoryCreditNoteRepository) Create(ctx context.Context, note models.CreditNote) (models.CreditNote, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.invoices[note.InvoiceID]; !ok {
+		return models.CreditNote{}, ErrNotFound
+	}
+	note.CreatedAt = now()
+	note.Period, note.Sequence, note.Number = r.store.takeNumber(note.RestaurantID, models.SequenceCreditNote, note.CreatedAt)
+	note.ID = r.store.newID("credit_notes")
+	note.Items, note.Refunds = slices.Clone(note.Items), nil
+	r.store.creditNotes[note.ID] = note
+	return note, nil
+}
+
+func (r *memoryCreditNoteRepository) VoidedQuantities(ctx context.Context, orderID uint) (map[uint]uint, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	voided := map[uint]uint{}
+	for _, note := range r.store.creditNotes {
+		if note.OrderID != orderID {
+			continue
+		}
+		for _, item := range note.Items {
+			if item.OrderItemID != 0 {
+				voided[item.OrderItemID] += item.Quantity
+			}
+		}
+	}
+	return voided, nil
+}
+
+type memoryRefundRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryRefundRepository) Get(ctx context.Context, id uint) (models.Refund, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	refund, ok := r.store.refunds[id]
+	if !ok {
+		return models.Refund{}, ErrNotFound
+	}
+	return refund, nil
+}
+
+func (r *memoryRefundRepository) GetByProviderID(ctx context.Context, provider, providerRefundID string) (models.Refund, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, refund := range r.store.refunds {
+		if refund.Provider == provider && refund.ProviderRefundID == providerRefundID && providerRefundID != "" {
+			return refund, nil
+		}
+	}
+	return models.Refund{}, ErrNotFound
+}
+
+// providerIDTaken mirrors the unique index on provider refund IDs, callers must hold the lock
+func (r *memoryRefundRepository) providerIDTaken(refund models.Refund) bool {
+	for _, existing := range r.store.refunds {
+		if refund.ProviderRefundID != "" && existing.ID != refund.ID && existing.Provider == refund.Provider && existing.ProviderRefundID == refund.ProviderRefundID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryRefundRepository) Create(ctx context.Context, refund models.Refund) (models.Refund, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.creditNotes[refund.CreditNoteID]; !ok {
+		return models.Refund{}, ErrNotFound
+	}
+	if _, ok := r.store.payments[refund.PaymentID]; !ok {
+		return models.Refund{}, ErrNotFound
+	}
+	if r.providerIDTaken(refund) {
+		return models.Refund{}, ErrDuplicate
+	}
+	refund.ID = r.store.newID("refunds")
+	refund.CreatedAt, refund.UpdatedAt = now(), now()
+	r.store.refunds[refund.ID] = refund
+	return refund, nil
+}
+
+func (r *memoryRefundRepository) UpdateStatus(ctx context.Context, id uint, status, providerRefundID, failureReason string) (models.Refund, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	refund, ok := r.store.refunds[id]
+	if !ok {
+		return models.Refund{}, ErrNotFound
+	}
+	if providerRefundID != "" {
+		refund.ProviderRefundID = providerRefundID
+	}
+	if r.providerIDTaken(refund) {
+		return models.Refund{}, ErrDuplicate
+	}
+	refund.Status, refund.FailureReason, refund.UpdatedAt = status, failureReason, now()
+	r.store.refunds[id] = refund
+	return refund, nil
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+type memoryInvoiceRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryInvoiceRepository) ListByRestaurant(ctx context.Context, restaurantID uint) ([]models.Invoice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var invoices []models.Invoice
+	for _, invoice := range sortedValues(r.store.invoices) {
+		if invoice.RestaurantID == restaurantID && invoice.ParentID == 0 {
+			invoices = append(invoices, invoice)
+		}
+	}
+	return invoices, nil
+}
+
+func (r *memoryInvoiceRepository) Get(ctx context.Context, id uint) (models.Invoice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	invoice, ok := r.store.invoices[id]
+	if !ok {
+		return models.Invoice{}, ErrNotFound
+	}
+	return r.withDetails(invoice), nil
+}
+
+func (r *memoryInvoiceRepository) GetByOrder(ctx context.Context, orderID uint) (models.Invoice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, invoice := range r.store.invoices {
+		if invoice.OrderID == orderID && invoice.ParentID == 0 {
+			return r.withDetails(invoice), nil
+		}
+	}
+	return models.Invoice{}, ErrNotFound
+}
+
+// withDetails adds the parts of an invoice and the payments taken against them, callers must hold the lock
+func (r *memoryInvoiceRepository) withDetails(invoice models.Invoice) models.Invoice {
+	invoice.Splits = r.splits(invoice.ID)
+	invoice.Payments = r.store.invoicePayments(invoice.ID)
+	return invoice
+}
+
+func (r *memoryInvoiceRepository) splits(parentID uint) []models.Invoice {
+	var splits []models.Invoice
+	for _, invoice := range sortedValues(r.store.invoices) {
+		if invoice.ParentID == parentID {
+			splits = append(splits, invoice)
+		}
+	}
+	return splits
+}
+
+func (r *memoryInvoiceRepository) ListSplits(ctx context.Context, parentID uint) ([]models.Invoice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.splits(parentID), nil
+}
+
+func (r *memoryInvoiceRepository) UpsertForOrder(ctx context.Context, invoice models.Invoice) (models.Invoice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	invoice.Splits, invoice.Payments = nil, nil
+	for id, existing := range r.store.invoices {
+		if existing.OrderID == invoice.OrderID && existing.ParentID == 0 {
+			invoice.ID, invoice.CreatedAt, invoice.UpdatedAt = id, existing.CreatedAt, now()
+			invoice.SplitMode, invoice.AmountPaid, invoice.Tip, invoice.Credited = existing.SplitMode, existing.AmountPaid, existing.Tip, existing.Credited
+			invoice.Number, invoice.IssuedAt = existing.Number, existing.IssuedAt
+			invoice.Balance = balance(invoice)
+			r.store.invoices[id] = invoice
+			return invoice, nil
+		}
+	}
+
+	invoice.ID = r.store.newID("invoices")
+	invoice.ParentID, invoice.SplitMode, invoice.AmountPaid, invoice.Tip, invoice.Credited = 0, "", 0, 0, 0
+	invoice.Number, invoice.IssuedAt = "", nil
+	invoice.Balance = balance(invoice)
+	invoice.CreatedAt, invoice.UpdatedAt = now(), now()
+	r.store.invoices[invoice.ID] = invoice
+	return invoice, nil
+}
+
+func (r *memoryInvoiceRepository) ReplaceSplits(ctx context.Context, parentID uint, mode string, splits []models.Invoice) ([]models.Invoice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	parent, ok := r.store.invoices[parentID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	for id, invoice := range r.store.invoices {
+		if invoice.ParentID == parentID {
+			r.store.deleteInvoice(id)
+		}
+	}
+	parent.SplitMode, parent.UpdatedAt = mode, now()
+	r.store.invoices[parentID] = parent
+
+	saved := make([]models.Invoice, 0, len(splits))
+	for _, split := range splits {
+		split.ID = r.store.newID("invoices")
+		split.ParentID, split.SplitMode, split.AmountPaid, split.Tip, split.Credited = parentID, "", 0, 0, 0
+		split.Splits, split.Payments = nil, nil
+		split.Number, split.IssuedAt = "", nil
+		split.Balance = balance(split)
+		split.CreatedAt, split.UpdatedAt = now(), now()
+		r.store.invoices[split.ID] = split
+		saved = append(saved, split)
+	}
+	return saved, nil
+}
+
+func (r *memoryInvoiceRepository) UpdatePayment(ctx context.Context, id uint, amountPaid, tip float64, status, paymentMethod string) (models.Invoice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	invoice, ok := r.store.invoices[id]
+	if !ok {
+		return models.Invoice{}, ErrNotFound
+	}
+	invoice.AmountPaid, invoice.Tip, invoice.Status, invoice.PaymentMethod, invoice.UpdatedAt = amountPaid, tip, status, paymentMethod, now()
+	invoice.Balance = balance(invoice)
+	r.store.invoices[id] = invoice
+	return invoice, nil
+}
+
+func (r *memoryInvoiceRepository) UpdateCredited(ctx context.Context, id uint, credited float64) (models.Invoice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	invoice, ok := r.store.invoices[id]
+	if !ok {
+		return models.Invoice{}, ErrNotFound
+	}
+	invoice.Credited, invoice.UpdatedAt = credited, now()
+	r.store.invoices[id] = invoice
+	return invoice, nil
+}
+
+func (r *memoryInvoiceRepository) Issue(ctx context.Context, id uint) (models.Invoice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	invoice, ok := r.store.invoices[id]
+	if !ok {
+		return models.Invoice{}, ErrNotFound
+	}
+	if invoice.Number == "" {
+		issuedAt := now()
+		_, _, invoice.Number = r.store.takeNumber(invoice.RestaurantID, models.SequenceInvoice, issuedAt)
+		invoice.IssuedAt = &issuedAt
+		r.store.invoices[id] = invoice
+	}
+	return r.withDetails(invoice), nil
+}
+
+func (r *memoryInvoiceRepository) DeleteByOrder(ctx context.Context, orderID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, invoice := range r.store.invoices {
+		if invoice.OrderID == orderID {
+			r.store.deleteInvoice(id)
+		}
+	}
+	return nil
+}
+
+// deleteInvoice removes an invoice along with its parts, payments and credit notes, mirroring ON DELETE CASCADE.
+// Callers must hold the write lock.
+func (s *memoryStore) deleteInvoice(id uint) {
+	delete(s.invoices, id)
+	for paymentID, payment := range s.payments {
+		if payment.InvoiceID == id {
+			delete(s.payments, paymentID)
+		}
+	}
+	for intentID, intent := range s.paymentIntents {
+		if intent.InvoiceID == id {
+			delete(s.paymentIntents, intentID)
+		}
+	}
+	for deliveryID, delivery := range s.deliveries {
+		if delivery.InvoiceID == id {
+			delete(s.deliveries, deliveryID)
+		}
+	}
+	for noteID, note := range s.creditNotes {
+		if note.InvoiceID == id {
+			delete(s.creditNotes, noteID)
+		}
+	}
+	for refundID, refund := range s.refunds {
+		if _, ok := s.creditNotes[refund.CreditNoteID]; !ok {
+			delete(s.refunds, refundID)
+		} else if _, ok := s.payments[refund.PaymentID]; !ok {
+			delete(s.refunds, refundID)
+		}
+	}
+	for partID, part := range s.invoices {
+		if part.ParentID == id {
+			s.deleteInvoice(partID)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+type memoryInvoiceDeliveryRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryInvoiceDeliveryRepository) ListByInvoice(ctx context.Context, invoiceID uint) ([]models.InvoiceDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var deliveries []models.InvoiceDelivery
+	for _, delivery := range sortedValues(r.store.deliveries) {
+		if delivery.InvoiceID == invoiceID {
+			deliveries = append([]models.InvoiceDelivery{delivery}, deliveries...)
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *memoryInvoiceDeliveryRepository) Create(ctx context.Context, delivery models.InvoiceDelivery) (models.InvoiceDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.invoices[delivery.InvoiceID]; !ok {
+		return models.InvoiceDelivery{}, ErrNotFound
+	}
+	delivery.ID = r.store.newID("invoice_deliveries")
+	delivery.Status, delivery.Attempts, delivery.LastError, delivery.SentAt = models.DeliveryStatusPending, 0, "", nil
+	delivery.CreatedAt, delivery.UpdatedAt = now(), now()
+	r.store.deliveries[delivery.ID] = delivery
+	return delivery, nil
+}
+
+func (r *memoryInvoiceDeliveryRepository) RecordAttempt(ctx context.Context, id uint, status, lastError string) (models.InvoiceDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delivery, ok := r.store.deliveries[id]
+	if !ok {
+		return models.InvoiceDelivery{}, ErrNotFound
+	}
+	delivery.Status, delivery.LastError, delivery.UpdatedAt = status, lastError, now()
+	delivery.Attempts++
+	if status == models.DeliveryStatusSent {
+		sentAt := now()
+		delivery.SentAt = &sentAt
+	}
+	r.store.deliveries[id] = delivery
+	return delivery, nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"restaurant-management/models"
+)
+
+type memoryInvoiceTemplateRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryInvoiceTemplateRepository) Get(ctx context.Context, restaurantID uint) (models.InvoiceTemplate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	template, ok := r.store.templates[restaurantID]
+	if !ok {
+		return models.DefaultInvoiceTemplate(restaurantID), nil
+	}
+	template.TaxIDs = slices.Clone(template.TaxIDs)
+	return template, nil
+}
+
+func (r *memoryInvoiceTemplateRepository) Upsert(ctx context.Context, template models.InvoiceTemplate) (models.InvoiceTemplate, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.restaurants[template.RestaurantID]; !ok {
+		return models.InvoiceTemplate{}, ErrNotFound
+	}
+	template.TaxIDs = slices.Clone(template.TaxIDs)
+	template.UpdatedAt = now()
+	r.store.templates[template.RestaurantID] = template
+	return template, nil
+}
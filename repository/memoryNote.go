@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+type memoryNoteRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryNoteRepository) ListByRestaurant(ctx context.Context, restaurantID uint) ([]models.Note, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var notes []models.Note
+	for _, note := range sortedValues(r.store.notes) {
+		if note.RestaurantID == restaurantID {
+			notes = append(notes, note)
+		}
+	}
+	return notes, nil
+}
+
+func (r *memoryNoteRepository) Create(ctx context.Context, note models.Note) (models.Note, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	note.ID = r.store.newID("notes")
+	note.CreatedAt, note.UpdatedAt = now(), now()
+	r.store.notes[note.ID] = note
+	return note, nil
+}
+
+func (r *memoryNoteRepository) Update(ctx context.Context, note models.Note) (models.Note, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.notes[note.ID]
+	if !ok {
+		return models.Note{}, ErrNotFound
+	}
+	existing.Title, existing.Content, existing.Priority, existing.UpdatedAt = note.Title, note.Content, note.Priority, now()
+	r.store.notes[note.ID] = existing
+	return existing, nil
+}
+
+func (r *memoryNoteRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.notes, id)
+	return nil
+}
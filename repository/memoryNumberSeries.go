@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"restaurant-management/models"
+)
+
+type memoryNumberSeriesRepository struct {
+	store *memoryStore
+}
+
+func seriesKey(restaurantID uint, kind string) string {
+	return fmt.Sprintf("%d/%s", restaurantID, kind)
+}
+
+func (r *memoryNumberSeriesRepository) List(ctx context.Context, restaurantID uint) ([]models.NumberSeries, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	series := make([]models.NumberSeries, 0, len(models.DocumentKinds))
+	for _, kind := range models.DocumentKinds {
+		series = append(series, r.store.series(restaurantID, kind))
+	}
+	return series, nil
+}
+
+func (r *memoryNumberSeriesRepository) Get(ctx context.Context, restaurantID uint, kind string) (models.NumberSeries, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.series(restaurantID, kind), nil
+}
+
+// series returns a restaurant's series for a kind of document, callers must hold the lock
+func (s *memoryStore) series(restaurantID uint, kind string) models.NumberSeries {
+	if series, ok := s.numberSeries[seriesKey(restaurantID, kind)]; ok {
+		return series
+	}
+	return models.DefaultNumberSeries(restaurantID, kind)
+}
+
+func (r *memoryNumberSeriesRepository) Upsert(ctx context.Context, series models.NumberSeries) (models.NumberSeries, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	series.UpdatedAt = now()
+	r.store.numberSeries[seriesKey(series.RestaurantID, series.Kind)] = series
+	return series, nil
+}
+
+// takeNumber numbers a document of a restaurant issued at the given time, next in the
+// period of its series. Callers must hold the write lock.
+func (s *memoryStore) takeNumber(restaurantID uint, kind string, at time.Time) (int, int, string) {
+	series := s.series(restaurantID, kind)
+	period := series.Period(at)
+	key := fmt.Sprintf("%s/%d", seriesKey(restaurantID, kind), period)
+	s.sequences[key]++
+	return period, s.sequences[key], series.Format(period, s.sequences[key])
+}
@@ -0,0 +1,515 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"restaurant-management/models"
+)
+
+type memoryOrderRepository struct {
+	store *memoryStore
+}
+
+// withItems attaches the order's items, callers must hold the read lock
+func (r *memoryOrderRepository) withItems(order models.Order) models.Order {
+	order.OrderItems = r.store.listItems(func(item models.OrderItem) bool { return item.OrderID == order.ID })
+	return order
+}
+
+func (r *memoryOrderRepository) List(ctx context.Context, restaurantID uint) ([]models.Order, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var orders []models.Order
+	for _, order := range sortedValues(r.store.orders) {
+		if order.RestaurantID == restaurantID {
+			orders = append(orders, r.withItems(order))
+		}
+	}
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].OrderDate.After(orders[j].OrderDate) })
+	return orders, nil
+}
+
+func (r *memoryOrderRepository) Get(ctx context.Context, id uint) (models.Order, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	order, ok := r.store.orders[id]
+	if !ok {
+		return models.Order{}, ErrNotFound
+	}
+	order = r.withItems(order)
+	for _, discount := range sortedValues(r.store.discounts) {
+		if discount.OrderID == id {
+			order.Discounts = append(order.Discounts, discount)
+		}
+	}
+	return order, nil
+}
+
+// GetForUpdate needs no row lock, memory units of work already run one at a time
+func (r *memoryOrderRepository) GetForUpdate(ctx context.Context, id uint) (models.Order, error) {
+	return r.Get(ctx, id)
+}
+
+func (r *memoryOrderRepository) Create(ctx context.Context, order models.Order) (models.Order, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	order.ID = r.store.newID("orders")
+	order.CreatedAt, order.UpdatedAt = now(), now()
+	order.OrderItems, order.Discounts, order.DiscountTotal = nil, nil, 0
+	order.Allergens = textArray(order.Allergens)
+	r.store.orders[order.ID] = order
+	return order, nil
+}
+
+func (r *memoryOrderRepository) CreateBlank(ctx context.Context) (uint, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	id := r.store.newID("orders")
+	r.store.orders[id] = models.Order{ID: id, Status: models.OrderStatusPending, OrderDate: now(), Allergens: []string{}, CreatedAt: now(), UpdatedAt: now()}
+	return id, nil
+}
+
+func (r *memoryOrderRepository) Update(ctx context.Context, order models.Order) (models.Order, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.orders[order.ID]
+	if !ok {
+		return models.Order{}, ErrNotFound
+	}
+	items := order.OrderItems
+	order.OrderItems, order.Discounts = nil, nil
+	order.DiscountTotal, order.Allergens = existing.DiscountTotal, textArray(order.Allergens)
+	order.CreatedAt, order.UpdatedAt = existing.CreatedAt, now()
+	r.store.orders[order.ID] = order
+	order.OrderItems = items
+	return order, nil
+}
+
+func (r *memoryOrderRepository) UpdateStatus(ctx context.Context, id uint, status string) (models.Order, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	order, ok := r.store.orders[id]
+	if !ok {
+		return models.Order{}, ErrNotFound
+	}
+	order.Status, order.UpdatedAt = status, now()
+	r.store.orders[id] = order
+	return order, nil
+}
+
+func (r *memoryOrderRepository) RecalculateTotal(ctx context.Context, id uint) (models.Order, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	order, ok := r.store.orders[id]
+	if !ok {
+		return models.Order{}, ErrNotFound
+	}
+	order.TotalPrice = 0
+	for _, item := range r.store.orderItems {
+		if item.OrderID == id && item.ParentID == 0 {
+			order.TotalPrice += item.SubTotal
+		}
+	}
+	order.UpdatedAt = now()
+	r.store.orders[id] = order
+	return r.withItems(order), nil
+}
+
+func (r *memoryOrderRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.orders[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.orders, id)
+	// mirror ON DELETE CASCADE
+	for itemID, item := range r.store.orderItems {
+		if item.OrderID == id {
+			delete(r.store.orderItems, itemID)
+		}
+	}
+	for changeID, change := range r.store.history {
+		if change.OrderID == id {
+			delete(r.store.history, changeID)
+		}
+	}
+	for invoiceID, invoice := range r.store.invoices {
+		if invoice.OrderID == id {
+			r.store.deleteInvoice(invoiceID)
+		}
+	}
+	for discountID, discount := range r.store.discounts {
+		if discount.OrderID == id {
+			delete(r.store.discounts, discountID)
+		}
+	}
+	return nil
+}
+
+func (r *memoryOrderRepository) RecordStatusChange(ctx context.Context, change models.OrderStatusChange) (models.OrderStatusChange, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.orders[change.OrderID]; !ok {
+		return models.OrderStatusChange{}, ErrNotFound
+	}
+	change.ID = r.store.newID("order_status_history")
+	change.ChangedAt = now()
+	r.store.history[change.ID] = change
+	return change, nil
+}
+
+func (r *memoryOrderRepository) StatusHistory(ctx context.Context, orderID uint) ([]models.OrderStatusChange, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var history []models.OrderStatusChange
+	for _, change := range sortedValues(r.store.history) {
+		if change.OrderID == orderID {
+			history = append(history, change)
+		}
+	}
+	return history, nil
+}
+
+func (r *memoryOrderRepository) UpdateDiscountTotal(ctx context.Context, id uint, discountTotal float64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	order, ok := r.store.orders[id]
+	if !ok {
+		return ErrNotFound
+	}
+	order.DiscountTotal, order.UpdatedAt = discountTotal, now()
+	r.store.orders[id] = order
+	return nil
+}
+
+func (r *memoryOrderRepository) ListOpen(ctx context.Context, restaurantID uint) ([]models.Order, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var orders []models.Order
+	for _, order := range sortedValues(r.store.orders) {
+		if order.RestaurantID == restaurantID && order.Status != models.OrderStatusPaid && order.Status != models.OrderStatusCancelled {
+			orders = append(orders, order)
+		}
+	}
+	sort.SliceStable(orders, func(i, j int) bool { return orders[i].OrderDate.Before(orders[j].OrderDate) })
+	return orders, nil
+}
+
+func (r *memoryOrderRepository) AverageTurnTime(ctx context.Context, restaurantID uint, since time.Time) (time.Duration, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var total time.Duration
+	var count int
+	for _, change := range r.store.history {
+		order := r.store.orders[change.OrderID]
+		if order.RestaurantID != restaurantID || change.ToStatus != models.OrderStatusPaid || change.ChangedAt.Before(since) {
+			continue
+		}
+		total += change.ChangedAt.Sub(order.OrderDate)
+		count++
+	}
+	if count == 0 {
+		return 0, 0, nil
+	}
+	return total / time.Duration(count), count, nil
+}
+
+type memoryOrderItemRepository struct {
+	store *memoryStore
+}
+
+// itemWithFood fills FoodName, TaxCategory and Allergens the way the Postgres join does, from the
+// combo for a combo item, and attaches a combo's components. Callers must hold the lock.
+func (s *memoryStore) itemWithFood(item models.OrderItem) models.OrderItem {
+	food := s.foods[item.FoodID]
+	item.FoodName, item.MenuID, item.TaxCategory = food.Name, food.MenuID, food.TaxCategory
+	item.Allergens = textArray(food.Allergens)
+	if item.ComboID != 0 {
+		combo := s.combos[item.ComboID]
+		item.FoodName, item.TaxCategory = combo.Name, combo.TaxCategory
+		item.Components = nil
+		for _, component := range sortedValues(s.orderItems) {
+			if component.ParentID == item.ID {
+				item.Components = append(item.Components, s.itemWithFood(component))
+			}
+		}
+		item.Allergens = models.ComponentAllergens(item.Components)
+	}
+	if item.TaxCategory == "" {
+		item.TaxCategory = models.TaxCategoryFood
+	}
+	return item
+}
+
+// listItems returns the items matching keep that are not components of a combo, callers must hold the lock
+func (s *memoryStore) listItems(keep func(item models.OrderItem) bool) []models.OrderItem {
+	var items []models.OrderItem
+	for _, item := range sortedValues(s.orderItems) {
+		if item.ParentID == 0 && keep(item) {
+			items = append(items, s.itemWithFood(item))
+		}
+	}
+	return items
+}
+
+func (r *memoryOrderItemRepository) ListByRestaurant(ctx context.Context, restaurantID uint) ([]models.OrderItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listItems(func(item models.OrderItem) bool { return r.store.orders[item.OrderID].RestaurantID == restaurantID }), nil
+}
+
+func (r *memoryOrderItemRepository) ListByOrder(ctx context.Context, orderID uint) ([]models.OrderItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listItems(func(item models.OrderItem) bool { return item.OrderID == orderID }), nil
+}
+
+func (r *memoryOrderItemRepository) Get(ctx context.Context, id uint) (models.OrderItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	item, ok := r.store.orderItems[id]
+	if !ok {
+		return models.OrderItem{}, ErrNotFound
+	}
+	return r.store.itemWithFood(item), nil
+}
+
+func (r *memoryOrderItemRepository) Create(ctx context.Context, item models.OrderItem) (models.OrderItem, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.createOrderItem(item), nil
+}
+
+// createOrderItem stores an item and its components, callers must hold the write lock
+func (s *memoryStore) createOrderItem(item models.OrderItem) models.OrderItem {
+	item.ID = s.newID("orderitems")
+	item.CreatedAt, item.UpdatedAt = now(), now()
+	item.FoodName, item.MenuID, item.TaxCategory = "", 0, ""
+	item.PrepStatus, item.BumpedAt = models.PrepStatusQueued, nil
+	item.ModifierIDs, item.Modifiers = nil, slices.Clone(item.Modifiers)
+	components := item.Components
+	item.ComboPicks, item.Components = nil, nil
+	s.orderItems[item.ID] = item
+	for _, component := range components {
+		component.OrderID, component.ParentID = item.OrderID, item.ID
+		s.createOrderItem(component)
+	}
+	return s.itemWithFood(item)
+}
+
+func (r *memoryOrderItemRepository) UpdateQuantity(ctx context.Context, id uint, quantity uint, subtotal float64) (models.OrderItem, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	item, ok := r.store.orderItems[id]
+	if !ok {
+		return models.OrderItem{}, ErrNotFound
+	}
+	// the components of a combo scale with it
+	for componentID, component := range r.store.orderItems {
+		if component.ParentID == id {
+			component.SubTotal = math.Round(component.SubTotal/float64(item.Quantity)*float64(quantity)*100) / 100
+			component.Quantity = component.Quantity / item.Quantity * quantity
+			component.UpdatedAt = now()
+			r.store.orderItems[componentID] = component
+		}
+	}
+	item.Quantity, item.SubTotal, item.UpdatedAt = quantity, subtotal, now()
+	r.store.orderItems[id] = item
+	return r.store.itemWithFood(item), nil
+}
+
+func (r *memoryOrderItemRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.orderItems[id]; !ok {
+		return ErrNotFound
+	}
+	r.store.deleteOrderItem(id)
+	return nil
+}
+
+// deleteOrderItem removes an item, callers must hold the write lock
+func (s *memoryStore) deleteOrderItem(id uint) {
+	delete(s.orderItems, id)
+	// mirror ON DELETE CASCADE on the components of a combo
+	for componentID, component := range s.orderItems {
+		if component.ParentID == id {
+			s.deleteOrderItem(componentID)
+		}
+	}
+	// mirror ON DELETE CASCADE on the items of split invoices
+	for invoiceID, invoice := range s.invoices {
+		if slices.Contains(invoice.OrderItemIDs, id) {
+			invoice.OrderItemIDs = slices.DeleteFunc(slices.Clone(invoice.OrderItemIDs), func(itemID uint) bool { return itemID == id })
+			s.invoices[invoiceID] = invoice
+		}
+	}
+	// and ON DELETE SET NULL on the items credit notes voided
+	for noteID, note := range s.creditNotes {
+		for i, item := range note.Items {
+			if item.OrderItemID == id {
+				note.Items = slices.Clone(note.Items)
+				note.Items[i].OrderItemID = 0
+				s.creditNotes[noteID] = note
+			}
+		}
+	}
+}
+
+func (r *memoryOrderItemRepository) Bump(ctx context.Context, id uint) (models.OrderItem, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	item, ok := r.store.orderItems[id]
+	if !ok {
+		return models.OrderItem{}, ErrNotFound
+	}
+	bumpedAt := now()
+	item.PrepStatus, item.BumpedAt, item.UpdatedAt = models.PrepStatusDone, &bumpedAt, bumpedAt
+	r.store.orderItems[id] = item
+
+	// a combo is prepared once its last component is
+	if parent, ok := r.store.orderItems[item.ParentID]; ok {
+		prepared := true
+		for _, component := range r.store.orderItems {
+			prepared = prepared && (component.ParentID != parent.ID || component.PrepStatus == models.PrepStatusDone)
+		}
+		if prepared {
+			parent.PrepStatus, parent.BumpedAt, parent.UpdatedAt = models.PrepStatusDone, &bumpedAt, bumpedAt
+			r.store.orderItems[parent.ID] = parent
+		}
+	}
+	return r.store.itemWithFood(item), nil
+}
+
+func (r *memoryOrderItemRepository) SalesByFood(ctx context.Context, restaurantID uint, from, to time.Time) ([]models.FoodSales, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byFood := map[uint]*models.FoodSales{}
+	var sales []*models.FoodSales
+	for _, item := range sortedValues(r.store.orderItems) {
+		order := r.store.orders[item.OrderID]
+		food, ok := r.store.foods[item.FoodID]
+		if !ok || order.RestaurantID != restaurantID || order.Status != models.OrderStatusPaid ||
+			!from.IsZero() && order.OrderDate.Before(from) || !to.IsZero() && !order.OrderDate.Before(to) {
+			continue
+		}
+		summary, ok := byFood[food.ID]
+		if !ok {
+			summary = &models.FoodSales{FoodID: food.ID, Name: food.Name}
+			byFood[food.ID] = summary
+			sales = append(sales, summary)
+		}
+		summary.Quantity += item.Quantity
+		summary.Revenue = math.Round((summary.Revenue+item.SubTotal)*100) / 100
+		if item.ParentID != 0 {
+			summary.ComboQuantity += item.Quantity
+			summary.ComboRevenue = math.Round((summary.ComboRevenue+item.SubTotal)*100) / 100
+		}
+	}
+
+	sort.SliceStable(sales, func(i, j int) bool {
+		if sales[i].Revenue != sales[j].Revenue {
+			return sales[i].Revenue > sales[j].Revenue
+		}
+		return sales[i].FoodID < sales[j].FoodID
+	})
+	result := make([]models.FoodSales, len(sales))
+	for i, summary := range sales {
+		result[i] = *summary
+	}
+	return result, nil
+}
+
+func (r *memoryOrderItemRepository) StationQueue(ctx context.Context, restaurantID, stationID uint) ([]models.StationTicket, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tickets []models.StationTicket
+	for _, item := range sortedValues(r.store.orderItems) {
+		order := r.store.orders[item.OrderID]
+		if order.RestaurantID != restaurantID || order.Status != models.OrderStatusPreparing || item.PrepStatus != models.PrepStatusQueued || item.ComboID != 0 {
+			continue
+		}
+
+		ticket := r.ticket(item, order)
+		if stationID != 0 && ticket.StationID != stationID {
+			continue
+		}
+		tickets = append(tickets, ticket)
+	}
+	sort.SliceStable(tickets, func(i, j int) bool {
+		if !tickets[i].OrderDate.Equal(tickets[j].OrderDate) {
+			return tickets[i].OrderDate.Before(tickets[j].OrderDate)
+		}
+		return tickets[i].PrepTime > tickets[j].PrepTime
+	})
+	return tickets, nil
+}
+
+func (r *memoryOrderItemRepository) ListTicketsByOrder(ctx context.Context, orderID uint) ([]models.StationTicket, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tickets []models.StationTicket
+	for _, item := range sortedValues(r.store.orderItems) {
+		if item.OrderID == orderID && item.ComboID == 0 {
+			tickets = append(tickets, r.ticket(item, r.store.orders[orderID]))
+		}
+	}
+	return tickets, nil
+}
+
+// ticket routes an item to its food's station, or its menu's, callers must hold the lock
+func (r *memoryOrderItemRepository) ticket(item models.OrderItem, order models.Order) models.StationTicket {
+	food := r.store.foods[item.FoodID]
+	station := food.StationID
+	if station == 0 {
+		station = r.store.menus[food.MenuID].StationID
+	}
+	var modifiers []string
+	for _, modifier := range item.Modifiers {
+		modifiers = append(modifiers, modifier.Name)
+	}
+	return models.StationTicket{
+		OrderItemID: item.ID,
+		OrderID:     order.ID,
+		TableID:     order.TableID,
+		StationID:   station,
+		FoodID:      item.FoodID,
+		FoodName:    food.Name,
+		VariantName: item.VariantName,
+		ComboName:   r.store.combos[r.store.orderItems[item.ParentID].ComboID].Name,
+		Modifiers:   modifiers,
+		Allergens:   models.SharedAllergens(order.Allergens, food.Allergens),
+		Quantity:    item.Quantity,
+		PrepTime:    food.PrepTime,
+		PrepStatus:  item.PrepStatus,
+		OrderNotes:  order.Notes,
+		OrderDate:   order.OrderDate,
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"restaurant-management/models"
+)
+
+type memoryPaymentRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryPaymentRepository) ListByInvoice(ctx context.Context, invoiceID uint) ([]models.Payment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.invoicePayments(invoiceID), nil
+}
+
+// invoicePayments returns the payments taken against an invoice and its parts, callers must hold the lock
+func (s *memoryStore) invoicePayments(invoiceID uint) []models.Payment {
+	var payments []models.Payment
+	for _, payment := range sortedValues(s.payments) {
+		if payment.InvoiceID == invoiceID || s.invoices[payment.InvoiceID].ParentID == invoiceID {
+			payment.Refunded = s.refunded(payment.ID)
+			payments = append(payments, payment)
+		}
+	}
+	return payments
+}
+
+// refunded adds up the refunds of a payment that have not failed, callers must hold the lock
+func (s *memoryStore) refunded(paymentID uint) float64 {
+	var refunded int64
+	for _, refund := range s.refunds {
+		if refund.PaymentID == paymentID && refund.Status != models.RefundStatusFailed {
+			refunded += int64(math.Round(refund.Amount * 100))
+		}
+	}
+	return float64(refunded) / 100
+}
+
+func (r *memoryPaymentRepository) Create(ctx context.Context, payment models.Payment) (models.Payment, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.invoices[payment.InvoiceID]; !ok {
+		return models.Payment{}, ErrNotFound
+	}
+	// mirror the unique index on provider transaction IDs
+	for _, existing := range r.store.payments {
+		if payment.TransactionID != "" && existing.Provider == payment.Provider && existing.TransactionID == payment.TransactionID {
+			return models.Payment{}, ErrDuplicate
+		}
+	}
+	payment.ID = r.store.newID("payments")
+	payment.CreatedAt = now()
+	r.store.payments[payment.ID] = payment
+	return payment, nil
+}
+
+func (r *memoryPaymentRepository) TipsByStaff(ctx context.Context, restaurantID uint, from, to time.Time) ([]models.TipSummary, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byStaff := map[uint]*models.TipSummary{}
+	var staffIDs []uint
+	for _, payment := range sortedValues(r.store.payments) {
+		if payment.Tip <= 0 || r.store.invoices[payment.InvoiceID].RestaurantID != restaurantID ||
+			!from.IsZero() && payment.CreatedAt.Before(from) || !to.IsZero() && !payment.CreatedAt.Before(to) {
+			continue
+		}
+		var staffID uint // 0 stands for the NULL group
+		if payment.TipStaffID != nil {
+			staffID = *payment.TipStaffID
+		}
+		summary, ok := byStaff[staffID]
+		if !ok {
+			summary = &models.TipSummary{StaffID: payment.TipStaffID, Username: r.store.users[staffID].Username}
+			byStaff[staffID] = summary
+			staffIDs = append(staffIDs, staffID)
+		}
+		summary.Tips = math.Round((summary.Tips+payment.Tip)*100) / 100
+		summary.Payments++
+	}
+
+	// Postgres sorts the NULL group after every staff member on a tie
+	sort.Slice(staffIDs, func(i, j int) bool { return staffIDs[j] == 0 || staffIDs[i] != 0 && staffIDs[i] < staffIDs[j] })
+	tips := make([]models.TipSummary, 0, len(staffIDs))
+	for _, id := range staffIDs {
+		tips = append(tips, *byStaff[id])
+	}
+	sort.SliceStable(tips, func(i, j int) bool { return tips[i].Tips > tips[j].Tips })
+	return tips, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+type memoryPaymentIntentRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryPaymentIntentRepository) ListByInvoice(ctx context.Context, invoiceID uint) ([]models.PaymentIntent, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var intents []models.PaymentIntent
+	for _, intent := range sortedValues(r.store.paymentIntents) {
+		if intent.InvoiceID == invoiceID {
+			intents = append(intents, intent)
+		}
+	}
+	return intents, nil
+}
+
+func (r *memoryPaymentIntentRepository) Get(ctx context.Context, id uint) (models.PaymentIntent, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	intent, ok := r.store.paymentIntents[id]
+	if !ok {
+		return models.PaymentIntent{}, ErrNotFound
+	}
+	return intent, nil
+}
+
+func (r *memoryPaymentIntentRepository) GetByProviderID(ctx context.Context, provider, providerIntentID string) (models.PaymentIntent, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, intent := range r.store.paymentIntents {
+		if intent.Provider == provider && intent.ProviderIntentID == providerIntentID {
+			return intent, nil
+		}
+	}
+	return models.PaymentIntent{}, ErrNotFound
+}
+
+func (r *memoryPaymentIntentRepository) Create(ctx context.Context, intent models.PaymentIntent) (models.PaymentIntent, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.invoices[intent.InvoiceID]; !ok {
+		return models.PaymentIntent{}, ErrNotFound
+	}
+	// mirror UNIQUE (provider, provider_intent_id)
+	for _, existing := range r.store.paymentIntents {
+		if existing.Provider == intent.Provider && existing.ProviderIntentID == intent.ProviderIntentID {
+			return models.PaymentIntent{}, ErrDuplicate
+		}
+	}
+
+	intent.ID = r.store.newID("payment_intents")
+	intent.ClientSecret, intent.FailureReason, intent.PaymentID = "", "", 0
+	intent.CreatedAt, intent.UpdatedAt = now(), now()
+	r.store.paymentIntents[intent.ID] = intent
+	return intent, nil
+}
+
+func (r *memoryPaymentIntentRepository) UpdateStatus(ctx context.Context, id uint, status, failureReason string, paymentID uint) (models.PaymentIntent, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	intent, ok := r.store.paymentIntents[id]
+	if !ok {
+		return models.PaymentIntent{}, ErrNotFound
+	}
+	intent.Status, intent.FailureReason, intent.PaymentID, intent.UpdatedAt = status, failureReason, paymentID, now()
+	r.store.paymentIntents[id] = intent
+	return intent, nil
+}
+
+type memoryWebhookEventRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryWebhookEventRepository) Record(ctx context.Context, event models.WebhookEvent) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := event.Provider + "/" + event.EventID
+	if _, ok := r.store.webhookEvents[key]; ok {
+		return false, nil
+	}
+	event.ReceivedAt = now()
+	r.store.webhookEvents[key] = event
+	return true, nil
+}
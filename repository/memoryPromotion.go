@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"restaurant-management/models"
+)
+
+type memoryPromotionRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryPromotionRepository) List(ctx context.Context, restaurantID uint) ([]models.Promotion, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var promotions []models.Promotion
+	for _, promotion := range sortedValues(r.store.promotions) {
+		if promotion.RestaurantID == restaurantID {
+			promotions = append(promotions, promotion)
+		}
+	}
+	sort.SliceStable(promotions, func(i, j int) bool { return promotions[i].ID > promotions[j].ID })
+	return promotions, nil
+}
+
+func (r *memoryPromotionRepository) Get(ctx context.Context, id uint) (models.Promotion, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	promotion, ok := r.store.promotions[id]
+	if !ok {
+		return models.Promotion{}, ErrNotFound
+	}
+	return promotion, nil
+}
+
+// GetForUpdate needs no row lock, memory units of work already run one at a time
+func (r *memoryPromotionRepository) GetForUpdate(ctx context.Context, id uint) (models.Promotion, error) {
+	return r.Get(ctx, id)
+}
+
+func (r *memoryPromotionRepository) GetByCodeForUpdate(ctx context.Context, restaurantID uint, code string) (models.Promotion, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, promotion := range r.store.promotions {
+		if promotion.RestaurantID == restaurantID && promotion.Code != "" && promotion.Code == code {
+			return promotion, nil
+		}
+	}
+	return models.Promotion{}, ErrNotFound
+}
+
+// codeTaken mirrors UNIQUE (restaurant_id, code), callers must hold the lock
+func (r *memoryPromotionRepository) codeTaken(promotion models.Promotion) bool {
+	if promotion.Code == "" {
+		return false
+	}
+	for _, existing := range r.store.promotions {
+		if existing.ID != promotion.ID && existing.RestaurantID == promotion.RestaurantID && existing.Code == promotion.Code {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryPromotionRepository) Create(ctx context.Context, promotion models.Promotion) (models.Promotion, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	promotion.ID = 0
+	if r.codeTaken(promotion) {
+		return models.Promotion{}, ErrDuplicate
+	}
+
+	promotion.ID = r.store.newID("promotions")
+	promotion.TimesUsed = 0
+	promotion.CreatedAt, promotion.UpdatedAt = now(), now()
+	r.store.promotions[promotion.ID] = promotion
+	return promotion, nil
+}
+
+func (r *memoryPromotionRepository) Update(ctx context.Context, promotion models.Promotion) (models.Promotion, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.promotions[promotion.ID]
+	if !ok {
+		return models.Promotion{}, ErrNotFound
+	}
+	promotion.RestaurantID, promotion.TimesUsed = existing.RestaurantID, existing.TimesUsed
+	if r.codeTaken(promotion) {
+		return models.Promotion{}, ErrDuplicate
+	}
+	promotion.CreatedAt, promotion.UpdatedAt = existing.CreatedAt, now()
+	r.store.promotions[promotion.ID] = promotion
+	return promotion, nil
+}
+
+func (r *memoryPromotionRepository) AddUsage(ctx context.Context, id uint, delta int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	promotion, ok := r.store.promotions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	promotion.TimesUsed = max(promotion.TimesUsed+delta, 0)
+	r.store.promotions[id] = promotion
+	return nil
+}
+
+func (r *memoryPromotionRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.promotions[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.promotions, id)
+	// mirror ON DELETE SET NULL
+	for discountID, discount := range r.store.discounts {
+		if discount.PromotionID == id {
+			discount.PromotionID = 0
+			r.store.discounts[discountID] = discount
+		}
+	}
+	return nil
+}
+
+type memoryDiscountRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryDiscountRepository) ListByOrder(ctx context.Context, orderID uint) ([]models.OrderDiscount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var discounts []models.OrderDiscount
+	for _, discount := range sortedValues(r.store.discounts) {
+		if discount.OrderID == orderID {
+			discounts = append(discounts, discount)
+		}
+	}
+	return discounts, nil
+}
+
+func (r *memoryDiscountRepository) Get(ctx context.Context, id uint) (models.OrderDiscount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	discount, ok := r.store.discounts[id]
+	if !ok {
+		return models.OrderDiscount{}, ErrNotFound
+	}
+	return discount, nil
+}
+
+func (r *memoryDiscountRepository) Create(ctx context.Context, discount models.OrderDiscount) (models.OrderDiscount, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// mirror UNIQUE (order_id, promotion_id), manual discounts have no promotion
+	for _, existing := range r.store.discounts {
+		if discount.PromotionID != 0 && existing.OrderID == discount.OrderID && existing.PromotionID == discount.PromotionID {
+			return models.OrderDiscount{}, ErrDuplicate
+		}
+	}
+
+	discount.ID = r.store.newID("order_discounts")
+	discount.Amount = 0
+	discount.CreatedAt, discount.UpdatedAt = now(), now()
+	r.store.discounts[discount.ID] = discount
+	return discount, nil
+}
+
+func (r *memoryDiscountRepository) Approve(ctx context.Context, id uint, approvedBy *uint) (models.OrderDiscount, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	discount, ok := r.store.discounts[id]
+	if !ok {
+		return models.OrderDiscount{}, ErrNotFound
+	}
+	discount.Status, discount.ApprovedBy, discount.UpdatedAt = models.DiscountStatusApproved, approvedBy, now()
+	r.store.discounts[id] = discount
+	return discount, nil
+}
+
+func (r *memoryDiscountRepository) UpdateAmount(ctx context.Context, id uint, amount float64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	discount, ok := r.store.discounts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	discount.Amount, discount.UpdatedAt = amount, now()
+	r.store.discounts[id] = discount
+	return nil
+}
+
+func (r *memoryDiscountRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.discounts[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.discounts, id)
+	return nil
+}
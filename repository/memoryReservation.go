@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"restaurant-management/models"
+)
+
+type memoryReservationRepository struct {
+	store *memoryStore
+}
+
+// holdsTable reports whether the reservation claims its table during [start, end)
+func holdsTable(reservation models.Reservation, start, end time.Time) bool {
+	if reservation.Status != models.ReservationStatusBooked && reservation.Status != models.ReservationStatusSeated {
+		return false
+	}
+	return reservation.StartsAt.Before(end) && reservation.EndsAt().After(start)
+}
+
+// sortedReservations orders reservations by slot like the Postgres queries, callers must hold the lock
+func (r *memoryReservationRepository) sortedReservations(keep func(models.Reservation) bool) []models.Reservation {
+	var reservations []models.Reservation
+	for _, reservation := range sortedValues(r.store.reservations) {
+		if keep(reservation) {
+			reservations = append(reservations, reservation)
+		}
+	}
+	sort.SliceStable(reservations, func(i, j int) bool { return reservations[i].StartsAt.Before(reservations[j].StartsAt) })
+	return reservations
+}
+
+func (r *memoryReservationRepository) List(ctx context.Context, restaurantID uint, from, to time.Time) ([]models.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.sortedReservations(func(reservation models.Reservation) bool {
+		return reservation.RestaurantID == restaurantID &&
+			(from.IsZero() || !reservation.StartsAt.Before(from)) &&
+			(to.IsZero() || reservation.StartsAt.Before(to))
+	}), nil
+}
+
+func (r *memoryReservationRepository) Get(ctx context.Context, id uint) (models.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	reservation, ok := r.store.reservations[id]
+	if !ok {
+		return models.Reservation{}, ErrNotFound
+	}
+	return reservation, nil
+}
+
+func (r *memoryReservationRepository) Create(ctx context.Context, reservation models.Reservation) (models.Reservation, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	reservation.ID = r.store.newID("reservations")
+	reservation.CreatedAt, reservation.UpdatedAt = now(), now()
+	r.store.reservations[reservation.ID] = reservation
+	return reservation, nil
+}
+
+func (r *memoryReservationRepository) Update(ctx context.Context, reservation models.Reservation) (models.Reservation, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.reservations[reservation.ID]
+	if !ok {
+		return models.Reservation{}, ErrNotFound
+	}
+	reservation.RestaurantID, reservation.Status = existing.RestaurantID, existing.Status
+	reservation.CreatedAt, reservation.UpdatedAt = existing.CreatedAt, now()
+	r.store.reservations[reservation.ID] = reservation
+	return reservation, nil
+}
+
+func (r *memoryReservationRepository) UpdateStatus(ctx context.Context, id uint, status string) (models.Reservation, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	reservation, ok := r.store.reservations[id]
+	if !ok {
+		return models.Reservation{}, ErrNotFound
+	}
+	reservation.Status, reservation.UpdatedAt = status, now()
+	r.store.reservations[id] = reservation
+	return reservation, nil
+}
+
+func (r *memoryReservationRepository) Overlapping(ctx context.Context, tableID uint, start, end time.Time, excludeID uint) ([]models.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.sortedReservations(func(reservation models.Reservation) bool {
+		return reservation.TableID == tableID && reservation.ID != excludeID && holdsTable(reservation, start, end)
+	}), nil
+}
+
+func (r *memoryReservationRepository) AvailableTables(ctx context.Context, restaurantID uint, partySize int, start, end time.Time) ([]models.Table, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tables []models.Table
+	for _, table := range sortedValues(r.store.tables) {
+		if uint(table.RestaurantID) != restaurantID || table.Capacity < partySize {
+			continue
+		}
+		free := true
+		for _, reservation := range r.store.reservations {
+			if reservation.TableID == table.ID && holdsTable(reservation, start, end) {
+				free = false
+				break
+			}
+		}
+		if free {
+			tables = append(tables, table)
+		}
+	}
+	sort.SliceStable(tables, func(i, j int) bool { return tables[i].Capacity < tables[j].Capacity })
+	return tables, nil
+}
+
+func (r *memoryReservationRepository) BookedBefore(ctx context.Context, before time.Time) ([]models.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.sortedReservations(func(reservation models.Reservation) bool {
+		return reservation.Status == models.ReservationStatusBooked && reservation.StartsAt.Before(before)
+	}), nil
+}
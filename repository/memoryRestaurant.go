@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+type memoryRestaurantRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryRestaurantRepository) List(ctx context.Context) ([]models.Restaurant, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return sortedValues(r.store.restaurants), nil
+}
+
+func (r *memoryRestaurantRepository) ListByOwner(ctx context.Context, ownerID uint) ([]models.Restaurant, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var restaurants []models.Restaurant
+	for _, restaurant := range sortedValues(r.store.restaurants) {
+		if restaurant.OwnerID == ownerID {
+			restaurants = append(restaurants, restaurant)
+		}
+	}
+	return restaurants, nil
+}
+
+func (r *memoryRestaurantRepository) Get(ctx context.Context, id uint) (models.Restaurant, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	restaurant, ok := r.store.restaurants[id]
+	if !ok {
+		return models.Restaurant{}, ErrNotFound
+	}
+	return restaurant, nil
+}
+
+func (r *memoryRestaurantRepository) Create(ctx context.Context, restaurant models.Restaurant) (models.Restaurant, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	restaurant.ID = r.store.newID("restaurants")
+	if restaurant.TimeZone == "" {
+		restaurant.TimeZone = "UTC"
+	}
+	restaurant.CreatedAt, restaurant.UpdatedAt = now(), now()
+	r.store.restaurants[restaurant.ID] = restaurant
+	return restaurant, nil
+}
+
+func (r *memoryRestaurantRepository) Update(ctx context.Context, restaurant models.Restaurant) (models.Restaurant, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.restaurants[restaurant.ID]
+	if !ok {
+		return models.Restaurant{}, ErrNotFound
+	}
+	restaurant.CreatedAt, restaurant.UpdatedAt = existing.CreatedAt, now()
+	if restaurant.TimeZone == "" {
+		restaurant.TimeZone = "UTC"
+	}
+	r.store.restaurants[restaurant.ID] = restaurant
+	return restaurant, nil
+}
+
+func (r *memoryRestaurantRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.restaurants[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.restaurants, id)
+	for staffID, member := range r.store.staff {
+		if member.RestaurantID == id {
+			delete(r.store.staff, staffID)
+		}
+	}
+	for stationID, station := range r.store.stations {
+		if station.RestaurantID == id {
+			delete(r.store.stations, stationID)
+		}
+	}
+	for reservationID, reservation := range r.store.reservations {
+		if reservation.RestaurantID == id {
+			delete(r.store.reservations, reservationID)
+		}
+	}
+	for entryID, entry := range r.store.waitlist {
+		if entry.RestaurantID == id {
+			delete(r.store.waitlist, entryID)
+		}
+	}
+	for rateID, rate := range r.store.taxRates {
+		if rate.RestaurantID == id {
+			delete(r.store.taxRates, rateID)
+		}
+	}
+	for promotionID, promotion := range r.store.promotions {
+		if promotion.RestaurantID == id {
+			delete(r.store.promotions, promotionID)
+		}
+	}
+	for comboID, combo := range r.store.combos {
+		if combo.RestaurantID == id {
+			delete(r.store.combos, comboID)
+		}
+	}
+	for key, series := range r.store.numberSeries {
+		if series.RestaurantID == id {
+			delete(r.store.numberSeries, key)
+		}
+	}
+	delete(r.store.templates, id)
+	return nil
+}
+
+func (r *memoryRestaurantRepository) ListStaff(ctx context.Context, restaurantID uint) ([]models.RestaurantStaff, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var staff []models.RestaurantStaff
+	for _, member := range sortedValues(r.store.staff) {
+		if member.RestaurantID == restaurantID {
+			staff = append(staff, member)
+		}
+	}
+	return staff, nil
+}
+
+func (r *memoryRestaurantRepository) UpsertStaff(ctx context.Context, member models.RestaurantStaff) (models.RestaurantStaff, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, existing := range r.store.staff {
+		if existing.RestaurantID == member.RestaurantID && existing.UserID == member.UserID {
+			existing.Role, existing.UpdatedAt = member.Role, now()
+			r.store.staff[id] = existing
+			return existing, nil
+		}
+	}
+
+	member.ID = r.store.newID("restaurant_staff")
+	member.CreatedAt, member.UpdatedAt = now(), now()
+	r.store.staff[member.ID] = member
+	return member, nil
+}
+
+func (r *memoryRestaurantRepository) RemoveStaff(ctx context.Context, restaurantID, userID uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, member := range r.store.staff {
+		if member.RestaurantID == restaurantID && member.UserID == userID {
+			delete(r.store.staff, id)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryRestaurantRepository) Memberships(ctx context.Context, userID uint) ([]models.Membership, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var memberships []models.Membership
+	owned := map[uint]bool{}
+	for _, restaurant := range sortedValues(r.store.restaurants) {
+		if restaurant.OwnerID == userID {
+			owned[restaurant.ID] = true
+			memberships = append(memberships, models.Membership{RestaurantID: restaurant.ID, Role: models.MembershipOwner})
+		}
+	}
+	for _, member := range sortedValues(r.store.staff) {
+		if member.UserID == userID && !owned[member.RestaurantID] {
+			memberships = append(memberships, models.Membership{RestaurantID: member.RestaurantID, Role: member.Role})
+		}
+	}
+	return memberships, nil
+}
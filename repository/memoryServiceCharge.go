@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+type memoryServiceChargeRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryServiceChargeRepository) List(ctx context.Context, restaurantID uint) ([]models.ServiceCharge, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var charges []models.ServiceCharge
+	for _, charge := range sortedValues(r.store.serviceCharges) {
+		if charge.RestaurantID == restaurantID {
+			charges = append(charges, charge)
+		}
+	}
+	return charges, nil
+}
+
+func (r *memoryServiceChargeRepository) Get(ctx context.Context, id uint) (models.ServiceCharge, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	charge, ok := r.store.serviceCharges[id]
+	if !ok {
+		return models.ServiceCharge{}, ErrNotFound
+	}
+	return charge, nil
+}
+
+// nameTaken mirrors UNIQUE (restaurant_id, name), callers must hold the lock
+func (r *memoryServiceChargeRepository) nameTaken(charge models.ServiceCharge) bool {
+	for _, existing := range r.store.serviceCharges {
+		if existing.ID != charge.ID && existing.RestaurantID == charge.RestaurantID && existing.Name == charge.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryServiceChargeRepository) Create(ctx context.Context, charge models.ServiceCharge) (models.ServiceCharge, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	charge.ID = 0
+	if r.nameTaken(charge) {
+		return models.ServiceCharge{}, ErrDuplicate
+	}
+
+	charge.ID = r.store.newID("service_charges")
+	charge.CreatedAt, charge.UpdatedAt = now(), now()
+	r.store.serviceCharges[charge.ID] = charge
+	return charge, nil
+}
+
+func (r *memoryServiceChargeRepository) Update(ctx context.Context, charge models.ServiceCharge) (models.ServiceCharge, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.serviceCharges[charge.ID]
+	if !ok {
+		return models.ServiceCharge{}, ErrNotFound
+	}
+	charge.RestaurantID = existing.RestaurantID
+	if r.nameTaken(charge) {
+		return models.ServiceCharge{}, ErrDuplicate
+	}
+	charge.CreatedAt, charge.UpdatedAt = existing.CreatedAt, now()
+	r.store.serviceCharges[charge.ID] = charge
+	return charge, nil
+}
+
+func (r *memoryServiceChargeRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.serviceCharges[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.serviceCharges, id)
+	// mirror ON DELETE SET NULL on the invoice breakdowns
+	for invoiceID, invoice := range r.store.invoices {
+		changed := false
+		charges := append([]models.InvoiceServiceCharge{}, invoice.ServiceCharges...)
+		for i := range charges {
+			if charges[i].ServiceChargeID == id {
+				charges[i].ServiceChargeID, changed = 0, true
+			}
+		}
+		if changed {
+			invoice.ServiceCharges = charges
+			r.store.invoices[invoiceID] = invoice
+		}
+	}
+	return nil
+}
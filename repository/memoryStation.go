@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"restaurant-management/models"
+)
+
+type memoryStationRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryStationRepository) List(ctx context.Context, restaurantID uint) ([]models.Station, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var stations []models.Station
+	for _, station := range sortedValues(r.store.stations) {
+		if station.RestaurantID == restaurantID {
+			stations = append(stations, station)
+		}
+	}
+	sort.SliceStable(stations, func(i, j int) bool { return stations[i].Name < stations[j].Name })
+	return stations, nil
+}
+
+func (r *memoryStationRepository) Get(ctx context.Context, id uint) (models.Station, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	station, ok := r.store.stations[id]
+	if !ok {
+		return models.Station{}, ErrNotFound
+	}
+	return station, nil
+}
+
+// nameTaken mirrors UNIQUE (restaurant_id, name), callers must hold the lock
+func (r *memoryStationRepository) nameTaken(station models.Station) bool {
+	for _, existing := range r.store.stations {
+		if existing.ID != station.ID && existing.RestaurantID == station.RestaurantID && existing.Name == station.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryStationRepository) Create(ctx context.Context, station models.Station) (models.Station, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	station.ID = 0
+	if r.nameTaken(station) {
+		return models.Station{}, ErrDuplicate
+	}
+
+	station.ID = r.store.newID("stations")
+	station.CreatedAt, station.UpdatedAt = now(), now()
+	r.store.stations[station.ID] = station
+	return station, nil
+}
+
+func (r *memoryStationRepository) Update(ctx context.Context, station models.Station) (models.Station, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.stations[station.ID]
+	if !ok {
+		return models.Station{}, ErrNotFound
+	}
+	station.RestaurantID = existing.RestaurantID
+	if r.nameTaken(station) {
+		return models.Station{}, ErrDuplicate
+	}
+	existing.Name, existing.PrinterAddress, existing.UpdatedAt = station.Name, station.PrinterAddress, now()
+	r.store.stations[station.ID] = existing
+	return existing, nil
+}
+
+func (r *memoryStationRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.stations[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.stations, id)
+	// mirror ON DELETE SET NULL
+	for foodID, food := range r.store.foods {
+		if food.StationID == id {
+			food.StationID = 0
+			r.store.foods[foodID] = food
+		}
+	}
+	for menuID, menu := range r.store.menus {
+		if menu.StationID == id {
+			menu.StationID = 0
+			r.store.menus[menuID] = menu
+		}
+	}
+	return nil
+}
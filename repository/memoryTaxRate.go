@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"restaurant-management/models"
+)
+
+type memoryTaxRateRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryTaxRateRepository) List(ctx context.Context, restaurantID uint) ([]models.TaxRate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rates []models.TaxRate
+	for _, rate := range sortedValues(r.store.taxRates) {
+		if rate.RestaurantID == restaurantID {
+			rates = append(rates, rate)
+		}
+	}
+	sort.SliceStable(rates, func(i, j int) bool { return rates[i].Category < rates[j].Category })
+	return rates, nil
+}
+
+func (r *memoryTaxRateRepository) Get(ctx context.Context, id uint) (models.TaxRate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rate, ok := r.store.taxRates[id]
+	if !ok {
+		return models.TaxRate{}, ErrNotFound
+	}
+	return rate, nil
+}
+
+// nameTaken mirrors UNIQUE (restaurant_id, category, name), callers must hold the lock
+func (r *memoryTaxRateRepository) nameTaken(rate models.TaxRate) bool {
+	for _, existing := range r.store.taxRates {
+		if existing.ID != rate.ID && existing.RestaurantID == rate.RestaurantID && existing.Category == rate.Category && existing.Name == rate.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryTaxRateRepository) Create(ctx context.Context, rate models.TaxRate) (models.TaxRate, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rate.ID = 0
+	if r.nameTaken(rate) {
+		return models.TaxRate{}, ErrDuplicate
+	}
+
+	rate.ID = r.store.newID("tax_rates")
+	rate.CreatedAt, rate.UpdatedAt = now(), now()
+	r.store.taxRates[rate.ID] = rate
+	return rate, nil
+}
+
+func (r *memoryTaxRateRepository) Update(ctx context.Context, rate models.TaxRate) (models.TaxRate, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.taxRates[rate.ID]
+	if !ok {
+		return models.TaxRate{}, ErrNotFound
+	}
+	rate.RestaurantID = existing.RestaurantID
+	if r.nameTaken(rate) {
+		return models.TaxRate{}, ErrDuplicate
+	}
+	rate.CreatedAt, rate.UpdatedAt = existing.CreatedAt, now()
+	r.store.taxRates[rate.ID] = rate
+	return rate, nil
+}
+
+func (r *memoryTaxRateRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.taxRates[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.taxRates, id)
+	// mirror ON DELETE SET NULL on the invoice breakdowns
+	for invoiceID, invoice := range r.store.invoices {
+		changed := false
+		taxes := append([]models.InvoiceTax{}, invoice.Taxes...)
+		for i := range taxes {
+			if taxes[i].TaxRateID == id {
+				taxes[i].TaxRateID, changed = 0, true
+			}
+		}
+		if changed {
+			invoice.Taxes = taxes
+			r.store.invoices[invoiceID] = invoice
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+type memoryUserRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryUserRepository) List(ctx context.Context) ([]models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return sortedValues(r.store.users), nil
+}
+
+func (r *memoryUserRepository) Get(ctx context.Context, id uint) (models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *memoryUserRepository) FindByIdentifier(ctx context.Context, identifier string) (models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range sortedValues(r.store.users) {
+		if user.Username == identifier || user.Email == identifier || user.Phone == identifier {
+			return user, nil
+		}
+	}
+	return models.User{}, ErrNotFound
+}
+
+func (r *memoryUserRepository) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range sortedValues(r.store.users) {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return models.User{}, ErrNotFound
+}
+
+func (r *memoryUserRepository) Exists(ctx context.Context, username, email, phone string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.Username == username || user.Email == email || user.Phone == phone {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user models.User) (models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user.ID = r.store.newID("users")
+	user.CreatedAt, user.UpdatedAt = now(), now()
+	r.store.users[user.ID] = user
+	return user, nil
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, user models.User) (models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.users[user.ID]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	user.CreatedAt, user.UpdatedAt = existing.CreatedAt, now()
+	r.store.users[user.ID] = user
+	return user, nil
+}
+
+func (r *memoryUserRepository) UpdateToken(ctx context.Context, id uint, token string) (models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	user.Token = token
+	r.store.users[id] = user
+	return user, nil
+}
+
+func (r *memoryUserRepository) UpdateRole(ctx context.Context, id uint, role string) (models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	user.Role, user.UpdatedAt = role, now()
+	r.store.users[id] = user
+	return user, nil
+}
+
+func (r *memoryUserRepository) UpdatePasswordByEmail(ctx context.Context, email, hashedPassword string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, user := range r.store.users {
+		if user.Email == email {
+			user.Password, user.UpdatedAt = hashedPassword, now()
+			r.store.users[id] = user
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.users, id)
+	return nil
+}
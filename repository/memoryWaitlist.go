@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+type memoryWaitlistRepository struct {
+	store *memoryStore
+}
+
+func (r *memoryWaitlistRepository) ListActive(ctx context.Context, restaurantID uint) ([]models.WaitlistEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// IDs grow with created_at, so ID order is queue order
+	var entries []models.WaitlistEntry
+	for _, entry := range sortedValues(r.store.waitlist) {
+		if entry.RestaurantID == restaurantID && (entry.Status == models.WaitlistStatusWaiting || entry.Status == models.WaitlistStatusNotified) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *memoryWaitlistRepository) Get(ctx context.Context, id uint) (models.WaitlistEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	entry, ok := r.store.waitlist[id]
+	if !ok {
+		return models.WaitlistEntry{}, ErrNotFound
+	}
+	return entry, nil
+}
+
+func (r *memoryWaitlistRepository) Create(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	entry.ID = r.store.newID("waitlist_entries")
+	entry.TableID, entry.OrderID, entry.NotifiedAt, entry.SeatedAt = 0, 0, nil, nil
+	entry.CreatedAt, entry.UpdatedAt = now(), now()
+	r.store.waitlist[entry.ID] = entry
+	return entry, nil
+}
+
+func (r *memoryWaitlistRepository) Update(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.waitlist[entry.ID]
+	if !ok {
+		return models.WaitlistEntry{}, ErrNotFound
+	}
+	existing.Status, existing.TableID, existing.OrderID, existing.Notes = entry.Status, entry.TableID, entry.OrderID, entry.Notes
+	existing.NotifiedAt, existing.SeatedAt, existing.UpdatedAt = entry.NotifiedAt, entry.SeatedAt, now()
+	r.store.waitlist[entry.ID] = existing
+	return existing, nil
+}
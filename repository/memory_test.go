@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"restaurant-management/models"
+)
+
+func TestMemoryOrdersListNewestFirst(t *testing.T) {
+	ctx := context.Background()
+	repos, _ := NewMemory()
+
+	older, err := repos.Orders.Create(ctx, models.Order{TableID: 1, RestaurantID: 1, OrderDate: time.Now().Add(-time.Hour), Status: "pending"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	newer, err := repos.Orders.Create(ctx, models.Order{TableID: 2, RestaurantID: 1, OrderDate: time.Now(), Status: "pending"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := repos.Orders.Create(ctx, models.Order{TableID: 3, RestaurantID: 2, OrderDate: time.Now(), Status: "pending"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	orders, err := repos.Orders.List(ctx, 1)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(orders) != 2 || orders[0].ID != newer.ID || orders[1].ID != older.ID {
+		t.Errorf("List gave %+v, want orders %d then %d", orders, newer.ID, older.ID)
+	}
+}
+
+func TestMemoryOrderDeleteCascades(t *testing.T) {
+	ctx := context.Background()
+	repos, _ := NewMemory()
+
+	order, err := repos.Orders.Create(ctx, models.Order{TableID: 1, RestaurantID: 1, OrderDate: time.Now(), Status: "preparing"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	item, err := repos.OrderItems.Create(ctx, models.OrderItem{OrderID: order.ID, FoodID: 1, Quantity: 1, UnitPrice: 5, SubTotal: 5})
+	if err != nil {
+		t.Fatalf("creating the item: %v", err)
+	}
+	if _, err := repos.Invoices.UpsertForOrder(ctx, models.Invoice{OrderID: order.ID, RestaurantID: 1, Amount: 5, Total: 5, Status: "pending", PaymentMethod: "cash"}); err != nil {
+		t.Fatalf("creating the invoice: %v", err)
+	}
+
+	if err := repos.Orders.Delete(ctx, order.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repos.Orders.Get(ctx, order.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("the deleted order is still there: %v", err)
+	}
+	if _, err := repos.OrderItems.Get(ctx, item.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("the deleted order's item is still there: %v", err)
+	}
+	if _, err := repos.Invoices.GetByOrder(ctx, order.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("the deleted order's invoice is still there: %v", err)
+	}
+	if err := repos.Orders.Delete(ctx, order.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleting it again gave %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"restaurant-management/models"
+)
+
+// availabilityTable holds the schedules of the menus or foods in owners, keyed by their ID in key
+type availabilityTable struct {
+	name, key, owners string
+}
+
+var (
+	menuAvailability = availabilityTable{name: "menu_availability", key: "menu_id", owners: "menus"}
+	foodAvailability = availabilityTable{name: "food_availability", key: "food_id", owners: "foods"}
+)
+
+// load reads the schedules of the menus or foods matched by where, keyed by their ID
+func (t availabilityTable) load(ctx context.Context, db DBTX, where string, arg uint) (map[uint]models.Schedule, error) {
+	query := "SELECT " + t.key + `, days, COALESCE(to_char(start_time, 'HH24:MI'), ''), COALESCE(to_char(end_time, 'HH24:MI'), ''),
+		COALESCE(to_char(start_date, 'YYYY-MM-DD'), ''), COALESCE(to_char(end_date, 'YYYY-MM-DD'), '')
+		FROM ` + t.name + " WHERE " + where + " ORDER BY " + t.key + " ASC, position ASC"
+	rows, err := db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := map[uint]models.Schedule{}
+	for rows.Next() {
+		var id uint
+		var days string
+		var window models.AvailabilityWindow
+		if err := rows.Scan(&id, &days, &window.StartTime, &window.EndTime, &window.StartDate, &window.EndDate); err != nil {
+			return nil, err
+		}
+		if days != "" {
+			window.Days = strings.Split(days, ",")
+		}
+		schedules[id] = append(schedules[id], window)
+	}
+	return schedules, rows.Err()
+}
+
+// list loads the schedules of a restaurant's menus or foods, of every restaurant's for 0
+func (t availabilityTable) list(ctx context.Context, db DBTX, restaurantID uint) (map[uint]models.Schedule, error) {
+	return t.load(ctx, db, t.key+" IN (SELECT id FROM "+t.owners+" WHERE ($1 = 0 OR restaurant_id = $1))", restaurantID)
+}
+
+func (t availabilityTable) get(ctx context.Context, db DBTX, id uint) (models.Schedule, error) {
+	schedules, err := t.load(ctx, db, t.key+" = $1", id)
+	return schedules[id], err
+}
+
+// save replaces the schedule of a menu or food
+func (t availabilityTable) save(ctx context.Context, db DBTX, id uint, schedule models.Schedule) error {
+	if _, err := db.ExecContext(ctx, "DELETE FROM "+t.name+" WHERE "+t.key+" = $1", id); err != nil {
+		return err
+	}
+	query := "INSERT INTO " + t.name + " (" + t.key + `, position, days, start_time, end_time, start_date, end_date)
+		VALUES ($1, $2, $3, NULLIF($4, '')::time, NULLIF($5, '')::time, NULLIF($6, '')::date, NULLIF($7, '')::date)`
+	for i, window := range schedule {
+		_, err := db.ExecContext(ctx, query, id, i+1, strings.Join(window.Days, ","), window.StartTime, window.EndTime, window.StartDate, window.EndDate)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+const comboColumns = `id, restaurant_id, name, description, image_url, price, tax_category, available, created_at, updated_at`
+
+func scanCombo(row scanner) (models.Combo, error) {
+	var combo models.Combo
+	err := row.Scan(&combo.ID, &combo.RestaurantID, &combo.Name, &combo.Description, &combo.ImageURL, &combo.Price,
+		&combo.TaxCategory, &combo.Available, &combo.CreatedAt, &combo.UpdatedAt)
+	return combo, err
+}
+
+type postgresComboRepository struct {
+	db DBTX
+}
+
+func (r *postgresComboRepository) List(ctx context.Context, restaurantID uint) ([]models.Combo, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+comboColumns+" FROM combos WHERE restaurant_id = $1 ORDER BY id ASC", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var combos []models.Combo
+	for rows.Next() {
+		combo, err := scanCombo(rows)
+		if err != nil {
+			return nil, err
+		}
+		combos = append(combos, combo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slots, err := r.loadSlots(ctx, "s.combo_id IN (SELECT id FROM combos WHERE restaurant_id = $1)", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range combos {
+		combos[i].Slots = slots[combos[i].ID]
+	}
+	return combos, nil
+}
+
+func (r *postgresComboRepository) Get(ctx context.Context, id uint) (models.Combo, error) {
+	combo, err := scanCombo(r.db.QueryRowContext(ctx, "SELECT "+comboColumns+" FROM combos WHERE id = $1", id))
+	if err != nil {
+		return combo, notFound(err)
+	}
+	slots, err := r.loadSlots(ctx, "s.combo_id = $1", id)
+	combo.Slots = slots[id]
+	return combo, err
+}
+
+func (r *postgresComboRepository) Create(ctx context.Context, combo models.Combo) (models.Combo, error) {
+	query := `
+		INSERT INTO combos (restaurant_id, name, description, image_url, price, tax_category, available)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + comboColumns
+	created, err := scanCombo(r.db.QueryRowContext(ctx, query, combo.RestaurantID, combo.Name, combo.Description, combo.ImageURL,
+		combo.Price, combo.TaxCategory, combo.Available))
+	if err != nil {
+		return created, err
+	}
+	created.Slots, err = r.saveSlots(ctx, created.ID, combo.Slots)
+	return created, err
+}
+
+func (r *postgresComboRepository) Update(ctx context.Context, combo models.Combo) (models.Combo, error) {
+	query := `
+		UPDATE combos
+		SET name = $1, description = $2, image_url = $3, price = $4, tax_category = $5, available = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $7
+		RETURNING ` + comboColumns
+	updated, err := scanCombo(r.db.QueryRowContext(ctx, query, combo.Name, combo.Description, combo.ImageURL, combo.Price,
+		combo.TaxCategory, combo.Available, combo.ID))
+	if err != nil {
+		return updated, notFound(err)
+	}
+	updated.Slots, err = r.saveSlots(ctx, updated.ID, combo.Slots)
+	return updated, err
+}
+
+func (r *postgresComboRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM combos WHERE id = $1", id))
+}
+
+// loadSlots reads the slots and choices of the combos matched by where, a condition on the
+// slots s with one argument, keyed by combo ID
+func (r *postgresComboRepository) loadSlots(ctx context.Context, where string, arg uint) (map[uint][]models.ComboSlot, error) {
+	choices := map[uint][]models.ComboChoice{}
+	query := `
+		SELECT c.slot_id, c.food_id, c.price_delta
+		FROM combo_slot_choices c JOIN combo_slots s ON s.id = c.slot_id
+		WHERE ` + where + `
+		ORDER BY c.slot_id ASC, c.position ASC`
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var slotID uint
+		var choice models.ComboChoice
+		if err := rows.Scan(&slotID, &choice.FoodID, &choice.PriceDelta); err != nil {
+			return nil, err
+		}
+		choices[slotID] = append(choices[slotID], choice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = "SELECT s.combo_id, s.id, s.name, s.quantity, s.food_id FROM combo_slots s WHERE " + where + " ORDER BY s.combo_id ASC, s.position ASC"
+	slotRows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer slotRows.Close()
+
+	slots := map[uint][]models.ComboSlot{}
+	for slotRows.Next() {
+		var comboID uint
+		var slot models.ComboSlot
+		if err := slotRows.Scan(&comboID, &slot.ID, &slot.Name, &slot.Quantity, &slot.FoodID); err != nil {
+			return nil, err
+		}
+		slot.Choices = choices[slot.ID]
+		slots[comboID] = append(slots[comboID], slot)
+	}
+	return slots, slotRows.Err()
+}
+
+// saveSlots replaces the slots of a combo. Ordered combos name their slots on their
+// components, so slots don't need to keep their IDs.
+func (r *postgresComboRepository) saveSlots(ctx context.Context, comboID uint, slots []models.ComboSlot) ([]models.ComboSlot, error) {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM combo_slots WHERE combo_id = $1", comboID); err != nil {
+		return nil, err
+	}
+	saved := make([]models.ComboSlot, len(slots))
+	for i, slot := range slots {
+		slot.Quantity = slot.SlotQuantity()
+		err := r.db.QueryRowContext(ctx, "INSERT INTO combo_slots (combo_id, position, name, quantity, food_id) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+			comboID, i+1, slot.Name, slot.Quantity, slot.FoodID).Scan(&slot.ID)
+		if err != nil {
+			return nil, err
+		}
+		for j, choice := range slot.Choices {
+			_, err := r.db.ExecContext(ctx, "INSERT INTO combo_slot_choices (slot_id, position, food_id, price_delta) VALUES ($1, $2, $3, $4)",
+				slot.ID, j+1, choice.FoodID, choice.PriceDelta)
+			if err != nil {
+				return nil, err
+			}
+		}
+		saved[i] = slot
+	}
+	return saved, nil
+}
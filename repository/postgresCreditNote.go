@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"restaurant-management/models"
+)
+
+const creditNoteColumns = `c.id, c.number, c.period, c.sequence, c.restaurant_id, c.invoice_id, c.order_id, c.reason_code, c.notes, c.amount, c.tax,
+	c.service_charge, c.total, c.created_by, c.created_at`
+
+func scanCreditNote(row scanner) (models.CreditNote, error) {
+	var note models.CreditNote
+	var createdBy sql.NullInt64
+	err := row.Scan(&note.ID, &note.Number, &note.Period, &note.Sequence, &note.RestaurantID, &note.InvoiceID, &note.OrderID, &note.ReasonCode, &note.Notes,
+		&note.Amount, &note.Tax, &note.ServiceCharge, &note.Total, &createdBy, &note.CreatedAt)
+	note.CreatedBy = nullUser(createdBy)
+	return note, err
+}
+
+type postgresCreditNoteRepository struct {
+	db DBTX
+}
+
+func (r *postgresCreditNoteRepository) ListByInvoice(ctx context.Context, invoiceID uint) ([]models.CreditNote, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+creditNoteColumns+" FROM credit_notes c WHERE c.invoice_id = $1 ORDER BY c.id ASC", invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []models.CreditNote
+	for rows.Next() {
+		note, err := scanCreditNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := r.items(ctx, "c.invoice_id = $1", invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	refunds, err := listRefunds(ctx, r.db, "c.invoice_id = $1", invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range notes {
+		notes[i].Items, notes[i].Refunds = items[notes[i].ID], refunds[notes[i].ID]
+	}
+	return notes, nil
+}
+
+func (r *postgresCreditNoteRepository) Get(ctx context.Context, id uint) (models.CreditNote, error) {
+	note, err := scanCreditNote(r.db.QueryRowContext(ctx, "SELECT "+creditNoteColumns+" FROM credit_notes c WHERE c.id = $1", id))
+	if err != nil {
+		return note, notFound(err)
+	}
+
+	items, err := r.items(ctx, "c.id = $1", id)
+	if err != nil {
+		return note, err
+	}
+	refunds, err := listRefunds(ctx, r.db, "c.id = $1", id)
+	if err != nil {
+		return note, err
+	}
+	note.Items, note.Refunds = items[id], refunds[id]
+	return note, nil
+}
+
+// items loads the voided items of the credit notes matching where, keyed by credit note ID
+func (r *postgresCreditNoteRepository) items(ctx context.Context, where string, arg uint) (map[uint][]models.CreditNoteItem, error) {
+	query := `
+		SELECT i.credit_note_id, COALESCE(i.order_item_id, 0), i.food_name, i.quantity, i.unit_price, i.amount, i.reason_code
+		FROM credit_note_items i JOIN credit_notes c ON c.id = i.credit_note_id
+		WHERE ` + where + ` ORDER BY i.id ASC`
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := map[uint][]models.CreditNoteItem{}
+	for rows.Next() {
+		var noteID uint
+		var item models.CreditNoteItem
+		if err := rows.Scan(&noteID, &item.OrderItemID, &item.FoodName, &item.Quantity, &item.UnitPrice, &item.Amount, &item.ReasonCode); err != nil {
+			return nil, err
+		}
+		items[noteID] = append(items[noteID], item)
+	}
+	return items, rows.Err()
+}
+
+func (r *postgresCreditNoteRepository) Create(ctx context.Context, note models.CreditNote) (models.CreditNote, error) {
+	at := time.Now()
+	period, sequence, number, err := takeNumber(ctx, r.db, note.RestaurantID, models.SequenceCreditNote, at)
+	if err != nil {
+		return note, err
+	}
+
+	query := `
+		INSERT INTO credit_notes AS c (restaurant_id, invoice_id, order_id, period, sequence, number, reason_code, notes, amount, tax, service_charge,
+			total, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING ` + creditNoteColumns
+	saved, err := scanCreditNote(r.db.QueryRowContext(ctx, query, note.RestaurantID, note.InvoiceID, note.OrderID, period, sequence, number,
+		note.ReasonCode, note.Notes, note.Amount, note.Tax, note.ServiceCharge, note.Total, note.CreatedBy, at))
+	if err != nil {
+		return saved, duplicate(err)
+	}
+
+	for _, item := range note.Items {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO credit_note_items (credit_note_id, order_item_id, food_name, quantity, unit_price, amount, reason_code)
+			VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7)`,
+			saved.ID, item.OrderItemID, item.FoodName, item.Quantity, item.UnitPrice, item.Amount, item.ReasonCode)
+		if err != nil {
+			return saved, err
+		}
+	}
+	saved.Items = note.Items
+	return saved, nil
+}
+
+func (r *postgresCreditNoteRepository) VoidedQuantities(ctx context.Context, orderID uint) (map[uint]uint, error) {
+	query := `
+		SELECT i.order_item_id, SUM(i.quantity)
+		FROM credit_note_items i JOIN credit_notes c ON c.id = i.credit_note_id
+		WHERE c.order_id = $1 AND i.order_item_id IS NOT NULL
+		GROUP BY i.order_item_id`
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	voided := map[uint]uint{}
+	for rows.Next() {
+		var itemID, quantity uint
+		if err := rows.Scan(&itemID, &quantity); err != nil {
+			return nil, err
+		}
+		voided[itemID] = quantity
+	}
+	return voided, rows.Err()
+}
+
+// nextSequence takes the next number of a restaurant's documents of one kind in a period.
+// The counter row stays locked until the surrounding transaction ends, so numbers are
+// handed out without gaps and a rolled back document gives its number back.
+func nextSequence(ctx context.Context, db DBTX, restaurantID uint, kind string, period int) (int, error) {
+	query := `
+		INSERT INTO document_sequences (restaurant_id, kind, period, last_value) VALUES ($1, $2, $3, 1)
+		ON CONFLICT (restaurant_id, kind, period) DO UPDATE SET last_value = document_sequences.last_value + 1
+		RETURNING last_value`
+	var sequence int
+	err := db.QueryRowContext(ctx, query, restaurantID, kind, period).Scan(&sequence)
+	return sequence, err
+}
+
+const refundColumns = `r.id, r.credit_note_id, r.payment_id, r.amount, r.method, r.provider, COALESCE(r.provider_refund_id, ''), r.status,
+	r.failure_reason, r.created_by, r.created_at, r.updated_at`
+
+func scanRefund(row scanner) (models.Refund, error) {
+	var refund models.Refund
+	var createdBy sql.NullInt64
+	err := row.Scan(&refund.ID, &refund.CreditNoteID, &refund.PaymentID, &refund.Amount, &refund.Method, &refund.Provider,
+		&refund.ProviderRefundID, &refund.Status, &refund.FailureReason, &createdBy, &refund.CreatedAt, &refund.UpdatedAt)
+	refund.CreatedBy = nullUser(createdBy)
+	return refund, err
+}
+
+// listRefunds loads the refunds of the credit notes matching where, keyed by credit note ID
+func listRefunds(ctx context.Context, db DBTX, where string, arg uint) (map[uint][]models.Refund, error) {
+	query := "SELECT " + refundColumns + " FROM refunds r JOIN credit_notes c ON c.id = r.credit_note_id WHERE " + where + " ORDER BY r.id ASC"
+	rows, err := db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := map[uint][]models.Refund{}
+	for rows.Next() {
+		refund, err := scanRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		refunds[refund.CreditNoteID] = append(refunds[refund.CreditNoteID], refund)
+	}
+	return refunds, rows.Err()
+}
+
+type postgresRefundRepository struct {
+	db DBTX
+}
+
+func (r *postgresRefundRepository) Get(ctx context.Context, id uint) (models.Refund, error) {
+	refund, err := scanRefund(r.db.QueryRowContext(ctx, "SELECT "+refundColumns+" FROM refunds r WHERE r.id = $1", id))
+	return refund, notFound(err)
+}
+
+func (r *postgresRefundRepository) GetByProviderID(ctx context.Context, provider, providerRefundID string) (models.Refund, error) {
+	query := "SELECT " + refundColumns + " FROM refunds r WHERE r.provider = $1 AND r.provider_refund_id = $2"
+	refund, err := scanRefund(r.db.QueryRowContext(ctx, query, provider, providerRefundID))
+	return refund, notFound(err)
+}
+
+func (r *postgresRefundRepository) Create(ctx context.Context, refund models.Refund) (models.Refund, error) {
+	query := `
+		INSERT INTO refunds AS r (credit_note_id, payment_id, amount, method, provider, provider_refund_id, status, failure_reason, created_by)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9)
+		RETURNING ` + refundColumns
+	saved, err := scanRefund(r.db.QueryRowContext(ctx, query, refund.CreditNoteID, refund.PaymentID, refund.Amount, refund.Method,
+		refund.Provider, refund.ProviderRefundID, refund.Status, refund.FailureReason, refund.CreatedBy))
+	return saved, duplicate(err)
+}
+
+func (r *postgresRefundRepository) UpdateStatus(ctx context.Context, id uint, status, providerRefundID, failureReason string) (models.Refund, error) {
+	query := `
+		UPDATE refunds AS r
+		SET status = $1, provider_refund_id = COALESCE(NULLIF($2, ''), provider_refund_id), failure_reason = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING ` + refundColumns
+	refund, err := scanRefund(r.db.QueryRowContext(ctx, query, status, providerRefundID, failureReason, id))
+	return refund, duplicate(notFound(err))
+}
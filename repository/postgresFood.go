@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+
+	"github.com/lib/pq"
+)
+
+const foodColumns = `id, name, price, COALESCE(description, ''), COALESCE(image_url, ''), menu_id, restaurant_id, COALESCE(station_id, 0),
+	tax_category, COALESCE(ingredients, ''), COALESCE(prep_time, 0), COALESCE(calories, 0), COALESCE(spicy_level, 0),
+	COALESCE(vegetarian, FALSE), allergens, dietary_tags, COALESCE(available, FALSE), created_at, updated_at`
+
+func scanFood(row scanner) (models.Food, error) {
+	var food models.Food
+	err := row.Scan(&food.ID, &food.Name, &food.Price, &food.Description, &food.ImageURL, &food.MenuID,
+		&food.RestaurantID, &food.StationID, &food.TaxCategory, &food.Ingredients, &food.PrepTime, &food.Calories, &food.SpicyLevel,
+		&food.Vegetarian, pq.Array(&food.Allergens), pq.Array(&food.DietaryTags), &food.Available, &food.CreatedAt, &food.UpdatedAt)
+	return food, err
+}
+
+type postgresFoodRepository struct {
+	db DBTX
+}
+
+func (r *postgresFoodRepository) List(ctx context.Context, restaurantID uint) ([]models.Food, error) {
+	query := "SELECT " + foodColumns + " FROM foods WHERE ($1 = 0 OR restaurant_id = $1) ORDER BY id ASC"
+	rows, err := r.db.QueryContext(ctx, query, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foods []models.Food
+	for rows.Next() {
+		food, err := scanFood(rows)
+		if err != nil {
+			return nil, err
+		}
+		foods = append(foods, food)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	schedules, err := foodAvailability.list(ctx, r.db, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	options, err := loadFoodOptions(ctx, r.db, "food_id IN (SELECT id FROM foods WHERE ($1 = 0 OR restaurant_id = $1))", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range foods {
+		foods[i].Availability = schedules[foods[i].ID]
+		foods[i].Variants, foods[i].ModifierGroups = options.variants[foods[i].ID], options.groups[foods[i].ID]
+	}
+	return foods, nil
+}
+
+func (r *postgresFoodRepository) Get(ctx context.Context, id uint) (models.Food, error) {
+	food, err := scanFood(r.db.QueryRowContext(ctx, "SELECT "+foodColumns+" FROM foods WHERE id = $1", id))
+	if err != nil {
+		return food, notFound(err)
+	}
+	if food.Availability, err = foodAvailability.get(ctx, r.db, id); err != nil {
+		return food, err
+	}
+	options, err := loadFoodOptions(ctx, r.db, "food_id = $1", id)
+	food.Variants, food.ModifierGroups = options.variants[id], options.groups[id]
+	return food, err
+}
+
+func (r *postgresFoodRepository) Create(ctx context.Context, food models.Food) (models.Food, error) {
+	query := `
+		INSERT INTO foods
+		(name, price, description, image_url, menu_id, restaurant_id, ingredients, prep_time, calories, spicy_level, vegetarian, available, station_id, tax_category,
+		allergens, dietary_tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, 0), $14, COALESCE($15::TEXT[], '{}'), COALESCE($16::TEXT[], '{}'))
+		RETURNING ` + foodColumns
+	created, err := scanFood(r.db.QueryRowContext(ctx, query,
+		food.Name, food.Price, food.Description, food.ImageURL, food.MenuID,
+		food.RestaurantID, food.Ingredients, food.PrepTime, food.Calories, food.SpicyLevel,
+		food.Vegetarian, food.Available, food.StationID, food.TaxCategory,
+		pq.Array(food.Allergens), pq.Array(food.DietaryTags),
+	))
+	if err != nil {
+		return created, err
+	}
+	created.Availability = food.Availability
+	if err := foodAvailability.save(ctx, r.db, created.ID, food.Availability); err != nil {
+		return created, err
+	}
+
+	// A new food's options are all new
+	created.Variants, created.ModifierGroups = newFoodOptions(food)
+	return created, saveFoodOptions(ctx, r.db, &created)
+}
+
+func (r *postgresFoodRepository) Update(ctx context.Context, food models.Food) (models.Food, error) {
+	query := `
+		UPDATE foods SET
+		name = $1, price = $2, description = $3, image_url = $4,
+		menu_id = $5, restaurant_id = $6, ingredients = $7, prep_time = $8,
+		calories = $9, spicy_level = $10, vegetarian = $11, available = $12,
+		station_id = NULLIF($13, 0), tax_category = $14, allergens = COALESCE($15::TEXT[], '{}'),
+		dietary_tags = COALESCE($16::TEXT[], '{}'), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $17
+		RETURNING ` + foodColumns
+	updated, err := scanFood(r.db.QueryRowContext(ctx, query,
+		food.Name, food.Price, food.Description, food.ImageURL,
+		food.MenuID, food.RestaurantID, food.Ingredients, food.PrepTime,
+		food.Calories, food.SpicyLevel, food.Vegetarian, food.Available, food.StationID, food.TaxCategory,
+		pq.Array(food.Allergens), pq.Array(food.DietaryTags), food.ID,
+	))
+	if err != nil {
+		return updated, notFound(err)
+	}
+	updated.Availability = food.Availability
+	if err := foodAvailability.save(ctx, r.db, updated.ID, food.Availability); err != nil {
+		return updated, err
+	}
+	updated.Variants, updated.ModifierGroups = food.Variants, food.ModifierGroups
+	return updated, saveFoodOptions(ctx, r.db, &updated)
+}
+
+func (r *postgresFoodRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM foods WHERE id = $1", id))
+}
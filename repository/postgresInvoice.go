@@ -0,0 +1,351 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"restaurant-management/models"
+)
+
+const invoiceColumns = `id, COALESCE(number, ''), issued_at, order_id, COALESCE(parent_id, 0), COALESCE(split_mode, ''), restaurant_id, COALESCE(amount, 0), discount,
+	COALESCE(tax, 0), service_charge, COALESCE(total, 0), amount_paid, tip, credited, COALESCE(status, 'pending'), COALESCE(payment_method, 'cash'),
+	created_at, updated_at`
+
+func scanInvoice(row scanner) (models.Invoice, error) {
+	var invoice models.Invoice
+	var issuedAt sql.NullTime
+	err := row.Scan(&invoice.ID, &invoice.Number, &issuedAt, &invoice.OrderID, &invoice.ParentID, &invoice.SplitMode, &invoice.RestaurantID, &invoice.Amount,
+		&invoice.Discount, &invoice.Tax, &invoice.ServiceCharge, &invoice.Total, &invoice.AmountPaid, &invoice.Tip, &invoice.Credited, &invoice.Status,
+		&invoice.PaymentMethod, &invoice.CreatedAt, &invoice.UpdatedAt)
+	if issuedAt.Valid {
+		invoice.IssuedAt = &issuedAt.Time
+	}
+	invoice.Balance = balance(invoice)
+	return invoice, err
+}
+
+// balance is what is left to pay on an invoice, rounded to cents
+func balance(invoice models.Invoice) float64 {
+	return math.Round((invoice.Total-invoice.AmountPaid)*100) / 100
+}
+
+type postgresInvoiceRepository struct {
+	db DBTX
+}
+
+const invoiceTaxColumns = `t.invoice_id, COALESCE(t.tax_rate_id, 0), t.name, t.category, t.rate, t.inclusive, t.taxable_amount, t.tax_amount`
+
+const invoiceServiceChargeColumns = `s.invoice_id, COALESCE(s.service_charge_id, 0), s.name, s.rate, s.base_amount, s.amount`
+
+func (r *postgresInvoiceRepository) ListByRestaurant(ctx context.Context, restaurantID uint) ([]models.Invoice, error) {
+	query := "SELECT " + invoiceColumns + " FROM invoices WHERE restaurant_id = $1 AND parent_id IS NULL ORDER BY id ASC"
+	rows, err := r.db.QueryContext(ctx, query, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []models.Invoice
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	taxes, err := r.taxes(ctx, "i.restaurant_id = $1 AND i.parent_id IS NULL", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	charges, err := r.serviceCharges(ctx, "i.restaurant_id = $1 AND i.parent_id IS NULL", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range invoices {
+		invoices[i].Taxes, invoices[i].ServiceCharges = taxes[invoices[i].ID], charges[invoices[i].ID]
+	}
+	return invoices, nil
+}
+
+func (r *postgresInvoiceRepository) Get(ctx context.Context, id uint) (models.Invoice, error) {
+	return r.get(ctx, "id = $1", id)
+}
+
+func (r *postgresInvoiceRepository) GetByOrder(ctx context.Context, orderID uint) (models.Invoice, error) {
+	return r.get(ctx, "order_id = $1 AND parent_id IS NULL", orderID)
+}
+
+// get loads one invoice with its tax and service charge breakdowns, its parts and the payments taken against them
+func (r *postgresInvoiceRepository) get(ctx context.Context, where string, arg uint) (models.Invoice, error) {
+	invoice, err := scanInvoice(r.db.QueryRowContext(ctx, "SELECT "+invoiceColumns+" FROM invoices WHERE "+where, arg))
+	if err != nil {
+		return invoice, notFound(err)
+	}
+
+	if invoice.Splits, err = r.ListSplits(ctx, invoice.ID); err != nil {
+		return invoice, err
+	}
+	taxes, err := r.taxes(ctx, "i.id = $1", invoice.ID)
+	if err != nil {
+		return invoice, err
+	}
+	charges, err := r.serviceCharges(ctx, "i.id = $1", invoice.ID)
+	if err != nil {
+		return invoice, err
+	}
+	invoice.Taxes, invoice.ServiceCharges = taxes[invoice.ID], charges[invoice.ID]
+	if invoice.ParentID != 0 {
+		if invoice.OrderItemIDs, err = r.items(ctx, invoice.ID); err != nil {
+			return invoice, err
+		}
+	}
+
+	invoice.Payments, err = listPayments(ctx, r.db, invoice.ID)
+	return invoice, err
+}
+
+func (r *postgresInvoiceRepository) ListSplits(ctx context.Context, parentID uint) ([]models.Invoice, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+invoiceColumns+" FROM invoices WHERE parent_id = $1 ORDER BY id ASC", parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var splits []models.Invoice
+	for rows.Next() {
+		split, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		splits = append(splits, split)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	taxes, err := r.taxes(ctx, "i.parent_id = $1", parentID)
+	if err != nil {
+		return nil, err
+	}
+	charges, err := r.serviceCharges(ctx, "i.parent_id = $1", parentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range splits {
+		splits[i].Taxes, splits[i].ServiceCharges = taxes[splits[i].ID], charges[splits[i].ID]
+		if splits[i].OrderItemIDs, err = r.items(ctx, splits[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return splits, nil
+}
+
+// taxes loads the tax breakdowns of the invoices matching where, keyed by invoice ID
+func (r *postgresInvoiceRepository) taxes(ctx context.Context, where string, arg uint) (map[uint][]models.InvoiceTax, error) {
+	query := "SELECT " + invoiceTaxColumns + " FROM invoice_taxes t JOIN invoices i ON i.id = t.invoice_id WHERE " + where + " ORDER BY t.id ASC"
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	taxes := map[uint][]models.InvoiceTax{}
+	for rows.Next() {
+		var invoiceID uint
+		var tax models.InvoiceTax
+		if err := rows.Scan(&invoiceID, &tax.TaxRateID, &tax.Name, &tax.Category, &tax.Rate, &tax.Inclusive, &tax.TaxableAmount, &tax.TaxAmount); err != nil {
+			return nil, err
+		}
+		taxes[invoiceID] = append(taxes[invoiceID], tax)
+	}
+	return taxes, rows.Err()
+}
+
+// serviceCharges loads the service charge breakdowns of the invoices matching where, keyed by invoice ID
+func (r *postgresInvoiceRepository) serviceCharges(ctx context.Context, where string, arg uint) (map[uint][]models.InvoiceServiceCharge, error) {
+	query := "SELECT " + invoiceServiceChargeColumns + " FROM invoice_service_charges s JOIN invoices i ON i.id = s.invoice_id WHERE " + where + " ORDER BY s.id ASC"
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	charges := map[uint][]models.InvoiceServiceCharge{}
+	for rows.Next() {
+		var invoiceID uint
+		var charge models.InvoiceServiceCharge
+		if err := rows.Scan(&invoiceID, &charge.ServiceChargeID, &charge.Name, &charge.Rate, &charge.BaseAmount, &charge.Amount); err != nil {
+			return nil, err
+		}
+		charges[invoiceID] = append(charges[invoiceID], charge)
+	}
+	return charges, rows.Err()
+}
+
+// items loads the order items a part split by items covers
+func (r *postgresInvoiceRepository) items(ctx context.Context, invoiceID uint) ([]uint, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT order_item_id FROM invoice_items WHERE invoice_id = $1 ORDER BY order_item_id ASC", invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *postgresInvoiceRepository) UpsertForOrder(ctx context.Context, invoice models.Invoice) (models.Invoice, error) {
+	query := `
+		INSERT INTO invoices (order_id, amount, tax, total, status, payment_method, restaurant_id, discount, service_charge)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (order_id) WHERE parent_id IS NULL
+		DO UPDATE SET
+			amount = EXCLUDED.amount,
+			tax = EXCLUDED.tax,
+			service_charge = EXCLUDED.service_charge,
+			total = EXCLUDED.total,
+			status = EXCLUDED.status,
+			payment_method = EXCLUDED.payment_method,
+			restaurant_id = EXCLUDED.restaurant_id,
+			discount = EXCLUDED.discount,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING ` + invoiceColumns
+	saved, err := scanInvoice(r.db.QueryRowContext(ctx, query, invoice.OrderID, invoice.Amount, invoice.Tax, invoice.Total, invoice.Status,
+		invoice.PaymentMethod, invoice.RestaurantID, invoice.Discount, invoice.ServiceCharge))
+	if err != nil {
+		return saved, err
+	}
+
+	// The breakdowns are rewritten as a whole, callers run this in a unit of work
+	if err := r.writeTaxes(ctx, saved.ID, invoice.Taxes); err != nil {
+		return saved, err
+	}
+	if err := r.writeServiceCharges(ctx, saved.ID, invoice.ServiceCharges); err != nil {
+		return saved, err
+	}
+	saved.Taxes, saved.ServiceCharges = invoice.Taxes, invoice.ServiceCharges
+	return saved, nil
+}
+
+func (r *postgresInvoiceRepository) writeTaxes(ctx context.Context, invoiceID uint, taxes []models.InvoiceTax) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM invoice_taxes WHERE invoice_id = $1", invoiceID); err != nil {
+		return err
+	}
+	for _, tax := range taxes {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO invoice_taxes (invoice_id, tax_rate_id, name, category, rate, inclusive, taxable_amount, tax_amount)
+			VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7, $8)`,
+			invoiceID, tax.TaxRateID, tax.Name, tax.Category, tax.Rate, tax.Inclusive, tax.TaxableAmount, tax.TaxAmount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *postgresInvoiceRepository) writeServiceCharges(ctx context.Context, invoiceID uint, charges []models.InvoiceServiceCharge) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM invoice_service_charges WHERE invoice_id = $1", invoiceID); err != nil {
+		return err
+	}
+	for _, charge := range charges {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO invoice_service_charges (invoice_id, service_charge_id, name, rate, base_amount, amount)
+			VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6)`,
+			invoiceID, charge.ServiceChargeID, charge.Name, charge.Rate, charge.BaseAmount, charge.Amount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *postgresInvoiceRepository) ReplaceSplits(ctx context.Context, parentID uint, mode string, splits []models.Invoice) ([]models.Invoice, error) {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM invoices WHERE parent_id = $1", parentID); err != nil {
+		return nil, err
+	}
+	err := expectAffected(r.db.ExecContext(ctx, "UPDATE invoices SET split_mode = NULLIF($1, ''), updated_at = CURRENT_TIMESTAMP WHERE id = $2", mode, parentID))
+	if err != nil {
+		return nil, err
+	}
+
+	saved := make([]models.Invoice, 0, len(splits))
+	for _, split := range splits {
+		query := `
+			INSERT INTO invoices (order_id, parent_id, amount, discount, tax, service_charge, total, status, payment_method, restaurant_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING ` + invoiceColumns
+		part, err := scanInvoice(r.db.QueryRowContext(ctx, query, split.OrderID, parentID, split.Amount, split.Discount, split.Tax,
+			split.ServiceCharge, split.Total, split.Status, split.PaymentMethod, split.RestaurantID))
+		if err != nil {
+			return nil, err
+		}
+		if err := r.writeTaxes(ctx, part.ID, split.Taxes); err != nil {
+			return nil, err
+		}
+		if err := r.writeServiceCharges(ctx, part.ID, split.ServiceCharges); err != nil {
+			return nil, err
+		}
+		for _, itemID := range split.OrderItemIDs {
+			if _, err := r.db.ExecContext(ctx, "INSERT INTO invoice_items (invoice_id, order_item_id) VALUES ($1, $2)", part.ID, itemID); err != nil {
+				return nil, err
+			}
+		}
+		part.Taxes, part.ServiceCharges, part.OrderItemIDs = split.Taxes, split.ServiceCharges, split.OrderItemIDs
+		saved = append(saved, part)
+	}
+	return saved, nil
+}
+
+func (r *postgresInvoiceRepository) UpdatePayment(ctx context.Context, id uint, amountPaid, tip float64, status, paymentMethod string) (models.Invoice, error) {
+	query := `
+		UPDATE invoices SET amount_paid = $1, tip = $2, status = $3, payment_method = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5
+		RETURNING ` + invoiceColumns
+	invoice, err := scanInvoice(r.db.QueryRowContext(ctx, query, amountPaid, tip, status, paymentMethod, id))
+	return invoice, notFound(err)
+}
+
+func (r *postgresInvoiceRepository) UpdateCredited(ctx context.Context, id uint, credited float64) (models.Invoice, error) {
+	query := "UPDATE invoices SET credited = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 RETURNING " + invoiceColumns
+	invoice, err := scanInvoice(r.db.QueryRowContext(ctx, query, credited, id))
+	return invoice, notFound(err)
+}
+
+func (r *postgresInvoiceRepository) Issue(ctx context.Context, id uint) (models.Invoice, error) {
+	var restaurantID uint
+	var number sql.NullString
+	err := r.db.QueryRowContext(ctx, "SELECT restaurant_id, number FROM invoices WHERE id = $1 FOR UPDATE", id).Scan(&restaurantID, &number)
+	if err != nil {
+		return models.Invoice{}, notFound(err)
+	}
+	if !number.Valid {
+		at := time.Now()
+		_, _, number, err := takeNumber(ctx, r.db, restaurantID, models.SequenceInvoice, at)
+		if err != nil {
+			return models.Invoice{}, err
+		}
+		_, err = r.db.ExecContext(ctx, "UPDATE invoices SET number = $1, issued_at = $2 WHERE id = $3 AND number IS NULL", number, at, id)
+		if err != nil {
+			return models.Invoice{}, duplicate(err)
+		}
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *postgresInvoiceRepository) DeleteByOrder(ctx context.Context, orderID uint) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM invoices WHERE order_id = $1", orderID)
+	return err
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"restaurant-management/models"
+)
+
+const invoiceDeliveryColumns = `id, invoice_id, restaurant_id, recipient, status, attempts, last_error, sent_by, sent_at, created_at, updated_at`
+
+func scanInvoiceDelivery(row scanner) (models.InvoiceDelivery, error) {
+	var delivery models.InvoiceDelivery
+	var sentBy sql.NullInt64
+	var sentAt sql.NullTime
+	err := row.Scan(&delivery.ID, &delivery.InvoiceID, &delivery.RestaurantID, &delivery.Recipient, &delivery.Status, &delivery.Attempts,
+		&delivery.LastError, &sentBy, &sentAt, &delivery.CreatedAt, &delivery.UpdatedAt)
+	delivery.SentBy = nullUser(sentBy)
+	if sentAt.Valid {
+		delivery.SentAt = &sentAt.Time
+	}
+	return delivery, err
+}
+
+type postgresInvoiceDeliveryRepository struct {
+	db DBTX
+}
+
+func (r *postgresInvoiceDeliveryRepository) ListByInvoice(ctx context.Context, invoiceID uint) ([]models.InvoiceDelivery, error) {
+	query := "SELECT " + invoiceDeliveryColumns + " FROM invoice_deliveries WHERE invoice_id = $1 ORDER BY id DESC"
+	rows, err := r.db.QueryContext(ctx, query, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.InvoiceDelivery
+	for rows.Next() {
+		delivery, err := scanInvoiceDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *postgresInvoiceDeliveryRepository) Create(ctx context.Context, delivery models.InvoiceDelivery) (models.InvoiceDelivery, error) {
+	query := `
+		INSERT INTO invoice_deliveries (invoice_id, restaurant_id, recipient, status, sent_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + invoiceDeliveryColumns
+	return scanInvoiceDelivery(r.db.QueryRowContext(ctx, query, delivery.InvoiceID, delivery.RestaurantID, delivery.Recipient,
+		models.DeliveryStatusPending, delivery.SentBy))
+}
+
+func (r *postgresInvoiceDeliveryRepository) RecordAttempt(ctx context.Context, id uint, status, lastError string) (models.InvoiceDelivery, error) {
+	query := `
+		UPDATE invoice_deliveries
+		SET status = $1, last_error = $2, attempts = attempts + 1, updated_at = CURRENT_TIMESTAMP,
+			sent_at = CASE WHEN $1 = 'sent' THEN CURRENT_TIMESTAMP ELSE sent_at END
+		WHERE id = $3
+		RETURNING ` + invoiceDeliveryColumns
+	delivery, err := scanInvoiceDelivery(r.db.QueryRowContext(ctx, query, status, lastError, id))
+	return delivery, notFound(err)
+}
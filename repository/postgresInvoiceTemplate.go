@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"restaurant-management/models"
+)
+
+const invoiceTemplateColumns = `restaurant_id, show_logo, show_address, footer_text, accent_color, paper_size, payment_qr, payment_url, receipt_printer, updated_at`
+
+func scanInvoiceTemplate(row scanner) (models.InvoiceTemplate, error) {
+	var template models.InvoiceTemplate
+	err := row.Scan(&template.RestaurantID, &template.ShowLogo, &template.ShowAddress, &template.FooterText, &template.AccentColor,
+		&template.PaperSize, &template.PaymentQR, &template.PaymentURL, &template.ReceiptPrinter, &template.UpdatedAt)
+	return template, err
+}
+
+type postgresInvoiceTemplateRepository struct {
+	db DBTX
+}
+
+func (r *postgresInvoiceTemplateRepository) Get(ctx context.Context, restaurantID uint) (models.InvoiceTemplate, error) {
+	query := "SELECT " + invoiceTemplateColumns + " FROM invoice_templates WHERE restaurant_id = $1"
+	template, err := scanInvoiceTemplate(r.db.QueryRowContext(ctx, query, restaurantID))
+	if errors.Is(notFound(err), ErrNotFound) {
+		return models.DefaultInvoiceTemplate(restaurantID), nil
+	}
+	if err != nil {
+		return template, err
+	}
+
+	template.TaxIDs, err = r.taxIDs(ctx, restaurantID)
+	return template, err
+}
+
+func (r *postgresInvoiceTemplateRepository) taxIDs(ctx context.Context, restaurantID uint) ([]models.TaxID, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT label, value FROM invoice_template_tax_ids WHERE restaurant_id = $1 ORDER BY position ASC", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taxIDs []models.TaxID
+	for rows.Next() {
+		var taxID models.TaxID
+		if err := rows.Scan(&taxID.Label, &taxID.Value); err != nil {
+			return nil, err
+		}
+		taxIDs = append(taxIDs, taxID)
+	}
+	return taxIDs, rows.Err()
+}
+
+func (r *postgresInvoiceTemplateRepository) Upsert(ctx context.Context, template models.InvoiceTemplate) (models.InvoiceTemplate, error) {
+	query := `
+		INSERT INTO invoice_templates (restaurant_id, show_logo, show_address, footer_text, accent_color, paper_size, payment_qr, payment_url, receipt_printer)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (restaurant_id) DO UPDATE
+		SET show_logo = EXCLUDED.show_logo, show_address = EXCLUDED.show_address, footer_text = EXCLUDED.footer_text,
+			accent_color = EXCLUDED.accent_color, paper_size = EXCLUDED.paper_size, payment_qr = EXCLUDED.payment_qr,
+			payment_url = EXCLUDED.payment_url, receipt_printer = EXCLUDED.receipt_printer, updated_at = CURRENT_TIMESTAMP
+		RETURNING ` + invoiceTemplateColumns
+	saved, err := scanInvoiceTemplate(r.db.QueryRowContext(ctx, query, template.RestaurantID, template.ShowLogo, template.ShowAddress,
+		template.FooterText, template.AccentColor, template.PaperSize, template.PaymentQR, template.PaymentURL, template.ReceiptPrinter))
+	if err != nil {
+		return saved, err
+	}
+
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM invoice_template_tax_ids WHERE restaurant_id = $1", template.RestaurantID); err != nil {
+		return saved, err
+	}
+	for i, taxID := range template.TaxIDs {
+		_, err := r.db.ExecContext(ctx, "INSERT INTO invoice_template_tax_ids (restaurant_id, position, label, value) VALUES ($1, $2, $3, $4)",
+			template.RestaurantID, i+1, taxID.Label, taxID.Value)
+		if err != nil {
+			return saved, err
+		}
+	}
+	saved.TaxIDs = template.TaxIDs
+	return saved, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+const menuColumns = `id, name, description, image, position, COALESCE(parent_id, 0), restaurant_id, COALESCE(station_id, 0), created_at, updated_at`
+
+func scanMenu(row scanner) (models.Menu, error) {
+	var menu models.Menu
+	err := row.Scan(&menu.ID, &menu.Name, &menu.Description, &menu.Image, &menu.Position, &menu.ParentID, &menu.RestaurantID,
+		&menu.StationID, &menu.CreatedAt, &menu.UpdatedAt)
+	return menu, err
+}
+
+type postgresMenuRepository struct {
+	db DBTX
+}
+
+func (r *postgresMenuRepository) List(ctx context.Context, restaurantID uint) ([]models.Menu, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+menuColumns+" FROM menus WHERE ($1 = 0 OR restaurant_id = $1) ORDER BY position ASC, id ASC", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var menus []models.Menu
+	for rows.Next() {
+		menu, err := scanMenu(rows)
+		if err != nil {
+			return nil, err
+		}
+		menus = append(menus, menu)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	schedules, err := menuAvailability.list(ctx, r.db, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range menus {
+		menus[i].Availability = schedules[menus[i].ID]
+	}
+	return menus, nil
+}
+
+func (r *postgresMenuRepository) Get(ctx context.Context, id uint) (models.Menu, error) {
+	menu, err := scanMenu(r.db.QueryRowContext(ctx, "SELECT "+menuColumns+" FROM menus WHERE id = $1", id))
+	if err != nil {
+		return menu, notFound(err)
+	}
+	menu.Availability, err = menuAvailability.get(ctx, r.db, id)
+	return menu, err
+}
+
+func (r *postgresMenuRepository) Create(ctx context.Context, menu models.Menu) (models.Menu, error) {
+	query := `
+		INSERT INTO menus (name, description, image, position, parent_id, restaurant_id, station_id)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6, NULLIF($7, 0))
+		RETURNING ` + menuColumns
+	created, err := scanMenu(r.db.QueryRowContext(ctx, query, menu.Name, menu.Description, menu.Image, menu.Position, menu.ParentID, menu.RestaurantID, menu.StationID))
+	if err != nil {
+		return created, err
+	}
+	created.Availability = menu.Availability
+	return created, menuAvailability.save(ctx, r.db, created.ID, menu.Availability)
+}
+
+func (r *postgresMenuRepository) Update(ctx context.Context, menu models.Menu) (models.Menu, error) {
+	query := `
+		UPDATE menus
+		SET name = $1, description = $2, image = $3, position = $4, parent_id = NULLIF($5, 0), restaurant_id = $6,
+			station_id = NULLIF($7, 0), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $8
+		RETURNING ` + menuColumns
+	updated, err := scanMenu(r.db.QueryRowContext(ctx, query, menu.Name, menu.Description, menu.Image, menu.Position, menu.ParentID,
+		menu.RestaurantID, menu.StationID, menu.ID))
+	if err != nil {
+		return updated, notFound(err)
+	}
+	updated.Availability = menu.Availability
+	return updated, menuAvailability.save(ctx, r.db, updated.ID, menu.Availability)
+}
+
+func (r *postgresMenuRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM menus WHERE id = $1", id))
+}
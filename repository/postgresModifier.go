@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+
+	"github.com/lib/pq"
+)
+
+// foodOptions are the variants and modifier groups of foods, keyed by food ID
+type foodOptions struct {
+	variants map[uint][]models.FoodVariant
+	groups   map[uint][]models.ModifierGroup
+}
+
+// loadFoodOptions reads the variants and modifier groups of the foods matched by where, a
+// condition on food_id with one argument
+func loadFoodOptions(ctx context.Context, db DBTX, where string, arg uint) (foodOptions, error) {
+	options := foodOptions{variants: map[uint][]models.FoodVariant{}, groups: map[uint][]models.ModifierGroup{}}
+
+	rows, err := db.QueryContext(ctx, "SELECT food_id, id, name, price FROM food_variants WHERE "+where+" ORDER BY food_id ASC, position ASC", arg)
+	if err != nil {
+		return options, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var foodID uint
+		var variant models.FoodVariant
+		if err := rows.Scan(&foodID, &variant.ID, &variant.Name, &variant.Price); err != nil {
+			return options, err
+		}
+		options.variants[foodID] = append(options.variants[foodID], variant)
+	}
+	if err := rows.Err(); err != nil {
+		return options, err
+	}
+
+	modifiers := map[uint][]models.Modifier{}
+	query := `
+		SELECT m.group_id, m.id, m.name, m.price_delta
+		FROM modifiers m JOIN modifier_groups g ON g.id = m.group_id
+		WHERE ` + where + `
+		ORDER BY m.group_id ASC, m.position ASC`
+	modifierRows, err := db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return options, err
+	}
+	defer modifierRows.Close()
+	for modifierRows.Next() {
+		var groupID uint
+		var modifier models.Modifier
+		if err := modifierRows.Scan(&groupID, &modifier.ID, &modifier.Name, &modifier.PriceDelta); err != nil {
+			return options, err
+		}
+		modifiers[groupID] = append(modifiers[groupID], modifier)
+	}
+	if err := modifierRows.Err(); err != nil {
+		return options, err
+	}
+
+	query = "SELECT food_id, id, name, required, min_selections, max_selections FROM modifier_groups WHERE " + where + " ORDER BY food_id ASC, position ASC"
+	groupRows, err := db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return options, err
+	}
+	defer groupRows.Close()
+	for groupRows.Next() {
+		var foodID uint
+		var group models.ModifierGroup
+		if err := groupRows.Scan(&foodID, &group.ID, &group.Name, &group.Required, &group.MinSelections, &group.MaxSelections); err != nil {
+			return options, err
+		}
+		group.Options = modifiers[group.ID]
+		options.groups[foodID] = append(options.groups[foodID], group)
+	}
+	return options, groupRows.Err()
+}
+
+// newFoodOptions copies the variants and modifier groups of a food without their IDs
+func newFoodOptions(food models.Food) ([]models.FoodVariant, []models.ModifierGroup) {
+	var variants []models.FoodVariant
+	for _, variant := range food.Variants {
+		variant.ID = 0
+		variants = append(variants, variant)
+	}
+	var groups []models.ModifierGroup
+	for _, group := range food.ModifierGroups {
+		var options []models.Modifier
+		for _, option := range group.Options {
+			option.ID = 0
+			options = append(options, option)
+		}
+		group.ID, group.Options = 0, options
+		groups = append(groups, group)
+	}
+	return variants, groups
+}
+
+// saveFoodOptions replaces the variants and modifier groups of a food. Variants, groups and
+// options that come with an ID are updated in place, so order items keep referring to them,
+// the others are added and whatever the food no longer lists is removed. The IDs of the
+// added ones are filled in.
+func saveFoodOptions(ctx context.Context, db DBTX, food *models.Food) error {
+	var kept []int64
+	for _, variant := range food.Variants {
+		if variant.ID != 0 {
+			kept = append(kept, int64(variant.ID))
+		}
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM food_variants WHERE food_id = $1 AND NOT (id = ANY($2))", food.ID, pq.Array(kept)); err != nil {
+		return err
+	}
+	for i := range food.Variants {
+		variant := &food.Variants[i]
+		if variant.ID != 0 {
+			err := expectAffected(db.ExecContext(ctx, "UPDATE food_variants SET position = $1, name = $2, price = $3 WHERE id = $4 AND food_id = $5",
+				i+1, variant.Name, variant.Price, variant.ID, food.ID))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err := db.QueryRowContext(ctx, "INSERT INTO food_variants (food_id, position, name, price) VALUES ($1, $2, $3, $4) RETURNING id",
+			food.ID, i+1, variant.Name, variant.Price).Scan(&variant.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	kept = nil
+	for _, group := range food.ModifierGroups {
+		if group.ID != 0 {
+			kept = append(kept, int64(group.ID))
+		}
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM modifier_groups WHERE food_id = $1 AND NOT (id = ANY($2))", food.ID, pq.Array(kept)); err != nil {
+		return err
+	}
+	for i := range food.ModifierGroups {
+		group := &food.ModifierGroups[i]
+		if group.ID != 0 {
+			err := expectAffected(db.ExecContext(ctx, `
+				UPDATE modifier_groups SET position = $1, name = $2, required = $3, min_selections = $4, max_selections = $5
+				WHERE id = $6 AND food_id = $7`,
+				i+1, group.Name, group.Required, group.MinSelections, group.MaxSelections, group.ID, food.ID))
+			if err != nil {
+				return err
+			}
+		} else {
+			err := db.QueryRowContext(ctx, `
+				INSERT INTO modifier_groups (food_id, position, name, required, min_selections, max_selections)
+				VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+				food.ID, i+1, group.Name, group.Required, group.MinSelections, group.MaxSelections).Scan(&group.ID)
+			if err != nil {
+				return err
+			}
+		}
+		if err := saveModifiers(ctx, db, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveModifiers replaces the options of a modifier group the way saveFoodOptions does
+func saveModifiers(ctx context.Context, db DBTX, group *models.ModifierGroup) error {
+	var kept []int64
+	for _, option := range group.Options {
+		if option.ID != 0 {
+			kept = append(kept, int64(option.ID))
+		}
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM modifiers WHERE group_id = $1 AND NOT (id = ANY($2))", group.ID, pq.Array(kept)); err != nil {
+		return err
+	}
+	for i := range group.Options {
+		option := &group.Options[i]
+		if option.ID != 0 {
+			err := expectAffected(db.ExecContext(ctx, "UPDATE modifiers SET position = $1, name = $2, price_delta = $3 WHERE id = $4 AND group_id = $5",
+				i+1, option.Name, option.PriceDelta, option.ID, group.ID))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err := db.QueryRowContext(ctx, "INSERT INTO modifiers (group_id, position, name, price_delta) VALUES ($1, $2, $3, $4) RETURNING id",
+			group.ID, i+1, option.Name, option.PriceDelta).Scan(&option.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadItemModifiers reads the modifiers order items were ordered with, keyed by order item ID
+func loadItemModifiers(ctx context.Context, db DBTX, itemIDs []int64) (map[uint][]models.OrderItemModifier, error) {
+	modifiers := map[uint][]models.OrderItemModifier{}
+	if len(itemIDs) == 0 {
+		return modifiers, nil
+	}
+	query := `
+		SELECT order_item_id, COALESCE(modifier_id, 0), group_name, name, price_delta
+		FROM orderitem_modifiers WHERE order_item_id = ANY($1)
+		ORDER BY order_item_id ASC, position ASC`
+	rows, err := db.QueryContext(ctx, query, pq.Array(itemIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID uint
+		var modifier models.OrderItemModifier
+		if err := rows.Scan(&itemID, &modifier.ModifierID, &modifier.Group, &modifier.Name, &modifier.PriceDelta); err != nil {
+			return nil, err
+		}
+		modifiers[itemID] = append(modifiers[itemID], modifier)
+	}
+	return modifiers, rows.Err()
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+const noteColumns = `id, title, content, priority, restaurant_id, created_at, updated_at`
+
+func scanNote(row scanner) (models.Note, error) {
+	var note models.Note
+	err := row.Scan(&note.ID, &note.Title, &note.Content, &note.Priority, &note.RestaurantID, &note.CreatedAt, &note.UpdatedAt)
+	return note, err
+}
+
+type postgresNoteRepository struct {
+	db DBTX
+}
+
+func (r *postgresNoteRepository) ListByRestaurant(ctx context.Context, restaurantID uint) ([]models.Note, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+noteColumns+" FROM notes WHERE restaurant_id = $1 ORDER BY id ASC", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []models.Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+func (r *postgresNoteRepository) Create(ctx context.Context, note models.Note) (models.Note, error) {
+	query := `
+		INSERT INTO notes (title, content, priority, restaurant_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + noteColumns
+	return scanNote(r.db.QueryRowContext(ctx, query, note.Title, note.Content, note.Priority, note.RestaurantID))
+}
+
+func (r *postgresNoteRepository) Update(ctx context.Context, note models.Note) (models.Note, error) {
+	query := `
+		UPDATE notes
+		SET title = $1, content = $2, priority = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING ` + noteColumns
+	updated, err := scanNote(r.db.QueryRowContext(ctx, query, note.Title, note.Content, note.Priority, note.ID))
+	return updated, notFound(err)
+}
+
+func (r *postgresNoteRepository) Delete(ctx context.Context, id uint) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM notes WHERE id = $1", id)
+	return err
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"restaurant-management/models"
+)
+
+const numberSeriesColumns = `restaurant_id, kind, prefix, padding, reset, fiscal_year_start, updated_at`
+
+func scanNumberSeries(row scanner) (models.NumberSeries, error) {
+	var series models.NumberSeries
+	err := row.Scan(&series.RestaurantID, &series.Kind, &series.Prefix, &series.Padding, &series.Reset, &series.FiscalYearStart, &series.UpdatedAt)
+	return series, err
+}
+
+type postgresNumberSeriesRepository struct {
+	db DBTX
+}
+
+func (r *postgresNumberSeriesRepository) List(ctx context.Context, restaurantID uint) ([]models.NumberSeries, error) {
+	series := make([]models.NumberSeries, 0, len(models.DocumentKinds))
+	for _, kind := range models.DocumentKinds {
+		s, err := r.Get(ctx, restaurantID, kind)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, s)
+	}
+	return series, nil
+}
+
+func (r *postgresNumberSeriesRepository) Get(ctx context.Context, restaurantID uint, kind string) (models.NumberSeries, error) {
+	query := "SELECT " + numberSeriesColumns + " FROM number_series WHERE restaurant_id = $1 AND kind = $2"
+	series, err := scanNumberSeries(r.db.QueryRowContext(ctx, query, restaurantID, kind))
+	if errors.Is(notFound(err), ErrNotFound) {
+		return models.DefaultNumberSeries(restaurantID, kind), nil
+	}
+	return series, err
+}
+
+func (r *postgresNumberSeriesRepository) Upsert(ctx context.Context, series models.NumberSeries) (models.NumberSeries, error) {
+	query := `
+		INSERT INTO number_series (restaurant_id, kind, prefix, padding, reset, fiscal_year_start)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (restaurant_id, kind) DO UPDATE
+		SET prefix = EXCLUDED.prefix, padding = EXCLUDED.padding, reset = EXCLUDED.reset,
+			fiscal_year_start = EXCLUDED.fiscal_year_start, updated_at = CURRENT_TIMESTAMP
+		RETURNING ` + numberSeriesColumns
+	return scanNumberSeries(r.db.QueryRowContext(ctx, query, series.RestaurantID, series.Kind, series.Prefix, series.Padding,
+		series.Reset, series.FiscalYearStart))
+}
+
+// takeNumber numbers a document of a restaurant issued at the given time, next in the
+// period of its series. The number is only taken for good once the surrounding
+// transaction commits, see nextSequence.
+func takeNumber(ctx context.Context, db DBTX, restaurantID uint, kind string, at time.Time) (int, int, string, error) {
+	series, err := (&postgresNumberSeriesRepository{db: db}).Get(ctx, restaurantID, kind)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	period := series.Period(at)
+	sequence, err := nextSequence(ctx, db, restaurantID, kind, period)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	return period, sequence, series.Format(period, sequence), nil
+}
@@ -0,0 +1,533 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"restaurant-management/models"
+
+	"github.com/lib/pq"
+)
+
+// Blank orders created through CreateBlank have NULL columns until they are filled in
+const orderColumns = `id, COALESCE(table_id, 0), COALESCE(restaurant_id, 0), COALESCE(order_date, created_at),
+	COALESCE(total_price, 0), discount_total, COALESCE(guest_count, 0), COALESCE(status, ''), COALESCE(notes, ''), allergens, created_at, updated_at`
+
+// A combo item is named and taxed after its combo, its components after their foods
+const orderItemColumns = `oi.id, oi.order_id, COALESCE(oi.food_id, 0), COALESCE(oi.combo_id, 0), COALESCE(oi.parent_id, 0), oi.slot_name,
+	COALESCE(f.name, cb.name, ''), COALESCE(oi.variant_id, 0), oi.variant_name, COALESCE(f.menu_id, 0), COALESCE(f.tax_category, cb.tax_category, 'food'),
+	COALESCE(f.allergens, '{}'), COALESCE(oi.quantity, 1), COALESCE(oi.unit_price, 0), COALESCE(oi.subtotal, 0), oi.prep_status, oi.bumped_at, oi.created_at, oi.updated_at`
+
+const orderItemJoins = `LEFT JOIN foods f ON f.id = oi.food_id LEFT JOIN combos cb ON cb.id = oi.combo_id`
+
+func scanOrder(row scanner) (models.Order, error) {
+	var order models.Order
+	err := row.Scan(&order.ID, &order.TableID, &order.RestaurantID, &order.OrderDate, &order.TotalPrice,
+		&order.DiscountTotal, &order.GuestCount, &order.Status, &order.Notes, pq.Array(&order.Allergens), &order.CreatedAt, &order.UpdatedAt)
+	return order, err
+}
+
+func scanOrderItem(row scanner) (models.OrderItem, error) {
+	var item models.OrderItem
+	var bumpedAt sql.NullTime
+	err := row.Scan(&item.ID, &item.OrderID, &item.FoodID, &item.ComboID, &item.ParentID, &item.SlotName, &item.FoodName, &item.VariantID,
+		&item.VariantName, &item.MenuID, &item.TaxCategory, pq.Array(&item.Allergens), &item.Quantity, &item.UnitPrice, &item.SubTotal, &item.PrepStatus, &bumpedAt, &item.CreatedAt, &item.UpdatedAt)
+	if bumpedAt.Valid {
+		item.BumpedAt = &bumpedAt.Time
+	}
+	return item, err
+}
+
+type postgresOrderRepository struct {
+	db DBTX
+}
+
+func (r *postgresOrderRepository) List(ctx context.Context, restaurantID uint) ([]models.Order, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+orderColumns+" FROM orders WHERE restaurant_id = $1 ORDER BY order_date DESC", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []models.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := (&postgresOrderItemRepository{db: r.db}).ListByRestaurant(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		for _, item := range items {
+			if item.OrderID == orders[i].ID {
+				orders[i].OrderItems = append(orders[i].OrderItems, item)
+			}
+		}
+	}
+
+	return orders, nil
+}
+
+func (r *postgresOrderRepository) Get(ctx context.Context, id uint) (models.Order, error) {
+	return r.get(ctx, "SELECT "+orderColumns+" FROM orders WHERE id = $1", id)
+}
+
+func (r *postgresOrderRepository) GetForUpdate(ctx context.Context, id uint) (models.Order, error) {
+	return r.get(ctx, "SELECT "+orderColumns+" FROM orders WHERE id = $1 FOR UPDATE", id)
+}
+
+func (r *postgresOrderRepository) get(ctx context.Context, query string, id uint) (models.Order, error) {
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return order, notFound(err)
+	}
+
+	if order.OrderItems, err = (&postgresOrderItemRepository{db: r.db}).ListByOrder(ctx, id); err != nil {
+		return order, err
+	}
+	order.Discounts, err = (&postgresDiscountRepository{db: r.db}).ListByOrder(ctx, id)
+	return order, err
+}
+
+func (r *postgresOrderRepository) Create(ctx context.Context, order models.Order) (models.Order, error) {
+	query := `
+		INSERT INTO orders (table_id, restaurant_id, order_date, status, notes, guest_count, allergens)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), COALESCE($7::TEXT[], '{}'))
+		RETURNING ` + orderColumns
+	return scanOrder(r.db.QueryRowContext(ctx, query, order.TableID, order.RestaurantID, order.OrderDate, order.Status, order.Notes, order.GuestCount,
+		pq.Array(order.Allergens)))
+}
+
+func (r *postgresOrderRepository) CreateBlank(ctx context.Context) (uint, error) {
+	var id uint
+	err := r.db.QueryRowContext(ctx, "INSERT INTO orders (status) VALUES ('pending') RETURNING id").Scan(&id)
+	return id, err
+}
+
+func (r *postgresOrderRepository) Update(ctx context.Context, order models.Order) (models.Order, error) {
+	query := `
+		UPDATE orders
+		SET table_id = $1, restaurant_id = $2, order_date = $3, status = $4, total_price = $5, notes = $6, guest_count = NULLIF($7, 0),
+			allergens = COALESCE($8::TEXT[], '{}'), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $9
+		RETURNING ` + orderColumns
+	updated, err := scanOrder(r.db.QueryRowContext(ctx, query, order.TableID, order.RestaurantID, order.OrderDate, order.Status, order.TotalPrice,
+		order.Notes, order.GuestCount, pq.Array(order.Allergens), order.ID))
+	if err != nil {
+		return updated, notFound(err)
+	}
+	updated.OrderItems = order.OrderItems
+	return updated, nil
+}
+
+func (r *postgresOrderRepository) UpdateStatus(ctx context.Context, id uint, status string) (models.Order, error) {
+	query := `
+		UPDATE orders
+		SET status = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+		RETURNING ` + orderColumns
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, status, id))
+	return order, notFound(err)
+}
+
+func (r *postgresOrderRepository) RecalculateTotal(ctx context.Context, id uint) (models.Order, error) {
+	query := `
+		UPDATE orders
+		SET total_price = (SELECT COALESCE(SUM(subtotal), 0) FROM orderitems WHERE order_id = $1 AND parent_id IS NULL), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING ` + orderColumns
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return order, notFound(err)
+	}
+
+	order.OrderItems, err = (&postgresOrderItemRepository{db: r.db}).ListByOrder(ctx, id)
+	return order, err
+}
+
+func (r *postgresOrderRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM orders WHERE id = $1", id))
+}
+
+const statusChangeColumns = `id, order_id, from_status, to_status, changed_by, changed_at`
+
+func scanStatusChange(row scanner) (models.OrderStatusChange, error) {
+	var change models.OrderStatusChange
+	var changedBy sql.NullInt64
+	err := row.Scan(&change.ID, &change.OrderID, &change.FromStatus, &change.ToStatus, &changedBy, &change.ChangedAt)
+	if changedBy.Valid {
+		actor := uint(changedBy.Int64)
+		change.ChangedBy = &actor
+	}
+	return change, err
+}
+
+func (r *postgresOrderRepository) RecordStatusChange(ctx context.Context, change models.OrderStatusChange) (models.OrderStatusChange, error) {
+	query := `
+		INSERT INTO order_status_history (order_id, from_status, to_status, changed_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + statusChangeColumns
+	return scanStatusChange(r.db.QueryRowContext(ctx, query, change.OrderID, change.FromStatus, change.ToStatus, change.ChangedBy))
+}
+
+func (r *postgresOrderRepository) StatusHistory(ctx context.Context, orderID uint) ([]models.OrderStatusChange, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+statusChangeColumns+" FROM order_status_history WHERE order_id = $1 ORDER BY changed_at ASC, id ASC", orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []models.OrderStatusChange
+	for rows.Next() {
+		change, err := scanStatusChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
+
+func (r *postgresOrderRepository) UpdateDiscountTotal(ctx context.Context, id uint, discountTotal float64) error {
+	return expectAffected(r.db.ExecContext(ctx, "UPDATE orders SET discount_total = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2", discountTotal, id))
+}
+
+func (r *postgresOrderRepository) ListOpen(ctx context.Context, restaurantID uint) ([]models.Order, error) {
+	query := "SELECT " + orderColumns + " FROM orders WHERE restaurant_id = $1 AND status NOT IN ('paid', 'cancelled') ORDER BY COALESCE(order_date, created_at) ASC"
+	rows, err := r.db.QueryContext(ctx, query, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []models.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+func (r *postgresOrderRepository) AverageTurnTime(ctx context.Context, restaurantID uint, since time.Time) (time.Duration, int, error) {
+	query := `
+		SELECT COALESCE(EXTRACT(EPOCH FROM AVG(h.changed_at - COALESCE(o.order_date, o.created_at))), 0), COUNT(*)
+		FROM order_status_history h
+		JOIN orders o ON o.id = h.order_id
+		WHERE o.restaurant_id = $1 AND h.to_status = 'paid' AND h.changed_at >= $2`
+	var seconds float64
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, restaurantID, since).Scan(&seconds, &count); err != nil {
+		return 0, 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), count, nil
+}
+
+type postgresOrderItemRepository struct {
+	db DBTX
+}
+
+func (r *postgresOrderItemRepository) list(ctx context.Context, where string, args ...any) ([]models.OrderItem, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+orderItemColumns+" FROM orderitems oi "+orderItemJoins+" WHERE "+where+" ORDER BY oi.id ASC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.OrderItem
+	for rows.Next() {
+		item, err := scanOrderItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, r.withModifiers(ctx, items)
+}
+
+// listTopLevel lists the items matched by where that are not components of a combo, each
+// combo with its components
+func (r *postgresOrderItemRepository) listTopLevel(ctx context.Context, where string, args ...any) ([]models.OrderItem, error) {
+	items, err := r.list(ctx, where+" AND oi.parent_id IS NULL", args...)
+	if err != nil {
+		return nil, err
+	}
+	return items, r.withComponents(ctx, items)
+}
+
+// withModifiers fills in the modifiers the items were ordered with
+func (r *postgresOrderItemRepository) withModifiers(ctx context.Context, items []models.OrderItem) error {
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = int64(item.ID)
+	}
+	modifiers, err := loadItemModifiers(ctx, r.db, ids)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Modifiers = modifiers[items[i].ID]
+	}
+	return nil
+}
+
+// withComponents fills in the components of the combo items
+func (r *postgresOrderItemRepository) withComponents(ctx context.Context, items []models.OrderItem) error {
+	var ids []int64
+	for _, item := range items {
+		if item.ComboID != 0 {
+			ids = append(ids, int64(item.ID))
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	components, err := r.list(ctx, "oi.parent_id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		for _, component := range components {
+			if component.ParentID == items[i].ID {
+				items[i].Components = append(items[i].Components, component)
+			}
+		}
+		if items[i].ComboID != 0 {
+			items[i].Allergens = models.ComponentAllergens(items[i].Components)
+		}
+	}
+	return nil
+}
+
+// one fills in the modifiers and components of a single item read by a query
+func (r *postgresOrderItemRepository) one(ctx context.Context, item models.OrderItem, err error) (models.OrderItem, error) {
+	if err != nil {
+		return item, notFound(err)
+	}
+	items := []models.OrderItem{item}
+	if err := r.withModifiers(ctx, items); err != nil {
+		return items[0], err
+	}
+	err = r.withComponents(ctx, items)
+	return items[0], err
+}
+
+func (r *postgresOrderItemRepository) ListByRestaurant(ctx context.Context, restaurantID uint) ([]models.OrderItem, error) {
+	return r.listTopLevel(ctx, "oi.order_id IN (SELECT id FROM orders WHERE restaurant_id = $1)", restaurantID)
+}
+
+func (r *postgresOrderItemRepository) ListByOrder(ctx context.Context, orderID uint) ([]models.OrderItem, error) {
+	return r.listTopLevel(ctx, "oi.order_id = $1", orderID)
+}
+
+func (r *postgresOrderItemRepository) Get(ctx context.Context, id uint) (models.OrderItem, error) {
+	item, err := scanOrderItem(r.db.QueryRowContext(ctx, "SELECT "+orderItemColumns+" FROM orderitems oi "+orderItemJoins+" WHERE oi.id = $1", id))
+	return r.one(ctx, item, err)
+}
+
+func (r *postgresOrderItemRepository) Create(ctx context.Context, item models.OrderItem) (models.OrderItem, error) {
+	query := `
+		WITH oi AS (
+			INSERT INTO orderitems (order_id, food_id, combo_id, parent_id, slot_name, quantity, unit_price, subtotal, variant_id, variant_name)
+			VALUES ($1, NULLIF($2, 0), NULLIF($3, 0), NULLIF($4, 0), $5, $6, $7, $8, NULLIF($9, 0), $10)
+			RETURNING *
+		)
+		SELECT ` + orderItemColumns + ` FROM oi ` + orderItemJoins
+	created, err := scanOrderItem(r.db.QueryRowContext(ctx, query, item.OrderID, item.FoodID, item.ComboID, item.ParentID, item.SlotName,
+		item.Quantity, item.UnitPrice, item.SubTotal, item.VariantID, item.VariantName))
+	if err != nil {
+		return created, err
+	}
+
+	for i, modifier := range item.Modifiers {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO orderitem_modifiers (order_item_id, position, modifier_id, group_name, name, price_delta)
+			VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6)`,
+			created.ID, i+1, modifier.ModifierID, modifier.Group, modifier.Name, modifier.PriceDelta)
+		if err != nil {
+			return created, err
+		}
+	}
+	created.Modifiers = item.Modifiers
+
+	for _, component := range item.Components {
+		component.OrderID, component.ParentID = created.OrderID, created.ID
+		if component, err = r.Create(ctx, component); err != nil {
+			return created, err
+		}
+		created.Components = append(created.Components, component)
+	}
+	if created.ComboID != 0 {
+		created.Allergens = models.ComponentAllergens(created.Components)
+	}
+	return created, nil
+}
+
+// UpdateQuantity also scales the components of a combo item, which come in whole multiples
+// of the combo's quantity and carry a whole number of cents per combo
+func (r *postgresOrderItemRepository) UpdateQuantity(ctx context.Context, id uint, quantity uint, subtotal float64) (models.OrderItem, error) {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE orderitems c
+		SET quantity = c.quantity / p.quantity * $1, subtotal = ROUND(c.subtotal / p.quantity * $1, 2), updated_at = CURRENT_TIMESTAMP
+		FROM orderitems p
+		WHERE p.id = $2 AND c.parent_id = p.id`, quantity, id)
+	if err != nil {
+		return models.OrderItem{}, err
+	}
+
+	query := `
+		WITH oi AS (
+			UPDATE orderitems SET quantity = $1, subtotal = $2, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $3
+			RETURNING *
+		)
+		SELECT ` + orderItemColumns + ` FROM oi ` + orderItemJoins
+	item, err := scanOrderItem(r.db.QueryRowContext(ctx, query, quantity, subtotal, id))
+	return r.one(ctx, item, err)
+}
+
+func (r *postgresOrderItemRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM orderitems WHERE id = $1", id))
+}
+
+// Bump also marks a combo prepared once its last component is
+func (r *postgresOrderItemRepository) Bump(ctx context.Context, id uint) (models.OrderItem, error) {
+	query := `
+		WITH oi AS (
+			UPDATE orderitems SET prep_status = 'done', bumped_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + orderItemColumns + ` FROM oi ` + orderItemJoins
+	item, err := scanOrderItem(r.db.QueryRowContext(ctx, query, id))
+	if item, err = r.one(ctx, item, err); err != nil || item.ParentID == 0 {
+		return item, err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE orderitems p SET prep_status = 'done', bumped_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE p.id = $1 AND NOT EXISTS (SELECT 1 FROM orderitems c WHERE c.parent_id = p.id AND c.prep_status <> 'done')`, item.ParentID)
+	return item, err
+}
+
+// SalesByFood reads the items of paid orders, combos through their components
+func (r *postgresOrderItemRepository) SalesByFood(ctx context.Context, restaurantID uint, from, to time.Time) ([]models.FoodSales, error) {
+	query := `
+		SELECT oi.food_id, f.name, SUM(oi.quantity), SUM(oi.subtotal),
+			COALESCE(SUM(oi.quantity) FILTER (WHERE oi.parent_id IS NOT NULL), 0),
+			COALESCE(SUM(oi.subtotal) FILTER (WHERE oi.parent_id IS NOT NULL), 0)
+		FROM orderitems oi
+		JOIN orders o ON o.id = oi.order_id
+		JOIN foods f ON f.id = oi.food_id
+		WHERE o.restaurant_id = $1 AND o.status = 'paid'
+			AND ($2::timestamp IS NULL OR COALESCE(o.order_date, o.created_at) >= $2)
+			AND ($3::timestamp IS NULL OR COALESCE(o.order_date, o.created_at) < $3)
+		GROUP BY oi.food_id, f.name
+		ORDER BY SUM(oi.subtotal) DESC, oi.food_id ASC`
+	rows, err := r.db.QueryContext(ctx, query, restaurantID, nullTime(from), nullTime(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sales []models.FoodSales
+	for rows.Next() {
+		var food models.FoodSales
+		if err := rows.Scan(&food.FoodID, &food.Name, &food.Quantity, &food.Revenue, &food.ComboQuantity, &food.ComboRevenue); err != nil {
+			return nil, err
+		}
+		sales = append(sales, food)
+	}
+	return sales, rows.Err()
+}
+
+// A food's own station wins over its menu's station
+const ticketColumns = `oi.id, o.id, COALESCE(o.table_id, 0), COALESCE(f.station_id, m.station_id, 0), COALESCE(oi.food_id, 0),
+	COALESCE(f.name, ''), oi.variant_name, COALESCE(pc.name, ''), COALESCE(oi.quantity, 1), COALESCE(f.prep_time, 0), oi.prep_status,
+	COALESCE(o.notes, ''), COALESCE(o.order_date, o.created_at),
+	ARRAY(SELECT a FROM unnest(o.allergens) a WHERE a = ANY(f.allergens))`
+
+// Combo items are prepared through their components, which name the combo they are part of
+const ticketJoins = `
+		JOIN orders o ON o.id = oi.order_id
+		LEFT JOIN foods f ON f.id = oi.food_id
+		LEFT JOIN menus m ON m.id = f.menu_id
+		LEFT JOIN orderitems p ON p.id = oi.parent_id
+		LEFT JOIN combos pc ON pc.id = p.combo_id`
+
+func (r *postgresOrderItemRepository) StationQueue(ctx context.Context, restaurantID, stationID uint) ([]models.StationTicket, error) {
+	query := `
+		SELECT ` + ticketColumns + `
+		FROM orderitems oi` + ticketJoins + `
+		WHERE o.restaurant_id = $1 AND o.status = 'preparing' AND oi.prep_status = 'queued' AND oi.combo_id IS NULL
+			AND ($2 = 0 OR COALESCE(f.station_id, m.station_id) = $2)
+		ORDER BY COALESCE(o.order_date, o.created_at) ASC, COALESCE(f.prep_time, 0) DESC, oi.id ASC`
+	rows, err := r.db.QueryContext(ctx, query, restaurantID, stationID)
+	if err != nil {
+		return nil, err
+	}
+	return r.scanTickets(ctx, rows)
+}
+
+func (r *postgresOrderItemRepository) ListTicketsByOrder(ctx context.Context, orderID uint) ([]models.StationTicket, error) {
+	query := `
+		SELECT ` + ticketColumns + `
+		FROM orderitems oi` + ticketJoins + `
+		WHERE oi.order_id = $1 AND oi.combo_id IS NULL
+		ORDER BY oi.id ASC`
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return r.scanTickets(ctx, rows)
+}
+
+// scanTickets reads the tickets of a query along with the names of the modifiers their items were ordered with
+func (r *postgresOrderItemRepository) scanTickets(ctx context.Context, rows *sql.Rows) ([]models.StationTicket, error) {
+	defer rows.Close()
+
+	var tickets []models.StationTicket
+	for rows.Next() {
+		var ticket models.StationTicket
+		err := rows.Scan(&ticket.OrderItemID, &ticket.OrderID, &ticket.TableID, &ticket.StationID, &ticket.FoodID,
+			&ticket.FoodName, &ticket.VariantName, &ticket.ComboName, &ticket.Quantity, &ticket.PrepTime, &ticket.PrepStatus, &ticket.OrderNotes, &ticket.OrderDate,
+			pq.Array(&ticket.Allergens))
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	ids := make([]int64, len(tickets))
+	for i, ticket := range tickets {
+		ids[i] = int64(ticket.OrderItemID)
+	}
+	modifiers, err := loadItemModifiers(ctx, r.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tickets {
+		for _, modifier := range modifiers[tickets[i].OrderItemID] {
+			tickets[i].Modifiers = append(tickets[i].Modifiers, modifier.Name)
+		}
+	}
+	return tickets, nil
+}
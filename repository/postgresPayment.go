@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"restaurant-management/models"
+)
+
+const paymentColumns = `p.id, p.invoice_id, p.amount, p.tip, p.tip_staff_id, p.method, p.reference, p.provider, COALESCE(p.transaction_id, ''),
+	p.recorded_by, p.created_at,
+	(SELECT COALESCE(SUM(r.amount), 0) FROM refunds r WHERE r.payment_id = p.id AND r.status <> 'failed')`
+
+func scanPayment(row scanner) (models.Payment, error) {
+	var payment models.Payment
+	var tipStaff, recordedBy sql.NullInt64
+	err := row.Scan(&payment.ID, &payment.InvoiceID, &payment.Amount, &payment.Tip, &tipStaff, &payment.Method, &payment.Reference,
+		&payment.Provider, &payment.TransactionID, &recordedBy, &payment.CreatedAt, &payment.Refunded)
+	payment.TipStaffID, payment.RecordedBy = nullUser(tipStaff), nullUser(recordedBy)
+	return payment, err
+}
+
+type postgresPaymentRepository struct {
+	db DBTX
+}
+
+func (r *postgresPaymentRepository) ListByInvoice(ctx context.Context, invoiceID uint) ([]models.Payment, error) {
+	return listPayments(ctx, r.db, invoiceID)
+}
+
+// listPayments loads the payments taken against an invoice and its parts, oldest first
+func listPayments(ctx context.Context, db DBTX, invoiceID uint) ([]models.Payment, error) {
+	query := "SELECT " + paymentColumns + " FROM payments p JOIN invoices i ON i.id = p.invoice_id WHERE i.id = $1 OR i.parent_id = $1 ORDER BY p.id ASC"
+	rows, err := db.QueryContext(ctx, query, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []models.Payment
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+func (r *postgresPaymentRepository) Create(ctx context.Context, payment models.Payment) (models.Payment, error) {
+	query := `
+		INSERT INTO payments AS p (invoice_id, amount, tip, tip_staff_id, method, reference, provider, transaction_id, recorded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)
+		RETURNING ` + paymentColumns
+	saved, err := scanPayment(r.db.QueryRowContext(ctx, query, payment.InvoiceID, payment.Amount, payment.Tip, payment.TipStaffID, payment.Method,
+		payment.Reference, payment.Provider, payment.TransactionID, payment.RecordedBy))
+	return saved, duplicate(err)
+}
+
+func (r *postgresPaymentRepository) TipsByStaff(ctx context.Context, restaurantID uint, from, to time.Time) ([]models.TipSummary, error) {
+	query := `
+		SELECT p.tip_staff_id, COALESCE(u.username, ''), SUM(p.tip), COUNT(*)
+		FROM payments p
+		JOIN invoices i ON i.id = p.invoice_id
+		LEFT JOIN users u ON u.id = p.tip_staff_id
+		WHERE i.restaurant_id = $1 AND p.tip > 0
+			AND ($2::timestamp IS NULL OR p.created_at >= $2) AND ($3::timestamp IS NULL OR p.created_at < $3)
+		GROUP BY p.tip_staff_id, u.username
+		ORDER BY SUM(p.tip) DESC, p.tip_staff_id ASC`
+	rows, err := r.db.QueryContext(ctx, query, restaurantID, nullTime(from), nullTime(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tips []models.TipSummary
+	for rows.Next() {
+		var summary models.TipSummary
+		var staff sql.NullInt64
+		if err := rows.Scan(&staff, &summary.Username, &summary.Tips, &summary.Payments); err != nil {
+			return nil, err
+		}
+		summary.StaffID = nullUser(staff)
+		tips = append(tips, summary)
+	}
+	return tips, rows.Err()
+}
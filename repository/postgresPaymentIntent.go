@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"restaurant-management/models"
+)
+
+const paymentIntentColumns = `id, invoice_id, provider, provider_intent_id, amount, tip, tip_staff_id, currency, status, failure_reason,
+	COALESCE(payment_id, 0), created_by, created_at, updated_at`
+
+func scanPaymentIntent(row scanner) (models.PaymentIntent, error) {
+	var intent models.PaymentIntent
+	var tipStaff, createdBy sql.NullInt64
+	err := row.Scan(&intent.ID, &intent.InvoiceID, &intent.Provider, &intent.ProviderIntentID, &intent.Amount, &intent.Tip, &tipStaff,
+		&intent.Currency, &intent.Status, &intent.FailureReason, &intent.PaymentID, &createdBy, &intent.CreatedAt, &intent.UpdatedAt)
+	intent.TipStaffID, intent.CreatedBy = nullUser(tipStaff), nullUser(createdBy)
+	return intent, err
+}
+
+type postgresPaymentIntentRepository struct {
+	db DBTX
+}
+
+func (r *postgresPaymentIntentRepository) ListByInvoice(ctx context.Context, invoiceID uint) ([]models.PaymentIntent, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+paymentIntentColumns+" FROM payment_intents WHERE invoice_id = $1 ORDER BY id ASC", invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var intents []models.PaymentIntent
+	for rows.Next() {
+		intent, err := scanPaymentIntent(rows)
+		if err != nil {
+			return nil, err
+		}
+		intents = append(intents, intent)
+	}
+	return intents, rows.Err()
+}
+
+func (r *postgresPaymentIntentRepository) Get(ctx context.Context, id uint) (models.PaymentIntent, error) {
+	intent, err := scanPaymentIntent(r.db.QueryRowContext(ctx, "SELECT "+paymentIntentColumns+" FROM payment_intents WHERE id = $1", id))
+	return intent, notFound(err)
+}
+
+func (r *postgresPaymentIntentRepository) GetByProviderID(ctx context.Context, provider, providerIntentID string) (models.PaymentIntent, error) {
+	query := "SELECT " + paymentIntentColumns + " FROM payment_intents WHERE provider = $1 AND provider_intent_id = $2"
+	intent, err := scanPaymentIntent(r.db.QueryRowContext(ctx, query, provider, providerIntentID))
+	return intent, notFound(err)
+}
+
+func (r *postgresPaymentIntentRepository) Create(ctx context.Context, intent models.PaymentIntent) (models.PaymentIntent, error) {
+	query := `
+		INSERT INTO payment_intents (invoice_id, provider, provider_intent_id, amount, tip, tip_staff_id, currency, status, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + paymentIntentColumns
+	created, err := scanPaymentIntent(r.db.QueryRowContext(ctx, query, intent.InvoiceID, intent.Provider, intent.ProviderIntentID,
+		intent.Amount, intent.Tip, intent.TipStaffID, intent.Currency, intent.Status, intent.CreatedBy))
+	return created, duplicate(err)
+}
+
+func (r *postgresPaymentIntentRepository) UpdateStatus(ctx context.Context, id uint, status, failureReason string, paymentID uint) (models.PaymentIntent, error) {
+	query := `
+		UPDATE payment_intents
+		SET status = $1, failure_reason = $2, payment_id = NULLIF($3, 0), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING ` + paymentIntentColumns
+	intent, err := scanPaymentIntent(r.db.QueryRowContext(ctx, query, status, failureReason, paymentID, id))
+	return intent, notFound(err)
+}
+
+type postgresWebhookEventRepository struct {
+	db DBTX
+}
+
+func (r *postgresWebhookEventRepository) Record(ctx context.Context, event models.WebhookEvent) (bool, error) {
+	query := `
+		INSERT INTO webhook_events (provider, event_id, event_type)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, event_id) DO NOTHING`
+	result, err := r.db.ExecContext(ctx, query, event.Provider, event.EventID, event.EventType)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected == 1, err
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"restaurant-management/models"
+)
+
+const promotionColumns = `id, restaurant_id, name, COALESCE(code, ''), kind, value, buy_quantity, get_quantity, scope,
+	COALESCE(menu_id, 0), COALESCE(food_id, 0), COALESCE(usage_limit, 0), times_used, starts_at, ends_at, disabled,
+	created_at, updated_at`
+
+func scanPromotion(row scanner) (models.Promotion, error) {
+	var promotion models.Promotion
+	var startsAt, endsAt sql.NullTime
+	err := row.Scan(&promotion.ID, &promotion.RestaurantID, &promotion.Name, &promotion.Code, &promotion.Kind,
+		&promotion.Value, &promotion.BuyQuantity, &promotion.GetQuantity, &promotion.Scope, &promotion.MenuID,
+		&promotion.FoodID, &promotion.UsageLimit, &promotion.TimesUsed, &startsAt, &endsAt, &promotion.Disabled,
+		&promotion.CreatedAt, &promotion.UpdatedAt)
+	if startsAt.Valid {
+		promotion.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		promotion.EndsAt = &endsAt.Time
+	}
+	return promotion, err
+}
+
+type postgresPromotionRepository struct {
+	db DBTX
+}
+
+func (r *postgresPromotionRepository) List(ctx context.Context, restaurantID uint) ([]models.Promotion, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+promotionColumns+" FROM promotions WHERE restaurant_id = $1 ORDER BY id DESC", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []models.Promotion
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+	return promotions, rows.Err()
+}
+
+func (r *postgresPromotionRepository) Get(ctx context.Context, id uint) (models.Promotion, error) {
+	promotion, err := scanPromotion(r.db.QueryRowContext(ctx, "SELECT "+promotionColumns+" FROM promotions WHERE id = $1", id))
+	return promotion, notFound(err)
+}
+
+func (r *postgresPromotionRepository) GetForUpdate(ctx context.Context, id uint) (models.Promotion, error) {
+	promotion, err := scanPromotion(r.db.QueryRowContext(ctx, "SELECT "+promotionColumns+" FROM promotions WHERE id = $1 FOR UPDATE", id))
+	return promotion, notFound(err)
+}
+
+func (r *postgresPromotionRepository) GetByCodeForUpdate(ctx context.Context, restaurantID uint, code string) (models.Promotion, error) {
+	query := "SELECT " + promotionColumns + " FROM promotions WHERE restaurant_id = $1 AND code = $2 FOR UPDATE"
+	promotion, err := scanPromotion(r.db.QueryRowContext(ctx, query, restaurantID, code))
+	return promotion, notFound(err)
+}
+
+func (r *postgresPromotionRepository) Create(ctx context.Context, promotion models.Promotion) (models.Promotion, error) {
+	query := `
+		INSERT INTO promotions (restaurant_id, name, code, kind, value, buy_quantity, get_quantity, scope, menu_id, food_id,
+			usage_limit, starts_at, ends_at, disabled)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, NULLIF($9, 0), NULLIF($10, 0), NULLIF($11, 0), $12, $13, $14)
+		RETURNING ` + promotionColumns
+	created, err := scanPromotion(r.db.QueryRowContext(ctx, query, promotion.RestaurantID, promotion.Name, promotion.Code,
+		promotion.Kind, promotion.Value, promotion.BuyQuantity, promotion.GetQuantity, promotion.Scope, promotion.MenuID,
+		promotion.FoodID, promotion.UsageLimit, promotion.StartsAt, promotion.EndsAt, promotion.Disabled))
+	return created, duplicate(err)
+}
+
+func (r *postgresPromotionRepository) Update(ctx context.Context, promotion models.Promotion) (models.Promotion, error) {
+	query := `
+		UPDATE promotions
+		SET name = $1, code = NULLIF($2, ''), kind = $3, value = $4, buy_quantity = $5, get_quantity = $6, scope = $7,
+			menu_id = NULLIF($8, 0), food_id = NULLIF($9, 0), usage_limit = NULLIF($10, 0), starts_at = $11, ends_at = $12,
+			disabled = $13, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $14
+		RETURNING ` + promotionColumns
+	updated, err := scanPromotion(r.db.QueryRowContext(ctx, query, promotion.Name, promotion.Code, promotion.Kind,
+		promotion.Value, promotion.BuyQuantity, promotion.GetQuantity, promotion.Scope, promotion.MenuID, promotion.FoodID,
+		promotion.UsageLimit, promotion.StartsAt, promotion.EndsAt, promotion.Disabled, promotion.ID))
+	return updated, duplicate(notFound(err))
+}
+
+func (r *postgresPromotionRepository) AddUsage(ctx context.Context, id uint, delta int) error {
+	return expectAffected(r.db.ExecContext(ctx, "UPDATE promotions SET times_used = GREATEST(times_used + $1, 0) WHERE id = $2", delta, id))
+}
+
+func (r *postgresPromotionRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM promotions WHERE id = $1", id))
+}
+
+const discountColumns = `id, order_id, COALESCE(promotion_id, 0), description, kind, value, buy_quantity, get_quantity, scope,
+	COALESCE(menu_id, 0), COALESCE(food_id, 0), amount, status, requested_by, approved_by, created_at, updated_at`
+
+func scanDiscount(row scanner) (models.OrderDiscount, error) {
+	var discount models.OrderDiscount
+	var requestedBy, approvedBy sql.NullInt64
+	err := row.Scan(&discount.ID, &discount.OrderID, &discount.PromotionID, &discount.Description, &discount.Kind,
+		&discount.Value, &discount.BuyQuantity, &discount.GetQuantity, &discount.Scope, &discount.MenuID, &discount.FoodID,
+		&discount.Amount, &discount.Status, &requestedBy, &approvedBy, &discount.CreatedAt, &discount.UpdatedAt)
+	discount.RequestedBy, discount.ApprovedBy = nullUser(requestedBy), nullUser(approvedBy)
+	return discount, err
+}
+
+func nullUser(id sql.NullInt64) *uint {
+	if !id.Valid {
+		return nil
+	}
+	user := uint(id.Int64)
+	return &user
+}
+
+type postgresDiscountRepository struct {
+	db DBTX
+}
+
+func (r *postgresDiscountRepository) ListByOrder(ctx context.Context, orderID uint) ([]models.OrderDiscount, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+discountColumns+" FROM order_discounts WHERE order_id = $1 ORDER BY id ASC", orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var discounts []models.OrderDiscount
+	for rows.Next() {
+		discount, err := scanDiscount(rows)
+		if err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, discount)
+	}
+	return discounts, rows.Err()
+}
+
+func (r *postgresDiscountRepository) Get(ctx context.Context, id uint) (models.OrderDiscount, error) {
+	discount, err := scanDiscount(r.db.QueryRowContext(ctx, "SELECT "+discountColumns+" FROM order_discounts WHERE id = $1", id))
+	return discount, notFound(err)
+}
+
+func (r *postgresDiscountRepository) Create(ctx context.Context, discount models.OrderDiscount) (models.OrderDiscount, error) {
+	query := `
+		INSERT INTO order_discounts (order_id, promotion_id, description, kind, value, buy_quantity, get_quantity, scope,
+			menu_id, food_id, status, requested_by, approved_by)
+		VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7, $8, NULLIF($9, 0), NULLIF($10, 0), $11, $12, $13)
+		RETURNING ` + discountColumns
+	created, err := scanDiscount(r.db.QueryRowContext(ctx, query, discount.OrderID, discount.PromotionID, discount.Description,
+		discount.Kind, discount.Value, discount.BuyQuantity, discount.GetQuantity, discount.Scope, discount.MenuID,
+		discount.FoodID, discount.Status, discount.RequestedBy, discount.ApprovedBy))
+	return created, duplicate(err)
+}
+
+func (r *postgresDiscountRepository) Approve(ctx context.Context, id uint, approvedBy *uint) (models.OrderDiscount, error) {
+	query := `
+		UPDATE order_discounts
+		SET status = 'approved', approved_by = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+		RETURNING ` + discountColumns
+	discount, err := scanDiscount(r.db.QueryRowContext(ctx, query, approvedBy, id))
+	return discount, notFound(err)
+}
+
+func (r *postgresDiscountRepository) UpdateAmount(ctx context.Context, id uint, amount float64) error {
+	return expectAffected(r.db.ExecContext(ctx, "UPDATE order_discounts SET amount = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2", amount, id))
+}
+
+func (r *postgresDiscountRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM order_discounts WHERE id = $1", id))
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"restaurant-management/models"
+)
+
+const reservationColumns = `id, restaurant_id, table_id, guest_name, COALESCE(guest_phone, ''), COALESCE(guest_email, ''),
+	party_size, starts_at, duration_minutes, status, COALESCE(notes, ''), created_at, updated_at`
+
+// reservationOverlaps matches reservations whose slot overlaps [start, end), formatted with the
+// placeholder numbers of end and start
+const reservationOverlaps = `starts_at < $%d AND starts_at + duration_minutes * INTERVAL '1 minute' > $%d`
+
+func scanReservation(row scanner) (models.Reservation, error) {
+	var reservation models.Reservation
+	err := row.Scan(&reservation.ID, &reservation.RestaurantID, &reservation.TableID, &reservation.GuestName,
+		&reservation.GuestPhone, &reservation.GuestEmail, &reservation.PartySize, &reservation.StartsAt,
+		&reservation.DurationMinutes, &reservation.Status, &reservation.Notes, &reservation.CreatedAt, &reservation.UpdatedAt)
+	return reservation, err
+}
+
+type postgresReservationRepository struct {
+	db DBTX
+}
+
+func (r *postgresReservationRepository) list(ctx context.Context, where string, args ...any) ([]models.Reservation, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+reservationColumns+" FROM reservations WHERE "+where+" ORDER BY starts_at ASC, id ASC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []models.Reservation
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+	return reservations, rows.Err()
+}
+
+func (r *postgresReservationRepository) List(ctx context.Context, restaurantID uint, from, to time.Time) ([]models.Reservation, error) {
+	where := "restaurant_id = $1 AND ($2::timestamp IS NULL OR starts_at >= $2) AND ($3::timestamp IS NULL OR starts_at < $3)"
+	return r.list(ctx, where, restaurantID, nullTime(from), nullTime(to))
+}
+
+func (r *postgresReservationRepository) Get(ctx context.Context, id uint) (models.Reservation, error) {
+	reservation, err := scanReservation(r.db.QueryRowContext(ctx, "SELECT "+reservationColumns+" FROM reservations WHERE id = $1", id))
+	return reservation, notFound(err)
+}
+
+func (r *postgresReservationRepository) Create(ctx context.Context, reservation models.Reservation) (models.Reservation, error) {
+	query := `
+		INSERT INTO reservations (restaurant_id, table_id, guest_name, guest_phone, guest_email, party_size, starts_at, duration_minutes, status, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + reservationColumns
+	return scanReservation(r.db.QueryRowContext(ctx, query, reservation.RestaurantID, reservation.TableID, reservation.GuestName,
+		reservation.GuestPhone, reservation.GuestEmail, reservation.PartySize, reservation.StartsAt, reservation.DurationMinutes,
+		reservation.Status, reservation.Notes))
+}
+
+func (r *postgresReservationRepository) Update(ctx context.Context, reservation models.Reservation) (models.Reservation, error) {
+	query := `
+		UPDATE reservations
+		SET table_id = $1, guest_name = $2, guest_phone = $3, guest_email = $4, party_size = $5, starts_at = $6,
+			duration_minutes = $7, notes = $8, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $9
+		RETURNING ` + reservationColumns
+	updated, err := scanReservation(r.db.QueryRowContext(ctx, query, reservation.TableID, reservation.GuestName, reservation.GuestPhone,
+		reservation.GuestEmail, reservation.PartySize, reservation.StartsAt, reservation.DurationMinutes, reservation.Notes, reservation.ID))
+	return updated, notFound(err)
+}
+
+func (r *postgresReservationRepository) UpdateStatus(ctx context.Context, id uint, status string) (models.Reservation, error) {
+	query := "UPDATE reservations SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 RETURNING " + reservationColumns
+	reservation, err := scanReservation(r.db.QueryRowContext(ctx, query, status, id))
+	return reservation, notFound(err)
+}
+
+func (r *postgresReservationRepository) Overlapping(ctx context.Context, tableID uint, start, end time.Time, excludeID uint) ([]models.Reservation, error) {
+	where := "table_id = $1 AND id <> $2 AND status IN ('booked', 'seated') AND " + fmt.Sprintf(reservationOverlaps, 3, 4)
+	return r.list(ctx, where, tableID, excludeID, end, start)
+}
+
+func (r *postgresReservationRepository) AvailableTables(ctx context.Context, restaurantID uint, partySize int, start, end time.Time) ([]models.Table, error) {
+	query := `
+		SELECT ` + tableColumns + ` FROM tables t
+		WHERE t.restaurant_id = $1 AND t.capacity >= $2 AND NOT EXISTS (
+			SELECT 1 FROM reservations
+			WHERE table_id = t.id AND status IN ('booked', 'seated') AND ` + fmt.Sprintf(reservationOverlaps, 3, 4) + `
+		)
+		ORDER BY t.capacity ASC, t.id ASC`
+	rows, err := r.db.QueryContext(ctx, query, restaurantID, partySize, end, start)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []models.Table
+	for rows.Next() {
+		table, err := scanTable(rows)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+func (r *postgresReservationRepository) BookedBefore(ctx context.Context, before time.Time) ([]models.Reservation, error) {
+	return r.list(ctx, "status = 'booked' AND starts_at < $1", before)
+}
+
+// nullTime passes the zero time as NULL
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
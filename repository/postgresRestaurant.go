@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+const restaurantColumns = `id, name, owner_id, COALESCE(logo, ''), address, COALESCE(description, ''), time_zone, created_at, updated_at`
+
+const staffColumns = `id, restaurant_id, user_id, role, created_at, updated_at`
+
+func scanRestaurant(row scanner) (models.Restaurant, error) {
+	var restaurant models.Restaurant
+	err := row.Scan(&restaurant.ID, &restaurant.Name, &restaurant.OwnerID, &restaurant.Logo, &restaurant.Address, &restaurant.Description, &restaurant.TimeZone, &restaurant.CreatedAt, &restaurant.UpdatedAt)
+	return restaurant, err
+}
+
+func scanStaff(row scanner) (models.RestaurantStaff, error) {
+	var member models.RestaurantStaff
+	err := row.Scan(&member.ID, &member.RestaurantID, &member.UserID, &member.Role, &member.CreatedAt, &member.UpdatedAt)
+	return member, err
+}
+
+type postgresRestaurantRepository struct {
+	db DBTX
+}
+
+func (r *postgresRestaurantRepository) list(ctx context.Context, query string, args ...any) ([]models.Restaurant, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var restaurants []models.Restaurant
+	for rows.Next() {
+		restaurant, err := scanRestaurant(rows)
+		if err != nil {
+			return nil, err
+		}
+		restaurants = append(restaurants, restaurant)
+	}
+	return restaurants, rows.Err()
+}
+
+func (r *postgresRestaurantRepository) List(ctx context.Context) ([]models.Restaurant, error) {
+	return r.list(ctx, "SELECT "+restaurantColumns+" FROM restaurants ORDER BY id ASC")
+}
+
+func (r *postgresRestaurantRepository) ListByOwner(ctx context.Context, ownerID uint) ([]models.Restaurant, error) {
+	return r.list(ctx, "SELECT "+restaurantColumns+" FROM restaurants WHERE owner_id = $1 ORDER BY id ASC", ownerID)
+}
+
+func (r *postgresRestaurantRepository) Get(ctx context.Context, id uint) (models.Restaurant, error) {
+	restaurant, err := scanRestaurant(r.db.QueryRowContext(ctx, "SELECT "+restaurantColumns+" FROM restaurants WHERE id = $1", id))
+	return restaurant, notFound(err)
+}
+
+func (r *postgresRestaurantRepository) Create(ctx context.Context, restaurant models.Restaurant) (models.Restaurant, error) {
+	query := "INSERT INTO restaurants (name, owner_id, logo, address, description, time_zone) VALUES ($1, $2, $3, $4, $5, $6) RETURNING " + restaurantColumns
+	return scanRestaurant(r.db.QueryRowContext(ctx, query, restaurant.Name, restaurant.OwnerID, restaurant.Logo, restaurant.Address, restaurant.Description, restaurant.TimeZone))
+}
+
+func (r *postgresRestaurantRepository) Update(ctx context.Context, restaurant models.Restaurant) (models.Restaurant, error) {
+	query := "UPDATE restaurants SET name = $1, owner_id = $2, logo = $3, address = $4, description = $5, time_zone = $6, updated_at = CURRENT_TIMESTAMP WHERE id = $7 RETURNING " + restaurantColumns
+	updated, err := scanRestaurant(r.db.QueryRowContext(ctx, query, restaurant.Name, restaurant.OwnerID, restaurant.Logo, restaurant.Address, restaurant.Description, restaurant.TimeZone, restaurant.ID))
+	return updated, notFound(err)
+}
+
+func (r *postgresRestaurantRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM restaurants WHERE id = $1", id))
+}
+
+func (r *postgresRestaurantRepository) ListStaff(ctx context.Context, restaurantID uint) ([]models.RestaurantStaff, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+staffColumns+" FROM restaurant_staff WHERE restaurant_id = $1 ORDER BY id ASC", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var staff []models.RestaurantStaff
+	for rows.Next() {
+		member, err := scanStaff(rows)
+		if err != nil {
+			return nil, err
+		}
+		staff = append(staff, member)
+	}
+	return staff, rows.Err()
+}
+
+func (r *postgresRestaurantRepository) UpsertStaff(ctx context.Context, member models.RestaurantStaff) (models.RestaurantStaff, error) {
+	query := `
+		INSERT INTO restaurant_staff (restaurant_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (restaurant_id, user_id)
+		DO UPDATE SET role = EXCLUDED.role, updated_at = CURRENT_TIMESTAMP
+		RETURNING ` + staffColumns
+	return scanStaff(r.db.QueryRowContext(ctx, query, member.RestaurantID, member.UserID, member.Role))
+}
+
+func (r *postgresRestaurantRepository) RemoveStaff(ctx context.Context, restaurantID, userID uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM restaurant_staff WHERE restaurant_id = $1 AND user_id = $2", restaurantID, userID))
+}
+
+func (r *postgresRestaurantRepository) Memberships(ctx context.Context, userID uint) ([]models.Membership, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, 'owner' FROM restaurants WHERE owner_id = $1
+		UNION ALL
+		SELECT restaurant_id, role FROM restaurant_staff WHERE user_id = $1 AND restaurant_id NOT IN (SELECT id FROM restaurants WHERE owner_id = $1)
+		ORDER BY 1
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []models.Membership
+	for rows.Next() {
+		var membership models.Membership
+		if err := rows.Scan(&membership.RestaurantID, &membership.Role); err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, membership)
+	}
+	return memberships, rows.Err()
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+const serviceChargeColumns = `id, restaurant_id, name, rate, min_guests, created_at, updated_at`
+
+func scanServiceCharge(row scanner) (models.ServiceCharge, error) {
+	var charge models.ServiceCharge
+	err := row.Scan(&charge.ID, &charge.RestaurantID, &charge.Name, &charge.Rate, &charge.MinGuests, &charge.CreatedAt, &charge.UpdatedAt)
+	return charge, err
+}
+
+type postgresServiceChargeRepository struct {
+	db DBTX
+}
+
+func (r *postgresServiceChargeRepository) List(ctx context.Context, restaurantID uint) ([]models.ServiceCharge, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+serviceChargeColumns+" FROM service_charges WHERE restaurant_id = $1 ORDER BY id ASC", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var charges []models.ServiceCharge
+	for rows.Next() {
+		charge, err := scanServiceCharge(rows)
+		if err != nil {
+			return nil, err
+		}
+		charges = append(charges, charge)
+	}
+	return charges, rows.Err()
+}
+
+func (r *postgresServiceChargeRepository) Get(ctx context.Context, id uint) (models.ServiceCharge, error) {
+	charge, err := scanServiceCharge(r.db.QueryRowContext(ctx, "SELECT "+serviceChargeColumns+" FROM service_charges WHERE id = $1", id))
+	return charge, notFound(err)
+}
+
+func (r *postgresServiceChargeRepository) Create(ctx context.Context, charge models.ServiceCharge) (models.ServiceCharge, error) {
+	query := `
+		INSERT INTO service_charges (restaurant_id, name, rate, min_guests)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + serviceChargeColumns
+	created, err := scanServiceCharge(r.db.QueryRowContext(ctx, query, charge.RestaurantID, charge.Name, charge.Rate, charge.MinGuests))
+	return created, duplicate(err)
+}
+
+func (r *postgresServiceChargeRepository) Update(ctx context.Context, charge models.ServiceCharge) (models.ServiceCharge, error) {
+	query := `
+		UPDATE service_charges
+		SET name = $1, rate = $2, min_guests = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING ` + serviceChargeColumns
+	updated, err := scanServiceCharge(r.db.QueryRowContext(ctx, query, charge.Name, charge.Rate, charge.MinGuests, charge.ID))
+	return updated, duplicate(notFound(err))
+}
+
+func (r *postgresServiceChargeRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM service_charges WHERE id = $1", id))
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+const stationColumns = `id, restaurant_id, name, printer_address, created_at, updated_at`
+
+func scanStation(row scanner) (models.Station, error) {
+	var station models.Station
+	err := row.Scan(&station.ID, &station.RestaurantID, &station.Name, &station.PrinterAddress, &station.CreatedAt, &station.UpdatedAt)
+	return station, err
+}
+
+type postgresStationRepository struct {
+	db DBTX
+}
+
+func (r *postgresStationRepository) List(ctx context.Context, restaurantID uint) ([]models.Station, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+stationColumns+" FROM stations WHERE restaurant_id = $1 ORDER BY name ASC", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stations []models.Station
+	for rows.Next() {
+		station, err := scanStation(rows)
+		if err != nil {
+			return nil, err
+		}
+		stations = append(stations, station)
+	}
+	return stations, rows.Err()
+}
+
+func (r *postgresStationRepository) Get(ctx context.Context, id uint) (models.Station, error) {
+	station, err := scanStation(r.db.QueryRowContext(ctx, "SELECT "+stationColumns+" FROM stations WHERE id = $1", id))
+	return station, notFound(err)
+}
+
+func (r *postgresStationRepository) Create(ctx context.Context, station models.Station) (models.Station, error) {
+	created, err := scanStation(r.db.QueryRowContext(ctx, "INSERT INTO stations (restaurant_id, name, printer_address) VALUES ($1, $2, $3) RETURNING "+stationColumns,
+		station.RestaurantID, station.Name, station.PrinterAddress))
+	return created, duplicate(err)
+}
+
+func (r *postgresStationRepository) Update(ctx context.Context, station models.Station) (models.Station, error) {
+	updated, err := scanStation(r.db.QueryRowContext(ctx, "UPDATE stations SET name = $1, printer_address = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3 RETURNING "+stationColumns,
+		station.Name, station.PrinterAddress, station.ID))
+	return updated, duplicate(notFound(err))
+}
+
+func (r *postgresStationRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM stations WHERE id = $1", id))
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+const tableColumns = `id, name, capacity, restaurant_id, COALESCE(location, ''), status, created_at, updated_at`
+
+func scanTable(row scanner) (models.Table, error) {
+	var table models.Table
+	err := row.Scan(&table.ID, &table.Name, &table.Capacity, &table.RestaurantID, &table.Location, &table.Status, &table.CreatedAt, &table.UpdatedAt)
+	return table, err
+}
+
+type postgresTableRepository struct {
+	db DBTX
+}
+
+func (r *postgresTableRepository) List(ctx context.Context, restaurantID uint) ([]models.Table, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+tableColumns+" FROM tables WHERE ($1 = 0 OR restaurant_id = $1) ORDER BY id ASC", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []models.Table
+	for rows.Next() {
+		table, err := scanTable(rows)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+func (r *postgresTableRepository) Get(ctx context.Context, id uint) (models.Table, error) {
+	table, err := scanTable(r.db.QueryRowContext(ctx, "SELECT "+tableColumns+" FROM tables WHERE id = $1", id))
+	return table, notFound(err)
+}
+
+func (r *postgresTableRepository) GetForUpdate(ctx context.Context, id uint) (models.Table, error) {
+	table, err := scanTable(r.db.QueryRowContext(ctx, "SELECT "+tableColumns+" FROM tables WHERE id = $1 FOR UPDATE", id))
+	return table, notFound(err)
+}
+
+func (r *postgresTableRepository) Create(ctx context.Context, table models.Table) (models.Table, error) {
+	query := `
+		INSERT INTO tables (name, capacity, restaurant_id, location, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + tableColumns
+	return scanTable(r.db.QueryRowContext(ctx, query, table.Name, table.Capacity, table.RestaurantID, table.Location, table.Status))
+}
+
+func (r *postgresTableRepository) Update(ctx context.Context, table models.Table) (models.Table, error) {
+	query := `
+		UPDATE tables
+		SET name = $1, capacity = $2, restaurant_id = $3, location = $4, status = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6
+		RETURNING ` + tableColumns
+	updated, err := scanTable(r.db.QueryRowContext(ctx, query, table.Name, table.Capacity, table.RestaurantID, table.Location, table.Status, table.ID))
+	return updated, notFound(err)
+}
+
+func (r *postgresTableRepository) UpdateStatus(ctx context.Context, id uint, status string) (models.Table, error) {
+	table, err := scanTable(r.db.QueryRowContext(ctx, "UPDATE tables SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 RETURNING "+tableColumns, status, id))
+	return table, notFound(err)
+}
+
+func (r *postgresTableRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM tables WHERE id = $1", id))
+}
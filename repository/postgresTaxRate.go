@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+const taxRateColumns = `id, restaurant_id, name, category, rate, inclusive, created_at, updated_at`
+
+func scanTaxRate(row scanner) (models.TaxRate, error) {
+	var rate models.TaxRate
+	err := row.Scan(&rate.ID, &rate.RestaurantID, &rate.Name, &rate.Category, &rate.Rate, &rate.Inclusive, &rate.CreatedAt, &rate.UpdatedAt)
+	return rate, err
+}
+
+type postgresTaxRateRepository struct {
+	db DBTX
+}
+
+func (r *postgresTaxRateRepository) List(ctx context.Context, restaurantID uint) ([]models.TaxRate, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+taxRateColumns+" FROM tax_rates WHERE restaurant_id = $1 ORDER BY category ASC, id ASC", restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []models.TaxRate
+	for rows.Next() {
+		rate, err := scanTaxRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (r *postgresTaxRateRepository) Get(ctx context.Context, id uint) (models.TaxRate, error) {
+	rate, err := scanTaxRate(r.db.QueryRowContext(ctx, "SELECT "+taxRateColumns+" FROM tax_rates WHERE id = $1", id))
+	return rate, notFound(err)
+}
+
+func (r *postgresTaxRateRepository) Create(ctx context.Context, rate models.TaxRate) (models.TaxRate, error) {
+	query := `
+		INSERT INTO tax_rates (restaurant_id, name, category, rate, inclusive)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + taxRateColumns
+	created, err := scanTaxRate(r.db.QueryRowContext(ctx, query, rate.RestaurantID, rate.Name, rate.Category, rate.Rate, rate.Inclusive))
+	return created, duplicate(err)
+}
+
+func (r *postgresTaxRateRepository) Update(ctx context.Context, rate models.TaxRate) (models.TaxRate, error) {
+	query := `
+		UPDATE tax_rates
+		SET name = $1, category = $2, rate = $3, inclusive = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5
+		RETURNING ` + taxRateColumns
+	updated, err := scanTaxRate(r.db.QueryRowContext(ctx, query, rate.Name, rate.Category, rate.Rate, rate.Inclusive, rate.ID))
+	return updated, duplicate(notFound(err))
+}
+
+func (r *postgresTaxRateRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM tax_rates WHERE id = $1", id))
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"restaurant-management/models"
+)
+
+const userColumns = `id, username, password, email, phone, role, COALESCE(token, ''), COALESCE(avatar_url, ''), created_at, updated_at`
+
+func scanUser(row scanner) (models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Phone, &user.Role, &user.Token, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt)
+	return user, err
+}
+
+type postgresUserRepository struct {
+	db DBTX
+}
+
+func (r *postgresUserRepository) List(ctx context.Context) ([]models.User, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+userColumns+" FROM users ORDER BY id ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *postgresUserRepository) Get(ctx context.Context, id uint) (models.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", id))
+	return user, notFound(err)
+}
+
+func (r *postgresUserRepository) FindByIdentifier(ctx context.Context, identifier string) (models.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE username = $1 OR email = $1 OR phone = $1 LIMIT 1", identifier))
+	return user, notFound(err)
+}
+
+func (r *postgresUserRepository) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE email = $1", email))
+	return user, notFound(err)
+}
+
+func (r *postgresUserRepository) Exists(ctx context.Context, username, email, phone string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE username = $1 OR email = $2 OR phone = $3)", username, email, phone).Scan(&exists)
+	return exists, err
+}
+
+func (r *postgresUserRepository) Create(ctx context.Context, user models.User) (models.User, error) {
+	query := "INSERT INTO users (username, password, email, phone, role, token, avatar_url) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7) RETURNING " + userColumns
+	return scanUser(r.db.QueryRowContext(ctx, query, user.Username, user.Password, user.Email, user.Phone, user.Role, user.Token, user.AvatarURL))
+}
+
+func (r *postgresUserRepository) Update(ctx context.Context, user models.User) (models.User, error) {
+	query := "UPDATE users SET username = $1, password = $2, email = $3, phone = $4, role = $5, token = NULLIF($6, ''), avatar_url = $7, updated_at = CURRENT_TIMESTAMP WHERE id = $8 RETURNING " + userColumns
+	updated, err := scanUser(r.db.QueryRowContext(ctx, query, user.Username, user.Password, user.Email, user.Phone, user.Role, user.Token, user.AvatarURL, user.ID))
+	return updated, notFound(err)
+}
+
+func (r *postgresUserRepository) UpdateToken(ctx context.Context, id uint, token string) (models.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "UPDATE users SET token = NULLIF($1, '') WHERE id = $2 RETURNING "+userColumns, token, id))
+	return user, notFound(err)
+}
+
+func (r *postgresUserRepository) UpdateRole(ctx context.Context, id uint, role string) (models.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "UPDATE users SET role = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 RETURNING "+userColumns, role, id))
+	return user, notFound(err)
+}
+
+func (r *postgresUserRepository) UpdatePasswordByEmail(ctx context.Context, email, hashedPassword string) error {
+	return expectAffected(r.db.ExecContext(ctx, "UPDATE users SET password = $1, updated_at = CURRENT_TIMESTAMP WHERE email = $2", hashedPassword, email))
+}
+
+func (r *postgresUserRepository) Delete(ctx context.Context, id uint) error {
+	return expectAffected(r.db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id))
+}
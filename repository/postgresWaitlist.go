@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"restaurant-management/models"
+)
+
+const waitlistColumns = `id, restaurant_id, guest_name, guest_phone, party_size, quoted_minutes, status,
+	COALESCE(table_id, 0), COALESCE(order_id, 0), COALESCE(notes, ''), notified_at, seated_at, created_at, updated_at`
+
+func scanWaitlistEntry(row scanner) (models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	var notifiedAt, seatedAt sql.NullTime
+	err := row.Scan(&entry.ID, &entry.RestaurantID, &entry.GuestName, &entry.GuestPhone, &entry.PartySize,
+		&entry.QuotedMinutes, &entry.Status, &entry.TableID, &entry.OrderID, &entry.Notes,
+		&notifiedAt, &seatedAt, &entry.CreatedAt, &entry.UpdatedAt)
+	if notifiedAt.Valid {
+		entry.NotifiedAt = &notifiedAt.Time
+	}
+	if seatedAt.Valid {
+		entry.SeatedAt = &seatedAt.Time
+	}
+	return entry, err
+}
+
+type postgresWaitlistRepository struct {
+	db DBTX
+}
+
+func (r *postgresWaitlistRepository) ListActive(ctx context.Context, restaurantID uint) ([]models.WaitlistEntry, error) {
+	query := "SELECT " + waitlistColumns + " FROM waitlist_entries WHERE restaurant_id = $1 AND status IN ('waiting', 'notified') ORDER BY created_at ASC, id ASC"
+	rows, err := r.db.QueryContext(ctx, query, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.WaitlistEntry
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (r *postgresWaitlistRepository) Get(ctx context.Context, id uint) (models.WaitlistEntry, error) {
+	entry, err := scanWaitlistEntry(r.db.QueryRowContext(ctx, "SELECT "+waitlistColumns+" FROM waitlist_entries WHERE id = $1", id))
+	return entry, notFound(err)
+}
+
+func (r *postgresWaitlistRepository) Create(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error) {
+	query := `
+		INSERT INTO waitlist_entries (restaurant_id, guest_name, guest_phone, party_size, quoted_minutes, status, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + waitlistColumns
+	return scanWaitlistEntry(r.db.QueryRowContext(ctx, query, entry.RestaurantID, entry.GuestName, entry.GuestPhone,
+		entry.PartySize, entry.QuotedMinutes, entry.Status, entry.Notes))
+}
+
+func (r *postgresWaitlistRepository) Update(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error) {
+	query := `
+		UPDATE waitlist_entries
+		SET status = $1, table_id = NULLIF($2, 0), order_id = NULLIF($3, 0), notes = $4, notified_at = $5, seated_at = $6,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $7
+		RETURNING ` + waitlistColumns
+	updated, err := scanWaitlistEntry(r.db.QueryRowContext(ctx, query, entry.Status, entry.TableID, entry.OrderID, entry.Notes,
+		entry.NotifiedAt, entry.SeatedAt, entry.ID))
+	return updated, notFound(err)
+}
@@ -0,0 +1,471 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"restaurant-management/models"
+
+	"github.com/lib/pq"
+)
+
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// ErrDuplicate is returned when a write would break a uniqueness rule
+var ErrDuplicate = errors.New("record already exists")
+
+// DBTX is the subset of *sql.DB and *sql.Tx the Postgres repositories need
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// Repositories groups one repository per aggregate
+type Repositories struct {
+	Orders         OrderRepository
+	OrderItems     OrderItemRepository
+	Foods          FoodRepository
+	Menus          MenuRepository
+	Combos         ComboRepository
+	Stations       StationRepository
+	Tables         TableRepository
+	Reservations   ReservationRepository
+	Waitlist       WaitlistRepository
+	TaxRates       TaxRateRepository
+	ServiceCharges ServiceChargeRepository
+	Promotions     PromotionRepository
+	Discounts      DiscountRepository
+	Invoices       InvoiceRepository
+	Payments       PaymentRepository
+	PaymentIntents PaymentIntentRepository
+	Deliveries     InvoiceDeliveryRepository
+	Templates      InvoiceTemplateRepository
+	WebhookEvents  WebhookEventRepository
+	CreditNotes    CreditNoteRepository
+	Refunds        RefundRepository
+	NumberSeries   NumberSeriesRepository
+	Restaurants    RestaurantRepository
+	Notes          NoteRepository
+	Users          UserRepository
+}
+
+type OrderRepository interface {
+	// List returns a restaurant's orders, newest first, with their items
+	List(ctx context.Context, restaurantID uint) ([]models.Order, error)
+	// Get returns one order with its items
+	Get(ctx context.Context, id uint) (models.Order, error)
+	// GetForUpdate is Get that also locks the order row until the surrounding unit of work ends
+	GetForUpdate(ctx context.Context, id uint) (models.Order, error)
+	Create(ctx context.Context, order models.Order) (models.Order, error)
+	// CreateBlank reserves a pending order before table or restaurant are known
+	CreateBlank(ctx context.Context) (uint, error)
+	Update(ctx context.Context, order models.Order) (models.Order, error)
+	UpdateStatus(ctx context.Context, id uint, status string) (models.Order, error)
+	// RecalculateTotal sets total_price to the sum of the order's item subtotals
+	RecalculateTotal(ctx context.Context, id uint) (models.Order, error)
+	Delete(ctx context.Context, id uint) error
+
+	// RecordStatusChange appends a transition to the order's status history
+	RecordStatusChange(ctx context.Context, change models.OrderStatusChange) (models.OrderStatusChange, error)
+	// UpdateDiscountTotal stores what the order's discounts take off its total
+	UpdateDiscountTotal(ctx context.Context, id uint, discountTotal float64) error
+	// StatusHistory returns the order's transitions, oldest first
+	StatusHistory(ctx context.Context, orderID uint) ([]models.OrderStatusChange, error)
+
+	// ListOpen returns a restaurant's orders that are neither paid nor cancelled, oldest first, without items
+	ListOpen(ctx context.Context, restaurantID uint) ([]models.Order, error)
+	// AverageTurnTime is the average time from order to payment of the restaurant's orders
+	// paid since the given time, along with how many orders it is based on
+	AverageTurnTime(ctx context.Context, restaurantID uint, since time.Time) (time.Duration, int, error)
+}
+
+// OrderItemRepository reads and writes order items. A combo is one item with a component
+// item per slot under it: lists return the items ordered, combos with their components.
+type OrderItemRepository interface {
+	ListByRestaurant(ctx context.Context, restaurantID uint) ([]models.OrderItem, error)
+	ListByOrder(ctx context.Context, orderID uint) ([]models.OrderItem, error)
+	// Get returns an item, with its components when it is a combo
+	Get(ctx context.Context, id uint) (models.OrderItem, error)
+	// Create stores an item, with its components when it is a combo
+	Create(ctx context.Context, item models.OrderItem) (models.OrderItem, error)
+	UpdateQuantity(ctx context.Context, id uint, quantity uint, subtotal float64) (models.OrderItem, error)
+	// Delete removes an item, with its components when it is a combo
+	Delete(ctx context.Context, id uint) error
+
+	// SalesByFood totals what each food of a restaurant sold in the paid orders placed in
+	// [from, to), combos counted through their components, best selling first. A zero bound is open.
+	SalesByFood(ctx context.Context, restaurantID uint, from, to time.Time) ([]models.FoodSales, error)
+
+	// Bump marks the item as prepared
+	Bump(ctx context.Context, id uint) (models.OrderItem, error)
+	// StationQueue returns the queued items of a restaurant's preparing orders routed to
+	// the station, or to any station when stationID is 0. Oldest orders come first and,
+	// within an order, the items that take longest to prepare.
+	StationQueue(ctx context.Context, restaurantID, stationID uint) ([]models.StationTicket, error)
+	// ListTicketsByOrder returns every item of the order as the kitchen stations see it, in the order they were added
+	ListTicketsByOrder(ctx context.Context, orderID uint) ([]models.StationTicket, error)
+}
+
+type WaitlistRepository interface {
+	// ListActive returns the restaurant's waiting and notified parties in queue order
+	ListActive(ctx context.Context, restaurantID uint) ([]models.WaitlistEntry, error)
+	Get(ctx context.Context, id uint) (models.WaitlistEntry, error)
+	Create(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error)
+	// Update overwrites the status, table, order, notes and timestamps of the entry
+	Update(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error)
+}
+
+type FoodRepository interface {
+	// List returns the foods of a restaurant, or every food when restaurantID is 0
+	List(ctx context.Context, restaurantID uint) ([]models.Food, error)
+	Get(ctx context.Context, id uint) (models.Food, error)
+	Create(ctx context.Context, food models.Food) (models.Food, error)
+	Update(ctx context.Context, food models.Food) (models.Food, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type MenuRepository interface {
+	// List returns the menus of a restaurant, or every menu when restaurantID is 0
+	List(ctx context.Context, restaurantID uint) ([]models.Menu, error)
+	Get(ctx context.Context, id uint) (models.Menu, error)
+	Create(ctx context.Context, menu models.Menu) (models.Menu, error)
+	Update(ctx context.Context, menu models.Menu) (models.Menu, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type ComboRepository interface {
+	// List returns a restaurant's combos with their slots
+	List(ctx context.Context, restaurantID uint) ([]models.Combo, error)
+	Get(ctx context.Context, id uint) (models.Combo, error)
+	Create(ctx context.Context, combo models.Combo) (models.Combo, error)
+	// Update changes everything but the restaurant of the combo and replaces its slots
+	Update(ctx context.Context, combo models.Combo) (models.Combo, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type StationRepository interface {
+	List(ctx context.Context, restaurantID uint) ([]models.Station, error)
+	Get(ctx context.Context, id uint) (models.Station, error)
+	Create(ctx context.Context, station models.Station) (models.Station, error)
+	Update(ctx context.Context, station models.Station) (models.Station, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type TableRepository interface {
+	// List returns the tables of a restaurant, or every table when restaurantID is 0
+	List(ctx context.Context, restaurantID uint) ([]models.Table, error)
+	Get(ctx context.Context, id uint) (models.Table, error)
+	// GetForUpdate is Get that also locks the table row until the surrounding unit of work ends
+	GetForUpdate(ctx context.Context, id uint) (models.Table, error)
+	Create(ctx context.Context, table models.Table) (models.Table, error)
+	Update(ctx context.Context, table models.Table) (models.Table, error)
+	UpdateStatus(ctx context.Context, id uint, status string) (models.Table, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type ReservationRepository interface {
+	// List returns a restaurant's reservations starting in [from, to), oldest first. A zero bound is open.
+	List(ctx context.Context, restaurantID uint, from, to time.Time) ([]models.Reservation, error)
+	Get(ctx context.Context, id uint) (models.Reservation, error)
+	Create(ctx context.Context, reservation models.Reservation) (models.Reservation, error)
+	// Update overwrites the guest details, slot and table
+	Update(ctx context.Context, reservation models.Reservation) (models.Reservation, error)
+	UpdateStatus(ctx context.Context, id uint, status string) (models.Reservation, error)
+	// Overlapping returns the booked or seated reservations of a table that overlap [start, end), ignoring excludeID
+	Overlapping(ctx context.Context, tableID uint, start, end time.Time, excludeID uint) ([]models.Reservation, error)
+	// AvailableTables returns the restaurant's tables seating partySize with no reservation
+	// overlapping [start, end), smallest table first
+	AvailableTables(ctx context.Context, restaurantID uint, partySize int, start, end time.Time) ([]models.Table, error)
+	// BookedBefore returns the booked reservations of every restaurant starting before the given time, oldest first
+	BookedBefore(ctx context.Context, before time.Time) ([]models.Reservation, error)
+}
+
+type InvoiceRepository interface {
+	// ListByRestaurant returns the restaurant's order invoices without their parts
+	ListByRestaurant(ctx context.Context, restaurantID uint) ([]models.Invoice, error)
+	// Get returns an invoice or part with its parts and the payments taken against them
+	Get(ctx context.Context, id uint) (models.Invoice, error)
+	// GetByOrder is Get for the order's own invoice
+	GetByOrder(ctx context.Context, orderID uint) (models.Invoice, error)
+	// ListSplits returns the parts an invoice is split into, in order
+	ListSplits(ctx context.Context, parentID uint) ([]models.Invoice, error)
+	// UpsertForOrder creates the order's invoice or overwrites the existing one, tax breakdown
+	// included. What has been paid and how the invoice is split are left as they are.
+	UpsertForOrder(ctx context.Context, invoice models.Invoice) (models.Invoice, error)
+	// ReplaceSplits swaps the parts of an invoice for new ones, no parts and an empty mode merge it back
+	ReplaceSplits(ctx context.Context, parentID uint, mode string, splits []models.Invoice) ([]models.Invoice, error)
+	// UpdatePayment stores what has been paid of an invoice and tipped on it, its status and the latest payment method
+	UpdatePayment(ctx context.Context, id uint, amountPaid, tip float64, status, paymentMethod string) (models.Invoice, error)
+	// UpdateCredited stores what credit notes have given back of an invoice
+	UpdateCredited(ctx context.Context, id uint, credited float64) (models.Invoice, error)
+	// Issue numbers an invoice next in its restaurant's invoice series, an invoice already numbered keeps its number
+	Issue(ctx context.Context, id uint) (models.Invoice, error)
+	// DeleteByOrder removes the order's invoice with its parts and payments
+	DeleteByOrder(ctx context.Context, orderID uint) error
+}
+
+type PaymentRepository interface {
+	// ListByInvoice returns the payments taken against an invoice and its parts, oldest first
+	ListByInvoice(ctx context.Context, invoiceID uint) ([]models.Payment, error)
+	// Create records a payment, ErrDuplicate when the provider's transaction is already recorded
+	Create(ctx context.Context, payment models.Payment) (models.Payment, error)
+	// TipsByStaff totals the tips of a restaurant's payments taken in [from, to) per staff
+	// member credited, most tipped first. A zero bound is open.
+	TipsByStaff(ctx context.Context, restaurantID uint, from, to time.Time) ([]models.TipSummary, error)
+}
+
+type PaymentIntentRepository interface {
+	ListByInvoice(ctx context.Context, invoiceID uint) ([]models.PaymentIntent, error)
+	Get(ctx context.Context, id uint) (models.PaymentIntent, error)
+	// GetByProviderID finds an intent by the ID the provider gave it
+	GetByProviderID(ctx context.Context, provider, providerIntentID string) (models.PaymentIntent, error)
+	Create(ctx context.Context, intent models.PaymentIntent) (models.PaymentIntent, error)
+	// UpdateStatus follows the provider's intent, paymentID is 0 until the money is recorded
+	UpdateStatus(ctx context.Context, id uint, status, failureReason string, paymentID uint) (models.PaymentIntent, error)
+}
+
+type InvoiceDeliveryRepository interface {
+	// ListByInvoice returns the times an invoice was emailed, latest first
+	ListByInvoice(ctx context.Context, invoiceID uint) ([]models.InvoiceDelivery, error)
+	// Create records a pending delivery that has not been tried yet
+	Create(ctx context.Context, delivery models.InvoiceDelivery) (models.InvoiceDelivery, error)
+	// RecordAttempt counts a try at sending and stores how it went
+	RecordAttempt(ctx context.Context, id uint, status, lastError string) (models.InvoiceDelivery, error)
+}
+
+type InvoiceTemplateRepository interface {
+	// Get returns how the restaurant's documents are printed, the default template until it sets one up
+	Get(ctx context.Context, restaurantID uint) (models.InvoiceTemplate, error)
+	// Upsert sets the restaurant's template up or replaces it, tax IDs included
+	Upsert(ctx context.Context, template models.InvoiceTemplate) (models.InvoiceTemplate, error)
+}
+
+type CreditNoteRepository interface {
+	// ListByInvoice returns the credit notes issued against an invoice, oldest first, with their items and refunds
+	ListByInvoice(ctx context.Context, invoiceID uint) ([]models.CreditNote, error)
+	// Get returns a credit note with its items and refunds
+	Get(ctx context.Context, id uint) (models.CreditNote, error)
+	// Create numbers the credit note next in its restaurant's credit note series and stores it with its items
+	Create(ctx context.Context, note models.CreditNote) (models.CreditNote, error)
+	// VoidedQuantities returns how many units of an order's items credit notes have voided, by order item ID
+	VoidedQuantities(ctx context.Context, orderID uint) (map[uint]uint, error)
+}
+
+type RefundRepository interface {
+	Get(ctx context.Context, id uint) (models.Refund, error)
+	// GetByProviderID finds a refund by the ID the provider gave it
+	GetByProviderID(ctx context.Context, provider, providerRefundID string) (models.Refund, error)
+	Create(ctx context.Context, refund models.Refund) (models.Refund, error)
+	// UpdateStatus follows the provider's refund, an empty providerRefundID keeps the one stored
+	UpdateStatus(ctx context.Context, id uint, status, providerRefundID, failureReason string) (models.Refund, error)
+}
+
+type NumberSeriesRepository interface {
+	// List returns how the restaurant numbers each kind of document, defaults included
+	List(ctx context.Context, restaurantID uint) ([]models.NumberSeries, error)
+	// Get returns the restaurant's series for a kind of document, the default one until it is set up
+	Get(ctx context.Context, restaurantID uint, kind string) (models.NumberSeries, error)
+	// Upsert sets a series up or changes it, numbers already issued stay as they are
+	Upsert(ctx context.Context, series models.NumberSeries) (models.NumberSeries, error)
+}
+
+type WebhookEventRepository interface {
+	// Record remembers a handled event and reports false if it had been recorded before
+	Record(ctx context.Context, event models.WebhookEvent) (bool, error)
+}
+
+type PromotionRepository interface {
+	// List returns a restaurant's promotions, newest first
+	List(ctx context.Context, restaurantID uint) ([]models.Promotion, error)
+	Get(ctx context.Context, id uint) (models.Promotion, error)
+	// GetForUpdate is Get that also locks the promotion row until the surrounding unit of work ends
+	GetForUpdate(ctx context.Context, id uint) (models.Promotion, error)
+	// GetByCodeForUpdate finds a restaurant's coupon by its code and locks it
+	GetByCodeForUpdate(ctx context.Context, restaurantID uint, code string) (models.Promotion, error)
+	Create(ctx context.Context, promotion models.Promotion) (models.Promotion, error)
+	// Update changes everything but the restaurant and usage count of the promotion
+	Update(ctx context.Context, promotion models.Promotion) (models.Promotion, error)
+	// AddUsage moves the usage count by delta, never below zero
+	AddUsage(ctx context.Context, id uint, delta int) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type DiscountRepository interface {
+	// ListByOrder returns the discounts applied to an order in the order they were added
+	ListByOrder(ctx context.Context, orderID uint) ([]models.OrderDiscount, error)
+	Get(ctx context.Context, id uint) (models.OrderDiscount, error)
+	Create(ctx context.Context, discount models.OrderDiscount) (models.OrderDiscount, error)
+	Approve(ctx context.Context, id uint, approvedBy *uint) (models.OrderDiscount, error)
+	UpdateAmount(ctx context.Context, id uint, amount float64) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type ServiceChargeRepository interface {
+	// List returns a restaurant's service charges in the order they were added
+	List(ctx context.Context, restaurantID uint) ([]models.ServiceCharge, error)
+	Get(ctx context.Context, id uint) (models.ServiceCharge, error)
+	Create(ctx context.Context, charge models.ServiceCharge) (models.ServiceCharge, error)
+	// Update changes everything but the restaurant of the charge
+	Update(ctx context.Context, charge models.ServiceCharge) (models.ServiceCharge, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type TaxRateRepository interface {
+	// List returns a restaurant's tax rates by category, in the order they were added
+	List(ctx context.Context, restaurantID uint) ([]models.TaxRate, error)
+	Get(ctx context.Context, id uint) (models.TaxRate, error)
+	Create(ctx context.Context, rate models.TaxRate) (models.TaxRate, error)
+	// Update changes everything but the restaurant of the rate
+	Update(ctx context.Context, rate models.TaxRate) (models.TaxRate, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type RestaurantRepository interface {
+	List(ctx context.Context) ([]models.Restaurant, error)
+	ListByOwner(ctx context.Context, ownerID uint) ([]models.Restaurant, error)
+	Get(ctx context.Context, id uint) (models.Restaurant, error)
+	Create(ctx context.Context, restaurant models.Restaurant) (models.Restaurant, error)
+	Update(ctx context.Context, restaurant models.Restaurant) (models.Restaurant, error)
+	Delete(ctx context.Context, id uint) error
+
+	ListStaff(ctx context.Context, restaurantID uint) ([]models.RestaurantStaff, error)
+	// UpsertStaff adds a staff member or changes the role of an existing one
+	UpsertStaff(ctx context.Context, member models.RestaurantStaff) (models.RestaurantStaff, error)
+	RemoveStaff(ctx context.Context, restaurantID, userID uint) error
+	// Memberships lists every restaurant the user owns or works in
+	Memberships(ctx context.Context, userID uint) ([]models.Membership, error)
+}
+
+type NoteRepository interface {
+	ListByRestaurant(ctx context.Context, restaurantID uint) ([]models.Note, error)
+	Create(ctx context.Context, note models.Note) (models.Note, error)
+	Update(ctx context.Context, note models.Note) (models.Note, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type UserRepository interface {
+	List(ctx context.Context) ([]models.User, error)
+	Get(ctx context.Context, id uint) (models.User, error)
+	// FindByIdentifier looks a user up by username, email or phone
+	FindByIdentifier(ctx context.Context, identifier string) (models.User, error)
+	FindByEmail(ctx context.Context, email string) (models.User, error)
+	// Exists reports whether any user already uses the username, email or phone
+	Exists(ctx context.Context, username, email, phone string) (bool, error)
+	// Create stores the user with an already hashed password
+	Create(ctx context.Context, user models.User) (models.User, error)
+	// Update overwrites profile, hashed password and token
+	Update(ctx context.Context, user models.User) (models.User, error)
+	UpdateToken(ctx context.Context, id uint, token string) (models.User, error)
+	UpdateRole(ctx context.Context, id uint, role string) (models.User, error)
+	UpdatePasswordByEmail(ctx context.Context, email, hashedPassword string) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// NewPostgres builds repositories backed by db, which may be a *sql.DB or a *sql.Tx
+func NewPostgres(db DBTX) Repositories {
+	return Repositories{
+		Orders:         &postgresOrderRepository{db: db},
+		OrderItems:     &postgresOrderItemRepository{db: db},
+		Foods:          &postgresFoodRepository{db: db},
+		Menus:          &postgresMenuRepository{db: db},
+		Combos:         &postgresComboRepository{db: db},
+		Stations:       &postgresStationRepository{db: db},
+		Tables:         &postgresTableRepository{db: db},
+		Reservations:   &postgresReservationRepository{db: db},
+		Waitlist:       &postgresWaitlistRepository{db: db},
+		TaxRates:       &postgresTaxRateRepository{db: db},
+		ServiceCharges: &postgresServiceChargeRepository{db: db},
+		Promotions:     &postgresPromotionRepository{db: db},
+		Discounts:      &postgresDiscountRepository{db: db},
+		Invoices:       &postgresInvoiceRepository{db: db},
+		Payments:       &postgresPaymentRepository{db: db},
+		PaymentIntents: &postgresPaymentIntentRepository{db: db},
+		Deliveries:     &postgresInvoiceDeliveryRepository{db: db},
+		Templates:      &postgresInvoiceTemplateRepository{db: db},
+		WebhookEvents:  &postgresWebhookEventRepository{db: db},
+		CreditNotes:    &postgresCreditNoteRepository{db: db},
+		Refunds:        &postgresRefundRepository{db: db},
+		NumberSeries:   &postgresNumberSeriesRepository{db: db},
+		Restaurants:    &postgresRestaurantRepository{db: db},
+		Notes:          &postgresNoteRepository{db: db},
+		Users:          &postgresUserRepository{db: db},
+	}
+}
+
+// NewMemory builds repositories sharing one in-memory store, and a unit of work over
+// the same store, meant for tests
+func NewMemory() (Repositories, UnitOfWork) {
+	store := newMemoryStore()
+	return newMemoryRepositories(store), &memoryUnitOfWork{store: store}
+}
+
+func newMemoryRepositories(store *memoryStore) Repositories {
+	return Repositories{
+		Orders:         &memoryOrderRepository{store: store},
+		OrderItems:     &memoryOrderItemRepository{store: store},
+		Foods:          &memoryFoodRepository{store: store},
+		Menus:          &memoryMenuRepository{store: store},
+		Combos:         &memoryComboRepository{store: store},
+		Stations:       &memoryStationRepository{store: store},
+		Tables:         &memoryTableRepository{store: store},
+		Reservations:   &memoryReservationRepository{store: store},
+		Waitlist:       &memoryWaitlistRepository{store: store},
+		TaxRates:       &memoryTaxRateRepository{store: store},
+		ServiceCharges: &memoryServiceChargeRepository{store: store},
+		Promotions:     &memoryPromotionRepository{store: store},
+		Discounts:      &memoryDiscountRepository{store: store},
+		Invoices:       &memoryInvoiceRepository{store: store},
+		Payments:       &memoryPaymentRepository{store: store},
+		PaymentIntents: &memoryPaymentIntentRepository{store: store},
+		Deliveries:     &memoryInvoiceDeliveryRepository{store: store},
+		Templates:      &memoryInvoiceTemplateRepository{store: store},
+		WebhookEvents:  &memoryWebhookEventRepository{store: store},
+		CreditNotes:    &memoryCreditNoteRepository{store: store},
+		Refunds:        &memoryRefundRepository{store: store},
+		NumberSeries:   &memoryNumberSeriesRepository{store: store},
+		Restaurants:    &memoryRestaurantRepository{store: store},
+		Notes:          &memoryNoteRepository{store: store},
+		Users:          &memoryUserRepository{store: store},
+	}
+}
+
+// notFound maps sql.ErrNoRows to ErrNotFound and passes other errors through
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// duplicate maps unique constraint violations to ErrDuplicate and passes other errors through
+func duplicate(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicate
+	}
+	return err
+}
+
+// expectAffected turns an exec result that touched no rows into ErrNotFound
+func expectAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"maps"
+)
+
+// UnitOfWork runs a group of repository calls atomically. The repositories handed to fn
+// share one transaction, which commits when fn returns nil and rolls back otherwise.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+type postgresUnitOfWork struct {
+	db *sql.DB
+}
+
+// NewPostgresUnitOfWork runs each unit of work in its own database transaction
+func NewPostgresUnitOfWork(db *sql.DB) UnitOfWork {
+	return &postgresUnitOfWork{db: db}
+}
+
+func (u *postgresUnitOfWork) Do(ctx context.Context, fn func(repos Repositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// A no-op once the transaction is committed
+	defer tx.Rollback()
+
+	if err := fn(NewPostgres(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// memoryUnitOfWork serialises units of work and restores a snapshot of the store when one fails
+type memoryUnitOfWork struct {
+	store *memoryStore
+}
+
+func (u *memoryUnitOfWork) Do(ctx context.Context, fn func(repos Repositories) error) error {
+	u.store.txMu.Lock()
+	defer u.store.txMu.Unlock()
+
+	snapshot := u.store.snapshot()
+	if err := fn(newMemoryRepositories(u.store)); err != nil {
+		u.store.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+func (s *memoryStore) snapshot() *memoryStore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return &memoryStore{
+		nextID:         maps.Clone(s.nextID),
+		orders:         maps.Clone(s.orders),
+		history:        maps.Clone(s.history),
+		orderItems:     maps.Clone(s.orderItems),
+		foods:          maps.Clone(s.foods),
+		menus:          maps.Clone(s.menus),
+		combos:         maps.Clone(s.combos),
+		stations:       maps.Clone(s.stations),
+		tables:         maps.Clone(s.tables),
+		reservations:   maps.Clone(s.reservations),
+		waitlist:       maps.Clone(s.waitlist),
+		taxRates:       maps.Clone(s.taxRates),
+		serviceCharges: maps.Clone(s.serviceCharges),
+		promotions:     maps.Clone(s.promotions),
+		discounts:      maps.Clone(s.discounts),
+		invoices:       maps.Clone(s.invoices),
+		payments:       maps.Clone(s.payments),
+		paymentIntents: maps.Clone(s.paymentIntents),
+		webhookEvents:  maps.Clone(s.webhookEvents),
+		creditNotes:    maps.Clone(s.creditNotes),
+		refunds:        maps.Clone(s.refunds),
+		deliveries:     maps.Clone(s.deliveries),
+		sequences:      maps.Clone(s.sequences),
+		numberSeries:   maps.Clone(s.numberSeries),
+		templates:      maps.Clone(s.templates),
+		restaurants:    maps.Clone(s.restaurants),
+		staff:          maps.Clone(s.staff),
+		notes:          maps.Clone(s.notes),
+		users:          maps.Clone(s.users),
+	}
+}
+
+func (s *memoryStore) restore(snapshot *memoryStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID = snapshot.nextID
+	s.orders, s.history, s.orderItems = snapshot.orders, snapshot.history, snapshot.orderItems
+	s.foods, s.menus, s.combos, s.stations, s.tables = snapshot.foods, snapshot.menus, snapshot.combos, snapshot.stations, snapshot.tables
+	s.reservations, s.waitlist = snapshot.reservations, snapshot.waitlist
+	s.taxRates, s.invoices, s.restaurants, s.staff = snapshot.taxRates, snapshot.invoices, snapshot.restaurants, snapshot.staff
+	s.serviceCharges, s.promotions, s.discounts = snapshot.serviceCharges, snapshot.promotions, snapshot.discounts
+	s.payments, s.paymentIntents, s.webhookEvents = snapshot.payments, snapshot.paymentIntents, snapshot.webhookEvents
+	s.deliveries, s.templates = snapshot.deliveries, snapshot.templates
+	s.creditNotes, s.refunds, s.sequences, s.numberSeries = snapshot.creditNotes, snapshot.refunds, snapshot.sequences, snapshot.numberSeries
+	s.notes, s.users = snapshot.notes, snapshot.users
+}
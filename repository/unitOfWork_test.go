@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"restaurant-management/models"
+)
+
+func TestMemoryUnitOfWorkCommits(t *testing.T) {
+	ctx := context.Background()
+	repos, uow := NewMemory()
+
+	var created models.Order
+	err := uow.Do(ctx, func(repos Repositories) error {
+		var err error
+		created, err = repos.Orders.Create(ctx, models.Order{TableID: 1, RestaurantID: 1, Status: models.OrderStatusPending})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	if _, err := repos.Orders.Get(ctx, created.ID); err != nil {
+		t.Fatalf("the order created in the unit of work is missing: %v", err)
+	}
+}
+
+func TestMemoryUnitOfWorkRollsBackOnError(t *testing.T) {
+	ctx := context.Background()
+	repos, uow := NewMemory()
+
+	kept, err := repos.Orders.Create(ctx, models.Order{TableID: 1, RestaurantID: 1, Status: models.OrderStatusPending})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	failure := errors.New("failed halfway")
+	var created models.Order
+	err = uow.Do(ctx, func(repos Repositories) error {
+		if _, err := repos.Orders.UpdateStatus(ctx, kept.ID, models.OrderStatusPreparing); err != nil {
+			return err
+		}
+		if created, err = repos.Orders.Create(ctx, models.Order{TableID: 2, RestaurantID: 1, Status: models.OrderStatusPending}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Do returned %v, want the error of fn", err)
+	}
+
+	order, err := repos.Orders.Get(ctx, kept.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if order.Status != models.OrderStatusPending {
+		t.Errorf("status is %q after the rollback, want %q", order.Status, models.OrderStatusPending)
+	}
+	if _, err := repos.Orders.Get(ctx, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("the order created in the failed unit of work is still there: %v", err)
+	}
+
+	// The IDs are rolled back too, so the next one can't collide with a kept row
+	next, err := repos.Orders.Create(ctx, models.Order{TableID: 3, RestaurantID: 1, Status: models.OrderStatusPending})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if next.ID == kept.ID {
+		t.Errorf("the new order took the ID %d of a kept one", next.ID)
+	}
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ComboRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/combos", canListCombos, controllers.GetCombos())
+	incomingRoutes.GET("/combos/:combo_id", canViewCombo, controllers.GetCombo())
+	incomingRoutes.POST("/combos", canCreateCombo, controllers.CreateCombo())
+	incomingRoutes.PATCH("/combos/:combo_id", canEditCombo, controllers.UpdateCombo())
+	incomingRoutes.DELETE("/combos/:combo_id", canEditCombo, controllers.DeleteCombo())
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CreditNoteRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/invoice-credit-notes/:invoice_id", canViewInvoice, controllers.GetCreditNotes())
+	incomingRoutes.POST("/invoice-credit-notes/:invoice_id", canCreditInvoice, controllers.IssueCreditNote())
+	incomingRoutes.GET("/credit-notes/:credit_note_id", canViewCreditNote, controllers.GetCreditNote())
+	incomingRoutes.GET("/credit-note-pdf/:credit_note_id", canViewCreditNote, controllers.DownloadCreditNote())
+	incomingRoutes.POST("/refunds/:refund_id/retry", canRetryRefund, controllers.RetryRefund())
+}
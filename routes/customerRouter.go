@@ -19,6 +19,9 @@ func PublicCustomerRoutes(incomingRoutes *gin.Engine) {
 	// Food routes for customers
 	incomingRoutes.GET("/customer/foods", controllers.CustomerGetFoodsByRestaurantID())
 
+	// Combo routes for customers
+	incomingRoutes.GET("/customer/combos", controllers.CustomerGetCombos())
+
 	// Order routes for customers
 	incomingRoutes.POST("/customer/orders", controllers.CustomerCreateOrderId())
 	incomingRoutes.PATCH("/customer/orders/:order_id", controllers.CustomerUpdateOrder())
@@ -7,9 +7,11 @@ import (
 )
 
 func FoodRoutes(incomingRoutes gin.IRoutes) {
-	incomingRoutes.GET("/foods", controllers.GetFoods())
-	incomingRoutes.GET("/foods/:food_id", controllers.GetFood())
-	incomingRoutes.POST("/foods", controllers.CreateFood())
-	incomingRoutes.PATCH("/foods/:food_id", controllers.UpdateFood())
-	incomingRoutes.DELETE("/foods/:food_id", controllers.DeleteFood())
+	incomingRoutes.GET("/foods", canListFoods, controllers.GetFoods())
+	incomingRoutes.GET("/foods/:food_id", canViewFood, controllers.GetFood())
+	incomingRoutes.POST("/foods", canCreateFood, controllers.CreateFood())
+	incomingRoutes.PATCH("/foods/:food_id", canEditFood, controllers.UpdateFood())
+	incomingRoutes.DELETE("/foods/:food_id", canDeleteFood, controllers.DeleteFood())
+
+	incomingRoutes.GET("/food-sales", canViewFoodSales, controllers.GetFoodSales())
 }
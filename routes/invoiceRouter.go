@@ -7,8 +7,11 @@ import (
 )
 
 func InvoiceRoutes(incomingRoutes gin.IRoutes) {
-	incomingRoutes.GET("/invoices/:restaurant_id", controllers.GetInvoices())
-	incomingRoutes.GET("/invoice-pdf/:invoice_id", controllers.DownloadInvoice())
+	incomingRoutes.GET("/invoices/:restaurant_id", canListInvoices, controllers.GetInvoices())
+	incomingRoutes.GET("/invoice-pdf/:invoice_id", canViewInvoice, controllers.DownloadInvoice())
+	incomingRoutes.GET("/invoice-details/:invoice_id", canViewInvoice, controllers.GetInvoice())
+	incomingRoutes.GET("/invoice-deliveries/:invoice_id", canViewInvoice, controllers.GetInvoiceDeliveries())
+	incomingRoutes.POST("/invoices/:invoice_id/send", canSendInvoice, controllers.SendInvoice())
 	// incomingRoutes.GET("/invoices/:invoice_id", controllers.GetInvoice())
 	// incomingRoutes.POST("/invoices", controllers.CreateInvoice())
 	// incomingRoutes.PATCH("/invoices/:invoice_id", controllers.UpdateInvoice())
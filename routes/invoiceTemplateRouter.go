@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InvoiceTemplateRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/invoice-template", canViewInvoiceTemplate, controllers.GetInvoiceTemplate())
+	incomingRoutes.PUT("/invoice-template", canSetInvoiceTemplate, controllers.SetInvoiceTemplate())
+	incomingRoutes.POST("/invoice-template/preview", canSetInvoiceTemplate, controllers.PreviewInvoiceTemplate())
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func KitchenRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/kitchen/:restaurant_id/feed", canViewKitchen, controllers.StreamKitchenFeed())
+	incomingRoutes.GET("/kitchen/:restaurant_id/queue", canViewKitchen, controllers.GetStationQueue())
+	incomingRoutes.POST("/kitchen/items/:order_item_id/bump", canBumpOrderItem, controllers.BumpOrderItem())
+}
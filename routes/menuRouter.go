@@ -7,9 +7,9 @@ import (
 )
 
 func MenuRoutes(incomingRoutes gin.IRoutes) {
-	incomingRoutes.GET("/menus", controllers.GetMenus())
-	incomingRoutes.GET("/menus/:menu_id", controllers.GetMenu())
-	incomingRoutes.POST("/menus", controllers.CreateMenu())
-	incomingRoutes.PATCH("/menus/:menu_id", controllers.UpdateMenu())
-	incomingRoutes.DELETE("/menus/:menu_id", controllers.DeleteMenu())
+	incomingRoutes.GET("/menus", canListMenus, controllers.GetMenus())
+	incomingRoutes.GET("/menus/:menu_id", canViewMenu, controllers.GetMenu())
+	incomingRoutes.POST("/menus", canCreateMenu, controllers.CreateMenu())
+	incomingRoutes.PATCH("/menus/:menu_id", canEditMenu, controllers.UpdateMenu())
+	incomingRoutes.DELETE("/menus/:menu_id", canDeleteMenu, controllers.DeleteMenu())
 }
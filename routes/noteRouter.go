@@ -7,9 +7,9 @@ import (
 )
 
 func NoteRoutes(incomingRoutes gin.IRoutes) {
-	incomingRoutes.GET("/notes/:restaurant_id", controllers.GetNotes())
+	incomingRoutes.GET("/notes/:restaurant_id", canListNotes, controllers.GetNotes())
 	// incomingRoutes.GET("/notes/:note_id", controllers.GetNote())
-	incomingRoutes.POST("/notes", controllers.CreateNote())
-	incomingRoutes.PATCH("/notes/:note_id", controllers.UpdateNote())
-	incomingRoutes.DELETE("/notes/:note_id", controllers.DeleteNote())
+	incomingRoutes.POST("/notes", canCreateNote, controllers.CreateNote())
+	incomingRoutes.PATCH("/notes/:note_id", canEditNote, controllers.UpdateNote())
+	incomingRoutes.DELETE("/notes/:note_id", canEditNote, controllers.DeleteNote())
 }
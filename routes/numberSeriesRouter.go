@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NumberSeriesRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/number-series", canListNumberSeries, controllers.GetNumberSeries())
+	incomingRoutes.PUT("/number-series", canSetNumberSeries, controllers.SetNumberSeries())
+}
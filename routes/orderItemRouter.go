@@ -7,10 +7,10 @@ import (
 )
 
 func OrderItemRoutes(incomingRoutes gin.IRoutes) {
-	incomingRoutes.GET("/order-items", controllers.GetOrderItems())
-	incomingRoutes.GET("/order-items/:order_item_id", controllers.GetOrderItem())
-	incomingRoutes.GET("/order-items-order/:order_id", controllers.GetOrderItemsByOrder())
-	incomingRoutes.POST("/order-items", controllers.CreateOrderItem())
-	incomingRoutes.PATCH("/order-items/:order_item_id", controllers.UpdateOrderItem())
-	incomingRoutes.DELETE("/order-items/:order_item_id", controllers.DeleteOrderItem())
+	incomingRoutes.GET("/order-items", canListOrderItems, controllers.GetOrderItems())
+	incomingRoutes.GET("/order-items/:order_item_id", canViewOrderItem, controllers.GetOrderItem())
+	incomingRoutes.GET("/order-items-order/:order_id", canViewOrder, controllers.GetOrderItemsByOrder())
+	incomingRoutes.POST("/order-items", canCreateOrderItem, controllers.CreateOrderItem())
+	incomingRoutes.PATCH("/order-items/:order_item_id", canEditOrderItem, controllers.UpdateOrderItem())
+	incomingRoutes.DELETE("/order-items/:order_item_id", canEditOrderItem, controllers.DeleteOrderItem())
 }
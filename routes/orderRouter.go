@@ -7,11 +7,12 @@ import (
 )
 
 func OrderRoutes(incomingRoutes gin.IRoutes) {
-	incomingRoutes.GET("/orders", controllers.GetOrders())
-	incomingRoutes.GET("/orders/:order_id", controllers.GetOrder())
-	incomingRoutes.GET("/order-id", controllers.CreateOrderId())
-	incomingRoutes.POST("/orders", controllers.CreateOrder())
-	incomingRoutes.PATCH("/orders/:order_id", controllers.UpdateOrder())
-	incomingRoutes.PATCH("/orders-status/:order_id", controllers.UpdateOrderStatus())
-	incomingRoutes.DELETE("/orders/:order_id", controllers.DeleteOrder())
+	incomingRoutes.GET("/orders", canListOrders, controllers.GetOrders())
+	incomingRoutes.GET("/orders/:order_id", canViewOrder, controllers.GetOrder())
+	incomingRoutes.GET("/orders/:order_id/history", canViewOrder, controllers.GetOrderHistory())
+	incomingRoutes.GET("/order-id", authenticated, controllers.CreateOrderId())
+	incomingRoutes.POST("/orders", canCreateOrder, controllers.CreateOrder())
+	incomingRoutes.PATCH("/orders/:order_id", canEditOrder, controllers.UpdateOrder())
+	incomingRoutes.PATCH("/orders-status/:order_id", canUpdateStatus, controllers.UpdateOrderStatus())
+	incomingRoutes.DELETE("/orders/:order_id", canDeleteOrder, controllers.DeleteOrder())
 }
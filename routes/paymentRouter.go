@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PaymentRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/invoice-payments/:invoice_id", canViewInvoice, controllers.GetPayments())
+	incomingRoutes.POST("/invoice-payments/:invoice_id", canTakePayment, controllers.CreatePayment())
+	incomingRoutes.POST("/invoice-split/:invoice_id", canSplitInvoice, controllers.SplitInvoice())
+	incomingRoutes.DELETE("/invoice-split/:invoice_id", canSplitInvoice, controllers.MergeInvoice())
+
+	incomingRoutes.GET("/invoice-intents/:invoice_id", canViewInvoice, controllers.GetPaymentIntents())
+	incomingRoutes.POST("/invoice-intents/:invoice_id", canTakePayment, controllers.CreatePaymentIntent())
+	incomingRoutes.POST("/payment-intents/:intent_id/capture", canCapturePayment, controllers.CapturePaymentIntent())
+
+	incomingRoutes.GET("/tips", canViewTips, controllers.GetTipReport())
+}
+
+// PublicPaymentRoutes receives the payment provider's webhooks, which are signed instead of authenticated
+func PublicPaymentRoutes(incomingRoutes *gin.Engine) {
+	incomingRoutes.POST("/webhooks/payments", controllers.PaymentWebhook())
+}
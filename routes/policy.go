@@ -0,0 +1,163 @@
+package routes
+
+import (
+	"restaurant-management/middlewares"
+	"restaurant-management/models"
+)
+
+// Restaurant resolvers for every resource that hangs off a restaurant
+var (
+	restaurantParam = middlewares.FromParam("restaurant_id")
+	restaurantQuery = middlewares.FromQuery("restaurant_id")
+	restaurantBody  = middlewares.FromBody("restaurant_id")
+
+	foodParam        = middlewares.LookupByParam("SELECT restaurant_id FROM foods WHERE id = $1", "food_id")
+	menuParam        = middlewares.LookupByParam("SELECT restaurant_id FROM menus WHERE id = $1", "menu_id")
+	comboParam       = middlewares.LookupByParam("SELECT restaurant_id FROM combos WHERE id = $1", "combo_id")
+	tableParam       = middlewares.LookupByParam("SELECT restaurant_id FROM tables WHERE id = $1", "table_id")
+	noteParam        = middlewares.LookupByParam("SELECT restaurant_id FROM notes WHERE id = $1", "note_id")
+	orderParam       = middlewares.LookupByParam("SELECT restaurant_id FROM orders WHERE id = $1", "order_id")
+	orderBody        = middlewares.LookupByBody("SELECT restaurant_id FROM orders WHERE id = $1", "order_id")
+	orderItemParam   = middlewares.LookupByParam("SELECT o.restaurant_id FROM orderitems oi JOIN orders o ON o.id = oi.order_id WHERE oi.id = $1", "order_item_id")
+	invoiceParam     = middlewares.LookupByParam("SELECT restaurant_id FROM invoices WHERE id = $1", "invoice_id")
+	intentParam      = middlewares.LookupByParam("SELECT i.restaurant_id FROM payment_intents p JOIN invoices i ON i.id = p.invoice_id WHERE p.id = $1", "intent_id")
+	stationParam     = middlewares.LookupByParam("SELECT restaurant_id FROM stations WHERE id = $1", "station_id")
+	reservationParam = middlewares.LookupByParam("SELECT restaurant_id FROM reservations WHERE id = $1", "reservation_id")
+	waitlistParam    = middlewares.LookupByParam("SELECT restaurant_id FROM waitlist_entries WHERE id = $1", "entry_id")
+	taxRateParam     = middlewares.LookupByParam("SELECT restaurant_id FROM tax_rates WHERE id = $1", "tax_rate_id")
+	chargeParam      = middlewares.LookupByParam("SELECT restaurant_id FROM service_charges WHERE id = $1", "service_charge_id")
+	promotionParam   = middlewares.LookupByParam("SELECT restaurant_id FROM promotions WHERE id = $1", "promotion_id")
+	discountParam    = middlewares.LookupByParam("SELECT o.restaurant_id FROM order_discounts d JOIN orders o ON o.id = d.order_id WHERE d.id = $1", "discount_id")
+	creditNoteParam  = middlewares.LookupByParam("SELECT restaurant_id FROM credit_notes WHERE id = $1", "credit_note_id")
+	refundParam      = middlewares.LookupByParam("SELECT c.restaurant_id FROM refunds r JOIN credit_notes c ON c.id = r.credit_note_id WHERE r.id = $1", "refund_id")
+)
+
+// Per-route permission requirements
+var (
+	authenticated = middlewares.Authorize()
+	selfUser      = middlewares.Authorize(middlewares.Self("user_id"))
+	selfOwner     = middlewares.Authorize(middlewares.Self("owner_id"))
+	canChangeRole = middlewares.Authorize(middlewares.RequireRole(models.RoleAdmin))
+
+	// Restaurants
+	canOpenRestaurant = middlewares.Authorize(middlewares.RequireRole(models.RoleAdmin), middlewares.SelfInBody("owner_id"))
+	canViewRestaurant = middlewares.Authorize(middlewares.Member(restaurantParam, models.MembershipStaff))
+	canManageStaff    = middlewares.Authorize(middlewares.Member(restaurantParam, models.MembershipManager))
+	canOwnRestaurant  = middlewares.Authorize(middlewares.Member(restaurantParam, models.MembershipOwner))
+
+	// Foods
+	canListFoods  = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canViewFood   = middlewares.Authorize(middlewares.Member(foodParam, models.MembershipStaff))
+	canCreateFood = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipManager))
+	canEditFood   = middlewares.Authorize(middlewares.Member(foodParam, models.MembershipManager), middlewares.Member(restaurantBody, models.MembershipManager))
+	canDeleteFood = middlewares.Authorize(middlewares.Member(foodParam, models.MembershipManager))
+
+	// Combos
+	canListCombos  = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canViewCombo   = middlewares.Authorize(middlewares.Member(comboParam, models.MembershipStaff))
+	canCreateCombo = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipManager))
+	canEditCombo   = middlewares.Authorize(middlewares.Member(comboParam, models.MembershipManager))
+
+	// Menus
+	canListMenus  = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canViewMenu   = middlewares.Authorize(middlewares.Member(menuParam, models.MembershipStaff))
+	canCreateMenu = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipManager))
+	canEditMenu   = middlewares.Authorize(middlewares.Member(menuParam, models.MembershipManager), middlewares.Member(restaurantBody, models.MembershipManager))
+	canDeleteMenu = middlewares.Authorize(middlewares.Member(menuParam, models.MembershipManager))
+
+	// Tables
+	canListTables  = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canViewTable   = middlewares.Authorize(middlewares.Member(tableParam, models.MembershipStaff))
+	canCreateTable = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipManager))
+	canEditTable   = middlewares.Authorize(middlewares.Member(tableParam, models.MembershipStaff), middlewares.Member(restaurantBody, models.MembershipStaff))
+	canDeleteTable = middlewares.Authorize(middlewares.Member(tableParam, models.MembershipManager))
+
+	// Orders
+	canListOrders   = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canViewOrder    = middlewares.Authorize(middlewares.Member(orderParam, models.MembershipStaff))
+	canCreateOrder  = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipStaff))
+	canEditOrder    = middlewares.Authorize(middlewares.MemberOrBlank(orderParam, models.MembershipStaff), middlewares.Member(restaurantBody, models.MembershipStaff))
+	canUpdateStatus = middlewares.Authorize(middlewares.Member(orderParam, models.MembershipStaff))
+	canDeleteOrder  = middlewares.Authorize(middlewares.Member(orderParam, models.MembershipManager))
+
+	// Order items
+	canListOrderItems  = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canViewOrderItem   = middlewares.Authorize(middlewares.Member(orderItemParam, models.MembershipStaff))
+	canCreateOrderItem = middlewares.Authorize(middlewares.Member(orderBody, models.MembershipStaff))
+	canEditOrderItem   = middlewares.Authorize(middlewares.Member(orderItemParam, models.MembershipStaff))
+
+	// Reservations
+	canListReservations  = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canViewReservation   = middlewares.Authorize(middlewares.Member(reservationParam, models.MembershipStaff))
+	canCreateReservation = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipStaff))
+	canEditReservation   = middlewares.Authorize(middlewares.Member(reservationParam, models.MembershipStaff))
+
+	// Waitlist
+	canListWaitlist = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canJoinWaitlist = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipStaff))
+	canEditWaitlist = middlewares.Authorize(middlewares.Member(waitlistParam, models.MembershipStaff))
+
+	// Kitchen
+	canViewKitchen   = middlewares.Authorize(middlewares.Member(restaurantParam, models.MembershipStaff))
+	canBumpOrderItem = middlewares.Authorize(middlewares.Member(orderItemParam, models.MembershipStaff))
+
+	// Stations
+	canListStations  = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canViewStation   = middlewares.Authorize(middlewares.Member(stationParam, models.MembershipStaff))
+	canCreateStation = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipManager))
+	canEditStation   = middlewares.Authorize(middlewares.Member(stationParam, models.MembershipManager))
+
+	// Tax rates
+	canListTaxRates  = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canViewTaxRate   = middlewares.Authorize(middlewares.Member(taxRateParam, models.MembershipStaff))
+	canCreateTaxRate = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipManager))
+	canEditTaxRate   = middlewares.Authorize(middlewares.Member(taxRateParam, models.MembershipManager))
+
+	// Service charges
+	canListServiceCharges  = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canViewServiceCharge   = middlewares.Authorize(middlewares.Member(chargeParam, models.MembershipStaff))
+	canCreateServiceCharge = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipManager))
+	canEditServiceCharge   = middlewares.Authorize(middlewares.Member(chargeParam, models.MembershipManager))
+
+	// Promotions and order discounts
+	canListPromotions  = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canViewPromotion   = middlewares.Authorize(middlewares.Member(promotionParam, models.MembershipStaff))
+	canCreatePromotion = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipManager))
+	canEditPromotion   = middlewares.Authorize(middlewares.Member(promotionParam, models.MembershipManager))
+	canDiscountOrder   = middlewares.Authorize(middlewares.Member(orderParam, models.MembershipStaff))
+	canApproveDiscount = middlewares.Authorize(middlewares.Member(discountParam, models.MembershipManager))
+	canRemoveDiscount  = middlewares.Authorize(middlewares.Member(discountParam, models.MembershipStaff))
+
+	// Invoices
+	canListInvoices = middlewares.Authorize(middlewares.Member(restaurantParam, models.MembershipStaff))
+	canViewInvoice  = middlewares.Authorize(middlewares.Member(invoiceParam, models.MembershipStaff))
+	canTakePayment  = middlewares.Authorize(middlewares.Member(invoiceParam, models.MembershipStaff))
+	canSplitInvoice = middlewares.Authorize(middlewares.Member(invoiceParam, models.MembershipStaff))
+	canSendInvoice  = middlewares.Authorize(middlewares.Member(invoiceParam, models.MembershipStaff))
+
+	canCapturePayment = middlewares.Authorize(middlewares.Member(intentParam, models.MembershipStaff))
+	canViewTips       = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipManager))
+	canViewFoodSales  = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipManager))
+
+	// Credit notes and refunds are a manager's call
+	canCreditInvoice  = middlewares.Authorize(middlewares.Member(invoiceParam, models.MembershipManager))
+	canViewCreditNote = middlewares.Authorize(middlewares.Member(creditNoteParam, models.MembershipStaff))
+	canRetryRefund    = middlewares.Authorize(middlewares.Member(refundParam, models.MembershipManager))
+
+	// How invoices and credit notes are numbered
+	canListNumberSeries = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canSetNumberSeries  = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipManager))
+
+	// How invoices and credit notes are printed
+	canViewInvoiceTemplate = middlewares.Authorize(middlewares.Member(restaurantQuery, models.MembershipStaff))
+	canSetInvoiceTemplate  = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipManager))
+
+	// Thermal printers
+	canPrintReceipt       = middlewares.Authorize(middlewares.Member(invoiceParam, models.MembershipStaff))
+	canPrintKitchenTicket = middlewares.Authorize(middlewares.Member(orderParam, models.MembershipStaff))
+
+	// Notes
+	canListNotes  = middlewares.Authorize(middlewares.Member(restaurantParam, models.MembershipStaff))
+	canCreateNote = middlewares.Authorize(middlewares.Member(restaurantBody, models.MembershipStaff))
+	canEditNote   = middlewares.Authorize(middlewares.Member(noteParam, models.MembershipStaff))
+)
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PromotionRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/promotions", canListPromotions, controllers.GetPromotions())
+	incomingRoutes.GET("/promotions/:promotion_id", canViewPromotion, controllers.GetPromotion())
+	incomingRoutes.POST("/promotions", canCreatePromotion, controllers.CreatePromotion())
+	incomingRoutes.PATCH("/promotions/:promotion_id", canEditPromotion, controllers.UpdatePromotion())
+	incomingRoutes.DELETE("/promotions/:promotion_id", canEditPromotion, controllers.DeletePromotion())
+
+	incomingRoutes.GET("/orders/:order_id/discounts", canViewOrder, controllers.GetOrderDiscounts())
+	incomingRoutes.POST("/orders/:order_id/discounts", canDiscountOrder, controllers.ApplyOrderDiscount())
+	incomingRoutes.POST("/order-discounts/:discount_id/approve", canApproveDiscount, controllers.ApproveOrderDiscount())
+	incomingRoutes.DELETE("/order-discounts/:discount_id", canRemoveDiscount, controllers.RemoveOrderDiscount())
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ReceiptRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/invoice-receipt/:invoice_id", canViewInvoice, controllers.GetReceipt())
+	incomingRoutes.POST("/invoice-receipt/:invoice_id/print", canPrintReceipt, controllers.PrintReceipt())
+	incomingRoutes.GET("/orders/:order_id/kitchen-tickets", canViewOrder, controllers.GetKitchenTickets())
+	incomingRoutes.POST("/orders/:order_id/kitchen-tickets/print", canPrintKitchenTicket, controllers.PrintKitchenTickets())
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ReservationRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/reservations", canListReservations, controllers.GetReservations())
+	incomingRoutes.GET("/reservations-availability", canListReservations, controllers.SearchAvailability())
+	incomingRoutes.GET("/reservations/:reservation_id", canViewReservation, controllers.GetReservation())
+	incomingRoutes.POST("/reservations", canCreateReservation, controllers.CreateReservation())
+	incomingRoutes.PATCH("/reservations/:reservation_id", canEditReservation, controllers.UpdateReservation())
+	incomingRoutes.PATCH("/reservations-status/:reservation_id", canEditReservation, controllers.UpdateReservationStatus())
+}
@@ -7,10 +7,15 @@ import (
 )
 
 func RestaurantRoutes(incomingRoutes gin.IRoutes) {
-	incomingRoutes.GET("/restaurant", controllers.GetRestaurants())
-	incomingRoutes.GET("/restaurant/:restaurant_id", controllers.GetRestaurant())
-	incomingRoutes.GET("/restaurant-owner/:owner_id", controllers.GetRestaurantsByOwner())
-	incomingRoutes.POST("/restaurant", controllers.CreateRestaurant())
-	incomingRoutes.PATCH("/restaurant/:restaurant_id", controllers.UpdateRestaurant())
-	incomingRoutes.DELETE("/restaurant/:restaurant_id", controllers.DeleteRestaurant())
+	incomingRoutes.GET("/restaurant", authenticated, controllers.GetRestaurants())
+	incomingRoutes.GET("/restaurant/:restaurant_id", canViewRestaurant, controllers.GetRestaurant())
+	incomingRoutes.GET("/restaurant-owner/:owner_id", selfOwner, controllers.GetRestaurantsByOwner())
+	incomingRoutes.POST("/restaurant", canOpenRestaurant, controllers.CreateRestaurant())
+	incomingRoutes.PATCH("/restaurant/:restaurant_id", canOwnRestaurant, controllers.UpdateRestaurant())
+	incomingRoutes.DELETE("/restaurant/:restaurant_id", canOwnRestaurant, controllers.DeleteRestaurant())
+
+	// Staff memberships
+	incomingRoutes.GET("/restaurant/:restaurant_id/staff", canManageStaff, controllers.GetRestaurantStaff())
+	incomingRoutes.POST("/restaurant/:restaurant_id/staff", canOwnRestaurant, controllers.AddRestaurantStaff())
+	incomingRoutes.DELETE("/restaurant/:restaurant_id/staff/:user_id", canOwnRestaurant, controllers.RemoveRestaurantStaff())
 }
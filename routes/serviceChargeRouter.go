@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ServiceChargeRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/service-charges", canListServiceCharges, controllers.GetServiceCharges())
+	incomingRoutes.GET("/service-charges/:service_charge_id", canViewServiceCharge, controllers.GetServiceCharge())
+	incomingRoutes.POST("/service-charges", canCreateServiceCharge, controllers.CreateServiceCharge())
+	incomingRoutes.PATCH("/service-charges/:service_charge_id", canEditServiceCharge, controllers.UpdateServiceCharge())
+	incomingRoutes.DELETE("/service-charges/:service_charge_id", canEditServiceCharge, controllers.DeleteServiceCharge())
+}
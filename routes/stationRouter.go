@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func StationRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/stations", canListStations, controllers.GetStations())
+	incomingRoutes.GET("/stations/:station_id", canViewStation, controllers.GetStation())
+	incomingRoutes.POST("/stations", canCreateStation, controllers.CreateStation())
+	incomingRoutes.PATCH("/stations/:station_id", canEditStation, controllers.UpdateStation())
+	incomingRoutes.DELETE("/stations/:station_id", canEditStation, controllers.DeleteStation())
+}
@@ -7,9 +7,9 @@ import (
 )
 
 func TableRoutes(incomingRoutes gin.IRoutes) {
-	incomingRoutes.GET("/tables", controllers.GetTablesByRestaurantId())
-	incomingRoutes.GET("/tables/:table_id", controllers.GetTable())
-	incomingRoutes.POST("/tables", controllers.CreateTable())
-	incomingRoutes.PATCH("/tables/:table_id", controllers.UpdateTable())
-	incomingRoutes.DELETE("/tables/:table_id", controllers.DeleteTable())
+	incomingRoutes.GET("/tables", canListTables, controllers.GetTablesByRestaurantId())
+	incomingRoutes.GET("/tables/:table_id", canViewTable, controllers.GetTable())
+	incomingRoutes.POST("/tables", canCreateTable, controllers.CreateTable())
+	incomingRoutes.PATCH("/tables/:table_id", canEditTable, controllers.UpdateTable())
+	incomingRoutes.DELETE("/tables/:table_id", canDeleteTable, controllers.DeleteTable())
 }
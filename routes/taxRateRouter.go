@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TaxRateRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/tax-rates", canListTaxRates, controllers.GetTaxRates())
+	incomingRoutes.GET("/tax-rates/:tax_rate_id", canViewTaxRate, controllers.GetTaxRate())
+	incomingRoutes.POST("/tax-rates", canCreateTaxRate, controllers.CreateTaxRate())
+	incomingRoutes.PATCH("/tax-rates/:tax_rate_id", canEditTaxRate, controllers.UpdateTaxRate())
+	incomingRoutes.DELETE("/tax-rates/:tax_rate_id", canEditTaxRate, controllers.DeleteTaxRate())
+}
@@ -11,12 +11,15 @@ func PublicUserRoutes(incomingRoutes *gin.Engine) {
 	incomingRoutes.GET("/users/:user_id", controllers.GetUser())
 	incomingRoutes.POST("/users/signup", controllers.SignUp())
 	incomingRoutes.POST("/users/login", controllers.Login())
+	incomingRoutes.POST("/users/refresh", controllers.RefreshToken())
 	incomingRoutes.POST("/users/reset-password-otp", controllers.SendPasswordResetEmail())
 	incomingRoutes.POST("/users/reset-password", controllers.PasswordReset())
 }
 
 func ProtectedUserRoutes(incomingRoutes gin.IRoutes) {
-	incomingRoutes.PATCH("/users/:user_id", controllers.UpdateUser())
-	incomingRoutes.DELETE("/users/:user_id", controllers.DeleteUser())
+	incomingRoutes.POST("/users/logout", authenticated, controllers.Logout())
+	incomingRoutes.PATCH("/users/:user_id", selfUser, controllers.UpdateUser())
+	incomingRoutes.PATCH("/users/:user_id/role", canChangeRole, controllers.UpdateUserRole())
+	incomingRoutes.DELETE("/users/:user_id", selfUser, controllers.DeleteUser())
 
 }
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"restaurant-management/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func WaitlistRoutes(incomingRoutes gin.IRoutes) {
+	incomingRoutes.GET("/waitlist", canListWaitlist, controllers.GetWaitlist())
+	incomingRoutes.GET("/waitlist-estimate", canListWaitlist, controllers.GetWaitEstimate())
+	incomingRoutes.POST("/waitlist", canJoinWaitlist, controllers.CreateWaitlistEntry())
+	incomingRoutes.POST("/waitlist/:entry_id/notify", canEditWaitlist, controllers.NotifyWaitlistEntry())
+	incomingRoutes.POST("/waitlist/:entry_id/seat", canEditWaitlist, controllers.SeatWaitlistEntry())
+	incomingRoutes.DELETE("/waitlist/:entry_id", canEditWaitlist, controllers.DeleteWaitlistEntry())
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"restaurant-management/config"
+	"restaurant-management/helpers"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, all sessions from this login were revoked")
+)
+
+// rotateScript swaps the family's current refresh token ID only if the caller presented it
+var rotateScript = redis.NewScript(`
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+		return 1
+	end
+	return 0
+`)
+
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // access token lifetime in seconds
+}
+
+// IssueTokenPair signs a short-lived access token and a refresh token. An empty
+// familyID starts a new token family, as happens on login.
+func IssueTokenPair(ctx context.Context, claims helpers.Claims, familyID string) (TokenPair, error) {
+	if familyID == "" {
+		var err error
+		if familyID, err = helpers.NewTokenID(); err != nil {
+			return TokenPair{}, err
+		}
+	}
+	claims.FamilyID = familyID
+
+	claims.TokenType = helpers.TokenTypeAccess
+	accessToken, _, err := helpers.CreateToken(claims, authTokenExp)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	// refresh tokens only need to identify the user, memberships are reloaded on refresh
+	refreshClaims := helpers.Claims{UserID: claims.UserID, Username: claims.Username, TokenType: helpers.TokenTypeRefresh, FamilyID: familyID}
+	refreshToken, issued, err := helpers.CreateToken(refreshClaims, refreshTokenExp)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	pipe := config.RedisClient.TxPipeline()
+	pipe.Set(ctx, familyKeyPrefix+familyID, issued.ID, refreshTokenExp)
+	pipe.SAdd(ctx, userFamilyKey(claims.UserID), familyID)
+	pipe.Expire(ctx, userFamilyKey(claims.UserID), refreshTokenExp)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresIn: int(authTokenExp.Seconds())}, nil
+}
+
+// RotateRefreshToken validates a refresh token and marks it as spent. The returned
+// claims identify the user and family the new pair must be issued for. Presenting a
+// refresh token that was already rotated revokes the whole family.
+func RotateRefreshToken(ctx context.Context, refreshToken string) (*helpers.Claims, error) {
+	claims, err := helpers.VerifyToken(refreshToken)
+	if err != nil || claims.TokenType != helpers.TokenTypeRefresh || claims.FamilyID == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	revoked, err := IsTokenRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// mark the presented token as spent, IssueTokenPair then records its successor
+	spent, err := rotateScript.Run(ctx, config.RedisClient, []string{familyKeyPrefix + claims.FamilyID}, claims.ID, "spent:"+claims.ID, refreshTokenExp.Milliseconds()).Int()
+	if err != nil {
+		return nil, err
+	}
+	if spent == 0 {
+		exists, err := config.RedisClient.Exists(ctx, familyKeyPrefix+claims.FamilyID).Result()
+		if err != nil {
+			return nil, err
+		}
+		if exists == 0 {
+			return nil, ErrInvalidRefreshToken
+		}
+		// the family is alive but has moved on, so this token was stolen or replayed
+		if err := RevokeTokenFamily(ctx, claims.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	return claims, nil
+}
+
+// RevokeToken blacklists a single access token for the rest of its lifetime
+func RevokeToken(ctx context.Context, claims *helpers.Claims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+	return config.RedisClient.Set(ctx, blacklistKeyPrefix+claims.ID, 1, ttl).Err()
+}
+
+// RevokeTokenFamily invalidates every access and refresh token issued from one login
+func RevokeTokenFamily(ctx context.Context, familyID string) error {
+	pipe := config.RedisClient.TxPipeline()
+	pipe.Set(ctx, blacklistKeyPrefix+"family:"+familyID, 1, refreshTokenExp)
+	pipe.Del(ctx, familyKeyPrefix+familyID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RevokeUserTokens signs the user out everywhere, used after password changes
+func RevokeUserTokens(ctx context.Context, userID uint) error {
+	families, err := config.RedisClient.SMembers(ctx, userFamilyKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+	for _, familyID := range families {
+		if err := RevokeTokenFamily(ctx, familyID); err != nil {
+			return err
+		}
+	}
+	return config.RedisClient.Del(ctx, userFamilyKey(userID)).Err()
+}
+
+// IsTokenRevoked reports whether the token itself or its family has been blacklisted
+func IsTokenRevoked(ctx context.Context, claims *helpers.Claims) (bool, error) {
+	keys := []string{blacklistKeyPrefix + claims.ID}
+	if claims.FamilyID != "" {
+		keys = append(keys, blacklistKeyPrefix+"family:"+claims.FamilyID)
+	}
+
+	found, err := config.RedisClient.Exists(ctx, keys...).Result()
+	if err != nil {
+		return false, err
+	}
+	return found > 0, nil
+}
+
+func userFamilyKey(userID uint) string {
+	return userFamilyPrefix + strconv.FormatUint(uint64(userID), 10)
+}
@@ -3,13 +3,19 @@ package utils
 import "time"
 
 const (
-	authTokenExp       = time.Minute * 10
-	refreshTokenExp    = time.Hour * 24 * 30 // 1 month
-	blacklistKeyPrefix = "blacklisted:"
-	otpKeyPrefix       = "password-reset:"
-	otpExp             = time.Minute * 10
-	otpCharSet         = "1234567890"
-	emailTemplate      = "To: %s\r\n" +
+	authTokenExp        = time.Minute * 10
+	refreshTokenExp     = time.Hour * 24 * 30 // 1 month
+	blacklistKeyPrefix  = "blacklisted:"
+	familyKeyPrefix     = "refresh-family:" // holds the only refresh token ID still valid in a family
+	userFamilyPrefix    = "user-families:"  // set of live token families per user
+	otpKeyPrefix        = "password-reset:"
+	otpExp              = time.Minute * 10
+	otpCharSet          = "1234567890"
+	kitchenStreamPrefix = "kitchen-stream:" // recent kitchen events per restaurant, replayed on resume
+	kitchenNotifyPrefix = "kitchen-notify:" // pub/sub channel announcing new kitchen events
+	kitchenStreamLength = 1000
+	kitchenStreamExp    = time.Hour * 24
+	emailTemplate       = "To: %s\r\n" +
 		"Subject: Restaurant-Management Password Reset\r\n" +
 		"\r\n" +
 		"Your OTP for password reset is %s\r\n"
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"restaurant-management/config"
+	"restaurant-management/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrInvalidEventID = errors.New("last event ID is not a valid kitchen feed event ID")
+
+// publishScript appends the event to the restaurant's stream and announces its ID in one round trip
+var publishScript = redis.NewScript(`
+	local id = redis.call("XADD", KEYS[1], "MAXLEN", "~", ARGV[1], "*", "event", ARGV[2])
+	redis.call("PEXPIRE", KEYS[1], ARGV[3])
+	redis.call("PUBLISH", KEYS[2], id)
+	return id
+`)
+
+// PublishKitchenEvent records the event in the restaurant's kitchen feed. Events go to a
+// capped Redis stream, so reconnecting clients can catch up, and every API instance
+// streaming that restaurant is woken up through pub/sub.
+func PublishKitchenEvent(ctx context.Context, event models.KitchenEvent) error {
+	if event.RestaurantID == 0 {
+		return nil
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{kitchenStreamKey(event.RestaurantID), kitchenNotifyKey(event.RestaurantID)}
+	return publishScript.Run(ctx, config.RedisClient, keys, kitchenStreamLength, payload, kitchenStreamExp.Milliseconds()).Err()
+}
+
+// KitchenFeed follows one restaurant's kitchen events for a single client
+type KitchenFeed struct {
+	restaurantID uint
+	lastID       string
+	pubsub       *redis.PubSub
+}
+
+// SubscribeKitchenFeed starts following a restaurant's kitchen events after lastEventID.
+// An empty lastEventID starts from the newest event, so only future changes are delivered.
+// The caller must Close the feed.
+func SubscribeKitchenFeed(ctx context.Context, restaurantID uint, lastEventID string) (*KitchenFeed, error) {
+	if lastEventID != "" && !validStreamID(lastEventID) {
+		return nil, ErrInvalidEventID
+	}
+
+	// subscribe before reading the stream so nothing published in between is missed
+	pubsub := config.RedisClient.Subscribe(ctx, kitchenNotifyKey(restaurantID))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	feed := &KitchenFeed{restaurantID: restaurantID, lastID: lastEventID, pubsub: pubsub}
+	if feed.lastID == "" {
+		latest, err := config.RedisClient.XRevRangeN(ctx, kitchenStreamKey(restaurantID), "+", "-", 1).Result()
+		if err != nil {
+			pubsub.Close()
+			return nil, err
+		}
+		feed.lastID = "0-0"
+		if len(latest) > 0 {
+			feed.lastID = latest[0].ID
+		}
+	}
+
+	return feed, nil
+}
+
+// Notifications fires whenever a new event may be waiting to be read with Next
+func (f *KitchenFeed) Notifications() <-chan *redis.Message {
+	return f.pubsub.Channel()
+}
+
+// Next returns every event published after the last one returned, oldest first.
+// missed is true when the stream was trimmed past the client's last event ID, in which
+// case the client should reload the orders it shows before applying the events.
+func (f *KitchenFeed) Next(ctx context.Context) (events []models.KitchenEvent, missed bool, err error) {
+	key := kitchenStreamKey(f.restaurantID)
+
+	if f.lastID != "0-0" {
+		oldest, err := config.RedisClient.XRangeN(ctx, key, "-", "+", 1).Result()
+		if err != nil {
+			return nil, false, err
+		}
+		missed = len(oldest) > 0 && compareStreamIDs(oldest[0].ID, f.lastID) > 0
+	}
+
+	entries, err := config.RedisClient.XRange(ctx, key, "("+f.lastID, "+").Result()
+	if err != nil {
+		return nil, missed, err
+	}
+
+	for _, entry := range entries {
+		var event models.KitchenEvent
+		payload, _ := entry.Values["event"].(string)
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			return events, missed, err
+		}
+		event.ID = entry.ID
+		events = append(events, event)
+		f.lastID = entry.ID
+	}
+
+	return events, missed, nil
+}
+
+func (f *KitchenFeed) Close() error {
+	return f.pubsub.Close()
+}
+
+func kitchenStreamKey(restaurantID uint) string {
+	return kitchenStreamPrefix + strconv.FormatUint(uint64(restaurantID), 10)
+}
+
+func kitchenNotifyKey(restaurantID uint) string {
+	return kitchenNotifyPrefix + strconv.FormatUint(uint64(restaurantID), 10)
+}
+
+// validStreamID reports whether id has the "<milliseconds>-<sequence>" shape of a stream entry ID
+func validStreamID(id string) bool {
+	_, _, ok := parseStreamID(id)
+	return ok
+}
+
+func parseStreamID(id string) (ms, seq uint64, ok bool) {
+	msPart, seqPart, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err = strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return ms, seq, true
+}
+
+func compareStreamIDs(a, b string) int {
+	aMs, aSeq, _ := parseStreamID(a)
+	bMs, bSeq, _ := parseStreamID(b)
+	switch {
+	case aMs != bMs:
+		if aMs < bMs {
+			return -1
+		}
+		return 1
+	case aSeq != bSeq:
+		if aSeq < bSeq {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}